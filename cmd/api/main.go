@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"flag"
 	"log"
 	"net/http"
@@ -10,14 +11,33 @@ import (
 	"syscall"
 	"time"
 
+	gpubsub "cloud.google.com/go/pubsub"
 	"github.com/IsaacDSC/gqueue/cmd/setup/backoffice"
+	"github.com/IsaacDSC/gqueue/cmd/setup/memstore"
 	"github.com/IsaacDSC/gqueue/cmd/setup/pubsub"
 	"github.com/IsaacDSC/gqueue/cmd/setup/task"
+	"github.com/IsaacDSC/gqueue/internal/alerting"
+	"github.com/IsaacDSC/gqueue/internal/apikeys"
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/app/pubsubapp"
+	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
+	"github.com/IsaacDSC/gqueue/internal/backlog"
 	"github.com/IsaacDSC/gqueue/internal/cfg"
+	"github.com/IsaacDSC/gqueue/internal/dlqstore"
+	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/internal/fetcher"
 	"github.com/IsaacDSC/gqueue/internal/interstore"
+	"github.com/IsaacDSC/gqueue/internal/projects"
+	"github.com/IsaacDSC/gqueue/internal/quarantine"
+	"github.com/IsaacDSC/gqueue/internal/registry"
+	"github.com/IsaacDSC/gqueue/internal/replay"
 	"github.com/IsaacDSC/gqueue/internal/storests"
+	"github.com/IsaacDSC/gqueue/internal/timeline"
+	"github.com/IsaacDSC/gqueue/pkg/auth"
+	"github.com/IsaacDSC/gqueue/pkg/pgqueue"
+	"github.com/IsaacDSC/gqueue/pkg/pubadapter"
 	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+	"github.com/hibiken/asynq"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -51,6 +71,7 @@ func waitForShutdown(ctx context.Context, servers []*http.Server) {
 // go run . --scope=backoffice
 // go run . --scope=pubsub
 // go run . --scope=task
+// go run . migrate up|down|status
 func main() {
 	conf := cfg.Get()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -59,8 +80,29 @@ func main() {
 	scope := flag.String("scope", "all", "service to run")
 	flag.Parse()
 
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(ctx, conf.ConfigDatabase, flag.Args()[1:]); err != nil {
+			log.Fatalf("[!] migrate: %v", err)
+		}
+		return
+	}
+
+	if err := checkSchema(ctx, conf.ConfigDatabase); err != nil {
+		panic(err)
+	}
+
 	_, err := telemetry.New(telemetry.Config{
-		Enabled: conf.MetricsEnabled,
+		Enabled:   conf.MetricsEnabled,
+		Exporters: conf.MetricsExporters,
+		OTLP: telemetry.OTLPConfig{
+			Endpoint:    conf.OTELExporterOTLPEndpoint,
+			Protocol:    conf.OTELExporterOTLPProtocol,
+			Interval:    conf.MetricsExportInterval,
+			Timeout:     conf.MetricsExportTimeout,
+			Temporality: conf.MetricsTemporality,
+		},
+		Resource: telemetry.ResourceConfig{ServiceName: "gqueue", Scope: *scope},
+		Tracing:  telemetry.TracingConfig{SampleRatio: conf.TracesSampleRatio},
 	})
 	if err != nil {
 		panic(err)
@@ -71,38 +113,151 @@ func main() {
 		panic(err)
 	}
 
-	storeInsights := storests.NewStore(redisClient)
+	storeInsights := storests.NewStore(redisClient, storests.Retention{
+		Minute: conf.Insights.MinuteRetention,
+		Hour:   conf.Insights.HourRetention,
+		Day:    conf.Insights.DayRetention,
+	})
+	deadLetters := dlqstore.NewStore(redisClient, conf.DeadLetter.Retention)
+
+	// the lifecycle of the messages is written in the background, it never delays them
+	timelines := timeline.NewStore(redisClient, conf.Timeline.TTL)
+	recorder := timeline.NewRecorder(timelines, conf.Timeline.Buffer)
 
-	store, err := interstore.NewPostgresStoreFromDSN(conf.ConfigDatabase.DbConn)
+	quarantines := quarantine.NewStore(redisClient)
+
+	store, err := interstore.NewRepository(ctx, conf.ConfigDatabase.Driver, conf.ConfigDatabase.DbConn)
 	if err != nil {
 		panic(err)
 	}
 
+	// registry changes are broadcast so every instance updates its mem store right away
+	broker := registry.NewBroker(redisClient)
+	store = interstore.NewBroadcastStore(store, broker)
+
+	// every API request runs in the project found from its credentials or X-Project-ID header
+	resolver := projects.NewResolver(store, conf.Projects.CacheTTL, conf.Auth.Required)
+	authenticator := apikeys.NewAuthenticator(store, conf.Auth.APIKeysCacheTTL)
+	verifier := newJWTVerifier(conf.Auth.JWT)
+	if !conf.Auth.Required {
+		log.Println("[!] AUTH_REQUIRED is disabled, requests without credentials are accepted")
+	}
+
 	var servers []*http.Server
 	var closers []func()
 
 	log.Println("[*] Starting scope", *scope)
 
 	if scopeOrAll(*scope, "backoffice") {
+		inspector := asynq.NewInspector(asynq.RedisClientOpt{Addr: conf.Cache.CacheAddr})
+		closers = append(closers, func() { _ = inspector.Close() })
+
+		taskManager := taskapp.NewTaskManager(inspector, recorder)
+
+		// replay jobs search every store of failed deliveries
+		var replaySources []replay.Source
+
+		// dead letters are replayed through the Pub/Sub request topic, other queues have no
+		// Pub/Sub client and answer the replays with an error
+		var deadLetterReplayer backofficeapp.DeadLetterReplayer
+		if conf.WQ == cfg.WQGooglePubSub {
+			pubsubClient, err := gpubsub.NewClient(ctx, conf.GCPProjectID)
+			if err != nil {
+				panic(err)
+			}
+			closers = append(closers, func() { _ = pubsubClient.Close() })
+
+			replayer := pubsubapp.NewDeadLetterReplayer(pubadapter.NewPubSubGoogle(pubsubClient), store, recorder)
+			deadLetterReplayer = replayer
+			replaySources = append(replaySources, replay.NewDeadLetterSource(deadLetters, replayer))
+		}
+
+		replaySources = append(replaySources, replay.NewArchivedTaskSource(taskManager))
+
+		if conf.SQLQueue.Enabled {
+			db, err := sql.Open("postgres", conf.ConfigDatabase.DbConn)
+			if err != nil {
+				panic(err)
+			}
+			closers = append(closers, func() { _ = db.Close() })
+
+			replaySources = append(replaySources, replay.NewDurableSQLSource(pgqueue.NewClient(db), recorder))
+		}
+
+		replays := replay.NewManager(replay.NewStore(redisClient), replaySources...)
+		closers = append(closers, replays.Close)
+
+		alertStates := alerting.NewStateStore(redisClient)
+		evaluator := alerting.NewEvaluator(store, storeInsights, deadLetters, alertStates, fetcher.NewNotification(), alerting.Config{
+			Interval:   conf.Alerts.Interval,
+			WebhookURL: conf.Alerts.WebhookURL,
+		})
+		go evaluator.Run(ctx)
+
+		monitor := quarantine.NewMonitor(store, storeInsights, quarantines, fetcher.NewNotification(), quarantine.Config{
+			Enabled:  conf.Quarantine.Enabled,
+			Interval: conf.Quarantine.ProbeInterval,
+			Policy: domain.HealthPolicy{
+				Window:        conf.Quarantine.Window,
+				FailureBudget: conf.Quarantine.FailureBudget,
+				MinDeliveries: conf.Quarantine.MinDeliveries,
+			},
+		})
+		go monitor.Run(ctx)
+
+		// the Pub/Sub backlog is only reported by Cloud Monitoring, not by the emulator
+		var subscriptions backlog.SubscriptionReader
+		if conf.Backlog.PubSubEnabled {
+			reader, err := backlog.NewMonitoringReader(ctx, conf.GCPProjectID)
+			if err != nil {
+				panic(err)
+			}
+			subscriptions = reader
+		}
+
+		collector := backlog.NewCollector(inspector, store, subscriptions, backlog.Config{
+			Interval:        conf.Backlog.Interval,
+			MaxScannedTasks: conf.Backlog.MaxScannedTasks,
+		})
+		go collector.Run(ctx)
+
 		backofficeServer := backoffice.Start(
 			redisClient,
 			store,
 			storeInsights,
+			broker,
+			deadLetters,
+			deadLetterReplayer,
+			taskManager,
+			replays,
+			timelines,
+			alertStates,
+			monitor,
+			resolver,
+			authenticator,
 		)
 		servers = append(servers, backofficeServer)
 	}
 
 	var memStore *interstore.MemStore
 	var fetch *fetcher.Notification
+	var quarantineChecker *quarantine.Checker
 	// task and pubsub share some dependencies, so we initialize them here and pass to both services
 	if *scope == "pubsub" || *scope == "task" || *scope == "all" {
 		memStore = interstore.NewMemStore(store)
 		fetch = fetcher.NewNotification()
+		quarantineChecker = quarantine.NewChecker(quarantines)
+
+		syncer := memstore.NewSyncer(memStore, broker)
+		if err := syncer.Resync(ctx); err != nil {
+			log.Printf("[!] Error loading mem store: %v", err)
+		}
+		go syncer.Run(ctx)
 	}
 
 	if scopeOrAll(*scope, "pubsub") {
 		s := pubsub.New(
-			store, memStore, fetch, storeInsights,
+			store, memStore, fetch, storeInsights, deadLetters, recorder, quarantineChecker, resolver, authenticator, verifier,
 		)
 		s.Start(ctx, conf)
 		closers = append(closers, s.Close)
@@ -111,7 +266,7 @@ func main() {
 
 	if scopeOrAll(*scope, "task") {
 		s := task.New(
-			store, memStore, fetch, storeInsights,
+			store, memStore, fetch, storeInsights, recorder, quarantineChecker, resolver, authenticator, verifier,
 		)
 		s.Start(ctx, conf)
 		closers = append(closers, s.Close)
@@ -125,6 +280,9 @@ func main() {
 		closeFn()
 	}
 
+	// the consumers are stopped, the entries they recorded can be written
+	recorder.Close()
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 5*time.Second)
 	defer shutdownCancel()
 	if err := telemetry.Shutdown(shutdownCtx); err != nil {
@@ -135,3 +293,22 @@ func main() {
 func scopeOrAll(scope, expected string) bool {
 	return scope == "all" || scope == expected
 }
+
+// newJWTVerifier verifies the tokens of publishers against the configured JWKS, publishers cannot
+// authenticate with tokens when none is configured
+func newJWTVerifier(conf cfg.JWTConfig) *auth.JWTVerifier {
+	if conf.JWKS == "" {
+		return nil
+	}
+
+	return auth.NewJWTVerifier(auth.NewJWKS(conf.JWKS, conf.JWKSRefresh), auth.JWTConfig{
+		Issuer:   conf.Issuer,
+		Audience: conf.Audience,
+		Leeway:   conf.Leeway,
+		Mapping: auth.ClaimsMapping{
+			Publisher: conf.PublisherClaim,
+			Events:    conf.EventsClaim,
+			Project:   conf.ProjectClaim,
+		},
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/IsaacDSC/gqueue/internal/cfg"
+	"github.com/IsaacDSC/gqueue/internal/interstore"
+	"github.com/IsaacDSC/gqueue/internal/migrations"
+)
+
+// go run . migrate up
+// go run . migrate down
+// go run . migrate status
+func runMigrate(ctx context.Context, conf cfg.ConfigDatabase, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: migrate up|down|status")
+	}
+
+	if conf.Driver == interstore.DriverMongo {
+		return errors.New("migrations are only supported for the pg driver")
+	}
+
+	m, err := migrations.Open(conf.DbConn)
+	if err != nil {
+		return err
+	}
+
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			log.Printf("[*] Applied migration %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Println("[*] Schema is up to date")
+		}
+
+	case "down":
+		reverted, ok, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			log.Println("[*] No migration to roll back")
+			return nil
+		}
+		log.Printf("[*] Rolled back migration %d_%s", reverted.Version, reverted.Name)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up|down|status", args[0])
+	}
+
+	return nil
+}
+
+// checkSchema refuses to start against a database that is not at the version expected by the binary
+func checkSchema(ctx context.Context, conf cfg.ConfigDatabase) error {
+	if conf.Driver == interstore.DriverMongo {
+		return nil
+	}
+
+	m, err := migrations.Open(conf.DbConn)
+	if err != nil {
+		return err
+	}
+
+	defer m.Close()
+
+	if err := m.Check(ctx); err != nil {
+		return fmt.Errorf("%w, run `migrate up`", err)
+	}
+
+	return nil
+}
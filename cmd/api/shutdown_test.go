@@ -169,6 +169,15 @@ func TestShutdownGraceful(t *testing.T) {
 
 	// Build and run the binary so SIGINT goes to the server process (go run would send it to the go process only).
 	exe := buildApiBinary(t, moduleRoot)
+
+	// The server refuses to start on an outdated schema, so apply the migrations first.
+	migrate := exec.Command(exe, "migrate", "up")
+	migrate.Dir = moduleRoot
+	migrate.Env = env
+	if out, err := migrate.CombinedOutput(); err != nil {
+		t.Fatalf("migrate up: %v\n%s", err, out)
+	}
+
 	cmd := exec.Command(exe)
 	cmd.Dir = moduleRoot
 	cmd.Env = env
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const headerProjectID = "X-Project-ID"
+
+// API is one of the gqueue services a request is sent to
+type API string
+
+const (
+	APIBackoffice API = "backoffice"
+	APIPubSub     API = "pubsub"
+	APITask       API = "task"
+)
+
+// APIError is a response outside 2xx, the message is the body written by http.Error
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	}
+
+	return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// Client sends the requests of the commands to the APIs of the target
+type Client struct {
+	http   *http.Client
+	target Target
+}
+
+func NewClient(target Target) *Client {
+	return &Client{http: &http.Client{Timeout: 30 * time.Second}, target: target}
+}
+
+// Request is a call to an API, Body is encoded as JSON
+type Request struct {
+	API     API
+	Method  string
+	Path    string
+	Query   url.Values
+	Body    any
+	Headers map[string]string
+}
+
+// Response is the body of a successful call with its headers
+type Response struct {
+	Body   []byte
+	Header http.Header
+}
+
+// Decode decodes the body into v
+func (r Response) Decode(v any) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) Do(ctx context.Context, req Request) (Response, error) {
+	endpoint, err := c.url(req)
+	if err != nil {
+		return Response{}, err
+	}
+
+	var body io.Reader
+	if req.Body != nil {
+		content, err := json.Marshal(req.Body)
+		if err != nil {
+			return Response{}, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(content)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, endpoint, body)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if req.Body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	c.authenticate(httpReq)
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to call %s: %w", req.API, err)
+	}
+
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Response{}, &APIError{Status: resp.StatusCode, Message: strings.TrimSpace(string(content))}
+	}
+
+	return Response{Body: content, Header: resp.Header}, nil
+}
+
+func (c *Client) url(req Request) (string, error) {
+	var base string
+	switch req.API {
+	case APIBackoffice:
+		base = c.target.Context.Backoffice
+	case APIPubSub:
+		base = c.target.Context.PubSub
+	case APITask:
+		base = c.target.Context.Task
+	default:
+		return "", fmt.Errorf("unknown api %q", req.API)
+	}
+
+	endpoint := strings.TrimSuffix(base, "/") + req.Path
+	if len(req.Query) > 0 {
+		endpoint += "?" + req.Query.Encode()
+	}
+
+	return endpoint, nil
+}
+
+// authenticate sends the API key of the profile, or its project credentials, and the project of
+// the context
+func (c *Client) authenticate(req *http.Request) {
+	profile := c.target.Profile
+	switch {
+	case profile.APIKey != "":
+		req.Header.Set("Authorization", "Bearer "+profile.APIKey)
+	case profile.Username != "":
+		req.SetBasicAuth(profile.Username, profile.Password)
+	}
+
+	if project := c.target.Context.Project; project != "" {
+		req.Header.Set(headerProjectID, project)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envConfig  = "GQUEUECTL_CONFIG"
+	envContext = "GQUEUECTL_CONTEXT"
+	envProfile = "GQUEUECTL_PROFILE"
+)
+
+// Config is the file of the environments gqueuectl talks to. A context names the APIs of an
+// environment and the project, a profile the credentials and preferences used with it.
+type Config struct {
+	CurrentContext string             `yaml:"current-context,omitempty"`
+	Contexts       map[string]Context `yaml:"contexts,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+type Context struct {
+	Backoffice string `yaml:"backoffice,omitempty"`
+	PubSub     string `yaml:"pubsub,omitempty"`
+	Task       string `yaml:"task,omitempty"`
+	// Project is sent as X-Project-ID, requests authenticated with an API key ignore it
+	Project string `yaml:"project,omitempty"`
+	Profile string `yaml:"profile,omitempty"`
+}
+
+type Profile struct {
+	APIKey   string `yaml:"api-key,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// Output is the default format, table when empty
+	Output string `yaml:"output,omitempty"`
+}
+
+// Target is the environment a command runs against, resolved from the context and profile
+type Target struct {
+	// Name is the name of the context, empty for the local APIs
+	Name    string
+	Context Context
+	Profile Profile
+}
+
+var localContext = Context{
+	Backoffice: "http://localhost:8081",
+	PubSub:     "http://localhost:8082",
+	Task:       "http://localhost:8083",
+}
+
+// defaultConfigPath is ~/.gqueue/config.yaml unless GQUEUECTL_CONFIG names another file
+func defaultConfigPath() string {
+	if path := os.Getenv(envConfig); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".gqueue.yaml"
+	}
+
+	return filepath.Join(home, ".gqueue", "config.yaml")
+}
+
+// LoadConfig reads the config file, a missing file is an empty config
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+
+	if err != nil {
+		return config, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return config, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Save writes the config readable only by the user, it holds credentials
+func (c Config) Save(path string) error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return nil
+}
+
+// Resolve finds the context and profile to use: the names given, then GQUEUECTL_CONTEXT and
+// GQUEUECTL_PROFILE, then the current context and its profile. Without any context the local
+// APIs started by docker compose are used.
+func (c Config) Resolve(contextName, profileName string) (Target, error) {
+	contextName = firstOf(contextName, os.Getenv(envContext), c.CurrentContext)
+
+	target := Target{Name: contextName, Context: localContext}
+	if contextName != "" {
+		ctx, ok := c.Contexts[contextName]
+		if !ok {
+			return Target{}, fmt.Errorf("context %q not found", contextName)
+		}
+
+		// the APIs left out are the local ones
+		ctx.Backoffice = firstOf(ctx.Backoffice, localContext.Backoffice)
+		ctx.PubSub = firstOf(ctx.PubSub, localContext.PubSub)
+		ctx.Task = firstOf(ctx.Task, localContext.Task)
+		target.Context = ctx
+	}
+
+	profileName = firstOf(profileName, os.Getenv(envProfile), target.Context.Profile)
+	if profileName != "" {
+		profile, ok := c.Profiles[profileName]
+		if !ok {
+			return Target{}, fmt.Errorf("profile %q not found", profileName)
+		}
+
+		target.Profile = profile
+	}
+
+	return target, nil
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
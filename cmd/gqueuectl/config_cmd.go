@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+)
+
+const redacted = "REDACTED"
+
+func configCommands() []Command {
+	return []Command{
+		{
+			Name:    "config view",
+			Summary: "Show the config file, credentials are redacted",
+			Offline: true,
+			Flags: func(fs *flag.FlagSet) Run {
+				raw := fs.Bool("raw", false, "show the credentials")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					config := env.Config
+					if !*raw {
+						config = config.redacted()
+					}
+
+					document, err := json.Marshal(configDocument(config))
+					if err != nil {
+						return err
+					}
+
+					// the config is a document, it has no table
+					if env.Printer.format == OutputTable {
+						env.Printer.format = OutputYAML
+					}
+
+					return env.Printer.Print(document, nil)
+				}
+			},
+		},
+		{
+			Name:    "config get-contexts",
+			Summary: "List the contexts, the current one is marked with *",
+			Offline: true,
+			Flags: func(fs *flag.FlagSet) Run {
+				return func(ctx context.Context, env *Env, args []string) error {
+					names := make([]string, 0, len(env.Config.Contexts))
+					for name := range env.Config.Contexts {
+						names = append(names, name)
+					}
+					sort.Strings(names)
+
+					rows := [][]string{{"CURRENT", "NAME", "BACKOFFICE", "PROJECT", "PROFILE"}}
+					for _, name := range names {
+						current := ""
+						if name == env.Config.CurrentContext {
+							current = "*"
+						}
+
+						c := env.Config.Contexts[name]
+						rows = append(rows, []string{current, name, c.Backoffice, orNone(c.Project), orNone(c.Profile)})
+					}
+
+					document, err := json.Marshal(configDocument(env.Config.redacted()).Contexts)
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Print(document, func() [][]string { return rows })
+				}
+			},
+		},
+		{
+			Name:    "config use-context",
+			Args:    "NAME",
+			Summary: "Make a context the current one",
+			Offline: true,
+			Flags: func(fs *flag.FlagSet) Run {
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "NAME"); err != nil {
+						return err
+					}
+
+					if _, ok := env.Config.Contexts[args[0]]; !ok {
+						return fmt.Errorf("context %q not found", args[0])
+					}
+
+					env.Config.CurrentContext = args[0]
+					if err := env.Config.Save(env.ConfigPath); err != nil {
+						return err
+					}
+
+					return env.Printer.Message(nil, "switched to context %s", args[0])
+				}
+			},
+		},
+		{
+			Name:    "config set-context",
+			Args:    "NAME",
+			Summary: "Create or change a context, the flags left out are kept",
+			Offline: true,
+			Flags: func(fs *flag.FlagSet) Run {
+				backoffice := fs.String("backoffice", "", "URL of the backoffice API")
+				pubsub := fs.String("pubsub", "", "URL of the pubsub API")
+				task := fs.String("task", "", "URL of the task API")
+				project := fs.String("project", "", "project sent as X-Project-ID")
+				profile := fs.String("use-profile", "", "profile of the context")
+				use := fs.Bool("use", false, "make it the current context")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "NAME"); err != nil {
+						return err
+					}
+
+					if *profile != "" {
+						if _, ok := env.Config.Profiles[*profile]; !ok {
+							return fmt.Errorf("profile %q not found, create it with config set-profile", *profile)
+						}
+					}
+
+					if env.Config.Contexts == nil {
+						env.Config.Contexts = make(map[string]Context)
+					}
+
+					c := env.Config.Contexts[args[0]]
+					c.Backoffice = firstOf(*backoffice, c.Backoffice)
+					c.PubSub = firstOf(*pubsub, c.PubSub)
+					c.Task = firstOf(*task, c.Task)
+					c.Project = firstOf(*project, c.Project)
+					c.Profile = firstOf(*profile, c.Profile)
+					env.Config.Contexts[args[0]] = c
+
+					if *use || env.Config.CurrentContext == "" {
+						env.Config.CurrentContext = args[0]
+					}
+
+					if err := env.Config.Save(env.ConfigPath); err != nil {
+						return err
+					}
+
+					return env.Printer.Message(nil, "context %s saved", args[0])
+				}
+			},
+		},
+		{
+			Name:    "config set-profile",
+			Args:    "NAME",
+			Summary: "Create or change a profile, the flags left out are kept",
+			Offline: true,
+			Flags: func(fs *flag.FlagSet) Run {
+				apiKey := fs.String("api-key", "", "API key sent as a bearer token")
+				username := fs.String("username", "", "project id of the Basic credentials")
+				password := fs.String("password", "", "project secret of the Basic credentials")
+				output := fs.String("default-output", "", "default output format: table, json or yaml")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "NAME"); err != nil {
+						return err
+					}
+
+					if *output != "" {
+						if err := validOutput(*output); err != nil {
+							return err
+						}
+					}
+
+					if env.Config.Profiles == nil {
+						env.Config.Profiles = make(map[string]Profile)
+					}
+
+					p := env.Config.Profiles[args[0]]
+					p.APIKey = firstOf(*apiKey, p.APIKey)
+					p.Username = firstOf(*username, p.Username)
+					p.Password = firstOf(*password, p.Password)
+					p.Output = firstOf(*output, p.Output)
+					env.Config.Profiles[args[0]] = p
+
+					if err := env.Config.Save(env.ConfigPath); err != nil {
+						return err
+					}
+
+					return env.Printer.Message(nil, "profile %s saved", args[0])
+				}
+			},
+		},
+		{
+			Name:    "config delete-context",
+			Args:    "NAME",
+			Summary: "Remove a context",
+			Offline: true,
+			Flags: func(fs *flag.FlagSet) Run {
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "NAME"); err != nil {
+						return err
+					}
+
+					if _, ok := env.Config.Contexts[args[0]]; !ok {
+						return fmt.Errorf("context %q not found", args[0])
+					}
+
+					delete(env.Config.Contexts, args[0])
+					if env.Config.CurrentContext == args[0] {
+						env.Config.CurrentContext = ""
+					}
+
+					if err := env.Config.Save(env.ConfigPath); err != nil {
+						return err
+					}
+
+					return env.Printer.Message(nil, "context %s deleted", args[0])
+				}
+			},
+		},
+	}
+}
+
+// redacted hides the secrets of the profiles
+func (c Config) redacted() Config {
+	profiles := make(map[string]Profile, len(c.Profiles))
+	for name, p := range c.Profiles {
+		if p.APIKey != "" {
+			p.APIKey = redacted
+		}
+		if p.Password != "" {
+			p.Password = redacted
+		}
+		profiles[name] = p
+	}
+
+	c.Profiles = profiles
+	return c
+}
+
+// configJSON is the config with the field names of the file
+type configJSON struct {
+	CurrentContext string                 `json:"current-context,omitempty"`
+	Contexts       map[string]contextJSON `json:"contexts,omitempty"`
+	Profiles       map[string]profileJSON `json:"profiles,omitempty"`
+}
+
+type contextJSON struct {
+	Backoffice string `json:"backoffice,omitempty"`
+	PubSub     string `json:"pubsub,omitempty"`
+	Task       string `json:"task,omitempty"`
+	Project    string `json:"project,omitempty"`
+	Profile    string `json:"profile,omitempty"`
+}
+
+type profileJSON struct {
+	APIKey   string `json:"api-key,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Output   string `json:"output,omitempty"`
+}
+
+// configDocument converts the config for the printer
+func configDocument(c Config) configJSON {
+	doc := configJSON{
+		CurrentContext: c.CurrentContext,
+		Contexts:       make(map[string]contextJSON, len(c.Contexts)),
+		Profiles:       make(map[string]profileJSON, len(c.Profiles)),
+	}
+
+	for name, ctx := range c.Contexts {
+		doc.Contexts[name] = contextJSON(ctx)
+	}
+
+	for name, p := range c.Profiles {
+		doc.Profiles[name] = profileJSON(p)
+	}
+
+	return doc
+}
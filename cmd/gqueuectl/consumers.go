@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+)
+
+func consumerCommands() []Command {
+	return []Command{
+		{
+			Name:    "consumers list",
+			Args:    "EVENT",
+			Summary: "List the consumers of an event",
+			Flags: func(fs *flag.FlagSet) Run {
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "EVENT"); err != nil {
+						return err
+					}
+
+					_, event, err := getEvent(ctx, env, args[0])
+					if err != nil {
+						return err
+					}
+
+					document, err := json.Marshal(event.Consumers)
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Print(document, func() [][]string { return consumerRows(event.Consumers...) })
+				}
+			},
+		},
+		{
+			Name:    "consumers get",
+			Args:    "EVENT CONSUMER",
+			Summary: "Show a consumer of an event",
+			Flags: func(fs *flag.FlagSet) Run {
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "EVENT", "CONSUMER"); err != nil {
+						return err
+					}
+
+					_, event, err := getEvent(ctx, env, args[0])
+					if err != nil {
+						return err
+					}
+
+					resp, consumer, err := getConsumer(ctx, env, event, args[1])
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Print(resp.Body, func() [][]string { return consumerRows(consumer) })
+				}
+			},
+		},
+		{
+			Name:    "consumers create",
+			Args:    "EVENT",
+			Summary: "Add a consumer to an event from a JSON or YAML file",
+			Flags: func(fs *flag.FlagSet) Run {
+				file := fs.String("f", "", "file of the consumer, - reads stdin")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "EVENT"); err != nil {
+						return err
+					}
+
+					document, err := readDocument(env, *file)
+					if err != nil {
+						return err
+					}
+
+					_, event, err := getEvent(ctx, env, args[0])
+					if err != nil {
+						return err
+					}
+
+					resp, err := env.Client.Do(ctx, Request{
+						API:    APIBackoffice,
+						Method: http.MethodPost,
+						Path:   consumersPath(event),
+						Body:   json.RawMessage(document),
+					})
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Message(resp.Body, "consumer added to event %s", event.Name)
+				}
+			},
+		},
+		{
+			Name:    "consumers edit",
+			Args:    "EVENT CONSUMER",
+			Summary: "Edit a consumer in $EDITOR, or replace it with a file",
+			Flags: func(fs *flag.FlagSet) Run {
+				file := fs.String("f", "", "file replacing the consumer instead of opening the editor, - reads stdin")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "EVENT", "CONSUMER"); err != nil {
+						return err
+					}
+
+					_, event, err := getEvent(ctx, env, args[0])
+					if err != nil {
+						return err
+					}
+
+					current, _, err := getConsumer(ctx, env, event, args[1])
+					if err != nil {
+						return err
+					}
+
+					document, err := editDocument(env, *file, current.Body)
+					if errors.Is(err, errNotChanged) {
+						return env.Printer.Message(nil, "consumer %s not changed", args[1])
+					}
+
+					if err != nil {
+						return err
+					}
+
+					// the change is refused when the event changed since the consumer was read
+					resp, err := env.Client.Do(ctx, Request{
+						API:     APIBackoffice,
+						Method:  http.MethodPut,
+						Path:    consumerPath(event, args[1]),
+						Body:    json.RawMessage(document),
+						Headers: ifMatch(current),
+					})
+					if isPreconditionFailed(err) {
+						return fmt.Errorf("event %s changed while editing, run the edit again", event.Name)
+					}
+
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Message(resp.Body, "consumer %s updated", args[1])
+				}
+			},
+		},
+		{
+			Name:    "consumers delete",
+			Args:    "EVENT CONSUMER",
+			Summary: "Remove a consumer from an event",
+			Flags: func(fs *flag.FlagSet) Run {
+				yes := fs.Bool("yes", false, "do not ask for confirmation")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "EVENT", "CONSUMER"); err != nil {
+						return err
+					}
+
+					resp, event, err := getEvent(ctx, env, args[0])
+					if err != nil {
+						return err
+					}
+
+					if _, ok := event.FindConsumer(args[1]); !ok {
+						return fmt.Errorf("consumer %s not found in event %s", args[1], event.Name)
+					}
+
+					if err := env.Confirm(*yes, fmt.Sprintf("Remove consumer %s from event %s?", args[1], event.Name)); err != nil {
+						return err
+					}
+
+					_, err = env.Client.Do(ctx, Request{
+						API:     APIBackoffice,
+						Method:  http.MethodDelete,
+						Path:    consumerPath(event, args[1]),
+						Headers: ifMatch(resp),
+					})
+					if isPreconditionFailed(err) {
+						return fmt.Errorf("event %s changed since it was read, run the delete again", event.Name)
+					}
+
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Message(nil, "consumer %s deleted", args[1])
+				}
+			},
+		},
+	}
+}
+
+// getConsumer reads the consumer with the ETag of the revision of its event
+func getConsumer(ctx context.Context, env *Env, event domain.Event, consumerName string) (Response, domain.Consumer, error) {
+	var consumer domain.Consumer
+
+	resp, err := env.Client.Do(ctx, Request{API: APIBackoffice, Method: http.MethodGet, Path: consumerPath(event, consumerName)})
+	if err != nil {
+		return Response{}, consumer, err
+	}
+
+	if err := resp.Decode(&consumer); err != nil {
+		return Response{}, consumer, err
+	}
+
+	return resp, consumer, nil
+}
+
+func consumersPath(event domain.Event) string {
+	return "/api/v1/events/" + event.ID.String() + "/consumers"
+}
+
+func consumerPath(event domain.Event, consumer string) string {
+	return consumersPath(event) + "/" + url.PathEscape(consumer)
+}
+
+func ifMatch(resp Response) map[string]string {
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return nil
+	}
+
+	return map[string]string{"If-Match": etag}
+}
+
+func isPreconditionFailed(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusPreconditionFailed
+}
+
+func consumerRows(consumers ...domain.Consumer) [][]string {
+	rows := [][]string{{"NAME", "HOST", "PATH", "TEAM", "PAUSED"}}
+	for _, consumer := range consumers {
+		rows = append(rows, []string{
+			consumer.ServiceName,
+			consumer.BaseUrl,
+			consumer.Path,
+			orNone(consumer.TeamOwner),
+			strconv.FormatBool(consumer.Paused),
+		})
+	}
+
+	return rows
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/google/uuid"
+)
+
+// deadLetterFilters are the flags selecting dead letters, shared by list and purge
+type deadLetterFilters struct {
+	event    string
+	consumer string
+	team     string
+	from     string
+	to       string
+}
+
+func (f *deadLetterFilters) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.event, "event", "", "event of the dead letters")
+	fs.StringVar(&f.consumer, "consumer", "", "consumer that failed")
+	fs.StringVar(&f.team, "team", "", "team owning the event or the consumer")
+	fs.StringVar(&f.from, "from", "", "dead-lettered after (RFC 3339)")
+	fs.StringVar(&f.to, "to", "", "dead-lettered before (RFC 3339)")
+}
+
+func (f *deadLetterFilters) query() url.Values {
+	query := url.Values{}
+	setQuery(query, "event_name", f.event)
+	setQuery(query, "consumer", f.consumer)
+	setQuery(query, "team_owner", f.team)
+	setQuery(query, "from", f.from)
+	setQuery(query, "to", f.to)
+	return query
+}
+
+func deadLetterCommands() []Command {
+	return []Command{
+		{
+			Name:    "dlq list",
+			Summary: "List the dead letters, the newest first",
+			Flags: func(fs *flag.FlagSet) Run {
+				var filters deadLetterFilters
+				filters.register(fs)
+				page := fs.Uint("page", 1, "page of the results")
+				limit := fs.Uint("limit", 100, "dead letters per page")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args); err != nil {
+						return err
+					}
+
+					query := filters.query()
+					query.Set("page", strconv.FormatUint(uint64(*page), 10))
+					query.Set("limit", strconv.FormatUint(uint64(*limit), 10))
+
+					resp, err := env.Client.Do(ctx, Request{API: APIBackoffice, Method: http.MethodGet, Path: "/api/v1/dlq", Query: query})
+					if err != nil {
+						return err
+					}
+
+					var deadLetters []domain.DeadLetter
+					if err := resp.Decode(&deadLetters); err != nil {
+						return err
+					}
+
+					return env.Printer.Print(resp.Body, func() [][]string { return deadLetterRows(deadLetters...) })
+				}
+			},
+		},
+		{
+			Name:    "dlq get",
+			Args:    "ID",
+			Summary: "Inspect a dead letter, -o yaml shows its payload",
+			Flags: func(fs *flag.FlagSet) Run {
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "ID"); err != nil {
+						return err
+					}
+
+					resp, err := env.Client.Do(ctx, Request{API: APIBackoffice, Method: http.MethodGet, Path: "/api/v1/dlq/" + url.PathEscape(args[0])})
+					if err != nil {
+						return err
+					}
+
+					var deadLetter domain.DeadLetter
+					if err := resp.Decode(&deadLetter); err != nil {
+						return err
+					}
+
+					return env.Printer.Print(resp.Body, func() [][]string { return deadLetterRows(deadLetter) })
+				}
+			},
+		},
+		{
+			Name:    "dlq replay",
+			Args:    "ID...",
+			Summary: "Publish dead letters again and remove them from the dead-letter store",
+			Flags: func(fs *flag.FlagSet) Run {
+				all := fs.Bool("all-consumers", false, "replay to every consumer of the event instead of the one that failed")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if len(args) == 0 {
+						return fmt.Errorf("expected at least one ID")
+					}
+
+					target := "original"
+					if *all {
+						target = "all"
+					}
+
+					var failed int
+					for _, id := range args {
+						_, err := env.Client.Do(ctx, Request{
+							API:    APIBackoffice,
+							Method: http.MethodPost,
+							Path:   "/api/v1/dlq/" + url.PathEscape(id) + "/replay",
+							Body:   map[string]string{"target": target},
+						})
+						if err != nil {
+							failed++
+							fmt.Fprintf(env.Out, "%s not replayed: %v\n", id, err)
+							continue
+						}
+
+						fmt.Fprintf(env.Out, "%s replayed\n", id)
+					}
+
+					if failed > 0 {
+						return fmt.Errorf("%d of %d dead letters not replayed", failed, len(args))
+					}
+
+					return nil
+				}
+			},
+		},
+		{
+			Name:    "dlq purge",
+			Args:    "[ID...]",
+			Summary: "Delete the dead letters with the IDs, or the ones matching the filters",
+			Flags: func(fs *flag.FlagSet) Run {
+				var filters deadLetterFilters
+				filters.register(fs)
+				all := fs.Bool("all", false, "purge every dead letter of the project when no ID or filter is given")
+				yes := fs.Bool("yes", false, "do not ask for confirmation")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					req := Request{API: APIBackoffice, Method: http.MethodDelete, Path: "/api/v1/dlq"}
+
+					var question string
+					if len(args) > 0 {
+						ids := make([]uuid.UUID, 0, len(args))
+						for _, arg := range args {
+							id, err := uuid.Parse(arg)
+							if err != nil {
+								return fmt.Errorf("invalid ID %q", arg)
+							}
+							ids = append(ids, id)
+						}
+
+						req.Body = map[string][]uuid.UUID{"ids": ids}
+						question = fmt.Sprintf("Purge %d dead letters?", len(ids))
+					} else {
+						req.Query = filters.query()
+						if len(req.Query) == 0 && !*all {
+							return fmt.Errorf("give IDs, filters or --all to purge every dead letter")
+						}
+
+						if *all {
+							req.Query.Set("all", "true")
+						}
+
+						question = "Purge the dead letters matching " + describeFilters(req.Query) + "?"
+					}
+
+					if err := env.Confirm(*yes, question); err != nil {
+						return err
+					}
+
+					resp, err := env.Client.Do(ctx, req)
+					if err != nil {
+						return err
+					}
+
+					var purged struct {
+						Purged int `json:"purged"`
+					}
+					if err := resp.Decode(&purged); err != nil {
+						return err
+					}
+
+					return env.Printer.Message(resp.Body, "%d dead letters purged", purged.Purged)
+				}
+			},
+		},
+	}
+}
+
+func describeFilters(query url.Values) string {
+	if query.Get("all") == "true" && len(query) == 1 {
+		return "everything"
+	}
+
+	var filters []string
+	for _, key := range []string{"event_name", "consumer", "team_owner", "from", "to"} {
+		if value := query.Get(key); value != "" {
+			filters = append(filters, key+"="+value)
+		}
+	}
+
+	return strings.Join(filters, " ")
+}
+
+func deadLetterRows(deadLetters ...domain.DeadLetter) [][]string {
+	rows := [][]string{{"ID", "EVENT", "CONSUMER", "RETRIES", "DEAD AT", "LAST ERROR"}}
+	for _, dl := range deadLetters {
+		rows = append(rows, []string{
+			dl.ID.String(),
+			dl.EventName,
+			dl.Consumer.ServiceName,
+			strconv.Itoa(dl.RetryCount),
+			dl.DeadAt.Local().Format(time.DateTime),
+			truncate(dl.LastError, 60),
+		})
+	}
+
+	return rows
+}
+
+func truncate(value string, length int) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	if len(value) <= length {
+		return value
+	}
+
+	return value[:length-3] + "..."
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var errNotChanged = errors.New("not changed")
+
+// editDocument returns the document replacing current: the file when one is given, otherwise
+// current edited as YAML in the editor. It fails with errNotChanged when the editor saves it as is.
+func editDocument(env *Env, file string, current []byte) ([]byte, error) {
+	if file != "" {
+		return readDocument(env, file)
+	}
+
+	content, err := toYAML(current)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "gqueuectl-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	// the editor can carry arguments, e.g. "code --wait"
+	editor := strings.Fields(firstOf(os.Getenv("GQUEUECTL_EDITOR"), os.Getenv("EDITOR"), "vi"))
+	cmd := exec.Command(editor[0], append(editor[1:], tmp.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read temp file: %w", err)
+	}
+
+	if bytes.Equal(bytes.TrimSpace(edited), bytes.TrimSpace(content)) {
+		return nil, errNotChanged
+	}
+
+	return fromYAML(edited)
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+)
+
+func eventCommands() []Command {
+	return []Command{
+		{
+			Name:    "events list",
+			Summary: "List the events of the project",
+			Flags: func(fs *flag.FlagSet) Run {
+				state := fs.String("state", "active", "state of the events: active, archived or all")
+				team := fs.String("team", "", "team owning the events")
+				service := fs.String("service", "", "service publishing the events")
+				limit := fs.Uint("limit", 500, "maximum number of events")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					query := url.Values{"limit": {strconv.FormatUint(uint64(*limit), 10)}}
+					if *state != "all" {
+						query.Set("state", *state)
+					} else {
+						query["state"] = []string{"active", "archived"}
+					}
+					setQuery(query, "team_owner", *team)
+					setQuery(query, "service_name", *service)
+
+					resp, err := env.Client.Do(ctx, Request{API: APIBackoffice, Method: http.MethodGet, Path: "/api/v1/events", Query: query})
+					if err != nil {
+						return err
+					}
+
+					var events []domain.Event
+					if err := resp.Decode(&events); err != nil {
+						return err
+					}
+
+					return env.Printer.Print(resp.Body, func() [][]string { return eventRows(events...) })
+				}
+			},
+		},
+		{
+			Name:    "events get",
+			Args:    "EVENT",
+			Summary: "Show an event and its consumers",
+			Flags: func(fs *flag.FlagSet) Run {
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "EVENT"); err != nil {
+						return err
+					}
+
+					resp, event, err := getEvent(ctx, env, args[0])
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Print(resp.Body, func() [][]string { return eventRows(event) })
+				}
+			},
+		},
+		{
+			Name:    "events create",
+			Summary: "Register an event from a JSON or YAML file",
+			Flags: func(fs *flag.FlagSet) Run {
+				file := fs.String("f", "", "file of the event, - reads stdin")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					document, err := readDocument(env, *file)
+					if err != nil {
+						return err
+					}
+
+					var event domain.Event
+					if err := json.Unmarshal(document, &event); err != nil {
+						return fmt.Errorf("invalid event: %w", err)
+					}
+
+					// registering is an upsert, creating an existing event would silently replace it
+					if _, _, err := getEvent(ctx, env, event.Name); err == nil {
+						return fmt.Errorf("event %q already exists, use events edit", event.Name)
+					} else if !isNotFound(err) {
+						return err
+					}
+
+					resp, err := saveEvent(ctx, env, document)
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Message(resp.Body, "event %s created", event.Name)
+				}
+			},
+		},
+		{
+			Name:    "events edit",
+			Args:    "EVENT",
+			Summary: "Edit an event in $EDITOR, or replace it with a file",
+			Flags: func(fs *flag.FlagSet) Run {
+				file := fs.String("f", "", "file replacing the event instead of opening the editor, - reads stdin")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "EVENT"); err != nil {
+						return err
+					}
+
+					current, _, err := getEvent(ctx, env, args[0])
+					if err != nil {
+						return err
+					}
+
+					document, err := editDocument(env, *file, current.Body)
+					if errors.Is(err, errNotChanged) {
+						return env.Printer.Message(nil, "event %s not changed", args[0])
+					}
+
+					if err != nil {
+						return err
+					}
+
+					var event domain.Event
+					if err := json.Unmarshal(document, &event); err != nil {
+						return fmt.Errorf("invalid event: %w", err)
+					}
+
+					// the name identifies the event, renaming would register another one
+					if event.Name != args[0] {
+						return fmt.Errorf("the name of an event can't be changed, create %q instead", event.Name)
+					}
+
+					resp, err := saveEvent(ctx, env, document)
+					if err != nil {
+						return err
+					}
+
+					return env.Printer.Message(resp.Body, "event %s updated", event.Name)
+				}
+			},
+		},
+		{
+			Name:    "events delete",
+			Args:    "EVENT",
+			Summary: "Remove an event, its consumers stop receiving it",
+			Flags: func(fs *flag.FlagSet) Run {
+				yes := fs.Bool("yes", false, "do not ask for confirmation")
+
+				return func(ctx context.Context, env *Env, args []string) error {
+					if err := expectArgs(args, "EVENT"); err != nil {
+						return err
+					}
+
+					_, event, err := getEvent(ctx, env, args[0])
+					if err != nil {
+						return err
+					}
+
+					if err := env.Confirm(*yes, fmt.Sprintf("Remove event %s and its %d consumers?", event.Name, len(event.Consumers))); err != nil {
+						return err
+					}
+
+					if _, err := env.Client.Do(ctx, Request{API: APIBackoffice, Method: http.MethodDelete, Path: "/api/v1/event/" + event.ID.String()}); err != nil {
+						return err
+					}
+
+					return env.Printer.Message(nil, "event %s deleted", event.Name)
+				}
+			},
+		},
+	}
+}
+
+func getEvent(ctx context.Context, env *Env, name string) (Response, domain.Event, error) {
+	var event domain.Event
+
+	resp, err := env.Client.Do(ctx, Request{API: APIBackoffice, Method: http.MethodGet, Path: "/api/v1/events/" + url.PathEscape(name)})
+	if err != nil {
+		return Response{}, event, err
+	}
+
+	if err := resp.Decode(&event); err != nil {
+		return Response{}, event, err
+	}
+
+	return resp, event, nil
+}
+
+// saveEvent registers the event, the document is sent as is so fields unknown to the CLI are kept
+func saveEvent(ctx context.Context, env *Env, document []byte) (Response, error) {
+	return env.Client.Do(ctx, Request{API: APIBackoffice, Method: http.MethodPut, Path: "/api/v1/event/consumer", Body: json.RawMessage(document)})
+}
+
+func eventRows(events ...domain.Event) [][]string {
+	rows := [][]string{{"NAME", "TYPE", "WQ TYPE", "CONSUMERS", "TEAM", "STATE", "PAUSED", "REVISION"}}
+	for _, event := range events {
+		rows = append(rows, []string{
+			event.Name,
+			string(event.Type),
+			string(event.Option.WqType),
+			strconv.Itoa(len(event.Consumers)),
+			orNone(event.TeamOwner),
+			event.State,
+			strconv.FormatBool(event.Paused),
+			strconv.Itoa(event.Revision),
+		})
+	}
+
+	return rows
+}
+
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+
+	return value
+}
+
+// readDocument reads the JSON or YAML file, or stdin for -, as JSON
+func readDocument(env *Env, file string) ([]byte, error) {
+	var (
+		content []byte
+		err     error
+	)
+
+	switch file {
+	case "":
+		return nil, errors.New("a file is required, use -f")
+	case "-":
+		content, err = io.ReadAll(env.In)
+	default:
+		content, err = os.ReadFile(file)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	return fromYAML(content)
+}
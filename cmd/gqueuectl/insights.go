@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+)
+
+func insightsCommand() Command {
+	return Command{
+		Name:    "insights",
+		Summary: "Show the publications and deliveries of the events, --watch tails them",
+		Flags: func(fs *flag.FlagSet) Run {
+			event := fs.String("event", "", "event to show")
+			consumer := fs.String("consumer", "", "consumer to show")
+			since := fs.Duration("since", time.Hour, "length of the window ending now")
+			from := fs.String("from", "", "start of the window (RFC 3339), instead of --since")
+			to := fs.String("to", "", "end of the window (RFC 3339), instead of now")
+			granularity := fs.String("granularity", "", "buckets of the series: minute, hour, day or whole minutes")
+			watch := fs.Bool("watch", false, "refresh the insights until interrupted")
+			fs.BoolVar(watch, "w", false, "shorthand for --watch")
+			interval := fs.Duration("interval", 10*time.Second, "refresh interval of --watch")
+
+			return func(ctx context.Context, env *Env, args []string) error {
+				if err := expectArgs(args); err != nil {
+					return err
+				}
+
+				if *watch && *to != "" {
+					return errors.New("--watch follows the current time, it can't be used with --to")
+				}
+
+				end := time.Time{}
+				if *to != "" {
+					parsed, err := time.Parse(time.RFC3339, *to)
+					if err != nil {
+						return fmt.Errorf("invalid --to: %w", err)
+					}
+					end = parsed
+				}
+
+				query := func() url.Values {
+					query := url.Values{}
+					setQuery(query, "event", *event)
+					setQuery(query, "consumer", *consumer)
+					setQuery(query, "granularity", *granularity)
+					setQuery(query, "to", *to)
+					setQuery(query, "from", *from)
+					if *from == "" {
+						// the window of --since ends at --to, or now
+						query.Set("from", firstTime(end, time.Now()).Add(-*since).UTC().Format(time.RFC3339))
+					}
+					return query
+				}
+
+				if !*watch {
+					return printInsights(ctx, env, query(), false)
+				}
+
+				trigger := time.NewTicker(*interval)
+				defer trigger.Stop()
+
+				for {
+					if err := printInsights(ctx, env, query(), true); err != nil {
+						return err
+					}
+
+					select {
+					case <-trigger.C:
+					case <-ctx.Done():
+						return nil
+					}
+				}
+			}
+		},
+	}
+}
+
+func printInsights(ctx context.Context, env *Env, query url.Values, watching bool) error {
+	resp, err := env.Client.Do(ctx, Request{API: APIBackoffice, Method: http.MethodGet, Path: "/api/v1/insights", Query: query})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var insights domain.Insights
+	if err := resp.Decode(&insights); err != nil {
+		return err
+	}
+
+	// every refresh is a document of its own
+	if watching {
+		switch env.Printer.format {
+		case OutputYAML:
+			fmt.Fprintln(env.Out, "---")
+		case OutputTable:
+			fmt.Fprintf(env.Out, "\n%s → %s\n", insights.From.Local().Format(time.DateTime), insights.To.Local().Format(time.DateTime))
+		}
+	}
+
+	return env.Printer.Print(resp.Body, func() [][]string { return insightRows(insights) })
+}
+
+func insightRows(insights domain.Insights) [][]string {
+	rows := [][]string{{"EVENT", "CONSUMER", "TOTAL", "SUCCESS", "FAILURE", "SUCCESS RATE", "P95 MS", "LAG P95 MS"}}
+	for _, event := range insights.Events {
+		rows = append(rows, counterRow(event.Event, "<published>", event.Published, event.PublishLatency.P95, "-"))
+		for _, consumer := range event.Consumers {
+			rows = append(rows, counterRow(event.Event, consumer.Consumer, consumer.Consumed, consumer.Latency.P95, formatFloat(consumer.Lag.P95)))
+		}
+	}
+
+	return rows
+}
+
+func counterRow(event, consumer string, counter domain.Counter, p95 float64, lag string) []string {
+	rate := "-"
+	if counter.SuccessRate != nil {
+		rate = strconv.FormatFloat(*counter.SuccessRate*100, 'f', 1, 64) + "%"
+	}
+
+	return []string{
+		event,
+		consumer,
+		strconv.FormatInt(counter.Total, 10),
+		strconv.FormatInt(counter.Success, 10),
+		strconv.FormatInt(counter.Failure, 10),
+		rate,
+		formatFloat(p95),
+		lag,
+	}
+}
+
+func firstTime(values ...time.Time) time.Time {
+	for _, value := range values {
+		if !value.IsZero() {
+			return value
+		}
+	}
+
+	return time.Time{}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 1, 64)
+}
@@ -0,0 +1,229 @@
+// gqueuectl manages the events, consumers and dead letters of gqueue through the backoffice and
+// publish APIs.
+//
+//	gqueuectl events list
+//	gqueuectl consumers edit payment.processed billing
+//	gqueuectl publish payment.processed --data '{"id": "1"}'
+//	gqueuectl insights --event payment.processed --watch
+//	gqueuectl dlq replay 6f1c2a9e-3b5d-4c7e-9a10-2b3c4d5e6f70
+//	gqueuectl config use-context staging
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+)
+
+// Run is the body of a command, args are its positional arguments
+type Run func(ctx context.Context, env *Env, args []string) error
+
+type Command struct {
+	Name    string
+	Args    string
+	Summary string
+	// Flags registers the flags of the command and returns the function running it
+	Flags func(fs *flag.FlagSet) Run
+	// Offline commands only touch the config file
+	Offline bool
+}
+
+// Env is what a command runs with: the resolved target, its client and the output
+type Env struct {
+	In         io.Reader
+	Out        io.Writer
+	ConfigPath string
+	Config     Config
+	Target     Target
+	Client     *Client
+	Printer    Printer
+}
+
+// Confirm asks before a destructive change, yes skips the question
+func (e *Env) Confirm(yes bool, question string) error {
+	if yes {
+		return nil
+	}
+
+	fmt.Fprintf(e.Out, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(e.In).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errors.New("aborted")
+	}
+}
+
+type globalFlags struct {
+	config  string
+	context string
+	profile string
+	output  string
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.config, "config", defaultConfigPath(), "config file")
+	fs.StringVar(&g.context, "context", "", "context to use instead of the current one")
+	fs.StringVar(&g.profile, "profile", "", "profile to use instead of the one of the context")
+	fs.StringVar(&g.output, "o", "", "output format: table, json or yaml")
+	fs.StringVar(&g.output, "output", "", "output format: table, json or yaml")
+}
+
+func commands() []Command {
+	var all []Command
+	all = append(all, eventCommands()...)
+	all = append(all, consumerCommands()...)
+	all = append(all, publishCommand(), insightsCommand())
+	all = append(all, deadLetterCommands()...)
+	all = append(all, configCommands()...)
+	return all
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, in io.Reader, out, errOut io.Writer) error {
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		usage(errOut)
+		if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			return flag.ErrHelp
+		}
+		return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+	}
+
+	fs := flag.NewFlagSet("gqueuectl "+cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.Usage = func() {
+		fmt.Fprintf(errOut, "Usage: gqueuectl %s %s\n\n%s\n\nFlags:\n", cmd.Name, cmd.Args, cmd.Summary)
+		fs.PrintDefaults()
+	}
+
+	var globals globalFlags
+	globals.register(fs)
+	runCmd := cmd.Flags(fs)
+
+	positional, err := parseFlags(fs, rest)
+	if err != nil {
+		return err
+	}
+
+	config, err := LoadConfig(globals.config)
+	if err != nil {
+		return err
+	}
+
+	env := &Env{In: in, Out: out, ConfigPath: globals.config, Config: config}
+
+	if !cmd.Offline {
+		env.Target, err = config.Resolve(globals.context, globals.profile)
+		if err != nil {
+			return err
+		}
+
+		env.Client = NewClient(env.Target)
+	}
+
+	format := firstOf(globals.output, env.Target.Profile.Output, OutputTable)
+	if err := validOutput(format); err != nil {
+		return err
+	}
+	env.Printer = Printer{w: out, format: format}
+
+	return runCmd(ctx, env, positional)
+}
+
+// findCommand matches the longest command name at the start of args
+func findCommand(args []string) (Command, []string, bool) {
+	for _, cmd := range commands() {
+		words := strings.Fields(cmd.Name)
+		if len(args) < len(words) {
+			continue
+		}
+
+		if strings.Join(args[:len(words)], " ") == cmd.Name {
+			return cmd, args[len(words):], true
+		}
+	}
+
+	return Command{}, nil, false
+}
+
+// parseFlags accepts the flags before and after the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// expectArgs checks the number of positional arguments of a command
+func expectArgs(args []string, names ...string) error {
+	if len(names) == 0 && len(args) > 0 {
+		return fmt.Errorf("unexpected arguments %s", strings.Join(args, " "))
+	}
+
+	if len(args) != len(names) {
+		return fmt.Errorf("expected %s, got %d arguments", strings.Join(names, " and "), len(args))
+	}
+
+	return nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "gqueuectl manages the events, consumers and dead letters of gqueue.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage: gqueuectl <command> [arguments] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	all := commands()
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range all {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.Name, cmd.Args, cmd.Summary)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Every command accepts --context, --profile, --config and -o table|json|yaml.")
+	fmt.Fprintln(w, "Run gqueuectl <command> -h for the flags of a command.")
+}
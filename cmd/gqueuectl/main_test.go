@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorded is a request received by the fake APIs
+type recorded struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   string
+}
+
+// fakeAPI answers the routes with the handlers and records every request
+func fakeAPI(t *testing.T, routes map[string]http.HandlerFunc) (*httptest.Server, *[]recorded) {
+	t.Helper()
+
+	var requests []recorded
+	mux := http.NewServeMux()
+	for pattern, handler := range routes {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, recorded{
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Query:  r.URL.RawQuery,
+				Header: r.Header.Clone(),
+				Body:   string(body),
+			})
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			handler(w, r)
+		})
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// writeConfig saves a config whose current context points every API at the server
+func writeConfig(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := Config{
+		CurrentContext: "test",
+		Contexts: map[string]Context{
+			"test": {Backoffice: server.URL, PubSub: server.URL, Task: server.URL, Project: "payments", Profile: "ops"},
+		},
+		Profiles: map[string]Profile{"ops": {APIKey: "secret-key"}},
+	}
+	require.NoError(t, config.Save(path))
+
+	return path
+}
+
+func runCtl(t *testing.T, configPath, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	args = append(args, "--config", configPath)
+	err := run(context.Background(), args, strings.NewReader(stdin), &out, &errOut)
+
+	return out.String(), err
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestConfigResolve(t *testing.T) {
+	config := Config{
+		CurrentContext: "staging",
+		Contexts: map[string]Context{
+			"staging":    {Backoffice: "https://backoffice.staging", Project: "payments", Profile: "ops"},
+			"production": {Backoffice: "https://backoffice.prod", PubSub: "https://pubsub.prod", Task: "https://task.prod"},
+		},
+		Profiles: map[string]Profile{
+			"ops":    {APIKey: "key"},
+			"reader": {Username: "payments", Password: "secret", Output: OutputJSON},
+		},
+	}
+
+	t.Run("current context with its profile", func(t *testing.T) {
+		target, err := config.Resolve("", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "staging", target.Name)
+		assert.Equal(t, "https://backoffice.staging", target.Context.Backoffice)
+		assert.Equal(t, localContext.PubSub, target.Context.PubSub)
+		assert.Equal(t, "key", target.Profile.APIKey)
+	})
+
+	t.Run("flags win over the current context", func(t *testing.T) {
+		target, err := config.Resolve("production", "reader")
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://task.prod", target.Context.Task)
+		assert.Equal(t, "payments", target.Profile.Username)
+	})
+
+	t.Run("environment wins over the current context", func(t *testing.T) {
+		t.Setenv(envContext, "production")
+
+		target, err := config.Resolve("", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "production", target.Name)
+		assert.Empty(t, target.Profile)
+	})
+
+	t.Run("unknown context", func(t *testing.T) {
+		_, err := config.Resolve("dev", "")
+		assert.ErrorContains(t, err, `context "dev" not found`)
+	})
+
+	t.Run("empty config uses the local APIs", func(t *testing.T) {
+		target, err := Config{}.Resolve("", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, localContext, target.Context)
+	})
+}
+
+func TestConfigCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gqueue", "config.yaml")
+
+	_, err := runCtl(t, path, "", "config", "set-context", "staging", "--backoffice", "https://backoffice.staging")
+	require.NoError(t, err)
+
+	_, err = runCtl(t, path, "", "config", "set-context", "staging", "--use-profile", "ops")
+	assert.ErrorContains(t, err, `profile "ops" not found`)
+
+	_, err = runCtl(t, path, "", "config", "set-profile", "ops", "--api-key", "secret-key")
+	require.NoError(t, err)
+
+	_, err = runCtl(t, path, "", "config", "set-context", "staging", "--use-profile", "ops", "--project", "payments")
+	require.NoError(t, err)
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "staging", config.CurrentContext)
+	assert.Equal(t, Context{Backoffice: "https://backoffice.staging", Project: "payments", Profile: "ops"}, config.Contexts["staging"])
+	assert.Equal(t, "secret-key", config.Profiles["ops"].APIKey)
+
+	out, err := runCtl(t, path, "", "config", "view")
+	require.NoError(t, err)
+	assert.Contains(t, out, "api-key: REDACTED")
+	assert.NotContains(t, out, "secret-key")
+
+	out, err = runCtl(t, path, "", "config", "get-contexts")
+	require.NoError(t, err)
+	assert.Regexp(t, `\*\s+staging\s+https://backoffice.staging\s+payments\s+ops`, out)
+
+	_, err = runCtl(t, path, "", "config", "use-context", "production")
+	assert.ErrorContains(t, err, `context "production" not found`)
+
+	_, err = runCtl(t, path, "", "config", "delete-context", "staging")
+	require.NoError(t, err)
+
+	config, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Empty(t, config.CurrentContext)
+	assert.Empty(t, config.Contexts)
+}
+
+func TestEventsList(t *testing.T) {
+	events := []domain.Event{
+		{ID: uuid.New(), Name: "payment.processed", ServiceName: "payments", Consumers: []domain.Consumer{{ServiceName: "billing"}}},
+	}
+
+	server, requests := fakeAPI(t, map[string]http.HandlerFunc{
+		"GET /api/v1/events": func(w http.ResponseWriter, r *http.Request) { writeJSON(w, events) },
+	})
+	path := writeConfig(t, server)
+
+	out, err := runCtl(t, path, "", "events", "list", "--team", "checkout")
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "Bearer secret-key", (*requests)[0].Header.Get("Authorization"))
+	assert.Equal(t, "payments", (*requests)[0].Header.Get(headerProjectID))
+	assert.Contains(t, (*requests)[0].Query, "team_owner=checkout")
+	assert.Contains(t, out, "payment.processed")
+
+	out, err = runCtl(t, path, "", "events", "list", "-o", "json")
+	require.NoError(t, err)
+
+	var decoded []domain.Event
+	require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+	assert.Equal(t, events[0].ID, decoded[0].ID)
+}
+
+func TestConsumersEdit(t *testing.T) {
+	event := domain.Event{ID: uuid.New(), Name: "payment.processed", Consumers: []domain.Consumer{{ServiceName: "billing", BaseUrl: "http://billing"}}}
+	consumerPath := "/api/v1/events/" + event.ID.String() + "/consumers/billing"
+
+	conflict := false
+	server, requests := fakeAPI(t, map[string]http.HandlerFunc{
+		"GET /api/v1/events/{name}": func(w http.ResponseWriter, r *http.Request) { writeJSON(w, event) },
+		"GET " + consumerPath: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"3"`)
+			writeJSON(w, event.Consumers[0])
+		},
+		"PUT " + consumerPath: func(w http.ResponseWriter, r *http.Request) {
+			if conflict {
+				http.Error(w, "event changed", http.StatusPreconditionFailed)
+				return
+			}
+			writeJSON(w, event.Consumers[0])
+		},
+	})
+	path := writeConfig(t, server)
+	document := "service_name: billing\nhost: http://billing.internal\n"
+
+	out, err := runCtl(t, path, document, "consumers", "edit", "payment.processed", "billing", "-f", "-")
+	require.NoError(t, err)
+	assert.Contains(t, out, "consumer billing updated")
+
+	put := (*requests)[len(*requests)-1]
+	assert.Equal(t, http.MethodPut, put.Method)
+	assert.Equal(t, `"3"`, put.Header.Get("If-Match"))
+	assert.JSONEq(t, `{"service_name":"billing","host":"http://billing.internal"}`, put.Body)
+
+	conflict = true
+	_, err = runCtl(t, path, document, "consumers", "edit", "payment.processed", "billing", "-f", "-")
+	assert.ErrorContains(t, err, "changed while editing")
+}
+
+func TestPublish(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]http.HandlerFunc{
+		"POST /api/v1/task": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]string{"message_id": "42"})
+		},
+	})
+	path := writeConfig(t, server)
+
+	out, err := runCtl(t, path, "", "publish", "payment.processed", "--via", "task", "--data", `{"id":"1"}`, "--header", "tenant=acme", "--count", "2")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, strings.Count(out, "payment.processed published: 42"))
+	require.Len(t, *requests, 2)
+	assert.JSONEq(t, `{"event_name":"payment.processed","data":{"id":"1"},"metadata":{"source":"gqueuectl","headers":{"tenant":"acme"}}}`, (*requests)[0].Body)
+
+	_, err = runCtl(t, path, "", "publish", "payment.processed", "--data", `["not","an","object"]`)
+	assert.ErrorContains(t, err, "must be a JSON object")
+}
+
+func TestDeadLetterPurge(t *testing.T) {
+	server, requests := fakeAPI(t, map[string]http.HandlerFunc{
+		"DELETE /api/v1/dlq": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]int{"purged": 7})
+		},
+	})
+	path := writeConfig(t, server)
+
+	t.Run("refuses without a selection", func(t *testing.T) {
+		_, err := runCtl(t, path, "", "dlq", "purge")
+		assert.ErrorContains(t, err, "give IDs, filters or --all")
+	})
+
+	t.Run("aborts when not confirmed", func(t *testing.T) {
+		_, err := runCtl(t, path, "n\n", "dlq", "purge", "--event", "payment.processed")
+		assert.ErrorContains(t, err, "aborted")
+		assert.Empty(t, *requests)
+	})
+
+	t.Run("purges the filtered dead letters when confirmed", func(t *testing.T) {
+		out, err := runCtl(t, path, "y\n", "dlq", "purge", "--event", "payment.processed")
+		require.NoError(t, err)
+
+		assert.Contains(t, out, "7 dead letters purged")
+		require.Len(t, *requests, 1)
+		assert.Equal(t, "event_name=payment.processed", (*requests)[0].Query)
+	})
+
+	t.Run("purges the IDs with --yes", func(t *testing.T) {
+		id := uuid.New()
+
+		_, err := runCtl(t, path, "", "dlq", "purge", id.String(), "--yes")
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"ids":["`+id.String()+`"]}`, (*requests)[len(*requests)-1].Body)
+	})
+}
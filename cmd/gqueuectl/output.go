@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+func validOutput(format string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output %q, expected table|json|yaml", format)
+	}
+}
+
+// Printer writes the responses in the output format. JSON and YAML keep the fields of the API,
+// tables show the columns an operator looks at.
+type Printer struct {
+	w      io.Writer
+	format string
+}
+
+// Table writes the rows of a response, the first one is the header
+type Table func() [][]string
+
+// Print writes the JSON document in the format, table renders the rows
+func (p Printer) Print(document []byte, table Table) error {
+	switch p.format {
+	case OutputJSON:
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, document, "", "  "); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		indented.WriteByte('\n')
+		_, err := p.w.Write(indented.Bytes())
+		return err
+	case OutputYAML:
+		content, err := toYAML(document)
+		if err != nil {
+			return err
+		}
+		_, err = p.w.Write(content)
+		return err
+	default:
+		return p.table(table())
+	}
+}
+
+func (p Printer) table(rows [][]string) error {
+	if len(rows) == 1 {
+		_, err := fmt.Fprintln(p.w, "No resources found.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// Message writes the outcome of a command, JSON and YAML print the document instead
+func (p Printer) Message(document []byte, format string, args ...any) error {
+	if p.format != OutputTable && len(document) > 0 {
+		return p.Print(document, nil)
+	}
+
+	_, err := fmt.Fprintf(p.w, format+"\n", args...)
+	return err
+}
+
+// toYAML converts a JSON document keeping its field names, the keys of the objects are sorted
+func toYAML(document []byte) ([]byte, error) {
+	var value any
+	if err := json.Unmarshal(document, &value); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	return content, nil
+}
+
+// fromYAML converts a YAML or JSON document to JSON, JSON being YAML too
+func fromYAML(content []byte) ([]byte, error) {
+	var value any
+	if err := yaml.Unmarshal(content, &value); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("empty document")
+	}
+
+	document, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	return document, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// headerFlags collects the repeated --header key=value flags
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	pairs := make([]string, 0, len(h))
+	for key, value := range h {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (h headerFlags) Set(pair string) error {
+	key, value, ok := strings.Cut(pair, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", pair)
+	}
+
+	h[key] = value
+	return nil
+}
+
+// publication is the body of the publish APIs
+type publication struct {
+	EventName string          `json:"event_name"`
+	Data      json.RawMessage `json:"data"`
+	Metadata  struct {
+		Source  string            `json:"source"`
+		Headers map[string]string `json:"headers,omitempty"`
+	} `json:"metadata"`
+}
+
+func publishCommand() Command {
+	return Command{
+		Name:    "publish",
+		Args:    "EVENT",
+		Summary: "Publish a test message to the consumers of an event",
+		Flags: func(fs *flag.FlagSet) Run {
+			data := fs.String("data", "", "JSON object of the message")
+			file := fs.String("f", "", "JSON or YAML file of the message data, - reads stdin")
+			via := fs.String("via", string(APIPubSub), "API publishing the message: pubsub or task")
+			count := fs.Int("count", 1, "number of messages to publish")
+			headers := headerFlags{}
+			fs.Var(headers, "header", "header sent to the consumers as key=value, can be repeated")
+
+			return func(ctx context.Context, env *Env, args []string) error {
+				if err := expectArgs(args, "EVENT"); err != nil {
+					return err
+				}
+
+				api := API(*via)
+				if api != APIPubSub && api != APITask {
+					return fmt.Errorf("invalid --via %q, expected pubsub or task", *via)
+				}
+
+				if *count < 1 {
+					return errors.New("--count must be at least 1")
+				}
+
+				body := publication{EventName: args[0]}
+				body.Metadata.Source = "gqueuectl"
+				body.Metadata.Headers = headers
+
+				switch {
+				case *data != "" && *file != "":
+					return errors.New("use either --data or -f")
+				case *file != "":
+					document, err := readDocument(env, *file)
+					if err != nil {
+						return err
+					}
+					body.Data = document
+				case *data != "":
+					body.Data = json.RawMessage(*data)
+				default:
+					return errors.New("the message data is required, use --data or -f")
+				}
+
+				if !json.Valid(body.Data) || !strings.HasPrefix(strings.TrimSpace(string(body.Data)), "{") {
+					return errors.New("the message data must be a JSON object")
+				}
+
+				path := "/api/v1/" + string(api)
+				for i := 0; i < *count; i++ {
+					resp, err := env.Client.Do(ctx, Request{API: api, Method: http.MethodPost, Path: path, Body: body})
+					if err != nil {
+						return err
+					}
+
+					var published struct {
+						MessageID string `json:"message_id"`
+					}
+					if err := resp.Decode(&published); err != nil {
+						return err
+					}
+
+					if err := env.Printer.Message(resp.Body, "%s published: %s", args[0], published.MessageID); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			}
+		},
+	}
+}
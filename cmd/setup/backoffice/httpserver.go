@@ -18,43 +18,96 @@ import (
 )
 
 type InsightsStore interface {
-	GetAll(ctx context.Context) (output domain.Metrics, err error)
+	GetRange(ctx context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error)
 }
 
 func Start(
 	rdsclient *redis.Client,
 	store interstore.Repository,
 	insightsStore InsightsStore,
+	registryStatus backofficeapp.RegistryStatusReader,
+	deadLetters backofficeapp.DeadLetterStore,
+	deadLetterReplayer backofficeapp.DeadLetterReplayer,
+	archivedTasks backofficeapp.ArchivedTaskManager,
+	replays backofficeapp.ReplayJobManager,
+	timelines backofficeapp.TimelineStore,
+	alertStates backofficeapp.AlertStateStore,
+	consumersHealth backofficeapp.ConsumerHealthMonitor,
+	resolver middleware.ProjectResolver,
+	authenticator middleware.APIKeyAuthenticator,
 ) *http.Server {
 	mux := http.NewServeMux()
 
 	// Rota de métricas para Prometheus.
 	mux.Handle("/metrics", telemetry.Handler())
 
+	// routes are limited to the scopes of the API key of the request, if any
 	routes := []httpadapter.HttpHandle{
 		health.GetHealthCheckHandler(),
-		backofficeapp.SaveConsumerHandle(store),
-		backofficeapp.GetEvent(store),
-		backofficeapp.GetEvents(store),
-		backofficeapp.GetRegisterTaskConsumerArchived(store),
-		backofficeapp.RemoveEvent(store),
-		backofficeapp.GetInsightsHandle(insightsStore),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.SaveConsumerHandle(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.GetEvent(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.GetEvents(store)),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.GetRegisterTaskConsumerArchived(store))),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.RemoveEvent(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.PauseEvent(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.ResumeEvent(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.PauseConsumer(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.ResumeConsumer(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.ReleaseConsumer(store, consumersHealth)),
+		backofficeapp.RequireScope(domain.ScopeInsightsRead, backofficeapp.GetConsumersHealth(consumersHealth)),
+		backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.GetEventConsumer(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.AddEventConsumer(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.UpdateEventConsumer(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.RemoveEventConsumer(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.GetEventRevisions(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.GetEventRevision(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.DiffEventRevisions(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsManage, backofficeapp.RollbackEventRevision(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.GetCatalog(store)),
+		backofficeapp.RequireScope(domain.ScopeEventsRead, backofficeapp.GetCatalogAsyncAPI(store)),
+		backofficeapp.RequireScope(domain.ScopeInsightsRead, backofficeapp.GetInsightsHandle(insightsStore)),
+		backofficeapp.RequireScope(domain.ScopeInsightsRead, backofficeapp.GetMessageTimeline(timelines)),
+		backofficeapp.RequireScope(domain.ScopeInsightsRead, backofficeapp.GetAlerts(store, alertStates)),
+		backofficeapp.RequireScope(domain.ScopeInsightsRead, backofficeapp.GetAlertRules(store)),
+		backofficeapp.RequireScope(domain.ScopeInsightsRead, backofficeapp.GetAlertRule(store)),
+		backofficeapp.RequireScope(domain.ScopeAlertsManage, backofficeapp.CreateAlertRule(store)),
+		backofficeapp.RequireScope(domain.ScopeAlertsManage, backofficeapp.UpdateAlertRule(store, alertStates)),
+		backofficeapp.RequireScope(domain.ScopeAlertsManage, backofficeapp.DeleteAlertRule(store, alertStates)),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeInsightsRead, backofficeapp.GetRegistryStatusHandle(registryStatus))),
+		backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.GetDeadLetters(deadLetters)),
+		backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.GetDeadLetter(deadLetters)),
+		backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.ReplayDeadLetter(deadLetters, deadLetterReplayer)),
+		backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.PurgeDeadLetters(deadLetters)),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.GetArchivedQueues(archivedTasks))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.GetArchivedTasks(archivedTasks))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.GetArchivedTask(archivedTasks))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.RequeueArchivedTask(archivedTasks))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.RequeueArchivedTasks(archivedTasks))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.DeleteArchivedTask(archivedTasks))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.DeleteArchivedTasks(archivedTasks))),
+		backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.CreateReplayJob(replays)),
+		backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.GetReplayJobs(replays)),
+		backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.GetReplayJob(replays)),
+		backofficeapp.RequireScope(domain.ScopeDLQManage, backofficeapp.CancelReplayJob(replays)),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeProjectsManage, backofficeapp.GetProjects(store))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeProjectsManage, backofficeapp.GetProject(store))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeProjectsManage, backofficeapp.CreateProject(store))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeProjectsManage, backofficeapp.UpdateProject(store))),
+		backofficeapp.OperatorOnly(backofficeapp.RequireScope(domain.ScopeProjectsManage, backofficeapp.DeleteProject(store))),
+		backofficeapp.RequireScope(domain.ScopeKeysManage, backofficeapp.GetAPIKeys(store)),
+		backofficeapp.RequireScope(domain.ScopeKeysManage, backofficeapp.CreateAPIKey(store)),
+		backofficeapp.RequireScope(domain.ScopeKeysManage, backofficeapp.RevokeAPIKey(store)),
 	}
 
 	for _, route := range routes {
 		mux.HandleFunc(route.Path, route.Handler)
 	}
 
-	// config := cfg.Get()
-
-	// authorization := auth.NewBasicAuth(map[string]string{
-	// 	config.ProjectID: config.SecretKey,
-	// })
-
+	// the events, insights, alerts, dead letters and replays are read and changed in the project of the request
+	scoped := middleware.APIKeyMiddleware(authenticator, middleware.ProjectMiddleware(resolver, mux))
 	handler := middleware.CORSMiddleware(
-		middleware.MetricsMiddleware(cfg.BACKOFFICE_APP_NAME, middleware.LoggerMiddleware(mux)),
+		middleware.MetricsMiddleware(cfg.BACKOFFICE_APP_NAME, middleware.LoggerMiddleware(scoped)),
 	)
-	// h := authorization.Middleware(handler.ServeHTTP)
 
 	env := cfg.Get()
 	port := env.BackofficeApiPort
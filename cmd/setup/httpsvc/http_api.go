@@ -9,11 +9,26 @@ import (
 	"github.com/IsaacDSC/gqueue/cmd/setup/middleware"
 	"github.com/IsaacDSC/gqueue/internal/app/health"
 	"github.com/IsaacDSC/gqueue/internal/cfg"
+	"github.com/IsaacDSC/gqueue/internal/projects"
+	"github.com/IsaacDSC/gqueue/pkg/auth"
 	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
 	"github.com/IsaacDSC/gqueue/pkg/telemetry"
 )
 
-func StartHttpServer(ctx context.Context, env cfg.Config, routes []httpadapter.HttpHandle, port string, serviceName string) *http.Server {
+// StartHttpServer serves the routes of a publishing API, every request runs in the project of
+// its API key or token, or the one found by the resolver, and publications are limited by the
+// quota of the project
+func StartHttpServer(
+	ctx context.Context,
+	env cfg.Config,
+	routes []httpadapter.HttpHandle,
+	port string,
+	serviceName string,
+	resolver middleware.ProjectResolver,
+	authenticator middleware.APIKeyAuthenticator,
+	verifier *auth.JWTVerifier,
+	limiter *projects.Limiter,
+) *http.Server {
 
 	mux := http.NewServeMux()
 
@@ -26,16 +41,11 @@ func StartHttpServer(ctx context.Context, env cfg.Config, routes []httpadapter.H
 		mux.HandleFunc(route.Path, route.Handler)
 	}
 
-	// config := cfg.Get()
-
-	// authorization := auth.NewBasicAuth(map[string]string{
-	// 	config.ProjectID: config.SecretKey,
-	// })
-
+	scoped := middleware.ProjectMiddleware(resolver, middleware.PublishRateLimitMiddleware(limiter, mux))
+	authenticated := middleware.JWTMiddleware(verifier, middleware.APIKeyMiddleware(authenticator, scoped))
 	handler := middleware.CORSMiddleware(
-		middleware.MetricsMiddleware(serviceName, middleware.LoggerMiddleware(mux)),
+		middleware.MetricsMiddleware(serviceName, middleware.TracingMiddleware(serviceName, middleware.LoggerMiddleware(authenticated))),
 	)
-	// h := authorization.Middleware(handler.ServeHTTP)
 
 	server := &http.Server{
 		Addr:         port,
@@ -0,0 +1,146 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/interstore"
+	"github.com/IsaacDSC/gqueue/internal/registry"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	resyncInterval   = time.Minute
+	resubscribeDelay = 5 * time.Second
+)
+
+// Syncer keeps the mem store in sync with the persistent store. Invalidations broadcast by the
+// backoffice are applied as soon as they arrive; a full resync every minute is the safety net
+// for messages lost while Redis was unreachable.
+type Syncer struct {
+	memStore   *interstore.MemStore
+	broker     *registry.Broker
+	instanceID string
+
+	// mu serializes resyncs and invalidations so the applied version matches the loaded events
+	mu sync.Mutex
+}
+
+func NewSyncer(memStore *interstore.MemStore, broker *registry.Broker) *Syncer {
+	host, _ := os.Hostname()
+	return &Syncer{
+		memStore:   memStore,
+		broker:     broker,
+		instanceID: fmt.Sprintf("%s-%s", host, uuid.NewString()[:8]),
+	}
+}
+
+// Run listens to invalidations and resyncs periodically until ctx is cancelled
+func (s *Syncer) Run(ctx context.Context) {
+	l := ctxlogger.GetLogger(ctx)
+
+	go s.listen(ctx)
+
+	trigger := time.NewTicker(resyncInterval)
+	defer trigger.Stop()
+
+	for {
+		select {
+		case <-trigger.C:
+			if err := s.Resync(ctx); err != nil {
+				l.Error("Error refreshing mem store with events from persistent store", "error", err)
+				continue
+			}
+
+			l.Debug("Executed periodic refresh of mem store with events from persistent store")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Resync reloads every event from the persistent store
+func (s *Syncer) Resync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+
+	// the version is read before loading: a change committed during the load is at most applied twice
+	version, err := s.broker.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.memStore.LoadInMemStore(ctx); err != nil {
+		return err
+	}
+
+	s.memStore.SetVersion(version)
+	telemetry.MemActivityDuration.Record(ctx, time.Since(start).Seconds())
+	s.report(ctx)
+
+	return nil
+}
+
+func (s *Syncer) listen(ctx context.Context) {
+	l := ctxlogger.GetLogger(ctx)
+	for {
+		if err := s.broker.Subscribe(ctx, s.apply); err != nil {
+			l.Error("Error subscribing to registry invalidations", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+func (s *Syncer) apply(ctx context.Context, inv registry.Invalidation) {
+	l := ctxlogger.GetLogger(ctx)
+
+	s.mu.Lock()
+	gap := inv.Version != s.memStore.Version()+1
+	if !gap {
+		project := domain.Project{ID: inv.ProjectID}
+		if project.ID == "" {
+			project = domain.DefaultProject()
+		}
+
+		if err := s.memStore.ReloadEvents(domain.WithProject(ctx, project), inv.EventNames...); err != nil {
+			s.mu.Unlock()
+			l.Error("Error applying registry invalidation", "version", inv.Version, "error", err)
+			return
+		}
+
+		s.memStore.SetVersion(inv.Version)
+		s.report(ctx)
+	}
+	s.mu.Unlock()
+
+	if gap {
+		// a message was missed (or the counter was reset), only a full load is safe
+		l.Warn("Registry version gap, running full resync", "applied_version", s.memStore.Version(), "received_version", inv.Version)
+		if err := s.Resync(ctx); err != nil {
+			l.Error("Error refreshing mem store with events from persistent store", "error", err)
+		}
+	}
+}
+
+func (s *Syncer) report(ctx context.Context) {
+	version := s.memStore.Version()
+	telemetry.MemStoreRegistryVersion.Set(ctx, version, attribute.String("instance_id", s.instanceID))
+
+	if err := s.broker.Report(ctx, s.instanceID, version); err != nil {
+		ctxlogger.GetLogger(ctx).Warn("Error reporting registry version", "error", err)
+	}
+}
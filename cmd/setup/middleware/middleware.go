@@ -2,17 +2,23 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"slices"
+	"strings"
 	"time"
 
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/projects"
+	"github.com/IsaacDSC/gqueue/pkg/auth"
 	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
 	"github.com/IsaacDSC/gqueue/pkg/logs"
 	"github.com/IsaacDSC/gqueue/pkg/telemetry"
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type CORSConfig struct {
@@ -28,7 +34,7 @@ func DefaultCORSConfig() CORSConfig {
 	return CORSConfig{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "PATCH", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With", projects.HeaderProjectID},
 		ExposedHeaders:   []string{},
 		AllowCredentials: false,
 		MaxAge:           86400, // 24 hours
@@ -119,6 +125,11 @@ func AsynqLogger(h asynq.Handler) asynq.Handler {
 		ctx = ctxlogger.WithLogger(ctx, logger)
 
 		err := h.ProcessTask(ctx, t)
+		if errors.Is(err, domain.DeliveryPaused) {
+			logger.Info("Delivery paused, task deferred", "reason", err)
+			return err
+		}
+
 		if err != nil {
 			logger.Error("Error processing task", "error", err)
 			return err
@@ -142,7 +153,12 @@ func AsynqMetrics(next asynq.Handler) asynq.Handler {
 		telemetry.TaskConsumerTotalProcessing.Increment(ctx, attrs...)
 		defer telemetry.TaskConsumerTotalProcessing.Decrement(ctx, attrs...)
 
-		if err := next.ProcessTask(ctx, t); err != nil {
+		err := next.ProcessTask(ctx, t)
+		if errors.Is(err, domain.DeliveryPaused) {
+			return err
+		}
+
+		if err != nil {
 			telemetry.TaskConsumerTotalFailure.Count(ctx, 1, attrs...)
 			return err
 		}
@@ -220,3 +236,158 @@ func MetricsMiddleware(serviceName string, next http.Handler) http.Handler {
 		telemetry.HTTPServerRequestDuration.Record(ctx, duration, attrs...)
 	})
 }
+
+// TracingMiddleware starts a server span for every request, in the trace of the traceparent header
+// of the request when it has one, so a publication can be followed up to the webhook deliveries.
+func TracingMiddleware(serviceName string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if path == "/metrics" || path == "/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := telemetry.ExtractHeaders(r.Context(), r.Header)
+		ctx, span := telemetry.StartSpan(ctx, r.Method+" "+path, trace.SpanKindServer,
+			attribute.String("http.method", r.Method),
+			attribute.String("http.route", path),
+			attribute.String("service.name", serviceName),
+		)
+
+		rec := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response_code", rec.statusCode))
+
+		var err error
+		if rec.statusCode >= http.StatusInternalServerError {
+			err = errors.New(http.StatusText(rec.statusCode))
+		}
+		telemetry.EndSpan(span, err)
+	})
+}
+
+// APIKeyAuthenticator checks the API key of a request
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (domain.APIKey, error)
+}
+
+// APIKeyMiddleware authenticates requests sent with an API key as bearer token, the key is set in
+// the context for ProjectMiddleware to find its project and for handlers to check its scopes.
+// Requests without a key, or already authenticated by JWTMiddleware, are left to ProjectMiddleware.
+func APIKeyMiddleware(authenticator APIKeyAuthenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if _, verified := auth.IdentityFromContext(r.Context()); !ok || verified || unscoped(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key, err := authenticator.Authenticate(r.Context(), token)
+		if errors.Is(err, domain.InvalidAPIKey) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gqueue"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if err != nil {
+			ctxlogger.GetLogger(r.Context()).Error("Error authenticating api key", "error", err)
+			http.Error(w, "failed to authenticate api key", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := domain.WithAPIKey(r.Context(), key)
+		// revisions record the key that changed the event
+		ctx = auth.WithUser(ctx, "api-key:"+key.Name)
+		ctx = ctxlogger.WithLogger(ctx, ctxlogger.GetLogger(ctx).With("api_key_id", key.ID.String()))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// JWTMiddleware authenticates publishers sent with a JWT as bearer token. The identity mapped
+// from its claims is set in the context next to the publisher as authenticated user. API keys are
+// left to APIKeyMiddleware and every bearer token is when verifier is nil.
+func JWTMiddleware(verifier *auth.JWTVerifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || verifier == nil || strings.HasPrefix(token, domain.APIKeyPrefix) || unscoped(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		identity, err := verifier.Verify(r.Context(), token)
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenExpired) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gqueue", error="invalid_token"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if err != nil {
+			ctxlogger.GetLogger(r.Context()).Error("Error verifying token", "error", err)
+			http.Error(w, "failed to verify token", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := auth.WithIdentity(r.Context(), identity)
+		ctx = auth.WithUser(ctx, identity.Publisher)
+		ctx = ctxlogger.WithLogger(ctx, ctxlogger.GetLogger(ctx).With("publisher", identity.Publisher))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ProjectResolver finds the project a request belongs to
+type ProjectResolver interface {
+	Resolve(r *http.Request) (domain.Project, error)
+}
+
+// ProjectMiddleware sets the project of the request in its context. Metrics and health checks
+// are not part of any project.
+func ProjectMiddleware(resolver ProjectResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unscoped(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		project, err := resolver.Resolve(r)
+		if errors.Is(err, domain.ProjectNotFound) || errors.Is(err, domain.InvalidProjectCredentials) {
+			// an unknown project is reported like wrong credentials so project ids cannot be probed
+			w.Header().Set("WWW-Authenticate", `Basic realm="gqueue"`)
+			http.Error(w, domain.InvalidProjectCredentials.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if err != nil {
+			ctxlogger.GetLogger(r.Context()).Error("Error resolving project", "error", err)
+			http.Error(w, "failed to resolve project", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := domain.WithProject(r.Context(), project)
+		ctx = ctxlogger.WithLogger(ctx, ctxlogger.GetLogger(ctx).With("project_id", project.ID))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// PublishRateLimitMiddleware rejects the publications of a project above its publish rate quota.
+// It must run after ProjectMiddleware.
+func PublishRateLimitMiddleware(limiter *projects.Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || unscoped(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !limiter.Allow(domain.ProjectFromContext(r.Context())) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "project publish rate exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func unscoped(r *http.Request) bool {
+	return r.URL.Path == "/metrics" || r.URL.Path == "/api/v1/ping"
+}
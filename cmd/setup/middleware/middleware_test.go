@@ -1,9 +1,24 @@
 package middleware
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/projects"
+	"github.com/IsaacDSC/gqueue/pkg/auth"
 )
 
 func TestCORSMiddleware_DefaultConfig(t *testing.T) {
@@ -35,8 +50,8 @@ func TestCORSMiddleware_DefaultConfig(t *testing.T) {
 		t.Errorf("Access-Control-Allow-Methods = %v, want %v", got, "GET, PATCH, POST, PUT, DELETE, OPTIONS")
 	}
 
-	if got := rr.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, Authorization, X-Requested-With" {
-		t.Errorf("Access-Control-Allow-Headers = %v, want %v", got, "Content-Type, Authorization, X-Requested-With")
+	if got := rr.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, Authorization, X-Requested-With, X-Project-ID" {
+		t.Errorf("Access-Control-Allow-Headers = %v, want %v", got, "Content-Type, Authorization, X-Requested-With, X-Project-ID")
 	}
 
 	if got := rr.Header().Get("Access-Control-Max-Age"); got != "86400" {
@@ -211,7 +226,7 @@ func TestDefaultCORSConfig(t *testing.T) {
 		t.Errorf("Default AllowedMethods length = %v, want %v", len(config.AllowedMethods), len(expectedMethods))
 	}
 
-	expectedHeaders := []string{"Content-Type", "Authorization", "X-Requested-With"}
+	expectedHeaders := []string{"Content-Type", "Authorization", "X-Requested-With", "X-Project-ID"}
 	if len(config.AllowedHeaders) != len(expectedHeaders) {
 		t.Errorf("Default AllowedHeaders length = %v, want %v", len(config.AllowedHeaders), len(expectedHeaders))
 	}
@@ -224,3 +239,219 @@ func TestDefaultCORSConfig(t *testing.T) {
 		t.Errorf("Default MaxAge = %v, want %v", config.MaxAge, 86400)
 	}
 }
+
+type fakeResolver struct {
+	project domain.Project
+	err     error
+}
+
+func (f fakeResolver) Resolve(r *http.Request) (domain.Project, error) {
+	return f.project, f.err
+}
+
+func TestProjectMiddleware(t *testing.T) {
+	var got domain.Project
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = domain.ProjectFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		resolver fakeResolver
+		wantCode int
+		wantID   string
+	}{
+		{name: "resolved project", path: "/api/v1/pubsub", resolver: fakeResolver{project: domain.Project{ID: "acme"}}, wantCode: http.StatusOK, wantID: "acme"},
+		{name: "invalid credentials", path: "/api/v1/pubsub", resolver: fakeResolver{err: domain.InvalidProjectCredentials}, wantCode: http.StatusUnauthorized},
+		{name: "unknown project", path: "/api/v1/pubsub", resolver: fakeResolver{err: domain.ProjectNotFound}, wantCode: http.StatusUnauthorized},
+		{name: "store error", path: "/api/v1/pubsub", resolver: fakeResolver{err: errors.New("connection refused")}, wantCode: http.StatusInternalServerError},
+		{name: "health check is not scoped", path: "/api/v1/ping", resolver: fakeResolver{err: domain.InvalidProjectCredentials}, wantCode: http.StatusOK, wantID: domain.DefaultProjectID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = domain.Project{}
+			rr := httptest.NewRecorder()
+			ProjectMiddleware(tt.resolver, next).ServeHTTP(rr, httptest.NewRequest("POST", tt.path, nil))
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("Status code = %v, want %v", rr.Code, tt.wantCode)
+			}
+
+			if got.ID != tt.wantID {
+				t.Errorf("project = %q, want %q", got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestPublishRateLimitMiddleware(t *testing.T) {
+	handler := PublishRateLimitMiddleware(projects.NewLimiter(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	project := domain.Project{ID: "acme", Quotas: domain.ProjectQuotas{PublishRate: 1}}
+	publish := func() int {
+		req := httptest.NewRequest("POST", "/api/v1/pubsub", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req.WithContext(domain.WithProject(req.Context(), project)))
+		return rr.Code
+	}
+
+	if code := publish(); code != http.StatusCreated {
+		t.Errorf("Status code = %v, want %v", code, http.StatusCreated)
+	}
+
+	if code := publish(); code != http.StatusTooManyRequests {
+		t.Errorf("Status code = %v, want %v", code, http.StatusTooManyRequests)
+	}
+}
+
+type fakeAuthenticator struct {
+	key domain.APIKey
+	err error
+}
+
+func (f fakeAuthenticator) Authenticate(ctx context.Context, token string) (domain.APIKey, error) {
+	return f.key, f.err
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	var got domain.APIKey
+	var authenticated bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, authenticated = domain.APIKeyFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		authenticator fakeAuthenticator
+		wantCode      int
+		wantKey       bool
+	}{
+		{name: "valid key", authorization: "Bearer gq_key", authenticator: fakeAuthenticator{key: domain.APIKey{Name: "billing"}}, wantCode: http.StatusOK, wantKey: true},
+		{name: "invalid key", authorization: "Bearer gq_key", authenticator: fakeAuthenticator{err: domain.InvalidAPIKey}, wantCode: http.StatusUnauthorized},
+		{name: "store error", authorization: "Bearer gq_key", authenticator: fakeAuthenticator{err: errors.New("connection refused")}, wantCode: http.StatusInternalServerError},
+		{name: "basic auth is left to the project", authorization: "Basic YWNtZTpzM2NyZXQ=", authenticator: fakeAuthenticator{err: domain.InvalidAPIKey}, wantCode: http.StatusOK},
+		{name: "no credentials", authenticator: fakeAuthenticator{err: domain.InvalidAPIKey}, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticated = false
+			req := httptest.NewRequest("POST", "/api/v1/pubsub", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			rr := httptest.NewRecorder()
+			APIKeyMiddleware(tt.authenticator, next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("Status code = %v, want %v", rr.Code, tt.wantCode)
+			}
+
+			if authenticated != tt.wantKey {
+				t.Errorf("authenticated = %v, want %v", authenticated, tt.wantKey)
+			}
+
+			if tt.wantKey && got.Name != tt.authenticator.key.Name {
+				t.Errorf("api key = %q, want %q", got.Name, tt.authenticator.key.Name)
+			}
+		})
+	}
+}
+
+// localJWKS writes the public key to a JWKS file and returns a function signing ES256 tokens with it
+func localJWKS(t *testing.T) (string, func(claims map[string]any) string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "EC", "kid": "mesh-1", "crv": "P-256", "x": b64(key.X.FillBytes(make([]byte, 32))), "y": b64(key.Y.FillBytes(make([]byte, 32)))},
+	}})
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(claims map[string]any) string {
+		header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": "mesh-1"})
+		payload, _ := json.Marshal(claims)
+		signed := b64(header) + "." + b64(payload)
+		digest := sha256.Sum256([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed + "." + b64(append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...))
+	}
+
+	return path, sign
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	path, sign := localJWKS(t)
+	verifier := auth.NewJWTVerifier(auth.NewJWKS(path, time.Minute), auth.JWTConfig{
+		Mapping: auth.ClaimsMapping{Events: "gqueue_events"},
+	})
+
+	var identity auth.Identity
+	var user string
+	var verified bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, verified = auth.IdentityFromContext(r.Context())
+		user, _ = auth.UserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	valid := sign(map[string]any{"sub": "billing", "exp": time.Now().Add(time.Hour).Unix(), "gqueue_events": []string{"payment.processed"}})
+	expired := sign(map[string]any{"sub": "billing", "exp": time.Now().Add(-time.Hour).Unix()})
+
+	tests := []struct {
+		name         string
+		verifier     *auth.JWTVerifier
+		token        string
+		wantCode     int
+		wantVerified bool
+	}{
+		{name: "valid token", verifier: verifier, token: valid, wantCode: http.StatusOK, wantVerified: true},
+		{name: "expired token", verifier: verifier, token: expired, wantCode: http.StatusUnauthorized},
+		{name: "forged token", verifier: verifier, token: valid[:len(valid)-4] + "AAAA", wantCode: http.StatusUnauthorized},
+		{name: "api keys are left to the api key middleware", verifier: verifier, token: domain.APIKeyPrefix + "key", wantCode: http.StatusOK},
+		{name: "without jwks tokens are left to the api key middleware", token: valid, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verified = false
+			req := httptest.NewRequest("POST", "/api/v1/pubsub", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rr := httptest.NewRecorder()
+			JWTMiddleware(tt.verifier, next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("Status code = %v, want %v", rr.Code, tt.wantCode)
+			}
+
+			if verified != tt.wantVerified {
+				t.Errorf("verified = %v, want %v", verified, tt.wantVerified)
+			}
+
+			if tt.wantVerified && (identity.Publisher != "billing" || user != "billing" || !identity.CanPublish("payment.processed")) {
+				t.Errorf("identity = %+v, user = %q", identity, user)
+			}
+		})
+	}
+}
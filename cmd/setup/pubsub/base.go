@@ -8,12 +8,15 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	vkit "cloud.google.com/go/pubsub/apiv1"
-	"github.com/IsaacDSC/gqueue/cmd/setup/memstore"
+	"github.com/IsaacDSC/gqueue/internal/apikeys"
+	"github.com/IsaacDSC/gqueue/internal/app/pubsubapp"
 	"github.com/IsaacDSC/gqueue/internal/cfg"
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/internal/fetcher"
 	"github.com/IsaacDSC/gqueue/internal/interstore"
+	"github.com/IsaacDSC/gqueue/internal/projects"
 	"github.com/IsaacDSC/gqueue/internal/storests"
+	"github.com/IsaacDSC/gqueue/pkg/auth"
 	"github.com/IsaacDSC/gqueue/pkg/pubadapter"
 	"github.com/googleapis/gax-go/v2"
 	"google.golang.org/grpc/codes"
@@ -22,6 +25,7 @@ import (
 type PersistentRepository interface {
 	GetAllEvents(ctx context.Context) ([]domain.Event, error)
 	GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error)
+	projects.Store
 }
 
 type Service struct {
@@ -33,6 +37,12 @@ type Service struct {
 	memStore        *interstore.MemStore
 	fetch           *fetcher.Notification
 	insightsStore   *storests.Store
+	resolver        *projects.Resolver
+	authenticator   *apikeys.Authenticator
+	verifier        *auth.JWTVerifier
+	deadLetters     pubsubapp.DeadLetterRecorder
+	timeline        pubsubapp.TimelineRecorder
+	quarantines     pubsubapp.QuarantineChecker
 }
 
 func New(
@@ -40,12 +50,24 @@ func New(
 	ms *interstore.MemStore,
 	fetch *fetcher.Notification,
 	insightsStore *storests.Store,
+	deadLetters pubsubapp.DeadLetterRecorder,
+	timeline pubsubapp.TimelineRecorder,
+	quarantines pubsubapp.QuarantineChecker,
+	resolver *projects.Resolver,
+	authenticator *apikeys.Authenticator,
+	verifier *auth.JWTVerifier,
 ) *Service {
 	return &Service{
 		persistentStore: ps,
 		memStore:        ms,
 		fetch:           fetch,
 		insightsStore:   insightsStore,
+		resolver:        resolver,
+		authenticator:   authenticator,
+		verifier:        verifier,
+		deadLetters:     deadLetters,
+		timeline:        timeline,
+		quarantines:     quarantines,
 	}
 }
 
@@ -72,7 +94,7 @@ func (s *Service) Start(ctx context.Context, env cfg.Config) {
 		},
 	}
 
-	clientPubsub, err := pubsub.NewClientWithConfig(ctx, domain.ProjectID, config)
+	clientPubsub, err := pubsub.NewClientWithConfig(ctx, env.GCPProjectID, config)
 	if err != nil {
 		log.Fatalf("Erro ao criar cliente: %v", err)
 	}
@@ -85,12 +107,6 @@ func (s *Service) Start(ctx context.Context, env cfg.Config) {
 	// setup consumer depends on publisher
 	go s.consumer(ctx, env)
 
-	// load mem store with events from persistent store
-	s.memStore.LoadInMemStore(ctx)
-
-	// task refresh mem store
-	go memstore.SyncMemStore(ctx, s.memStore)
-
 	s.server = s.startHttpServer(ctx, env)
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/IsaacDSC/gqueue/internal/app/pubsubapp"
 	"github.com/IsaacDSC/gqueue/internal/cfg"
 	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/projects"
 	"github.com/IsaacDSC/gqueue/pkg/gpubsub"
 	"github.com/IsaacDSC/gqueue/pkg/topicutils"
 )
@@ -19,84 +20,45 @@ func (s *Service) consumer(ctx context.Context, env cfg.Config) {
 
 	concurrency := env.AsynqConfig.Concurrency
 
+	// nacked messages, like the ones of a paused consumer, are redelivered with a backoff
+	retryPolicy := &pubsub.RetryPolicy{
+		MinimumBackoff: env.Pause.RedeliveryDelay,
+		MaximumBackoff: 10 * time.Minute,
+	}
+
 	handlers := []gpubsub.Handle{
-		pubsubapp.NewDeadLatterQueue(s.memStore, s.fetch).ToGPubSubHandler(s.gcppublisher),
-		pubsubapp.GetRequestHandle(s.fetch, s.insightsStore).ToGPubSubHandler(s.gcppublisher),
+		pubsubapp.NewDeadLatterQueue(s.memStore, s.fetch, s.deadLetters, s.timeline).ToGPubSubHandler(s.gcppublisher),
+		pubsubapp.GetRequestHandle(s.fetch, s.insightsStore, s.memStore, s.quarantines, s.timeline).ToGPubSubHandler(s.gcppublisher),
 	}
 
 	var wg sync.WaitGroup
 
-	for _, handler := range handlers {
-		wg.Add(1)
-		go func(handler gpubsub.Handle) {
-			defer wg.Done()
-
-			topicName := topicutils.BuildTopicName(domain.ProjectID, handler.TopicName)
-			log.Printf("[*] Starting subscriber for topic: %s", topicName)
-
-			// Register topic if not exists
-			topic := s.pubsubClient.Topic(topicName)
-			exists, err := topic.Exists(ctx)
-			if err != nil {
-				log.Printf("[!] Error checking if topic %s exists: %v", topicName, err)
-				return
-			}
-
-			if !exists {
-				log.Printf("[*] Creating topic: %s", topicName)
-				topic, err = s.pubsubClient.CreateTopic(ctx, topicName)
-				if err != nil {
-					log.Printf("[!] Error creating topic %s: %v", topicName, err)
-					return
-				}
-			}
-
-			subscriptionName := topicutils.BuildSubscriptionName(topicName)
-			subscription := s.pubsubClient.Subscription(subscriptionName)
-
-			subExists, err := subscription.Exists(ctx)
-			if err != nil {
-				log.Printf("[!] Error checking if subscription %s exists: %v", subscriptionName, err)
-				return
-			}
-
-			if !subExists {
-				log.Printf("[*] Creating subscription: %s", subscriptionName)
-
-				subscription, err = s.pubsubClient.CreateSubscription(ctx, subscriptionName, pubsub.SubscriptionConfig{
-					Topic:       topic,
-					AckDeadline: 20 * time.Second,
-					// DeadLetterPolicy: &pubsub.DeadLetterPolicy{
-					// 	DeadLetterTopic:     topicutils.BuildTopicName(domain.ProjectID, domain.EventQueueDeadLatter),
-					// 	MaxDeliveryAttempts: 10,
-					// },
-				})
-				if err != nil {
-					log.Printf("[!] Error creating subscription %s: %v", subscriptionName, err)
-					return
-				}
-			}
-
-			subscription.ReceiveSettings = pubsub.ReceiveSettings{
-				MaxExtension:           60 * time.Minute,
-				MaxOutstandingMessages: 1000,
-				MaxOutstandingBytes:    1e9,
-				NumGoroutines:          concurrency,
-			}
-
-			if err := subscription.Receive(ctx, handler.Handler); err != nil {
-				if ctx.Err() == context.Canceled {
-					log.Printf("[*] Subscriber for topic %s shutting down gracefully", topicName)
-				} else {
-					log.Printf("[!] Error in subscriber for topic %s: %v", topicName, err)
-				}
-			}
-
-		}(handler)
+	// every project has its own topics, the subscribers of the projects created later start
+	// when the watcher finds them. A deleted project may hand its prefix to a new one, so the
+	// subscribers of a prefix look up the project owning it.
+	var watcher *projects.Watcher
+	watcher = projects.NewWatcher(s.persistentStore, env.Projects.WatchInterval, func(ctx context.Context, project domain.Project) {
+		for _, handler := range handlers {
+			wg.Add(1)
+			go func(handler gpubsub.Handle) {
+				defer wg.Done()
+				s.subscribe(ctx, watcher, project, handler, retryPolicy, concurrency)
+			}(handler)
+		}
+	})
+
+	if err := watcher.Sync(ctx); err != nil {
+		log.Printf("[!] Error loading projects: %v", err)
 	}
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		watcher.Run(ctx)
+	}()
+
 	log.Println("[*] starting worker with configs")
-	log.Println("[*] wq.concurrency", (len(handlers))*concurrency)
+	log.Println("[*] wq.concurrency per project", (len(handlers))*concurrency)
 	log.Println("[*] Worker started. Waiting for shutdown signal from context...")
 
 	<-ctx.Done()
@@ -115,3 +77,102 @@ func (s *Service) consumer(ctx context.Context, env cfg.Config) {
 		log.Println("[!] Timeout waiting for subscribers to stop, forcing shutdown")
 	}
 }
+
+// subscribe consumes the topic of the handler in the project until ctx is cancelled,
+// creating the topic and its subscription when they do not exist
+func (s *Service) subscribe(ctx context.Context, watcher *projects.Watcher, project domain.Project, handler gpubsub.Handle, retryPolicy *pubsub.RetryPolicy, concurrency int) {
+	topicName := project.TopicName(handler.TopicName)
+	log.Printf("[*] Starting subscriber for topic: %s", topicName)
+
+	// Register topic if not exists
+	topic := s.pubsubClient.Topic(topicName)
+	exists, err := topic.Exists(ctx)
+	if err != nil {
+		log.Printf("[!] Error checking if topic %s exists: %v", topicName, err)
+		return
+	}
+
+	if !exists {
+		log.Printf("[*] Creating topic: %s", topicName)
+		topic, err = s.pubsubClient.CreateTopic(ctx, topicName)
+		if err != nil {
+			log.Printf("[!] Error creating topic %s: %v", topicName, err)
+			return
+		}
+	}
+
+	subscriptionName := topicutils.BuildSubscriptionName(topicName)
+	subscription := s.pubsubClient.Subscription(subscriptionName)
+
+	subExists, err := subscription.Exists(ctx)
+	if err != nil {
+		log.Printf("[!] Error checking if subscription %s exists: %v", subscriptionName, err)
+		return
+	}
+
+	if !subExists {
+		log.Printf("[*] Creating subscription: %s", subscriptionName)
+
+		subscription, err = s.pubsubClient.CreateSubscription(ctx, subscriptionName, pubsub.SubscriptionConfig{
+			Topic:       topic,
+			AckDeadline: 20 * time.Second,
+			RetryPolicy: retryPolicy,
+			// DeadLetterPolicy: &pubsub.DeadLetterPolicy{
+			// 	DeadLetterTopic:     project.TopicName(domain.EventQueueDeadLatter),
+			// 	MaxDeliveryAttempts: 10,
+			// },
+		})
+		if err != nil {
+			log.Printf("[!] Error creating subscription %s: %v", subscriptionName, err)
+			return
+		}
+	}
+
+	if subExists {
+		if err := ensureRetryPolicy(ctx, subscription, retryPolicy); err != nil {
+			log.Printf("[!] Error updating retry policy of subscription %s: %v", subscriptionName, err)
+		}
+	}
+
+	subscription.ReceiveSettings = pubsub.ReceiveSettings{
+		MaxExtension:           60 * time.Minute,
+		MaxOutstandingMessages: 1000,
+		MaxOutstandingBytes:    1e9,
+		NumGoroutines:          concurrency,
+	}
+
+	// the messages are handled in the project owning the prefix, the ones of a deleted project
+	// wait in the subscription
+	receive := func(ctx context.Context, msg *pubsub.Message) {
+		current, ok := watcher.Project(project.TopicPrefix)
+		if !ok {
+			msg.Nack()
+			return
+		}
+		handler.Handler(domain.WithProject(ctx, current), msg)
+	}
+
+	if err := subscription.Receive(ctx, receive); err != nil {
+		if ctx.Err() == context.Canceled {
+			log.Printf("[*] Subscriber for topic %s shutting down gracefully", topicName)
+		} else {
+			log.Printf("[!] Error in subscriber for topic %s: %v", topicName, err)
+		}
+	}
+}
+
+// ensureRetryPolicy sets the retry policy on subscriptions created before it existed,
+// without one a nacked message is redelivered right away
+func ensureRetryPolicy(ctx context.Context, subscription *pubsub.Subscription, policy *pubsub.RetryPolicy) error {
+	config, err := subscription.Config(ctx)
+	if err != nil {
+		return err
+	}
+
+	if config.RetryPolicy != nil {
+		return nil
+	}
+
+	_, err = subscription.Update(ctx, pubsub.SubscriptionConfigToUpdate{RetryPolicy: policy})
+	return err
+}
@@ -7,13 +7,14 @@ import (
 	"github.com/IsaacDSC/gqueue/cmd/setup/httpsvc"
 	"github.com/IsaacDSC/gqueue/internal/app/pubsubapp"
 	"github.com/IsaacDSC/gqueue/internal/cfg"
+	"github.com/IsaacDSC/gqueue/internal/projects"
 	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
 )
 
 func (s *Service) startHttpServer(ctx context.Context, env cfg.Config) *http.Server {
 	routes := []httpadapter.HttpHandle{
-		pubsubapp.PublisherEvent(s.memStore, s.gcppublisher, s.insightsStore),
+		pubsubapp.PublisherEvent(s.memStore, s.gcppublisher, s.insightsStore, s.timeline),
 	}
 
-	return httpsvc.StartHttpServer(ctx, env, routes, env.PubsubApiPort.String(), cfg.PUBSUB_APP_NAME)
+	return httpsvc.StartHttpServer(ctx, env, routes, env.PubsubApiPort.String(), cfg.PUBSUB_APP_NAME, s.resolver, s.authenticator, s.verifier, projects.NewLimiter())
 }
@@ -2,14 +2,22 @@ package task
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
-	"github.com/IsaacDSC/gqueue/cmd/setup/memstore"
+	"github.com/IsaacDSC/gqueue/internal/apikeys"
+	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
 	"github.com/IsaacDSC/gqueue/internal/cfg"
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/internal/fetcher"
 	"github.com/IsaacDSC/gqueue/internal/interstore"
+	"github.com/IsaacDSC/gqueue/internal/projects"
 	"github.com/IsaacDSC/gqueue/internal/storests"
+	"github.com/IsaacDSC/gqueue/pkg/auth"
+	"github.com/IsaacDSC/gqueue/pkg/pgqueue"
 	"github.com/IsaacDSC/gqueue/pkg/pubadapter"
 	"github.com/hibiken/asynq"
 )
@@ -17,18 +25,27 @@ import (
 type PersistentRepository interface {
 	GetAllEvents(ctx context.Context) ([]domain.Event, error)
 	GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error)
+	projects.Store
 }
 
 type Service struct {
 	asynqClient    *asynq.Client
 	asynqServer    *asynq.Server
 	asynqPublisher pubadapter.GenericPublisher
+	sqlDB          *sql.DB
+	sqlServer      *pgqueue.Server
+	publisher      pubadapter.GenericPublisher
 	server         *http.Server
 	// injectable dependencies
 	persistentStore PersistentRepository
 	memStore        *interstore.MemStore
 	fetch           *fetcher.Notification
 	insightsStore   *storests.Store
+	resolver        *projects.Resolver
+	authenticator   *apikeys.Authenticator
+	verifier        *auth.JWTVerifier
+	timeline        taskapp.TimelineRecorder
+	quarantines     taskapp.QuarantineChecker
 }
 
 func New(
@@ -36,12 +53,22 @@ func New(
 	ms *interstore.MemStore,
 	fetch *fetcher.Notification,
 	insightsStore *storests.Store,
+	timeline taskapp.TimelineRecorder,
+	quarantines taskapp.QuarantineChecker,
+	resolver *projects.Resolver,
+	authenticator *apikeys.Authenticator,
+	verifier *auth.JWTVerifier,
 ) *Service {
 	return &Service{
 		persistentStore: ps,
 		memStore:        ms,
 		fetch:           fetch,
 		insightsStore:   insightsStore,
+		resolver:        resolver,
+		authenticator:   authenticator,
+		verifier:        verifier,
+		timeline:        timeline,
+		quarantines:     quarantines,
 	}
 }
 
@@ -50,6 +77,13 @@ func (s *Service) Start(ctx context.Context, env cfg.Config) {
 
 	asynqCfg := asynq.Config{
 		Concurrency: env.AsynqConfig.Concurrency,
+		IsFailure:   isFailure,
+		RetryDelayFunc: func(n int, err error, task *asynq.Task) time.Duration {
+			if !isFailure(err) {
+				return env.Pause.RedeliveryDelay
+			}
+			return asynq.DefaultRetryDelayFunc(n, err, task)
+		},
 	}
 
 	s.asynqServer = asynq.NewServer(
@@ -61,15 +95,48 @@ func (s *Service) Start(ctx context.Context, env cfg.Config) {
 
 	s.asynqPublisher = pubadapter.NewPublisher(s.asynqClient)
 
-	// load mem store with events from persistent store
-	s.memStore.LoadInMemStore(ctx)
+	// the task API has no Pub/Sub client, so low_latency events keep being delivered through asynq
+	classification := pubadapter.ClassificationResult{
+		InternalPublisher: s.asynqPublisher,
+		ExternalPublisher: s.asynqPublisher,
+	}
+
+	if env.SQLQueue.Enabled {
+		db, err := sql.Open("postgres", env.ConfigDatabase.DbConn)
+		if err != nil {
+			log.Fatalf("Erro ao abrir conexão da fila SQL: %v", err)
+		}
+
+		s.sqlDB = db
+		s.sqlServer = pgqueue.NewServer(db, pgqueue.Config{
+			Concurrency:       env.AsynqConfig.Concurrency,
+			PollInterval:      env.SQLQueue.PollInterval,
+			VisibilityTimeout: env.SQLQueue.VisibilityTimeout,
+			IsFailure:         isFailure,
+			DeferDelay:        env.Pause.RedeliveryDelay,
+		})
+
+		go s.sqlConsumer(ctx, env)
+
+		classification.DurablePublisher = pubadapter.NewDurableSQLPublisher(pgqueue.NewClient(db))
+	}
 
-	// task refresh mem store
-	go memstore.SyncMemStore(ctx, s.memStore)
+	s.publisher = pubadapter.NewStrategy(&classification)
 
 	s.server = s.startHttpServer(ctx, env)
 }
 
-func (s *Service) Close() { _ = s.asynqClient.Close() }
+// isFailure tells the queues that a paused delivery is not a failure,
+// the task waits for the consumer to be resumed without consuming its retries
+func isFailure(err error) bool {
+	return !errors.Is(err, domain.DeliveryPaused)
+}
+
+func (s *Service) Close() {
+	_ = s.asynqClient.Close()
+	if s.sqlDB != nil {
+		_ = s.sqlDB.Close()
+	}
+}
 
 func (s *Service) Server() *http.Server { return s.server }
@@ -2,14 +2,16 @@ package task
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/IsaacDSC/gqueue/cmd/setup/middleware"
 	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
 	"github.com/IsaacDSC/gqueue/internal/cfg"
 	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/projects"
 	"github.com/IsaacDSC/gqueue/pkg/asynqsvc"
-	"github.com/IsaacDSC/gqueue/pkg/topicutils"
+	"github.com/IsaacDSC/gqueue/pkg/pgqueue"
 	"github.com/hibiken/asynq"
 )
 
@@ -20,14 +22,35 @@ func (s *Service) consumer(ctx context.Context, env cfg.Config, asynqCfg asynq.C
 	mux.Use(middleware.AsynqMetrics)
 
 	events := []asynqsvc.AsynqHandle{
-		taskapp.GetRequestHandle(s.fetch, s.insightsStore).ToAsynqHandler(),
+		taskapp.GetRequestHandle(s.fetch, s.insightsStore, s.memStore, s.quarantines, s.timeline).ToAsynqHandler(),
 	}
 
-	for _, event := range events {
-		topic := topicutils.BuildTopicName(domain.ProjectID, event.TopicName)
-		mux.HandleFunc(topic, event.Handler)
+	// every project has its own topics, the handlers run with the project in their context. A
+	// pattern can only be registered once, so the handlers of a prefix look up the project owning
+	// it: a deleted project may hand its prefix to a new one.
+	var watcher *projects.Watcher
+	watcher = projects.NewWatcher(s.persistentStore, env.Projects.WatchInterval, func(ctx context.Context, project domain.Project) {
+		prefix := project.TopicPrefix
+		for _, event := range events {
+			topic := project.TopicName(event.TopicName)
+			handler := event.Handler
+			mux.HandleFunc(topic, func(ctx context.Context, t *asynq.Task) error {
+				current, ok := watcher.Project(prefix)
+				if !ok {
+					return fmt.Errorf("topic %s: %w", topic, domain.ProjectNotFound)
+				}
+				return handler(domain.WithProject(ctx, current), t)
+			})
+			log.Printf("[*] Consuming topic %s of project %s", topic, project.ID)
+		}
+	})
+
+	if err := watcher.Sync(ctx); err != nil {
+		log.Printf("[!] Error loading projects: %v", err)
 	}
 
+	go watcher.Run(ctx)
+
 	log.Println("[*] starting worker with configs")
 	log.Println("[*] wq.concurrency", asynqCfg.Concurrency)
 	log.Println("[*] Asynq Worker started. Press Ctrl+C to gracefully shutdown...")
@@ -45,3 +68,43 @@ func (s *Service) consumer(ctx context.Context, env cfg.Config, asynqCfg asynq.C
 	log.Println("[*] Asynq server stopped gracefully")
 
 }
+
+func (s *Service) sqlConsumer(ctx context.Context, env cfg.Config) {
+	events := []pgqueue.Handle{
+		taskapp.GetRequestHandle(s.fetch, s.insightsStore, s.memStore, s.quarantines, s.timeline).ToPgQueueHandler(),
+	}
+
+	var watcher *projects.Watcher
+	watcher = projects.NewWatcher(s.persistentStore, env.Projects.WatchInterval, func(ctx context.Context, project domain.Project) {
+		prefix := project.TopicPrefix
+		for _, event := range events {
+			topic := project.TopicName(event.TopicName)
+			handler := event.Handler
+			s.sqlServer.Handle(pgqueue.Handle{
+				TopicName: topic,
+				Handler: func(ctx context.Context, job pgqueue.Job) error {
+					current, ok := watcher.Project(prefix)
+					if !ok {
+						return fmt.Errorf("topic %s: %w", topic, domain.ProjectNotFound)
+					}
+					return handler(domain.WithProject(ctx, current), job)
+				},
+			})
+		}
+	})
+
+	if err := watcher.Sync(ctx); err != nil {
+		log.Printf("[!] Error loading projects: %v", err)
+	}
+
+	go watcher.Run(ctx)
+
+	log.Println("[*] SQL queue worker started")
+
+	if err := s.sqlServer.Run(ctx); err != nil {
+		log.Printf("[!] SQL queue worker error: %v", err)
+		return
+	}
+
+	log.Println("[*] SQL queue worker stopped gracefully")
+}
@@ -7,13 +7,14 @@ import (
 	"github.com/IsaacDSC/gqueue/cmd/setup/httpsvc"
 	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
 	"github.com/IsaacDSC/gqueue/internal/cfg"
+	"github.com/IsaacDSC/gqueue/internal/projects"
 	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
 )
 
 func (s *Service) startHttpServer(ctx context.Context, env cfg.Config) *http.Server {
 	routes := []httpadapter.HttpHandle{
-		taskapp.PublisherEvent(s.memStore, s.asynqPublisher, s.insightsStore),
+		taskapp.PublisherEvent(s.memStore, s.publisher, s.insightsStore, s.timeline),
 	}
 
-	return httpsvc.StartHttpServer(ctx, env, routes, env.TaskApiPort.String(), cfg.TASK_APP_NAME)
+	return httpsvc.StartHttpServer(ctx, env, routes, env.TaskApiPort.String(), cfg.TASK_APP_NAME, s.resolver, s.authenticator, s.verifier, projects.NewLimiter())
 }
@@ -0,0 +1,276 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+)
+
+// lagQuantile is the percentile of the lag compared to the threshold of a consumer lag rule
+const lagQuantile = 0.95
+
+type RuleStore interface {
+	ListProjects(ctx context.Context) ([]domain.Project, error)
+	ListAlertRules(ctx context.Context) ([]domain.AlertRule, error)
+	GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error)
+}
+
+type InsightsStore interface {
+	GetRange(ctx context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error)
+}
+
+type DeadLetterStore interface {
+	List(ctx context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error)
+}
+
+type Notifier interface {
+	NotifyScheduler(ctx context.Context, url string, data any, headers map[string]string) error
+}
+
+type Config struct {
+	Interval time.Duration
+	// WebhookURL receives the firing and resolved notifications, none are sent when it is empty
+	WebhookURL string
+}
+
+// Evaluator measures the enabled rules of every project over their window at every interval,
+// stores their state and notifies the webhook when a rule starts or stops firing
+type Evaluator struct {
+	rules       RuleStore
+	insights    InsightsStore
+	deadLetters DeadLetterStore
+	states      *StateStore
+	notifier    Notifier
+	cfg         Config
+}
+
+func NewEvaluator(rules RuleStore, insights InsightsStore, deadLetters DeadLetterStore, states *StateStore, notifier Notifier, cfg Config) *Evaluator {
+	return &Evaluator{
+		rules:       rules,
+		insights:    insights,
+		deadLetters: deadLetters,
+		states:      states,
+		notifier:    notifier,
+		cfg:         cfg,
+	}
+}
+
+// Run evaluates the rules at every interval until ctx is cancelled. Every backoffice instance
+// runs it, the lock lets a single one evaluate an interval.
+func (e *Evaluator) Run(ctx context.Context) {
+	l := ctxlogger.GetLogger(ctx)
+
+	trigger := time.NewTicker(e.cfg.Interval)
+	defer trigger.Stop()
+
+	for {
+		select {
+		case <-trigger.C:
+			locked, err := e.states.TryLock(ctx, e.cfg.Interval/2)
+			if err != nil {
+				l.Error("Error locking the alert evaluation", "error", err)
+				continue
+			}
+
+			if !locked {
+				continue
+			}
+
+			if err := e.Evaluate(ctx, time.Now()); err != nil {
+				l.Error("Error evaluating alert rules", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Evaluate measures every enabled rule over the window ended at now. A rule failing to evaluate
+// keeps its previous state and does not stop the others.
+func (e *Evaluator) Evaluate(ctx context.Context, now time.Time) error {
+	l := ctxlogger.GetLogger(ctx)
+
+	projects, err := e.rules.ListProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	for _, project := range projects {
+		projectCtx := domain.WithProject(ctx, project)
+		if err := e.evaluateProject(projectCtx, now); err != nil {
+			l.Error("Error evaluating the alert rules of a project", "project", project.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (e *Evaluator) evaluateProject(ctx context.Context, now time.Time) error {
+	l := ctxlogger.GetLogger(ctx)
+
+	rules, err := e.rules.ListAlertRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	states, err := e.states.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+
+		value, err := e.measure(ctx, rule, now)
+		if err != nil {
+			l.Error("Error measuring alert rule", "rule", rule.ID, "error", err)
+			continue
+		}
+
+		state := nextState(rule, states[rule.ID], value, now)
+		if state.NeedsNotification() && e.cfg.WebhookURL != "" {
+			if err := e.notify(ctx, rule, state); err != nil {
+				l.Error("Error notifying alert", "rule", rule.ID, "status", state.Status, "error", err)
+			} else {
+				state.Notified = state.Status
+			}
+		}
+
+		if err := e.states.Save(ctx, state); err != nil {
+			l.Error("Error saving alert state", "rule", rule.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// nextState is the state after measuring the value, Since is kept while the status is unchanged
+func nextState(rule domain.AlertRule, previous domain.AlertState, value float64, now time.Time) domain.AlertState {
+	status := domain.AlertOK
+	if rule.Firing(value) {
+		status = domain.AlertFiring
+	}
+
+	state := domain.AlertState{
+		RuleID:      rule.ID,
+		Status:      status,
+		Value:       value,
+		Since:       previous.Since,
+		EvaluatedAt: now,
+		Notified:    previous.Notified,
+	}
+
+	if previous.Status != status {
+		state.Since = now
+	}
+
+	return state
+}
+
+// measure returns the value of the rule over its window ended at now
+func (e *Evaluator) measure(ctx context.Context, rule domain.AlertRule, now time.Time) (float64, error) {
+	window := domain.InsightsWindow{
+		From:        now.UTC().Add(-time.Duration(rule.Window)),
+		To:          now.UTC(),
+		Event:       rule.Event,
+		Consumer:    rule.Consumer,
+		Granularity: time.Minute,
+	}
+
+	if rule.Kind == domain.AlertDLQGrowth {
+		filters := domain.FilterDeadLetters{
+			From: window.From.Format(time.RFC3339),
+			To:   window.To.Format(time.RFC3339),
+		}
+		if rule.Event != "" {
+			filters.EventName = []string{rule.Event}
+		}
+		if rule.Consumer != "" {
+			filters.Consumer = []string{rule.Consumer}
+		}
+
+		deadLetters, err := e.deadLetters.List(ctx, filters)
+		if err != nil {
+			return 0, err
+		}
+
+		return float64(len(deadLetters)), nil
+	}
+
+	buckets, err := e.insights.GetRange(ctx, window)
+	if err != nil {
+		return 0, err
+	}
+
+	var published, consumed domain.SeriesStats
+	for _, bucket := range buckets {
+		for series, stats := range bucket.Series {
+			if !window.Match(series) {
+				continue
+			}
+
+			if series.Consumer == "" {
+				published.Success += stats.Success
+				published.Failure += stats.Failure
+				continue
+			}
+
+			consumed.Success += stats.Success
+			consumed.Failure += stats.Failure
+			consumed.Lag.Merge(stats.Lag)
+		}
+	}
+
+	switch rule.Kind {
+	case domain.AlertFailureRate:
+		total := consumed.Success + consumed.Failure
+		if total == 0 || total < rule.MinSamples {
+			return 0, nil
+		}
+		return float64(consumed.Failure) / float64(total), nil
+	case domain.AlertConsumerLag:
+		return consumed.Lag.Quantile(lagQuantile), nil
+	case domain.AlertNoPublishes:
+		return float64(published.Success + published.Failure), nil
+	default:
+		return 0, fmt.Errorf("unknown alert kind %q", rule.Kind)
+	}
+}
+
+func (e *Evaluator) notify(ctx context.Context, rule domain.AlertRule, state domain.AlertState) error {
+	notification := domain.AlertNotification{
+		Status:      domain.AlertNotificationResolved,
+		ProjectID:   domain.ProjectFromContext(ctx).ID,
+		Rule:        rule,
+		Value:       state.Value,
+		Since:       state.Since,
+		EvaluatedAt: state.EvaluatedAt,
+	}
+
+	if state.Status == domain.AlertFiring {
+		notification.Status = domain.AlertNotificationFiring
+	}
+
+	if rule.Event != "" {
+		// the owners only help whoever receives the notification, it is sent without them
+		if event, err := e.rules.GetInternalEvent(ctx, rule.Event); err == nil {
+			notification.EventOwner = &event.Owner
+			for _, consumer := range event.Consumers {
+				if consumer.ServiceName == rule.Consumer {
+					notification.ConsumerOwner = &consumer.Owner
+				}
+			}
+		}
+	}
+
+	return e.notifier.NotifyScheduler(ctx, e.cfg.WebhookURL, notification, nil)
+}
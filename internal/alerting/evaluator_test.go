@@ -0,0 +1,199 @@
+package alerting_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/alerting"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockalerting"
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const webhookURL = "http://alerts.internal/webhook"
+
+type evaluatorMocks struct {
+	rules       *mockalerting.MockRuleStore
+	insights    *mockalerting.MockInsightsStore
+	deadLetters *mockalerting.MockDeadLetterStore
+	notifier    *mockalerting.MockNotifier
+}
+
+func newTestEvaluator(t *testing.T) (*alerting.Evaluator, *alerting.StateStore, evaluatorMocks) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	ctrl := gomock.NewController(t)
+	mocks := evaluatorMocks{
+		rules:       mockalerting.NewMockRuleStore(ctrl),
+		insights:    mockalerting.NewMockInsightsStore(ctrl),
+		deadLetters: mockalerting.NewMockDeadLetterStore(ctrl),
+		notifier:    mockalerting.NewMockNotifier(ctrl),
+	}
+
+	states := alerting.NewStateStore(client)
+	evaluator := alerting.NewEvaluator(mocks.rules, mocks.insights, mocks.deadLetters, states, mocks.notifier, alerting.Config{
+		Interval:   time.Minute,
+		WebhookURL: webhookURL,
+	})
+
+	return evaluator, states, mocks
+}
+
+func deliveries(success, failure int64) domain.MetricsBuckets {
+	return domain.MetricsBuckets{{
+		Start: time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC),
+		Series: map[domain.Series]domain.SeriesStats{
+			{Event: "payment.created"}:                      {Success: success + failure},
+			{Event: "payment.created", Consumer: "billing"}: {Success: success, Failure: failure},
+			{Event: "user.created", Consumer: "billing"}:    {Failure: 100},
+		},
+	}}
+}
+
+func TestEvaluator_FailureRate(t *testing.T) {
+	ctx := context.Background()
+	evaluator, states, mocks := newTestEvaluator(t)
+
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme"}
+	acmeCtx := domain.WithProject(ctx, acme)
+	rule := domain.AlertRule{
+		ID:         uuid.New(),
+		ProjectID:  acme.ID,
+		Name:       "payments failing",
+		Kind:       domain.AlertFailureRate,
+		Event:      "payment.created",
+		Threshold:  0.1,
+		Window:     intertime.Duration(5 * time.Minute),
+		MinSamples: 10,
+		Enabled:    true,
+	}
+	disabled := domain.AlertRule{ID: uuid.New(), Kind: domain.AlertNoPublishes, Event: "payment.created"}
+	event := domain.Event{
+		Name:  "payment.created",
+		Owner: domain.Owner{TeamOwner: "payments"},
+	}
+
+	mocks.rules.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{acme}, nil).AnyTimes()
+	mocks.rules.EXPECT().ListAlertRules(gomock.Any()).Return([]domain.AlertRule{rule, disabled}, nil).AnyTimes()
+	mocks.rules.EXPECT().GetInternalEvent(gomock.Any(), "payment.created").Return(event, nil).AnyTimes()
+
+	now := time.Date(2025, 10, 10, 14, 5, 0, 0, time.UTC)
+
+	// 3 failures out of 10 deliveries of the event, the other events are not counted
+	mocks.insights.EXPECT().GetRange(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error) {
+			assert.Equal(t, now.Add(-5*time.Minute), window.From)
+			assert.Equal(t, "payment.created", window.Event)
+			return deliveries(7, 3), nil
+		})
+	mocks.notifier.EXPECT().NotifyScheduler(gomock.Any(), webhookURL, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, data any, _ map[string]string) error {
+			notification := data.(domain.AlertNotification)
+			assert.Equal(t, domain.AlertNotificationFiring, notification.Status)
+			assert.Equal(t, "acme", notification.ProjectID)
+			assert.InDelta(t, 0.3, notification.Value, 0.0001)
+			require.NotNil(t, notification.EventOwner)
+			assert.Equal(t, "payments", notification.EventOwner.TeamOwner)
+			return nil
+		})
+
+	require.NoError(t, evaluator.Evaluate(ctx, now))
+
+	evaluated, err := states.List(acmeCtx)
+	require.NoError(t, err)
+	require.Len(t, evaluated, 1, "disabled rules are not evaluated")
+	assert.Equal(t, domain.AlertFiring, evaluated[rule.ID].Status)
+	assert.Equal(t, domain.AlertFiring, evaluated[rule.ID].Notified)
+	assert.Equal(t, now, evaluated[rule.ID].Since)
+
+	// still firing: notified once
+	mocks.insights.EXPECT().GetRange(gomock.Any(), gomock.Any()).Return(deliveries(5, 5), nil)
+	require.NoError(t, evaluator.Evaluate(ctx, now.Add(time.Minute)))
+
+	evaluated, err = states.List(acmeCtx)
+	require.NoError(t, err)
+	assert.Equal(t, now, evaluated[rule.ID].Since, "the rule fires since its first evaluation")
+
+	// too few deliveries to fire
+	mocks.insights.EXPECT().GetRange(gomock.Any(), gomock.Any()).Return(deliveries(0, 5), nil)
+	mocks.notifier.EXPECT().NotifyScheduler(gomock.Any(), webhookURL, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, data any, _ map[string]string) error {
+			assert.Equal(t, domain.AlertNotificationResolved, data.(domain.AlertNotification).Status)
+			return nil
+		})
+	require.NoError(t, evaluator.Evaluate(ctx, now.Add(2*time.Minute)))
+
+	evaluated, err = states.List(acmeCtx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AlertOK, evaluated[rule.ID].Status)
+	assert.Equal(t, domain.AlertOK, evaluated[rule.ID].Notified)
+	assert.Equal(t, now.Add(2*time.Minute), evaluated[rule.ID].Since)
+}
+
+func TestEvaluator_RetriesFailedNotifications(t *testing.T) {
+	ctx := context.Background()
+	evaluator, states, mocks := newTestEvaluator(t)
+
+	rule := domain.AlertRule{
+		ID:        uuid.New(),
+		ProjectID: domain.DefaultProjectID,
+		Name:      "payments dead-lettered",
+		Kind:      domain.AlertDLQGrowth,
+		Event:     "payment.created",
+		Consumer:  "billing",
+		Threshold: 1,
+		Window:    intertime.Duration(10 * time.Minute),
+		Enabled:   true,
+	}
+
+	mocks.rules.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject()}, nil).AnyTimes()
+	mocks.rules.EXPECT().ListAlertRules(gomock.Any()).Return([]domain.AlertRule{rule}, nil).AnyTimes()
+	mocks.rules.EXPECT().GetInternalEvent(gomock.Any(), "payment.created").Return(domain.Event{}, domain.EventNotFound).AnyTimes()
+	mocks.deadLetters.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error) {
+			assert.Equal(t, []string{"payment.created"}, filters.EventName)
+			assert.Equal(t, []string{"billing"}, filters.Consumer)
+			return make([]domain.DeadLetter, 2), nil
+		}).Times(2)
+
+	now := time.Date(2025, 10, 10, 14, 5, 0, 0, time.UTC)
+	mocks.notifier.EXPECT().NotifyScheduler(gomock.Any(), webhookURL, gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+	require.NoError(t, evaluator.Evaluate(ctx, now))
+
+	evaluated, err := states.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AlertFiring, evaluated[rule.ID].Status)
+	assert.Empty(t, evaluated[rule.ID].Notified)
+
+	mocks.notifier.EXPECT().NotifyScheduler(gomock.Any(), webhookURL, gomock.Any(), gomock.Any()).Return(nil)
+	require.NoError(t, evaluator.Evaluate(ctx, now.Add(time.Minute)))
+
+	evaluated, err = states.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AlertFiring, evaluated[rule.ID].Notified)
+}
+
+func TestStateStore_TryLock(t *testing.T) {
+	ctx := context.Background()
+	_, states, _ := newTestEvaluator(t)
+
+	locked, err := states.TryLock(ctx, 30*time.Second)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	locked, err = states.TryLock(ctx, 30*time.Second)
+	require.NoError(t, err)
+	assert.False(t, locked, "another instance evaluates the interval")
+}
@@ -0,0 +1,85 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	statePrefix = "gqueue:alerts:state"
+	// lockKey is shared by every project: a single backoffice instance evaluates the rules of an
+	// interval
+	lockKey = "gqueue:alerts:lock"
+)
+
+// StateStore keeps the state of the rules of a project in a Redis hash by rule id. The keys are
+// namespaced by the project in ctx.
+type StateStore struct {
+	cache *redis.Client
+}
+
+func NewStateStore(cache *redis.Client) *StateStore {
+	return &StateStore{cache: cache}
+}
+
+func stateKey(ctx context.Context) string {
+	return domain.ProjectFromContext(ctx).Namespace(statePrefix)
+}
+
+// List returns the states of the rules evaluated at least once
+func (s *StateStore) List(ctx context.Context) (map[uuid.UUID]domain.AlertState, error) {
+	values, err := s.cache.HGetAll(ctx, stateKey(ctx)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert states: %w", err)
+	}
+
+	states := make(map[uuid.UUID]domain.AlertState, len(values))
+	for _, value := range values {
+		var state domain.AlertState
+		if err := json.Unmarshal([]byte(value), &state); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alert state: %w", err)
+		}
+		states[state.RuleID] = state
+	}
+
+	return states, nil
+}
+
+func (s *StateStore) Save(ctx context.Context, state domain.AlertState) error {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert state: %w", err)
+	}
+
+	if err := s.cache.HSet(ctx, stateKey(ctx), state.RuleID.String(), payload).Err(); err != nil {
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+
+	return nil
+}
+
+// Delete forgets the state of a rule, it is pending again
+func (s *StateStore) Delete(ctx context.Context, ruleID uuid.UUID) error {
+	if err := s.cache.HDel(ctx, stateKey(ctx), ruleID.String()).Err(); err != nil {
+		return fmt.Errorf("failed to delete alert state: %w", err)
+	}
+
+	return nil
+}
+
+// TryLock reports whether the caller holds the evaluation for the next ttl. The lock is not
+// released: it expires before the next interval.
+func (s *StateStore) TryLock(ctx context.Context, ttl time.Duration) (bool, error) {
+	ok, err := s.cache.SetNX(ctx, lockKey, time.Now().UTC().Format(time.RFC3339), ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to lock the alert evaluation: %w", err)
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,110 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultCacheTTL = 30 * time.Second
+	// touchInterval limits how often the last use of a key is written, a busy publisher would
+	// otherwise write it on every request
+	touchInterval = time.Minute
+)
+
+type Store interface {
+	GetAPIKey(ctx context.Context, keyID uuid.UUID) (domain.APIKey, error)
+	TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error
+}
+
+type cachedKey struct {
+	key       domain.APIKey
+	expiresAt time.Time
+	touchedAt time.Time
+}
+
+// Authenticator checks the API keys of requests. Keys are cached for a short time so a request
+// does not query the persistent store, a revoked key is rejected once its entry expires.
+type Authenticator struct {
+	store Store
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedKey
+}
+
+func NewAuthenticator(store Store, ttl time.Duration) *Authenticator {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	return &Authenticator{store: store, ttl: ttl, cache: make(map[uuid.UUID]cachedKey)}
+}
+
+// Authenticate returns the key of the token. Unknown, expired and revoked keys are all reported
+// as domain.InvalidAPIKey.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (domain.APIKey, error) {
+	keyID, secret, err := domain.ParseAPIKey(token)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	now := time.Now()
+	entry, err := a.get(ctx, keyID, now)
+	if errors.Is(err, domain.APIKeyNotFound) {
+		return domain.APIKey{}, domain.InvalidAPIKey
+	}
+
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	if !entry.key.CheckSecret(secret) || !entry.key.Active(now) {
+		return domain.APIKey{}, domain.InvalidAPIKey
+	}
+
+	if now.Sub(entry.touchedAt) >= touchInterval {
+		// the last use is informative, failing to write it must not reject a valid key. It is
+		// written again on the next request.
+		if err := a.store.TouchAPIKey(ctx, keyID, now); err != nil {
+			ctxlogger.GetLogger(ctx).Warn("failed to record api key use", "key_id", keyID, "error", err)
+		} else {
+			a.mu.Lock()
+			entry.touchedAt = now
+			a.cache[keyID] = entry
+			a.mu.Unlock()
+		}
+	}
+
+	return entry.key, nil
+}
+
+func (a *Authenticator) get(ctx context.Context, keyID uuid.UUID, now time.Time) (cachedKey, error) {
+	a.mu.Lock()
+	entry, ok := a.cache[keyID]
+	a.mu.Unlock()
+
+	if ok && now.Before(entry.expiresAt) {
+		return entry, nil
+	}
+
+	key, err := a.store.GetAPIKey(ctx, keyID)
+	if err != nil {
+		return cachedKey{}, err
+	}
+
+	// the last use survives the refresh so it is not written again before touchInterval
+	entry = cachedKey{key: key, expiresAt: now.Add(a.ttl), touchedAt: entry.touchedAt}
+
+	a.mu.Lock()
+	a.cache[keyID] = entry
+	a.mu.Unlock()
+
+	return entry, nil
+}
@@ -0,0 +1,111 @@
+package apikeys_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/apikeys"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockapikeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		change    func(key *domain.APIKey, token string) string
+		getErr    error
+		wantError error
+	}{
+		{name: "valid key", change: func(_ *domain.APIKey, token string) string { return token }},
+		{name: "wrong secret", change: func(_ *domain.APIKey, token string) string { return token + "0" }, wantError: domain.InvalidAPIKey},
+		{name: "expired key", change: func(key *domain.APIKey, token string) string { key.ExpiresAt = &past; return token }, wantError: domain.InvalidAPIKey},
+		{name: "revoked key", change: func(key *domain.APIKey, token string) string { key.RevokedAt = &past; return token }, wantError: domain.InvalidAPIKey},
+		{name: "unknown key", change: func(_ *domain.APIKey, token string) string { return token }, getErr: domain.APIKeyNotFound, wantError: domain.InvalidAPIKey},
+		{name: "malformed key", change: func(_ *domain.APIKey, _ string) string { return "secret" }, wantError: domain.InvalidAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			key, token, err := domain.NewAPIKey("acme", "billing", []domain.Scope{domain.ScopePublish}, nil)
+			require.NoError(t, err)
+			token = tt.change(&key, token)
+
+			store := mockapikeys.NewMockStore(ctrl)
+			store.EXPECT().GetAPIKey(gomock.Any(), key.ID).Return(key, tt.getErr).AnyTimes()
+			if tt.wantError == nil {
+				store.EXPECT().TouchAPIKey(gomock.Any(), key.ID, gomock.Any()).Return(nil)
+			}
+
+			got, err := apikeys.NewAuthenticator(store, time.Minute).Authenticate(context.Background(), token)
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, got.ID)
+		})
+	}
+}
+
+func TestAuthenticator_Cache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key, token, err := domain.NewAPIKey("acme", "billing", []domain.Scope{domain.ScopePublish}, nil)
+	require.NoError(t, err)
+
+	store := mockapikeys.NewMockStore(ctrl)
+	store.EXPECT().GetAPIKey(gomock.Any(), key.ID).Return(key, nil).Times(1)
+	// the last use is not written on every request
+	store.EXPECT().TouchAPIKey(gomock.Any(), key.ID, gomock.Any()).Return(nil).Times(1)
+
+	authenticator := apikeys.NewAuthenticator(store, time.Minute)
+	for range 3 {
+		_, err := authenticator.Authenticate(context.Background(), token)
+		require.NoError(t, err)
+	}
+}
+
+func TestAuthenticator_StoreError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key, token, err := domain.NewAPIKey("acme", "billing", []domain.Scope{domain.ScopePublish}, nil)
+	require.NoError(t, err)
+
+	t.Run("key not read", func(t *testing.T) {
+		store := mockapikeys.NewMockStore(ctrl)
+		store.EXPECT().GetAPIKey(gomock.Any(), key.ID).Return(domain.APIKey{}, errors.New("connection refused"))
+
+		_, err := apikeys.NewAuthenticator(store, time.Minute).Authenticate(context.Background(), token)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.InvalidAPIKey)
+	})
+
+	t.Run("last use not written", func(t *testing.T) {
+		store := mockapikeys.NewMockStore(ctrl)
+		store.EXPECT().GetAPIKey(gomock.Any(), key.ID).Return(key, nil)
+		store.EXPECT().TouchAPIKey(gomock.Any(), key.ID, gomock.Any()).Return(errors.New("connection refused")).Times(2)
+
+		authenticator := apikeys.NewAuthenticator(store, time.Minute)
+
+		got, err := authenticator.Authenticate(context.Background(), token)
+		require.NoError(t, err, "a valid key is accepted when its last use is not written")
+		assert.Equal(t, key.ID, got.ID)
+
+		// the cached key is still accepted and the write is retried
+		_, err = authenticator.Authenticate(context.Background(), token)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,254 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
+	"github.com/google/uuid"
+)
+
+type AlertRuleRepository interface {
+	CreateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error)
+	GetAlertRule(ctx context.Context, ruleID uuid.UUID) (domain.AlertRule, error)
+	ListAlertRules(ctx context.Context) ([]domain.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, ruleID uuid.UUID) error
+}
+
+type AlertStateStore interface {
+	List(ctx context.Context) (map[uuid.UUID]domain.AlertState, error)
+	Delete(ctx context.Context, ruleID uuid.UUID) error
+}
+
+type AlertRuleRequest struct {
+	Name       string             `json:"name"`
+	Kind       domain.AlertKind   `json:"kind"`
+	Event      string             `json:"event"`
+	Consumer   string             `json:"consumer"`
+	Threshold  float64            `json:"threshold"`
+	Window     intertime.Duration `json:"window"`
+	MinSamples int64              `json:"min_samples"`
+	// Enabled is true when omitted
+	Enabled *bool `json:"enabled"`
+}
+
+func (r AlertRuleRequest) ToDomain(id uuid.UUID, projectID string) domain.AlertRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return domain.AlertRule{
+		ID:         id,
+		ProjectID:  projectID,
+		Name:       r.Name,
+		Kind:       r.Kind,
+		Event:      r.Event,
+		Consumer:   r.Consumer,
+		Threshold:  r.Threshold,
+		Window:     r.Window,
+		MinSamples: r.MinSamples,
+		Enabled:    enabled,
+	}
+}
+
+func GetAlertRules(repo AlertRuleRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/alerts/rules",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			rules, err := repo.ListAlertRules(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			json.NewEncoder(w).Encode(rules)
+		},
+	}
+}
+
+func GetAlertRule(repo AlertRuleRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/alerts/rules/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ruleID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "invalid alert rule id", http.StatusBadRequest)
+				return
+			}
+
+			rule, err := repo.GetAlertRule(r.Context(), ruleID)
+			if errors.Is(err, domain.AlertRuleNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			json.NewEncoder(w).Encode(rule)
+		},
+	}
+}
+
+func CreateAlertRule(repo AlertRuleRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/alerts/rules",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			var payload AlertRuleRequest
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			rule := payload.ToDomain(uuid.New(), domain.ProjectFromContext(ctx).ID)
+			if err := rule.Validate(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			created, err := repo.CreateAlertRule(ctx, rule)
+			if err != nil {
+				ctxlogger.GetLogger(ctx).Error("failed to create alert rule", "error", err)
+				http.Error(w, "failed to create alert rule", http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(created)
+		},
+	}
+}
+
+// UpdateAlertRule replaces a rule. Disabling it forgets its state: a firing rule is not resolved.
+func UpdateAlertRule(repo AlertRuleRepository, states AlertStateStore) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "PUT /api/v1/alerts/rules/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			l := ctxlogger.GetLogger(ctx)
+
+			ruleID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "invalid alert rule id", http.StatusBadRequest)
+				return
+			}
+
+			var payload AlertRuleRequest
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			rule := payload.ToDomain(ruleID, domain.ProjectFromContext(ctx).ID)
+			if err := rule.Validate(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			updated, err := repo.UpdateAlertRule(ctx, rule)
+			if errors.Is(err, domain.AlertRuleNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				l.Error("failed to update alert rule", "rule_id", ruleID, "error", err)
+				http.Error(w, "failed to update alert rule", http.StatusInternalServerError)
+				return
+			}
+
+			if !updated.Enabled {
+				if err := states.Delete(ctx, ruleID); err != nil {
+					l.Warn("failed to delete alert state", "rule_id", ruleID, "error", err)
+				}
+			}
+
+			json.NewEncoder(w).Encode(updated)
+		},
+	}
+}
+
+// DeleteAlertRule removes a rule and its state, a firing rule is not resolved
+func DeleteAlertRule(repo AlertRuleRepository, states AlertStateStore) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "DELETE /api/v1/alerts/rules/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			l := ctxlogger.GetLogger(ctx)
+
+			ruleID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "invalid alert rule id", http.StatusBadRequest)
+				return
+			}
+
+			err = repo.DeleteAlertRule(ctx, ruleID)
+			if errors.Is(err, domain.AlertRuleNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				l.Error("failed to delete alert rule", "rule_id", ruleID, "error", err)
+				http.Error(w, "failed to delete alert rule", http.StatusInternalServerError)
+				return
+			}
+
+			if err := states.Delete(ctx, ruleID); err != nil {
+				l.Warn("failed to delete alert state", "rule_id", ruleID, "error", err)
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+}
+
+// GetAlerts returns every rule of the project with the result of its last evaluation, only the
+// rules in the status of the query when it is set, e.g. ?status=firing
+func GetAlerts(repo AlertRuleRepository, states AlertStateStore) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/alerts",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			status := domain.AlertStatus(r.URL.Query().Get("status"))
+
+			rules, err := repo.ListAlertRules(ctx)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			evaluated, err := states.List(ctx)
+			if err != nil {
+				ctxlogger.GetLogger(ctx).Error("failed to list alert states", "error", err)
+				http.Error(w, "failed to list alert states", http.StatusInternalServerError)
+				return
+			}
+
+			output := make([]domain.AlertRuleState, 0, len(rules))
+			for _, rule := range rules {
+				state, ok := evaluated[rule.ID]
+				ruleState := domain.NewAlertRuleState(rule, state, ok)
+				if status != "" && ruleState.Status != status {
+					continue
+				}
+				output = append(output, ruleState)
+			}
+
+			json.NewEncoder(w).Encode(output)
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package backofficeapp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAlertHandles(t *testing.T) {
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme"}
+
+	t.Run("create rule in the project of the request", func(t *testing.T) {
+		repo := mockbackofficeapp.NewMockAlertRuleRepository(gomock.NewController(t))
+		repo.EXPECT().CreateAlertRule(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, rule domain.AlertRule) (domain.AlertRule, error) {
+				assert.Equal(t, "acme", rule.ProjectID)
+				assert.Equal(t, intertime.Duration(5*time.Minute), rule.Window)
+				assert.True(t, rule.Enabled, "rules are enabled by default")
+				return rule, nil
+			})
+
+		rec := serveAs(acme, backofficeapp.CreateAlertRule(repo), http.MethodPost, "/api/v1/alerts/rules",
+			`{"name":"payments failing","kind":"failure_rate","event":"payment.created","threshold":0.05,"window":"5m"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		repo := mockbackofficeapp.NewMockAlertRuleRepository(gomock.NewController(t))
+
+		rec := serveAs(acme, backofficeapp.CreateAlertRule(repo), http.MethodPost, "/api/v1/alerts/rules",
+			`{"name":"payments failing","kind":"failure_rate","threshold":5,"window":"5m"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("disabling a rule forgets its state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ruleID := uuid.New()
+		repo := mockbackofficeapp.NewMockAlertRuleRepository(ctrl)
+		repo.EXPECT().UpdateAlertRule(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, rule domain.AlertRule) (domain.AlertRule, error) {
+				assert.Equal(t, ruleID, rule.ID)
+				return rule, nil
+			})
+		states := mockbackofficeapp.NewMockAlertStateStore(ctrl)
+		states.EXPECT().Delete(gomock.Any(), ruleID).Return(nil)
+
+		rec := serveAs(acme, backofficeapp.UpdateAlertRule(repo, states), http.MethodPut, "/api/v1/alerts/rules/"+ruleID.String(),
+			`{"name":"quiet payments","kind":"no_publishes","event":"payment.created","window":"30m","enabled":false}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("delete unknown rule", func(t *testing.T) {
+		ruleID := uuid.New()
+		repo := mockbackofficeapp.NewMockAlertRuleRepository(gomock.NewController(t))
+		repo.EXPECT().DeleteAlertRule(gomock.Any(), ruleID).Return(domain.AlertRuleNotFound)
+
+		rec := serveAs(acme, backofficeapp.DeleteAlertRule(repo, nil), http.MethodDelete, "/api/v1/alerts/rules/"+ruleID.String(), "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("firing rules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		firing := domain.AlertRule{ID: uuid.New(), Name: "payments failing", Enabled: true}
+		ok := domain.AlertRule{ID: uuid.New(), Name: "payments lagging", Enabled: true}
+		pending := domain.AlertRule{ID: uuid.New(), Name: "new rule", Enabled: true}
+		since := time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC)
+
+		repo := mockbackofficeapp.NewMockAlertRuleRepository(ctrl)
+		repo.EXPECT().ListAlertRules(gomock.Any()).Return([]domain.AlertRule{firing, ok, pending}, nil).Times(2)
+		states := mockbackofficeapp.NewMockAlertStateStore(ctrl)
+		states.EXPECT().List(gomock.Any()).Return(map[uuid.UUID]domain.AlertState{
+			firing.ID: {RuleID: firing.ID, Status: domain.AlertFiring, Value: 0.3, Since: since},
+			ok.ID:     {RuleID: ok.ID, Status: domain.AlertOK, Since: since},
+		}, nil).Times(2)
+
+		rec := serveAs(acme, backofficeapp.GetAlerts(repo, states), http.MethodGet, "/api/v1/alerts?status=firing", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var alerts []domain.AlertRuleState
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &alerts))
+		require.Len(t, alerts, 1)
+		assert.Equal(t, firing.ID, alerts[0].Rule.ID)
+		assert.Equal(t, 0.3, alerts[0].Value)
+		assert.Equal(t, since, *alerts[0].Since)
+
+		rec = serveAs(acme, backofficeapp.GetAlerts(repo, states), http.MethodGet, "/api/v1/alerts", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		alerts = nil
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &alerts))
+		require.Len(t, alerts, 3)
+		assert.Equal(t, domain.AlertPending, alerts[2].Status)
+		assert.Nil(t, alerts[2].Since)
+	})
+}
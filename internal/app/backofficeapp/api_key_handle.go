@@ -0,0 +1,147 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID uuid.UUID) (domain.APIKey, error)
+}
+
+type APIKeyRequest struct {
+	Name      string         `json:"name"`
+	Scopes    []domain.Scope `json:"scopes"`
+	ExpiresAt *time.Time     `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+	domain.APIKey
+	// Key is only returned when the key is created, it cannot be read again
+	Key string `json:"key,omitempty"`
+}
+
+// RequireScope rejects the requests whose API key does not grant the scope
+func RequireScope(scope domain.Scope, handle httpadapter.HttpHandle) httpadapter.HttpHandle {
+	next := handle.Handler
+	handle.Handler = func(w http.ResponseWriter, r *http.Request) {
+		if err := domain.Authorize(r.Context(), scope); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+
+	return handle
+}
+
+func GetAPIKeys(repo APIKeyRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/api-keys",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			keys, err := repo.ListAPIKeys(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			json.NewEncoder(w).Encode(keys)
+		},
+	}
+}
+
+// CreateAPIKey creates a key of the project of the request. A key cannot grant scopes the key of
+// the request does not have.
+func CreateAPIKey(repo APIKeyRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/api-keys",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			l := ctxlogger.GetLogger(ctx)
+
+			var payload APIKeyRequest
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if payload.ExpiresAt != nil && !payload.ExpiresAt.After(time.Now()) {
+				http.Error(w, "expires_at must be in the future", http.StatusBadRequest)
+				return
+			}
+
+			projectID := domain.ProjectFromContext(ctx).ID
+			key, token, err := domain.NewAPIKey(projectID, payload.Name, payload.Scopes, payload.ExpiresAt)
+			if err != nil {
+				l.Error("failed to generate api key", "error", err)
+				http.Error(w, "failed to create api key", http.StatusInternalServerError)
+				return
+			}
+
+			if err := key.Validate(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			for _, scope := range key.Scopes {
+				if err := domain.Authorize(ctx, scope); err != nil {
+					http.Error(w, fmt.Sprintf("cannot grant a scope the key of the request does not have: %s", err), http.StatusForbidden)
+					return
+				}
+			}
+
+			created, err := repo.CreateAPIKey(ctx, key)
+			if err != nil {
+				l.Error("failed to create api key", "error", err)
+				http.Error(w, "failed to create api key", http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(APIKeyResponse{APIKey: created, Key: token})
+		},
+	}
+}
+
+// RevokeAPIKey stops accepting a key of the project, instances reject it once their cache expires
+func RevokeAPIKey(repo APIKeyRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "DELETE /api/v1/api-keys/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			keyID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "invalid api key id", http.StatusBadRequest)
+				return
+			}
+
+			key, err := repo.RevokeAPIKey(ctx, keyID)
+			if errors.Is(err, domain.APIKeyNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				ctxlogger.GetLogger(ctx).Error("failed to revoke api key", "api_key_id", keyID, "error", err)
+				http.Error(w, "failed to revoke api key", http.StatusInternalServerError)
+				return
+			}
+
+			json.NewEncoder(w).Encode(key)
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package backofficeapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyHandles(t *testing.T) {
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme"}
+
+	t.Run("create returns the key once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockAPIKeyRepository(ctrl)
+		repo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, key domain.APIKey) (domain.APIKey, error) {
+				assert.Equal(t, "acme", key.ProjectID, "keys belong to the project of the request")
+				assert.NotEmpty(t, key.SecretHash)
+				return key, nil
+			})
+
+		rec := serveAs(acme, backofficeapp.CreateAPIKey(repo), http.MethodPost, "/api/v1/api-keys",
+			`{"name":"billing","scopes":["publish:payment.processed"]}`)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var output map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &output))
+		assert.Contains(t, output["key"], domain.APIKeyPrefix)
+		assert.NotContains(t, output, "secret_hash")
+	})
+
+	t.Run("invalid scope", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockAPIKeyRepository(ctrl)
+
+		rec := serveAs(acme, backofficeapp.CreateAPIKey(repo), http.MethodPost, "/api/v1/api-keys",
+			`{"name":"billing","scopes":["admin"]}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("a key cannot grant scopes it does not have", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockAPIKeyRepository(ctrl)
+
+		handle := withAPIKey(domain.APIKey{Scopes: []domain.Scope{domain.ScopeKeysManage}}, backofficeapp.CreateAPIKey(repo))
+		rec := serveAs(acme, handle, http.MethodPost, "/api/v1/api-keys", `{"name":"ops","scopes":["dlq:manage"]}`)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("revoke unknown key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		keyID := uuid.New()
+		repo := mockbackofficeapp.NewMockAPIKeyRepository(ctrl)
+		repo.EXPECT().RevokeAPIKey(gomock.Any(), keyID).Return(domain.APIKey{}, domain.APIKeyNotFound)
+
+		rec := serveAs(acme, backofficeapp.RevokeAPIKey(repo), http.MethodDelete, "/api/v1/api-keys/"+keyID.String(), "")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestRequireScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mockbackofficeapp.NewMockAPIKeyRepository(ctrl)
+	repo.EXPECT().ListAPIKeys(gomock.Any()).Return([]domain.APIKey{}, nil).Times(2)
+
+	handle := backofficeapp.RequireScope(domain.ScopeKeysManage, backofficeapp.GetAPIKeys(repo))
+
+	rec := serveAs(domain.DefaultProject(), handle, http.MethodGet, "/api/v1/api-keys", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "requests without a key are not limited by scopes")
+
+	rec = serveAs(domain.DefaultProject(), withAPIKey(domain.APIKey{Scopes: []domain.Scope{domain.ScopeKeysManage}}, handle), http.MethodGet, "/api/v1/api-keys", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serveAs(domain.DefaultProject(), withAPIKey(domain.APIKey{Scopes: []domain.Scope{domain.ScopePublish}}, handle), http.MethodGet, "/api/v1/api-keys", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+// withAPIKey serves the handle as a request authenticated with the key, like the api key middleware does
+func withAPIKey(key domain.APIKey, handle httpadapter.HttpHandle) httpadapter.HttpHandle {
+	next := handle.Handler
+	handle.Handler = func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(domain.WithAPIKey(r.Context(), key)))
+	}
+
+	return handle
+}
@@ -0,0 +1,202 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/IsaacDSC/gqueue/pkg/queryparser"
+)
+
+type ArchivedTaskManager interface {
+	ArchivedQueues(ctx context.Context) ([]taskapp.ArchivedQueue, error)
+	ListArchived(ctx context.Context, queue string, page, limit int) (taskapp.ArchivedTasksPage, error)
+	GetArchived(ctx context.Context, queue, id string) (taskapp.ArchivedTask, error)
+	RequeueTask(ctx context.Context, queue, id string) error
+	DeleteTask(ctx context.Context, queue, id string) error
+	Requeue(ctx context.Context, queue string, ids ...string) taskapp.BatchResult
+	Delete(ctx context.Context, queue string, ids ...string) taskapp.BatchResult
+}
+
+type ArchivedTasksRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type archivedTasksFilter struct {
+	Page  uint `query:"page"`
+	Limit uint `query:"limit"`
+}
+
+const maxArchivedTasksLimit = 500
+
+func GetArchivedQueues(manager ArchivedTaskManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/tasks/archived",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			queues, err := manager.ArchivedQueues(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(queues)
+		},
+	}
+}
+
+func GetArchivedTasks(manager ArchivedTaskManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/tasks/archived/{queue}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			var filter archivedTasksFilter
+
+			defaults := map[string]any{
+				"page":  uint(1),
+				"limit": uint(100),
+			}
+
+			if err := queryparser.ParseQueryParamsWithDefaults(r.URL.Query(), &filter, defaults); err != nil {
+				http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if filter.Page == 0 || filter.Limit == 0 || filter.Limit > maxArchivedTasksLimit {
+				http.Error(w, "Invalid query parameters: page must be positive and limit between 1 and 500", http.StatusBadRequest)
+				return
+			}
+
+			page, err := manager.ListArchived(r.Context(), r.PathValue("queue"), int(filter.Page), int(filter.Limit))
+			if errors.Is(err, taskapp.ErrorNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(page)
+		},
+	}
+}
+
+func GetArchivedTask(manager ArchivedTaskManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/tasks/archived/{queue}/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			task, err := manager.GetArchived(r.Context(), r.PathValue("queue"), r.PathValue("id"))
+			if errors.Is(err, taskapp.ErrorNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(task)
+		},
+	}
+}
+
+func RequeueArchivedTask(manager ArchivedTaskManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/tasks/archived/{queue}/{id}/requeue",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			err := manager.RequeueTask(r.Context(), r.PathValue("queue"), r.PathValue("id"))
+			writeTaskResult(w, err, http.StatusAccepted)
+		},
+	}
+}
+
+// RequeueArchivedTasks requeues the ids in the body, the response lists the ones that failed
+func RequeueArchivedTasks(manager ArchivedTaskManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/tasks/archived/{queue}/requeue",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ids, ok := decodeArchivedTaskIDs(w, r)
+			if !ok {
+				return
+			}
+
+			result := manager.Requeue(r.Context(), r.PathValue("queue"), ids...)
+			writeBatchResult(w, result)
+		},
+	}
+}
+
+func DeleteArchivedTask(manager ArchivedTaskManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "DELETE /api/v1/tasks/archived/{queue}/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			err := manager.DeleteTask(r.Context(), r.PathValue("queue"), r.PathValue("id"))
+			writeTaskResult(w, err, http.StatusNoContent)
+		},
+	}
+}
+
+func DeleteArchivedTasks(manager ArchivedTaskManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "DELETE /api/v1/tasks/archived/{queue}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ids, ok := decodeArchivedTaskIDs(w, r)
+			if !ok {
+				return
+			}
+
+			result := manager.Delete(r.Context(), r.PathValue("queue"), ids...)
+			writeBatchResult(w, result)
+		},
+	}
+}
+
+func decodeArchivedTaskIDs(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	defer r.Body.Close()
+
+	var payload ArchivedTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	if len(payload.IDs) == 0 {
+		http.Error(w, "ids is required", http.StatusBadRequest)
+		return nil, false
+	}
+
+	return payload.IDs, true
+}
+
+func writeTaskResult(w http.ResponseWriter, err error, status int) {
+	if errors.Is(err, taskapp.ErrorNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+}
+
+// writeBatchResult answers 207 when only part of the tasks were handled
+func writeBatchResult(w http.ResponseWriter, result taskapp.BatchResult) {
+	status := http.StatusOK
+	if len(result.Failed) > 0 {
+		status = http.StatusMultiStatus
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
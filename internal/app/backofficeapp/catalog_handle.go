@@ -0,0 +1,83 @@
+package backofficeapp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IsaacDSC/gqueue/internal/catalog"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/IsaacDSC/gqueue/pkg/queryparser"
+)
+
+// GetCatalog lists the active events of the project with their owners, schemas, consumers and
+// delivery options, searchable by name, owner and consumer
+func GetCatalog(repo Repository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/catalog",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			entries, ok := searchCatalog(w, r, repo)
+			if !ok {
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(entries)
+		},
+	}
+}
+
+// GetCatalogAsyncAPI exports the catalog as an AsyncAPI document, version 3 by default or 2 with
+// ?version=2. The catalog filters apply to the document too.
+func GetCatalogAsyncAPI(repo Repository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/catalog/asyncapi",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			version, err := catalog.ParseAsyncAPIVersion(r.URL.Query().Get("version"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			entries, ok := searchCatalog(w, r, repo)
+			if !ok {
+				return
+			}
+
+			project := domain.ProjectFromContext(r.Context())
+			doc, err := catalog.AsyncAPI(version, catalog.Info{
+				Title:       project.Name + " events",
+				Version:     "1.0.0",
+				Description: "Events registered in gqueue for the project " + project.ID,
+			}, entries)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(doc)
+		},
+	}
+}
+
+// searchCatalog writes the error response itself and reports false when the catalog cannot be read
+func searchCatalog(w http.ResponseWriter, r *http.Request, repo Repository) ([]catalog.Entry, bool) {
+	ctx := r.Context()
+
+	var filter catalog.Filter
+	if err := queryparser.ParseQueryParams(r.URL.Query(), &filter); err != nil {
+		http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	events, err := repo.GetInternalEvents(ctx, domain.FilterEvents{State: []string{"active"}})
+	if err != nil {
+		ctxlogger.GetLogger(ctx).Error("failed to get events", "error", err)
+		http.Error(w, "failed to get catalog", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return catalog.Search(events, filter), true
+}
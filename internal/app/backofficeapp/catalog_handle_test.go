@@ -0,0 +1,66 @@
+package backofficeapp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/catalog"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCatalogHandles(t *testing.T) {
+	acme := domain.Project{ID: "acme", Name: "Acme", TopicPrefix: "acme"}
+	events := []domain.Event{
+		{
+			Name:      "payment.processed",
+			Schema:    json.RawMessage(`{"type":"object"}`),
+			Consumers: []domain.Consumer{{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"}},
+		},
+		{
+			Name:      "order.created",
+			Owner:     domain.Owner{TeamOwner: "orders"},
+			Consumers: []domain.Consumer{{ServiceName: "shipping", BaseUrl: "http://shipping"}},
+		},
+	}
+
+	activeEvents := func(t *testing.T) *mockbackofficeapp.MockRepository {
+		repo := mockbackofficeapp.NewMockRepository(gomock.NewController(t))
+		repo.EXPECT().GetInternalEvents(gomock.Any(), domain.FilterEvents{State: []string{"active"}}).Return(events, nil)
+		return repo
+	}
+
+	t.Run("search by owner", func(t *testing.T) {
+		rec := serveAs(acme, backofficeapp.GetCatalog(activeEvents(t)), http.MethodGet, "/api/v1/catalog?owner=orders", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var entries []catalog.Entry
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+		require.Len(t, entries, 1)
+		assert.Equal(t, "order.created", entries[0].Name)
+		assert.Equal(t, "http://shipping/", entries[0].Consumers[0].URL)
+	})
+
+	t.Run("asyncapi 2", func(t *testing.T) {
+		rec := serveAs(acme, backofficeapp.GetCatalogAsyncAPI(activeEvents(t)), http.MethodGet, "/api/v1/catalog/asyncapi?version=2&name=payment", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, catalog.AsyncAPIV2, doc["asyncapi"])
+		assert.Equal(t, "Acme events", doc["info"].(map[string]any)["title"])
+		assert.Len(t, doc["channels"], 1)
+	})
+
+	t.Run("unsupported asyncapi version", func(t *testing.T) {
+		repo := mockbackofficeapp.NewMockRepository(gomock.NewController(t))
+
+		rec := serveAs(acme, backofficeapp.GetCatalogAsyncAPI(repo), http.MethodGet, "/api/v1/catalog/asyncapi?version=1", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
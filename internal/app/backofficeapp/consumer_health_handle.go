@@ -0,0 +1,89 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/google/uuid"
+)
+
+type ConsumerHealthMonitor interface {
+	Health(ctx context.Context, now time.Time) ([]domain.ConsumerHealth, error)
+	Release(ctx context.Context, event, consumer string, now time.Time) error
+}
+
+// GetConsumersHealth scores the consumers of the project over the quarantine window, only the ones
+// in the status of the query when it is set, e.g. ?status=quarantined
+func GetConsumersHealth(monitor ConsumerHealthMonitor) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/consumers/health",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			status := domain.HealthStatus(r.URL.Query().Get("status"))
+
+			health, err := monitor.Health(ctx, time.Now())
+			if err != nil {
+				ctxlogger.GetLogger(ctx).Error("failed to score consumers", "error", err)
+				http.Error(w, "failed to score consumers", http.StatusInternalServerError)
+				return
+			}
+
+			output := make([]domain.ConsumerHealth, 0, len(health))
+			for _, h := range health {
+				if status == "" || h.Status == status {
+					output = append(output, h)
+				}
+			}
+
+			json.NewEncoder(w).Encode(output)
+		},
+	}
+}
+
+// ReleaseConsumer ends the quarantine of a consumer without waiting for a probe to succeed
+func ReleaseConsumer(repo ConsumerRepository, monitor ConsumerHealthMonitor) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/events/{id}/consumers/{consumer}/release",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			eventID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "Invalid event ID", http.StatusBadRequest)
+				return
+			}
+
+			event, err := repo.GetEventByID(ctx, eventID)
+			if errors.Is(err, domain.EventNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			consumer := r.PathValue("consumer")
+			err = monitor.Release(ctx, event.Name, consumer, time.Now())
+			if errors.Is(err, domain.QuarantineNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				ctxlogger.GetLogger(ctx).Error("failed to release consumer", "event_name", event.Name, "consumer", consumer, "error", err)
+				http.Error(w, "failed to release consumer", http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+}
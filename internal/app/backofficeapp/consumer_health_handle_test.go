@@ -0,0 +1,51 @@
+package backofficeapp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestConsumerHealthHandles(t *testing.T) {
+	t.Run("quarantined consumers", func(t *testing.T) {
+		monitor := mockbackofficeapp.NewMockConsumerHealthMonitor(gomock.NewController(t))
+		monitor.EXPECT().Health(gomock.Any(), gomock.Any()).Return([]domain.ConsumerHealth{
+			{Event: "payment.processed", Consumer: "billing", Status: domain.HealthQuarantined, Quarantine: &domain.Quarantine{Since: time.Now()}},
+			{Event: "payment.processed", Consumer: "ledger", Status: domain.HealthHealthy},
+		}, nil)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.GetConsumersHealth(monitor), http.MethodGet, "/api/v1/consumers/health?status=quarantined", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var health []domain.ConsumerHealth
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+		require.Len(t, health, 1)
+		assert.Equal(t, "billing", health[0].Consumer)
+	})
+
+	t.Run("release a consumer of the event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		eventID := uuid.New()
+		repo := mockbackofficeapp.NewMockConsumerRepository(ctrl)
+		repo.EXPECT().GetEventByID(gomock.Any(), eventID).Return(domain.Event{ID: eventID, Name: "payment.processed"}, nil).Times(2)
+		monitor := mockbackofficeapp.NewMockConsumerHealthMonitor(ctrl)
+		monitor.EXPECT().Release(gomock.Any(), "payment.processed", "billing", gomock.Any()).Return(nil)
+		monitor.EXPECT().Release(gomock.Any(), "payment.processed", "ledger", gomock.Any()).Return(domain.QuarantineNotFound)
+
+		target := "/api/v1/events/" + eventID.String() + "/consumers/"
+		rec := serveAs(domain.DefaultProject(), backofficeapp.ReleaseConsumer(repo, monitor), http.MethodPost, target+"billing/release", "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = serveAs(domain.DefaultProject(), backofficeapp.ReleaseConsumer(repo, monitor), http.MethodPost, target+"ledger/release", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
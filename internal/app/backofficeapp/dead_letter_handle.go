@@ -0,0 +1,220 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/IsaacDSC/gqueue/pkg/queryparser"
+	"github.com/google/uuid"
+)
+
+type DeadLetterStore interface {
+	List(ctx context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error)
+	Get(ctx context.Context, id uuid.UUID) (domain.DeadLetter, error)
+	Delete(ctx context.Context, ids ...uuid.UUID) (int, error)
+	Purge(ctx context.Context, filters domain.FilterDeadLetters) (int, error)
+}
+
+type DeadLetterReplayer interface {
+	Replay(ctx context.Context, dl domain.DeadLetter, allConsumers bool) error
+}
+
+// ErrPubSubNotConfigured answers the replays of an instance without a Pub/Sub client, dead letters
+// are replayed through Google Pub/Sub
+var ErrPubSubNotConfigured = errors.New("pub/sub not configured")
+
+const (
+	ReplayTargetOriginal = "original"
+	ReplayTargetAll      = "all"
+)
+
+type ReplayDeadLetterRequest struct {
+	// Target is "original" (default) to replay to the consumer that failed or "all" for every consumer of the event
+	Target string `json:"target"`
+}
+
+type PurgeDeadLettersRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+func GetDeadLetters(store DeadLetterStore) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/dlq",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			var filter domain.FilterDeadLetters
+
+			defaults := map[string]any{
+				"page":  uint(1),
+				"limit": uint(100),
+			}
+
+			if err := queryparser.ParseQueryParamsWithDefaults(r.URL.Query(), &filter, defaults); err != nil {
+				http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if _, _, err := filter.Range(); err != nil {
+				http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			deadLetters, err := store.List(ctx, filter)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(deadLetters)
+		},
+	}
+}
+
+func GetDeadLetter(store DeadLetterStore) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/dlq/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			dl, ok := findDeadLetter(w, r, store)
+			if !ok {
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(dl)
+		},
+	}
+}
+
+// ReplayDeadLetter publishes the message again and removes it from the dead-letter store.
+// If it fails again it is dead-lettered as a new entry. A nil replayer answers 503.
+func ReplayDeadLetter(store DeadLetterStore, replayer DeadLetterReplayer) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/dlq/{id}/replay",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			l := ctxlogger.GetLogger(ctx)
+
+			if replayer == nil {
+				http.Error(w, ErrPubSubNotConfigured.Error()+", dead letters are replayed with WQ=googlepubsub", http.StatusServiceUnavailable)
+				return
+			}
+
+			var payload ReplayDeadLetterRequest
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if payload.Target == "" {
+				payload.Target = ReplayTargetOriginal
+			}
+
+			if payload.Target != ReplayTargetOriginal && payload.Target != ReplayTargetAll {
+				http.Error(w, "target must be original or all", http.StatusBadRequest)
+				return
+			}
+
+			dl, ok := findDeadLetter(w, r, store)
+			if !ok {
+				return
+			}
+
+			if err := replayer.Replay(ctx, dl, payload.Target == ReplayTargetAll); err != nil {
+				l.Error("failed to replay dead letter", "id", dl.ID, "error", err)
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			}
+
+			if _, err := store.Delete(ctx, dl.ID); err != nil {
+				l.Warn("replayed dead letter was not removed", "id", dl.ID, "error", err)
+			}
+
+			w.WriteHeader(http.StatusAccepted)
+		},
+	}
+}
+
+// PurgeDeadLetters deletes the ids in the body or, without ids, every dead letter matching the
+// query filters. Purging everything requires all=true so an empty request does not wipe the store.
+func PurgeDeadLetters(store DeadLetterStore) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "DELETE /api/v1/dlq",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			var payload PurgeDeadLettersRequest
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			var (
+				purged int
+				err    error
+			)
+
+			if len(payload.IDs) > 0 {
+				purged, err = store.Delete(ctx, payload.IDs...)
+			} else {
+				query := r.URL.Query()
+				var filter domain.FilterDeadLetters
+				if err := queryparser.ParseQueryParams(query, &filter); err != nil {
+					http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				if _, _, err := filter.Range(); err != nil {
+					http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				noFilter := len(filter.EventName) == 0 && len(filter.Consumer) == 0 && len(filter.TeamOwner) == 0 && filter.From == "" && filter.To == ""
+				if noFilter && query.Get("all") != "true" {
+					http.Error(w, "ids, filters or all=true is required", http.StatusBadRequest)
+					return
+				}
+
+				purged, err = store.Purge(ctx, filter)
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]int{"purged": purged})
+		},
+	}
+}
+
+func findDeadLetter(w http.ResponseWriter, r *http.Request, store DeadLetterStore) (domain.DeadLetter, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid dead letter ID", http.StatusBadRequest)
+		return domain.DeadLetter{}, false
+	}
+
+	dl, err := store.Get(r.Context(), id)
+	if errors.Is(err, domain.DeadLetterNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return domain.DeadLetter{}, false
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return domain.DeadLetter{}, false
+	}
+
+	return dl, true
+}
@@ -0,0 +1,38 @@
+package backofficeapp_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReplayDeadLetter(t *testing.T) {
+	t.Run("replays and removes the dead letter", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		dl := domain.DeadLetter{ID: uuid.New(), EventName: "payment.processed"}
+
+		store := mockbackofficeapp.NewMockDeadLetterStore(ctrl)
+		store.EXPECT().Get(gomock.Any(), dl.ID).Return(dl, nil)
+		store.EXPECT().Delete(gomock.Any(), dl.ID).Return(1, nil)
+
+		replayer := mockbackofficeapp.NewMockDeadLetterReplayer(ctrl)
+		replayer.EXPECT().Replay(gomock.Any(), dl, true).Return(nil)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.ReplayDeadLetter(store, replayer), http.MethodPost, "/api/v1/dlq/"+dl.ID.String()+"/replay", `{"target":"all"}`)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+
+	t.Run("without a Pub/Sub client", func(t *testing.T) {
+		store := mockbackofficeapp.NewMockDeadLetterStore(gomock.NewController(t))
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.ReplayDeadLetter(store, nil), http.MethodPost, "/api/v1/dlq/"+uuid.NewString()+"/replay", "")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), backofficeapp.ErrPubSubNotConfigured.Error())
+	})
+}
@@ -0,0 +1,214 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/google/uuid"
+)
+
+type ConsumerRepository interface {
+	GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error)
+	UpdateEventIfRevision(ctx context.Context, event domain.Event, revision int) (domain.Event, error)
+}
+
+func GetEventConsumer(repo ConsumerRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/events/{id}/consumers/{consumer}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			eventID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "Invalid event ID", http.StatusBadRequest)
+				return
+			}
+
+			event, err := repo.GetEventByID(r.Context(), eventID)
+			if errors.Is(err, domain.EventNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			consumer, ok := event.FindConsumer(r.PathValue("consumer"))
+			if !ok {
+				http.Error(w, domain.ConsumerNotFound.Error(), http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", revisionETag(event.Revision))
+			json.NewEncoder(w).Encode(consumer)
+		},
+	}
+}
+
+// AddEventConsumer adds one consumer to an existing event without sending the whole list
+func AddEventConsumer(repo ConsumerRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/events/{id}/consumers",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			var consumer domain.Consumer
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&consumer); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			changeEventConsumers(w, r, repo, http.StatusCreated, func(event *domain.Event) error {
+				return event.AddConsumer(consumer)
+			})
+		},
+	}
+}
+
+// UpdateEventConsumer replaces the configuration of one consumer, the body can rename it
+func UpdateEventConsumer(repo ConsumerRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "PUT /api/v1/events/{id}/consumers/{consumer}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			name := r.PathValue("consumer")
+
+			var consumer domain.Consumer
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&consumer); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if consumer.ServiceName == "" {
+				consumer.ServiceName = name
+			}
+
+			changeEventConsumers(w, r, repo, http.StatusOK, func(event *domain.Event) error {
+				return event.ReplaceConsumer(name, consumer)
+			})
+		},
+	}
+}
+
+func RemoveEventConsumer(repo ConsumerRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "DELETE /api/v1/events/{id}/consumers/{consumer}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			name := r.PathValue("consumer")
+
+			changeEventConsumers(w, r, repo, http.StatusOK, func(event *domain.Event) error {
+				return event.RemoveConsumer(name)
+			})
+		},
+	}
+}
+
+// changeEventConsumers applies change to the latest version of the event and saves it only if no
+// other request changed the event in the meantime. A client sending If-Match with the ETag it read
+// gets 412 when the event changed since then.
+func changeEventConsumers(w http.ResponseWriter, r *http.Request, repo ConsumerRepository, status int, change func(event *domain.Event) error) {
+	ctx := r.Context()
+	l := ctxlogger.GetLogger(ctx)
+
+	eventID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return
+	}
+
+	expected, hasExpected, err := ifMatchRevision(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	event, err := repo.GetEventByID(ctx, eventID)
+	if errors.Is(err, domain.EventNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if hasExpected && expected != event.Revision {
+		w.Header().Set("ETag", revisionETag(event.Revision))
+		http.Error(w, domain.EventVersionConflict.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	switch err := change(&event); {
+	case errors.Is(err, domain.ConsumerNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, domain.ConsumerAlreadyExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := domain.ProjectFromContext(ctx).Quotas.CheckConsumers(len(event.Consumers)); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	// the type is not stored with the event, publishing treats a missing one as internal
+	if event.Type == "" {
+		event.Type = domain.EventTypeInternal
+	}
+
+	if err := event.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid event payload: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	updated, err := repo.UpdateEventIfRevision(ctx, event, event.Revision)
+	switch {
+	case errors.Is(err, domain.EventVersionConflict):
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	case errors.Is(err, domain.EventNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		l.Error("failed to update event consumers", "event_id", eventID, "error", err)
+		http.Error(w, "failed to update event consumers", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", revisionETag(updated.Revision))
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(updated)
+}
+
+func revisionETag(revision int) string {
+	return strconv.Quote(strconv.Itoa(revision))
+}
+
+// ifMatchRevision reads the revision of the If-Match header, "*" matches any revision
+func ifMatchRevision(r *http.Request) (int, bool, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, false, nil
+	}
+
+	revision, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid If-Match header: %s", value)
+	}
+
+	return revision, true, nil
+}
@@ -0,0 +1,120 @@
+package backofficeapp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func serve(handle httpadapter.HttpHandle, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc(handle.Path, handle.Handler)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEventConsumerHandles(t *testing.T) {
+	eventID := uuid.New()
+	newEvent := func() domain.Event {
+		return domain.Event{
+			ID:       eventID,
+			Name:     "payment.processed",
+			Option:   domain.Opt{WqType: "low_throughput"},
+			Revision: 3,
+			Consumers: []domain.Consumer{
+				{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+			},
+		}
+	}
+	path := "/api/v1/events/" + eventID.String() + "/consumers"
+
+	t.Run("add consumer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockConsumerRepository(ctrl)
+		repo.EXPECT().GetEventByID(gomock.Any(), eventID).Return(newEvent(), nil)
+		repo.EXPECT().UpdateEventIfRevision(gomock.Any(), gomock.Any(), 3).DoAndReturn(
+			func(_ any, event domain.Event, _ int) (domain.Event, error) {
+				assert.Len(t, event.Consumers, 2)
+				event.Revision = 4
+				return event, nil
+			})
+
+		rec := serve(backofficeapp.AddEventConsumer(repo), http.MethodPost, path,
+			`{"service_name":"ledger","host":"http://ledger","path":"/webhook"}`, map[string]string{"If-Match": `"3"`})
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("stale If-Match", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockConsumerRepository(ctrl)
+		repo.EXPECT().GetEventByID(gomock.Any(), eventID).Return(newEvent(), nil)
+
+		rec := serve(backofficeapp.UpdateEventConsumer(repo), http.MethodPut, path+"/billing",
+			`{"host":"http://billing-v2"}`, map[string]string{"If-Match": `"2"`})
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("concurrent update", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockConsumerRepository(ctrl)
+		repo.EXPECT().GetEventByID(gomock.Any(), eventID).Return(newEvent(), nil)
+		repo.EXPECT().UpdateEventIfRevision(gomock.Any(), gomock.Any(), 3).Return(domain.Event{}, domain.EventVersionConflict)
+
+		rec := serve(backofficeapp.UpdateEventConsumer(repo), http.MethodPut, path+"/billing",
+			`{"host":"http://billing-v2"}`, nil)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	})
+
+	t.Run("duplicated consumer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockConsumerRepository(ctrl)
+		repo.EXPECT().GetEventByID(gomock.Any(), eventID).Return(newEvent(), nil)
+
+		rec := serve(backofficeapp.AddEventConsumer(repo), http.MethodPost, path,
+			`{"service_name":"billing","host":"http://billing"}`, nil)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("unknown consumer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockConsumerRepository(ctrl)
+		repo.EXPECT().GetEventByID(gomock.Any(), eventID).Return(newEvent(), nil)
+
+		rec := serve(backofficeapp.RemoveEventConsumer(repo), http.MethodDelete, path+"/ledger", "", nil)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("removing the last consumer fails validation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockConsumerRepository(ctrl)
+		repo.EXPECT().GetEventByID(gomock.Any(), eventID).Return(newEvent(), nil)
+
+		rec := serve(backofficeapp.RemoveEventConsumer(repo), http.MethodDelete, path+"/billing", "", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "at least one consumer is required")
+	})
+}
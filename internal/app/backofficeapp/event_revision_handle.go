@@ -0,0 +1,171 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/IsaacDSC/gqueue/pkg/queryparser"
+	"github.com/google/uuid"
+)
+
+type RevisionRepository interface {
+	ListRevisions(ctx context.Context, eventID uuid.UUID) ([]domain.EventRevision, error)
+	GetRevision(ctx context.Context, eventID uuid.UUID, revision int) (domain.EventRevision, error)
+	RollbackEvent(ctx context.Context, eventID uuid.UUID, revision int) (domain.Event, error)
+}
+
+type RevisionDiffParams struct {
+	From int `query:"from"`
+	To   int `query:"to"`
+}
+
+type RevisionDiff struct {
+	EventID uuid.UUID       `json:"event_id"`
+	From    int             `json:"from"`
+	To      int             `json:"to"`
+	Changes []domain.Change `json:"changes"`
+}
+
+// GetEventRevisions lists the revisions of an event, newest first
+func GetEventRevisions(repo RevisionRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/events/{id}/revisions",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			eventID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "Invalid event ID", http.StatusBadRequest)
+				return
+			}
+
+			revisions, err := repo.ListRevisions(r.Context(), eventID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(revisions)
+		},
+	}
+}
+
+func GetEventRevision(repo RevisionRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/events/{id}/revisions/{revision}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			eventID, revision, ok := parseRevisionPath(w, r)
+			if !ok {
+				return
+			}
+
+			output, err := repo.GetRevision(r.Context(), eventID, revision)
+			writeRevisionResult(w, output, err)
+		},
+	}
+}
+
+// DiffEventRevisions compares the configuration of two revisions of an event
+func DiffEventRevisions(repo RevisionRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/events/{id}/revisions/diff",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			eventID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "Invalid event ID", http.StatusBadRequest)
+				return
+			}
+
+			var params RevisionDiffParams
+			if err := queryparser.ParseQueryParams(r.URL.Query(), &params); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if params.From <= 0 || params.To <= 0 {
+				http.Error(w, "from and to revisions are required", http.StatusBadRequest)
+				return
+			}
+
+			from, err := repo.GetRevision(ctx, eventID, params.From)
+			if err != nil {
+				writeRevisionResult(w, nil, err)
+				return
+			}
+
+			to, err := repo.GetRevision(ctx, eventID, params.To)
+			if err != nil {
+				writeRevisionResult(w, nil, err)
+				return
+			}
+
+			writeRevisionResult(w, RevisionDiff{
+				EventID: eventID,
+				From:    params.From,
+				To:      params.To,
+				Changes: domain.DiffEvents(from.Event, to.Event),
+			}, nil)
+		},
+	}
+}
+
+// RollbackEventRevision restores the configuration of an earlier revision,
+// the rollback itself is recorded as a new revision
+func RollbackEventRevision(repo RevisionRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/events/{id}/revisions/{revision}/rollback",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			eventID, revision, ok := parseRevisionPath(w, r)
+			if !ok {
+				return
+			}
+
+			event, err := repo.RollbackEvent(ctx, eventID, revision)
+			if err != nil {
+				ctxlogger.GetLogger(ctx).Error("failed to rollback event", "event_id", eventID, "revision", revision, "error", err)
+			}
+
+			writeRevisionResult(w, event, err)
+		},
+	}
+}
+
+func parseRevisionPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, int, bool) {
+	eventID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid event ID", http.StatusBadRequest)
+		return uuid.Nil, 0, false
+	}
+
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil || revision <= 0 {
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return uuid.Nil, 0, false
+	}
+
+	return eventID, revision, true
+}
+
+func writeRevisionResult(w http.ResponseWriter, output any, err error) {
+	switch {
+	case errors.Is(err, domain.RevisionNotFound), errors.Is(err, domain.EventNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, domain.EventNameConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
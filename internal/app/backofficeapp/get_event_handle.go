@@ -20,6 +20,7 @@ func GetEvent(repo Repository) httpadapter.HttpHandle {
 				return
 			}
 
+			w.Header().Set("ETag", revisionETag(event.Revision))
 			w.WriteHeader(http.StatusOK)
 			if err := json.NewEncoder(w).Encode(event); err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -3,29 +3,54 @@ package backofficeapp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
 	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/IsaacDSC/gqueue/pkg/queryparser"
 )
 
 type InsightsStore interface {
-	GetAll(ctx context.Context) (domain.Metrics, error)
+	GetRange(ctx context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error)
 }
 
+// GetInsightsHandle summarizes the metrics of a time window, the hour before now by default. It
+// accepts from, to, event, consumer and granularity.
 func GetInsightsHandle(store InsightsStore) httpadapter.HttpHandle {
 	return httpadapter.HttpHandle{
 		Path: "/api/v1/insights",
 		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
-			metrics, err := store.GetAll(ctx)
+
+			var filter domain.FilterInsights
+			if err := queryparser.ParseQueryParams(r.URL.Query(), &filter); err != nil {
+				http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			window, err := filter.Window(time.Now())
+			if err != nil {
+				http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			buckets, err := store.GetRange(ctx, window)
+			if errors.Is(err, domain.InsightsNotRetained) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				ctxlogger.GetLogger(ctx).Error("failed to get insights", "error", err)
+				http.Error(w, "failed to get insights", http.StatusInternalServerError)
 				return
 			}
 
 			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(metrics.Insights())
+			json.NewEncoder(w).Encode(buckets.Insights(window))
 		}),
 	}
 }
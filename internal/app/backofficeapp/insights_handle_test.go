@@ -0,0 +1,55 @@
+package backofficeapp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetInsightsHandle(t *testing.T) {
+	t.Run("window of the query", func(t *testing.T) {
+		store := mockbackofficeapp.NewMockInsightsStore(gomock.NewController(t))
+		store.EXPECT().GetRange(gomock.Any(), domain.InsightsWindow{
+			From:        time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC),
+			To:          time.Date(2025, 10, 10, 15, 0, 0, 0, time.UTC),
+			Event:       "payment.processed",
+			Granularity: time.Hour,
+		}).Return(domain.MetricsBuckets{{
+			Start:  time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC),
+			Series: map[domain.Series]domain.SeriesStats{{Event: "payment.processed"}: {Success: 1}},
+		}}, nil)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.GetInsightsHandle(store), http.MethodGet,
+			"/api/v1/insights?from=2025-10-10T14:00:00Z&to=2025-10-10T15:00:00Z&event=payment.processed&granularity=hour", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var insights domain.Insights
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &insights))
+		assert.Equal(t, int64(1), insights.Published.Total)
+		assert.Len(t, insights.Series, 1)
+	})
+
+	t.Run("window no longer retained", func(t *testing.T) {
+		store := mockbackofficeapp.NewMockInsightsStore(gomock.NewController(t))
+		store.EXPECT().GetRange(gomock.Any(), gomock.Any()).Return(nil, domain.InsightsNotRetained)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.GetInsightsHandle(store), http.MethodGet,
+			"/api/v1/insights?from=2024-10-10T14:00:00Z&to=2024-10-10T15:00:00Z", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("invalid granularity", func(t *testing.T) {
+		store := mockbackofficeapp.NewMockInsightsStore(gomock.NewController(t))
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.GetInsightsHandle(store), http.MethodGet, "/api/v1/insights?granularity=week", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,84 @@
+package backofficeapp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/google/uuid"
+)
+
+// PauseEvent holds the deliveries to every consumer of the event, messages wait
+// in their queue until the event is resumed instead of being retried
+func PauseEvent(repo ConsumerRepository) httpadapter.HttpHandle {
+	return setPausedHandle(repo, "POST /api/v1/events/{id}/pause", true)
+}
+
+func ResumeEvent(repo ConsumerRepository) httpadapter.HttpHandle {
+	return setPausedHandle(repo, "POST /api/v1/events/{id}/resume", false)
+}
+
+// PauseConsumer holds the deliveries of the event to a single consumer
+func PauseConsumer(repo ConsumerRepository) httpadapter.HttpHandle {
+	return setPausedHandle(repo, "POST /api/v1/events/{id}/consumers/{consumer}/pause", true)
+}
+
+func ResumeConsumer(repo ConsumerRepository) httpadapter.HttpHandle {
+	return setPausedHandle(repo, "POST /api/v1/events/{id}/consumers/{consumer}/resume", false)
+}
+
+// setPausedHandle changes the pause state like any other update of the event, so it is
+// recorded as a revision and broadcast to every worker
+func setPausedHandle(repo ConsumerRepository, path string, paused bool) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: path,
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			l := ctxlogger.GetLogger(ctx)
+
+			eventID, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "Invalid event ID", http.StatusBadRequest)
+				return
+			}
+
+			event, err := repo.GetEventByID(ctx, eventID)
+			if errors.Is(err, domain.EventNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			consumer := r.PathValue("consumer")
+			if err := event.SetPaused(consumer, paused); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			event, err = repo.UpdateEventIfRevision(ctx, event, event.Revision)
+			if errors.Is(err, domain.EventVersionConflict) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+
+			if err != nil {
+				l.Error("failed to change event pause", "event_id", eventID, "consumer", consumer, "paused", paused, "error", err)
+				http.Error(w, "failed to change event pause", http.StatusInternalServerError)
+				return
+			}
+
+			l.Info("event pause changed", "event_name", event.Name, "consumer", consumer, "paused", paused)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", revisionETag(event.Revision))
+			json.NewEncoder(w).Encode(event)
+		},
+	}
+}
@@ -0,0 +1,242 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+)
+
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project domain.Project) (domain.Project, error)
+	GetProject(ctx context.Context, projectID string) (domain.Project, error)
+	ListProjects(ctx context.Context) ([]domain.Project, error)
+	UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error)
+	DeleteProject(ctx context.Context, projectID string) error
+}
+
+type ProjectRequest struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	TopicPrefix string `json:"topic_prefix"`
+	// SecretKey is stored hashed and never returned. On update, omitting it keeps the current
+	// secret and an empty one removes the credentials of the project.
+	SecretKey *string              `json:"secret_key"`
+	Quotas    domain.ProjectQuotas `json:"quotas"`
+}
+
+type ProjectResponse struct {
+	domain.Project
+	HasCredentials bool `json:"has_credentials"`
+}
+
+func newProjectResponse(project domain.Project) ProjectResponse {
+	return ProjectResponse{Project: project, HasCredentials: project.HasCredentials()}
+}
+
+// OperatorOnly restricts a route to the default project, the routes that manage projects or read
+// the data of every project are not available to the other projects
+func OperatorOnly(handle httpadapter.HttpHandle) httpadapter.HttpHandle {
+	next := handle.Handler
+	handle.Handler = func(w http.ResponseWriter, r *http.Request) {
+		if domain.ProjectFromContext(r.Context()).ID != domain.DefaultProjectID {
+			http.Error(w, "only available to the default project", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+
+	return handle
+}
+
+func GetProjects(repo ProjectRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/projects",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			projects, err := repo.ListProjects(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			output := make([]ProjectResponse, 0, len(projects))
+			for _, project := range projects {
+				output = append(output, newProjectResponse(project))
+			}
+
+			json.NewEncoder(w).Encode(output)
+		},
+	}
+}
+
+func GetProject(repo ProjectRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/projects/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			project, err := repo.GetProject(r.Context(), r.PathValue("id"))
+			if errors.Is(err, domain.ProjectNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			json.NewEncoder(w).Encode(newProjectResponse(project))
+		},
+	}
+}
+
+// CreateProject registers a project, its topic prefix defaults to its id
+func CreateProject(repo ProjectRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/projects",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			l := ctxlogger.GetLogger(ctx)
+
+			var payload ProjectRequest
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			project := domain.Project{
+				ID:          payload.ID,
+				Name:        payload.Name,
+				TopicPrefix: payload.TopicPrefix,
+				Quotas:      payload.Quotas,
+			}
+
+			if project.TopicPrefix == "" {
+				project.TopicPrefix = project.ID
+			}
+
+			if payload.SecretKey != nil {
+				project.SetSecret(*payload.SecretKey)
+			}
+
+			if err := project.Validate(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			created, err := repo.CreateProject(ctx, project)
+			if errors.Is(err, domain.ProjectAlreadyExists) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+
+			if err != nil {
+				l.Error("failed to create project", "project_id", project.ID, "error", err)
+				http.Error(w, "failed to create project", http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(newProjectResponse(created))
+		},
+	}
+}
+
+// UpdateProject changes the name, secret and quotas of a project, its topic prefix cannot change
+func UpdateProject(repo ProjectRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "PUT /api/v1/projects/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			l := ctxlogger.GetLogger(ctx)
+
+			var payload ProjectRequest
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			project, err := repo.GetProject(ctx, r.PathValue("id"))
+			if errors.Is(err, domain.ProjectNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			if payload.TopicPrefix != "" && payload.TopicPrefix != project.TopicPrefix {
+				http.Error(w, "topic_prefix cannot be changed", http.StatusBadRequest)
+				return
+			}
+
+			if payload.Name != "" {
+				project.Name = payload.Name
+			}
+
+			if payload.SecretKey != nil {
+				project.SetSecret(*payload.SecretKey)
+			}
+
+			project.Quotas = payload.Quotas
+			if err := project.Validate(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			updated, err := repo.UpdateProject(ctx, project)
+			if errors.Is(err, domain.ProjectNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				l.Error("failed to update project", "project_id", project.ID, "error", err)
+				http.Error(w, "failed to update project", http.StatusInternalServerError)
+				return
+			}
+
+			json.NewEncoder(w).Encode(newProjectResponse(updated))
+		},
+	}
+}
+
+// DeleteProject removes a project whose events were all removed, the default project is kept
+func DeleteProject(repo ProjectRepository) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "DELETE /api/v1/projects/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			projectID := r.PathValue("id")
+
+			if projectID == domain.DefaultProjectID {
+				http.Error(w, "the default project cannot be deleted", http.StatusBadRequest)
+				return
+			}
+
+			err := repo.DeleteProject(ctx, projectID)
+			switch {
+			case errors.Is(err, domain.ProjectNotFound):
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			case errors.Is(err, domain.ProjectNotEmpty):
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			case err != nil:
+				ctxlogger.GetLogger(ctx).Error("failed to delete project", "project_id", projectID, "error", err)
+				http.Error(w, "failed to delete project", http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+}
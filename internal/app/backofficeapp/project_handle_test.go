@@ -0,0 +1,132 @@
+package backofficeapp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// serveAs serves the request in the project, like the project middleware does
+func serveAs(project domain.Project, handle httpadapter.HttpHandle, method, target, body string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc(handle.Path, handle.Handler)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req = req.WithContext(domain.WithProject(req.Context(), project))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestProjectHandles(t *testing.T) {
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme"}
+
+	t.Run("create project hashes the secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockProjectRepository(ctrl)
+		repo.EXPECT().CreateProject(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, project domain.Project) (domain.Project, error) {
+				assert.Equal(t, "acme", project.TopicPrefix, "the topic prefix defaults to the id")
+				assert.True(t, project.CheckSecret("s3cret"))
+				return project, nil
+			})
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.OperatorOnly(backofficeapp.CreateProject(repo)),
+			http.MethodPost, "/api/v1/projects", `{"id":"acme","name":"Acme","secret_key":"s3cret"}`)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "s3cret")
+
+		var output map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &output))
+		assert.Equal(t, true, output["has_credentials"])
+		assert.NotContains(t, output, "secret_hash")
+	})
+
+	t.Run("invalid project id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockProjectRepository(ctrl)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.CreateProject(repo),
+			http.MethodPost, "/api/v1/projects", `{"id":"Acme Corp"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("topic prefix cannot change", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockProjectRepository(ctrl)
+		repo.EXPECT().GetProject(gomock.Any(), "acme").Return(acme, nil)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.UpdateProject(repo),
+			http.MethodPut, "/api/v1/projects/acme", `{"topic_prefix":"acme-v2"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("project with events cannot be deleted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockProjectRepository(ctrl)
+		repo.EXPECT().DeleteProject(gomock.Any(), "acme").Return(domain.ProjectNotEmpty)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.DeleteProject(repo),
+			http.MethodDelete, "/api/v1/projects/acme", "")
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("only the default project manages projects", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockProjectRepository(ctrl)
+
+		rec := serveAs(acme, backofficeapp.OperatorOnly(backofficeapp.GetProjects(repo)),
+			http.MethodGet, "/api/v1/projects", "")
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestProjectQuotas(t *testing.T) {
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme", Quotas: domain.ProjectQuotas{MaxEvents: 1, MaxConsumers: 1}}
+
+	t.Run("consumers per event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		eventID := uuid.New()
+		repo := mockbackofficeapp.NewMockConsumerRepository(ctrl)
+		repo.EXPECT().GetEventByID(gomock.Any(), eventID).Return(domain.Event{
+			ID:        eventID,
+			Name:      "payment.processed",
+			Option:    domain.Opt{WqType: "low_throughput"},
+			Consumers: []domain.Consumer{{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"}},
+		}, nil)
+
+		rec := serveAs(acme, backofficeapp.AddEventConsumer(repo), http.MethodPost,
+			"/api/v1/events/"+eventID.String()+"/consumers", `{"service_name":"ledger","host":"http://ledger","path":"/webhook"}`)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("events per project", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mockbackofficeapp.NewMockRepository(ctrl)
+		repo.EXPECT().GetInternalEvent(gomock.Any(), "order.created").Return(domain.Event{}, domain.EventNotFound)
+		repo.EXPECT().GetInternalEvents(gomock.Any(), domain.FilterEvents{}).Return([]domain.Event{{Name: "payment.processed"}}, nil)
+
+		rec := serveAs(acme, backofficeapp.SaveConsumerHandle(repo), http.MethodPut, "/api/v1/event/consumer",
+			`{"name":"order.created","type":"external","option":{"wq_type":"low_throughput"},"consumers":[{"service_name":"billing","host":"http://billing","path":"/webhook"}]}`)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -2,6 +2,7 @@ package backofficeapp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -17,6 +18,8 @@ type EventDto struct {
 	Type      domain.Type       `json:"type"`
 	Option    domain.Opt        `json:"option" bson:"option"`
 	Consumers []domain.Consumer `json:"consumers"`
+	Schema    json.RawMessage   `json:"schema,omitempty"`
+	domain.Owner
 }
 
 func (e *EventDto) ToDomain() domain.Event {
@@ -29,6 +32,8 @@ func (e *EventDto) ToDomain() domain.Event {
 		Type:        e.Type,
 		Option:      e.Option,
 		Consumers:   e.Consumers,
+		Owner:       e.Owner,
+		Schema:      e.Schema,
 	}
 }
 
@@ -52,6 +57,34 @@ func SaveConsumerHandle(repo Repository) httpadapter.HttpHandle {
 				return
 			}
 
+			quotas := domain.ProjectFromContext(ctx).Quotas
+			if err := quotas.CheckConsumers(len(event.Consumers)); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+
+			// the store keeps the pauses of an existing event, the lookup only finds out if it is new
+			_, err := repo.GetInternalEvent(ctx, event.Name)
+			if err != nil && !errors.Is(err, domain.EventNotFound) {
+				l.Error("failed to get event", "error", err)
+				http.Error(w, "failed to save consumer", http.StatusInternalServerError)
+				return
+			}
+
+			if err != nil && quotas.MaxEvents > 0 {
+				events, err := repo.GetInternalEvents(ctx, domain.FilterEvents{})
+				if err != nil {
+					l.Error("failed to count events", "error", err)
+					http.Error(w, "failed to save consumer", http.StatusInternalServerError)
+					return
+				}
+
+				if err := quotas.CheckEvents(len(events) + 1); err != nil {
+					http.Error(w, err.Error(), http.StatusForbidden)
+					return
+				}
+			}
+
 			if err := repo.Upsert(ctx, event); err != nil {
 				l.Error("failed to save consumer", "error", err)
 				http.Error(w, "failed to save consumer", http.StatusInternalServerError)
@@ -0,0 +1,60 @@
+package backofficeapp_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSaveConsumerHandle(t *testing.T) {
+	project := domain.Project{ID: "acme", TopicPrefix: "acme"}
+	body := `{"name":"order.created","type":"external","option":{"wq_type":"low_throughput"},"consumers":[{"service_name":"billing","host":"http://billing","path":"/webhook","paused":true}]}`
+
+	tests := []struct {
+		name       string
+		setupMock  func(repo *mockbackofficeapp.MockRepository)
+		wantStatus int
+	}{
+		{
+			name: "saves an existing event",
+			setupMock: func(repo *mockbackofficeapp.MockRepository) {
+				repo.EXPECT().GetInternalEvent(gomock.Any(), "order.created").Return(domain.Event{Name: "order.created"}, nil)
+				repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "saves a new event",
+			setupMock: func(repo *mockbackofficeapp.MockRepository) {
+				repo.EXPECT().GetInternalEvent(gomock.Any(), "order.created").Return(domain.Event{}, domain.EventNotFound)
+				repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "does not save when the event can't be read",
+			setupMock: func(repo *mockbackofficeapp.MockRepository) {
+				repo.EXPECT().GetInternalEvent(gomock.Any(), "order.created").Return(domain.Event{}, errors.New("connection refused"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mockbackofficeapp.NewMockRepository(ctrl)
+			tt.setupMock(repo)
+
+			rec := serveAs(project, backofficeapp.SaveConsumerHandle(repo), http.MethodPut, "/api/v1/event/consumer", body)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/IsaacDSC/gqueue/internal/registry"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+)
+
+type RegistryStatusReader interface {
+	Status(ctx context.Context) (registry.Status, error)
+}
+
+// GetRegistryStatusHandle reports the registry version applied by every instance, so operators
+// can confirm a change reached all of them.
+func GetRegistryStatusHandle(reader RegistryStatusReader) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/registry/status",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			status, err := reader.Status(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(status)
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/replay"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+	"github.com/google/uuid"
+)
+
+type ReplayJobManager interface {
+	Start(ctx context.Context, filter replay.Filter, ratePerSecond float64) (replay.Job, error)
+	Get(ctx context.Context, id uuid.UUID) (replay.Job, error)
+	List(ctx context.Context) ([]replay.Job, error)
+	Cancel(ctx context.Context, id uuid.UUID) (replay.Job, error)
+}
+
+type CreateReplayJobRequest struct {
+	EventName  string    `json:"event_name"`
+	Consumer   string    `json:"consumer"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	ErrorClass string    `json:"error_class"`
+	// Sources limits the stores searched: dead_letter, asynq or durable_sql
+	Sources []string `json:"sources"`
+	// RatePerSecond limits the replay throughput, zero means unlimited
+	RatePerSecond float64 `json:"rate_per_second"`
+}
+
+// CreateReplayJob redelivers the failed deliveries of a consumer in a time window,
+// the job runs in the background and its progress is read from GET /api/v1/replays/{id}
+func CreateReplayJob(manager ReplayJobManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/replays",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+
+			var payload CreateReplayJobRequest
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			filter := replay.Filter{
+				EventName:  payload.EventName,
+				Consumer:   payload.Consumer,
+				From:       payload.From,
+				To:         payload.To,
+				ErrorClass: replay.ErrorClass(payload.ErrorClass),
+				Sources:    payload.Sources,
+			}
+
+			job, err := manager.Start(r.Context(), filter, payload.RatePerSecond)
+			if errors.Is(err, replay.ErrInvalidJob) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(job)
+		},
+	}
+}
+
+func GetReplayJobs(manager ReplayJobManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/replays",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			jobs, err := manager.List(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(jobs)
+		},
+	}
+}
+
+func GetReplayJob(manager ReplayJobManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/replays/{id}",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			id, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "Invalid replay job ID", http.StatusBadRequest)
+				return
+			}
+
+			job, err := manager.Get(r.Context(), id)
+			writeReplayJob(w, job, err, http.StatusOK)
+		},
+	}
+}
+
+func CancelReplayJob(manager ReplayJobManager) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "POST /api/v1/replays/{id}/cancel",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			id, err := uuid.Parse(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, "Invalid replay job ID", http.StatusBadRequest)
+				return
+			}
+
+			job, err := manager.Cancel(r.Context(), id)
+			writeReplayJob(w, job, err, http.StatusAccepted)
+		},
+	}
+}
+
+func writeReplayJob(w http.ResponseWriter, job replay.Job, err error, status int) {
+	switch {
+	case errors.Is(err, replay.ErrJobNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, replay.ErrJobFinished):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,48 @@
+package backofficeapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
+)
+
+type TimelineStore interface {
+	Get(ctx context.Context, messageID string) ([]domain.TimelineEntry, error)
+}
+
+type MessageTimeline struct {
+	MessageID string                 `json:"message_id"`
+	Entries   []domain.TimelineEntry `json:"entries"`
+}
+
+// GetMessageTimeline returns what happened to a published message, from its publication to its
+// deliveries, retries, dead letter and replays. The id is the one answered by the publish API.
+func GetMessageTimeline(store TimelineStore) httpadapter.HttpHandle {
+	return httpadapter.HttpHandle{
+		Path: "GET /api/v1/messages/{id}/timeline",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			id := r.PathValue("id")
+
+			entries, err := store.Get(ctx, id)
+			if errors.Is(err, domain.TimelineNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			if err != nil {
+				ctxlogger.GetLogger(ctx).Error("failed to get timeline", "message_id", id, "error", err)
+				http.Error(w, "failed to get timeline", http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(MessageTimeline{MessageID: id, Entries: entries})
+		},
+	}
+}
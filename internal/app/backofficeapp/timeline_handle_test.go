@@ -0,0 +1,52 @@
+package backofficeapp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/app/backofficeapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbackofficeapp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetMessageTimeline(t *testing.T) {
+	t.Run("entries of the message", func(t *testing.T) {
+		accepted := time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC)
+		store := mockbackofficeapp.NewMockTimelineStore(gomock.NewController(t))
+		store.EXPECT().Get(gomock.Any(), "msg-1").Return([]domain.TimelineEntry{
+			{MessageID: "msg-1", Stage: domain.StageAccepted, At: accepted, EventName: "payment.processed"},
+			{MessageID: "msg-1", Stage: domain.StageEnqueued, At: accepted, EventName: "payment.processed", Consumer: "billing"},
+		}, nil)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.GetMessageTimeline(store), http.MethodGet, "/api/v1/messages/msg-1/timeline", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var timeline backofficeapp.MessageTimeline
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &timeline))
+		assert.Equal(t, "msg-1", timeline.MessageID)
+		require.Len(t, timeline.Entries, 2)
+		assert.Equal(t, "billing", timeline.Entries[1].Consumer)
+	})
+
+	t.Run("unknown or expired message", func(t *testing.T) {
+		store := mockbackofficeapp.NewMockTimelineStore(gomock.NewController(t))
+		store.EXPECT().Get(gomock.Any(), "msg-1").Return(nil, domain.TimelineNotFound)
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.GetMessageTimeline(store), http.MethodGet, "/api/v1/messages/msg-1/timeline", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		store := mockbackofficeapp.NewMockTimelineStore(gomock.NewController(t))
+		store.EXPECT().Get(gomock.Any(), "msg-1").Return(nil, errors.New("connection refused"))
+
+		rec := serveAs(domain.DefaultProject(), backofficeapp.GetMessageTimeline(store), http.MethodGet, "/api/v1/messages/msg-1/timeline", "")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
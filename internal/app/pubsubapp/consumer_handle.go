@@ -2,6 +2,7 @@ package pubsubapp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,8 +12,8 @@ import (
 	"github.com/IsaacDSC/gqueue/pkg/asyncadapter"
 	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
 	"github.com/IsaacDSC/gqueue/pkg/telemetry"
-	"github.com/IsaacDSC/gqueue/pkg/topicutils"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Fetcher interface {
@@ -23,19 +24,32 @@ type ConsumerInsights interface {
 	Consumed(ctx context.Context, input domain.ConsumerMetric) error
 }
 
-func GetRequestHandle(fetch Fetcher, insights ConsumerInsights) asyncadapter.Handle[RequestPayload] {
+type PauseChecker interface {
+	DeliveryPaused(ctx context.Context, eventName, serviceName string) bool
+}
+
+type QuarantineChecker interface {
+	Quarantined(ctx context.Context, eventName, serviceName string) bool
+}
+
+func GetRequestHandle(fetch Fetcher, insights ConsumerInsights, pauses PauseChecker, quarantines QuarantineChecker, timeline TimelineRecorder) asyncadapter.Handle[RequestPayload] {
 
 	insertInsights := func(ctx context.Context, payload RequestPayload, started time.Time, isSuccess bool) {
 		l := ctxlogger.GetLogger(ctx)
 		finished := time.Now()
-		if err := insights.Consumed(ctx, domain.ConsumerMetric{
+		metric := domain.ConsumerMetric{
 			TopicName:      payload.EventName,
 			ConsumerName:   payload.Consumer.ServiceName,
 			TimeStarted:    started,
 			TimeEnded:      finished,
 			TimeDurationMs: finished.Sub(started).Milliseconds(),
 			ACK:            isSuccess,
-		}); err != nil {
+		}
+		if payload.PublishedAt > 0 {
+			metric.PublishedAt = time.UnixMilli(payload.PublishedAt)
+		}
+
+		if err := insights.Consumed(ctx, metric); err != nil {
 			l.Warn("not save metric", "type", "consumer", "error", err.Error())
 		}
 
@@ -43,7 +57,7 @@ func GetRequestHandle(fetch Fetcher, insights ConsumerInsights) asyncadapter.Han
 
 	return asyncadapter.Handle[RequestPayload]{
 		EventName: domain.EventQueueRequestToExternal,
-		Handler: func(c asyncadapter.AsyncCtx[RequestPayload]) error {
+		Handler: func(c asyncadapter.AsyncCtx[RequestPayload]) (err error) {
 			started := time.Now()
 			ctx := c.Context()
 
@@ -52,6 +66,22 @@ func GetRequestHandle(fetch Fetcher, insights ConsumerInsights) asyncadapter.Han
 				return fmt.Errorf("get payload: %w", err)
 			}
 
+			ctx, span := startDelivery(ctx, payload, c.Retry())
+			defer func() {
+				endDelivery(span, err)
+				recordAttempt(ctx, timeline, payload, c, started, err)
+			}()
+
+			// the payload carries the consumer as it was when published, the pause state comes from the registry
+			if pauses.DeliveryPaused(ctx, payload.EventName, payload.Consumer.ServiceName) {
+				return fmt.Errorf("deliver to %s: %w", payload.Consumer.ServiceName, domain.DeliveryPaused)
+			}
+
+			// a quarantined consumer is probed by the backoffice, its messages are parked meanwhile
+			if quarantines.Quarantined(ctx, payload.EventName, payload.Consumer.ServiceName) {
+				return fmt.Errorf("deliver to %s: %w", payload.Consumer.ServiceName, domain.DeliveryQuarantined)
+			}
+
 			headers := payload.mergeHeaders(payload.Consumer.Headers)
 			if err := fetch.Notify(ctx, payload.Data, headers, payload.Consumer, notifyopt.HighThroughput); err != nil {
 				insertInsights(ctx, payload, started, false)
@@ -61,7 +91,7 @@ func GetRequestHandle(fetch Fetcher, insights ConsumerInsights) asyncadapter.Han
 
 			publishedTime := time.UnixMilli(payload.PublishedAt)
 			lag := started.Sub(publishedTime).Seconds()
-			topic := topicutils.BuildTopicName(domain.ProjectID, domain.EventQueueRequestToExternal)
+			topic := domain.ProjectFromContext(ctx).TopicName(domain.EventQueueRequestToExternal)
 			telemetry.PubSubConsumerLagSeconds.Record(ctx, lag,
 				attribute.String("topic", topic),
 				attribute.String("consumer.service_name", payload.Consumer.ServiceName))
@@ -95,3 +125,69 @@ func recordDuration(ctx context.Context, started time.Time, payload RequestPaylo
 		attrs...,
 	)
 }
+
+// startDelivery starts the span of an attempt to deliver the payload in the trace of its
+// publication, the attempts after the first one are retries
+func startDelivery(ctx context.Context, payload RequestPayload, retry int) (context.Context, trace.Span) {
+	name := "gqueue.deliver"
+	if retry > 0 {
+		name = "gqueue.retry"
+	}
+
+	ctx = telemetry.ExtractMap(ctx, payload.TraceContext)
+	return telemetry.StartSpan(ctx, name, trace.SpanKindConsumer,
+		attribute.String("gqueue.event", payload.EventName),
+		attribute.String("gqueue.consumer", payload.Consumer.ServiceName),
+		attribute.Int("gqueue.retry", retry),
+	)
+}
+
+// endDelivery ends the span of the attempt, a paused or quarantined delivery is deferred and not
+// failed
+func endDelivery(span trace.Span, err error) {
+	if errors.Is(err, domain.DeliveryQuarantined) {
+		span.SetAttributes(attribute.Bool("gqueue.quarantined", true))
+	}
+
+	if errors.Is(err, domain.DeliveryPaused) {
+		span.SetAttributes(attribute.Bool("gqueue.paused", true))
+		err = nil
+	}
+
+	telemetry.EndSpan(span, err)
+}
+
+// recordAttempt adds the attempt to the timeline of the message. A failed attempt is followed by a
+// retry unless it was the last one, the dead letter handler records the message then.
+func recordAttempt(ctx context.Context, timeline TimelineRecorder, payload RequestPayload, c asyncadapter.AsyncCtx[RequestPayload], started time.Time, err error) {
+	if errors.Is(err, domain.DeliveryPaused) {
+		return
+	}
+
+	entry := domain.TimelineEntry{
+		MessageID:  payload.MessageID,
+		Stage:      domain.StageDeliveryAttempt,
+		EventName:  payload.EventName,
+		Consumer:   payload.Consumer.ServiceName,
+		Attempt:    c.Retry() + 1,
+		Outcome:    domain.OutcomeSuccess,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+
+	if err != nil {
+		entry.Outcome = domain.OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	timeline.Record(ctx, entry)
+
+	if err != nil && !c.LastAttempt() {
+		timeline.Record(ctx, domain.TimelineEntry{
+			MessageID: payload.MessageID,
+			Stage:     domain.StageRetryScheduled,
+			EventName: payload.EventName,
+			Consumer:  payload.Consumer.ServiceName,
+			Attempt:   c.Retry() + 2,
+		})
+	}
+}
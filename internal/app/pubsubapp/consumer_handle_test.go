@@ -12,6 +12,8 @@ import (
 	"github.com/IsaacDSC/gqueue/internal/app/pubsubapp"
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/internal/notifyopt"
+	"github.com/IsaacDSC/gqueue/internal/quarantine"
+	"github.com/IsaacDSC/gqueue/internal/timeline"
 	"github.com/IsaacDSC/gqueue/mocks/mockpubsubapp"
 	"github.com/IsaacDSC/gqueue/pkg/asyncadapter"
 	"github.com/stretchr/testify/assert"
@@ -36,7 +38,7 @@ func TestGetRequestHandle(t *testing.T) {
 
 		mockFetch := mockpubsubapp.NewMockFetcher(ctrl)
 		mockInsights := mockpubsubapp.NewMockConsumerInsights(ctrl)
-		handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights)
+		handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 		assert.Equal(t, "event-queue.request-to-external", handle.EventName)
 		assert.NotNil(t, handle.Handler)
@@ -133,7 +135,7 @@ func TestGetRequestHandle_Handler(t *testing.T) {
 			}
 
 			// Get the handler
-			handle := pubsubapp.GetRequestHandle(mockFetcher, mockInsights)
+			handle := pubsubapp.GetRequestHandle(mockFetcher, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 			// Create task payload
 			taskPayload, err := json.Marshal(tt.payload)
@@ -163,7 +165,7 @@ func TestGetRequestHandle_Handler_InvalidPayload(t *testing.T) {
 
 	mockFetch := mockpubsubapp.NewMockFetcher(ctrl)
 	mockInsights := mockpubsubapp.NewMockConsumerInsights(ctrl)
-	handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights)
+	handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 	// Create AsyncCtx wrapper with invalid payload
 	asyncCtx := asyncadapter.NewAsyncCtx[pubsubapp.RequestPayload](context.Background(), []byte("invalid json"))
@@ -205,7 +207,7 @@ func TestGetRequestHandle_Handler_WithPublishedAtInBody_RecordsLag(t *testing.T)
 		Return(nil).Times(1)
 	mockInsights.EXPECT().Consumed(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-	handle := pubsubapp.GetRequestHandle(mockFetcher, mockInsights)
+	handle := pubsubapp.GetRequestHandle(mockFetcher, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 	asyncCtx := asyncadapter.NewAsyncCtx[pubsubapp.RequestPayload](context.Background(), taskPayload)
 
 	err = handle.Handler(asyncCtx)
@@ -308,7 +310,7 @@ func TestRequestPayload_mergeHeaders_Integration(t *testing.T) {
 				tt.setupMocks(mockInsights)
 			}
 
-			handle := pubsubapp.GetRequestHandle(mockFetcher, mockInsights)
+			handle := pubsubapp.GetRequestHandle(mockFetcher, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 			payload := pubsubapp.RequestPayload{
 				EventName: "user.created",
@@ -374,7 +376,7 @@ func TestMockFetcher_ErrorScenarios(t *testing.T) {
 				Return(tt.mockError).
 				Times(1)
 
-			handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights)
+			handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 			payload := pubsubapp.RequestPayload{
 				EventName: "user.created",
@@ -428,7 +430,7 @@ func TestGetRequestHandle_HeaderMerging(t *testing.T) {
 		}).
 		Times(1)
 
-	handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights)
+	handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 	payload := pubsubapp.RequestPayload{
 		EventName: "user.created",
@@ -494,7 +496,7 @@ func TestGetRequestHandle_DataPassing(t *testing.T) {
 		}).
 		Times(1)
 
-	handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights)
+	handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 	expectedData := map[string]any{
 		"user_id":   "123",
@@ -531,3 +533,56 @@ func TestGetRequestHandle_DataPassing(t *testing.T) {
 	assert.Equal(t, float64(42), receivedData["count"]) // JSON numbers are float64
 	assert.Equal(t, true, receivedData["is_active"])
 }
+
+func TestGetRequestHandle_Handler_DeliveryPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetch := mockpubsubapp.NewMockFetcher(ctrl)
+	mockInsights := mockpubsubapp.NewMockConsumerInsights(ctrl)
+	pauses := mockpubsubapp.NewMockPauseChecker(ctrl)
+	pauses.EXPECT().DeliveryPaused(gomock.Any(), "payment.processed", "billing").Return(true)
+
+	handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights, pauses, quarantine.Never, timeline.Discard)
+
+	payload, err := json.Marshal(pubsubapp.RequestPayload{
+		EventName: "payment.processed",
+		Consumer:  domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+		Data:      map[string]any{"id": "1"},
+	})
+	require.NoError(t, err)
+
+	// the consumer is not called and no failure is recorded
+	err = handle.Handler(asyncadapter.NewAsyncCtx[pubsubapp.RequestPayload](context.Background(), payload))
+	assert.ErrorIs(t, err, domain.DeliveryPaused)
+}
+
+func TestGetRequestHandle_Handler_DeliveryQuarantined(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetch := mockpubsubapp.NewMockFetcher(ctrl)
+	mockInsights := mockpubsubapp.NewMockConsumerInsights(ctrl)
+	quarantines := mockpubsubapp.NewMockQuarantineChecker(ctrl)
+	quarantines.EXPECT().Quarantined(gomock.Any(), "payment.processed", "billing").Return(true)
+
+	handle := pubsubapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantines, timeline.Discard)
+
+	payload, err := json.Marshal(pubsubapp.RequestPayload{
+		EventName: "payment.processed",
+		Consumer:  domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+		Data:      map[string]any{"id": "1"},
+	})
+	require.NoError(t, err)
+
+	// the message is parked like a paused one, without calling the consumer
+	err = handle.Handler(asyncadapter.NewAsyncCtx[pubsubapp.RequestPayload](context.Background(), payload))
+	assert.ErrorIs(t, err, domain.DeliveryQuarantined)
+	assert.ErrorIs(t, err, domain.DeliveryPaused)
+}
+
+func notPaused(ctrl *gomock.Controller) *mockpubsubapp.MockPauseChecker {
+	pauses := mockpubsubapp.NewMockPauseChecker(ctrl)
+	pauses.EXPECT().DeliveryPaused(gomock.Any(), gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	return pauses
+}
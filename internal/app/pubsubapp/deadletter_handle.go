@@ -2,21 +2,30 @@ package pubsubapp
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/internal/notifyopt"
 	"github.com/IsaacDSC/gqueue/pkg/asyncadapter"
 	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/google/uuid"
 )
 
 type DeadLetterStore interface {
+	GetEvent(ctx context.Context, eventName string) (domain.Event, error)
 	GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error)
 }
 
-func NewDeadLatterQueue(store DeadLetterStore, fetcher Fetcher) asyncadapter.Handle[pubsub.Message] {
+type DeadLetterRecorder interface {
+	Save(ctx context.Context, dl domain.DeadLetter) error
+}
+
+func NewDeadLatterQueue(store DeadLetterStore, fetcher Fetcher, recorder DeadLetterRecorder, timeline TimelineRecorder) asyncadapter.Handle[pubsub.Message] {
 	return asyncadapter.Handle[pubsub.Message]{
 		EventName: domain.EventQueueDeadLetter,
 		Handler: func(c asyncadapter.AsyncCtx[pubsub.Message]) error {
@@ -27,6 +36,31 @@ func NewDeadLatterQueue(store DeadLetterStore, fetcher Fetcher) asyncadapter.Han
 				return fmt.Errorf("failed to get payload: %w", err)
 			}
 
+			dl := newDeadLetter(p)
+			// the owners are resolved now so the notification reaches the team of the event
+			if dl.EventName != "" {
+				if event, err := store.GetEvent(ctx, dl.EventName); err == nil {
+					dl.SetOwners(event)
+				}
+			}
+
+			// persisted before notifying, so the message can be inspected and replayed from the backoffice
+			if err := recorder.Save(ctx, dl); err != nil {
+				l.Error("Error saving dead letter", "message_id", p.ID, "error", err)
+				return fmt.Errorf("failed to save dead letter: %w", err)
+			}
+
+			// the message id of the timeline is the one of the publication, not the Pub/Sub one
+			var request RequestPayload
+			_ = json.Unmarshal(dl.Payload, &request)
+			timeline.Record(ctx, domain.TimelineEntry{
+				MessageID: request.MessageID,
+				Stage:     domain.StageDeadLettered,
+				EventName: dl.EventName,
+				Consumer:  dl.Consumer.ServiceName,
+				Error:     dl.LastError,
+			})
+
 			// TODO: realizar um filtro por eventName para evitar
 			events, err := store.GetAllSchedulers(ctx, "archived")
 			if errors.Is(err, domain.EventNotFound) {
@@ -46,6 +80,13 @@ func NewDeadLatterQueue(store DeadLetterStore, fetcher Fetcher) asyncadapter.Han
 						"data":     p.Data,
 						"metadata": p.Attributes,
 						"event_at": p.PublishTime,
+						"dead_letter": map[string]any{
+							"event_name":     dl.EventName,
+							"consumer":       dl.Consumer.ServiceName,
+							"last_error":     dl.LastError,
+							"event_owner":    dl.EventOwner,
+							"consumer_owner": dl.Consumer.Owner,
+						},
 					}, consumer.Headers, domain.Consumer{
 						ServiceName: consumer.ServiceName,
 						BaseUrl:     consumer.BaseUrl,
@@ -59,3 +100,35 @@ func NewDeadLatterQueue(store DeadLetterStore, fetcher Fetcher) asyncadapter.Han
 		},
 	}
 }
+
+// newDeadLetter extracts the original request from the dead-lettered message.
+// The last error is the one retryable stored in the "msg" attribute.
+func newDeadLetter(msg pubsub.Message) domain.DeadLetter {
+	var request RequestPayload
+	_ = json.Unmarshal(msg.Data, &request)
+
+	payload := json.RawMessage(msg.Data)
+	if !json.Valid(msg.Data) {
+		payload, _ = json.Marshal(string(msg.Data))
+	}
+
+	publishedAt := msg.PublishTime
+	if request.PublishedAt > 0 {
+		publishedAt = time.UnixMilli(request.PublishedAt)
+	}
+
+	retryCount, _ := strconv.Atoi(msg.Attributes["retry_count"])
+
+	return domain.DeadLetter{
+		ID:          uuid.New(),
+		MessageID:   msg.ID,
+		EventName:   request.EventName,
+		Consumer:    request.Consumer,
+		Payload:     payload,
+		Attributes:  msg.Attributes,
+		LastError:   msg.Attributes["msg"],
+		RetryCount:  retryCount,
+		PublishedAt: publishedAt,
+		DeadAt:      time.Now(),
+	}
+}
@@ -11,8 +11,12 @@ import (
 	"github.com/IsaacDSC/gqueue/internal/cfg"
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/internal/notifyopt"
+	"github.com/IsaacDSC/gqueue/internal/timeline"
+	"github.com/IsaacDSC/gqueue/mocks/mockpubadapter"
 	"github.com/IsaacDSC/gqueue/mocks/mockpubsubapp"
 	"github.com/IsaacDSC/gqueue/pkg/asyncadapter"
+	"github.com/IsaacDSC/gqueue/pkg/pubadapter"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -32,7 +36,9 @@ func TestNewDeadLatterQueue(t *testing.T) {
 		mockStore := mockpubsubapp.NewMockDeadLetterStore(ctrl)
 		mockFetcher := mockpubsubapp.NewMockFetcher(ctrl)
 
-		handle := NewDeadLatterQueue(mockStore, mockFetcher)
+		mockRecorder := newSavingRecorder(ctrl)
+
+		handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 		assert.Equal(t, domain.EventQueueDeadLetter, handle.EventName)
 		assert.NotNil(t, handle.Handler)
@@ -40,7 +46,7 @@ func TestNewDeadLatterQueue(t *testing.T) {
 
 	t.Run("constructor_accepts_nil_dependencies", func(t *testing.T) {
 		// Test that constructor doesn't panic with nil dependencies
-		handle := NewDeadLatterQueue(nil, nil)
+		handle := NewDeadLatterQueue(nil, nil, nil, timeline.Discard)
 
 		assert.Equal(t, domain.EventQueueDeadLetter, handle.EventName)
 		assert.NotNil(t, handle.Handler)
@@ -52,8 +58,8 @@ func TestNewDeadLatterQueue(t *testing.T) {
 		mockStore2 := mockpubsubapp.NewMockDeadLetterStore(ctrl)
 		mockFetcher2 := mockpubsubapp.NewMockFetcher(ctrl)
 
-		handle1 := NewDeadLatterQueue(mockStore1, mockFetcher1)
-		handle2 := NewDeadLatterQueue(mockStore2, mockFetcher2)
+		handle1 := NewDeadLatterQueue(mockStore1, mockFetcher1, mockpubsubapp.NewMockDeadLetterRecorder(ctrl), timeline.Discard)
+		handle2 := NewDeadLatterQueue(mockStore2, mockFetcher2, mockpubsubapp.NewMockDeadLetterRecorder(ctrl), timeline.Discard)
 
 		// Both should have same event name but different handler instances
 		assert.Equal(t, handle1.EventName, handle2.EventName)
@@ -139,7 +145,9 @@ func TestDeadLetterQueue_Handler_Success(t *testing.T) {
 		}).
 		Times(3)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Marshal pubsub message for AsyncCtx
 	messageBytes, err := json.Marshal(pubsubMessage)
@@ -220,7 +228,9 @@ func TestDeadLetterQueue_Handler_EventNotFound(t *testing.T) {
 
 	mockFetcher := mockpubsubapp.NewMockFetcher(ctrl)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Marshal pubsub message for AsyncCtx
 	messageBytes, err := json.Marshal(pubsubMessage)
@@ -254,7 +264,9 @@ func TestDeadLetterQueue_Handler_StoreError(t *testing.T) {
 
 	mockFetcher := mockpubsubapp.NewMockFetcher(ctrl)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Marshal pubsub message for AsyncCtx
 	messageBytes, err := json.Marshal(pubsubMessage)
@@ -275,7 +287,9 @@ func TestDeadLetterQueue_Handler_InvalidPayload(t *testing.T) {
 	mockStore := mockpubsubapp.NewMockDeadLetterStore(ctrl)
 	mockFetcher := mockpubsubapp.NewMockFetcher(ctrl)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Create AsyncCtx with invalid JSON payload
 	asyncCtx := asyncadapter.NewAsyncCtx[pubsub.Message](context.Background(), []byte("invalid json"))
@@ -304,7 +318,9 @@ func TestDeadLetterQueue_Handler_EmptyEvents(t *testing.T) {
 
 	mockFetcher := mockpubsubapp.NewMockFetcher(ctrl)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Marshal pubsub message for AsyncCtx
 	messageBytes, err := json.Marshal(pubsubMessage)
@@ -344,7 +360,9 @@ func TestDeadLetterQueue_Handler_EventsWithNoConsumers(t *testing.T) {
 
 	mockFetcher := mockpubsubapp.NewMockFetcher(ctrl)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Marshal pubsub message for AsyncCtx
 	messageBytes, err := json.Marshal(pubsubMessage)
@@ -397,7 +415,9 @@ func TestDeadLetterQueue_Handler_FetcherError(t *testing.T) {
 		Return(fetcherError).
 		Times(1)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Marshal pubsub message for AsyncCtx
 	messageBytes, err := json.Marshal(pubsubMessage)
@@ -481,7 +501,9 @@ func TestDeadLetterQueue_Handler_MultipleEventsWithMixedConsumers(t *testing.T)
 		}).
 		Times(3)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Marshal pubsub message for AsyncCtx
 	messageBytes, err := json.Marshal(pubsubMessage)
@@ -540,7 +562,9 @@ func TestDeadLetterQueue_Handler_VerifyState(t *testing.T) {
 
 	mockFetcher := mockpubsubapp.NewMockFetcher(ctrl)
 
-	handle := NewDeadLatterQueue(mockStore, mockFetcher)
+	mockRecorder := newSavingRecorder(ctrl)
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, mockRecorder, timeline.Discard)
 
 	// Marshal pubsub message for AsyncCtx
 	messageBytes, err := json.Marshal(pubsubMessage)
@@ -553,3 +577,209 @@ func TestDeadLetterQueue_Handler_VerifyState(t *testing.T) {
 
 	// The expectation on the mock already verifies that "archived" state was passed
 }
+
+func newSavingRecorder(ctrl *gomock.Controller) *mockpubsubapp.MockDeadLetterRecorder {
+	recorder := mockpubsubapp.NewMockDeadLetterRecorder(ctrl)
+	recorder.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return recorder
+}
+
+func TestDeadLetterQueue_Handler_SavesDeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	publishedAt := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	request := RequestPayload{
+		EventName:   "payment.processed",
+		Consumer:    domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+		Data:        map[string]any{"amount": float64(10)},
+		PublishedAt: publishedAt.UnixMilli(),
+	}
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	messageBytes, err := json.Marshal(&pubsub.Message{
+		ID:   "test-message-id",
+		Data: data,
+		Attributes: map[string]string{
+			"msg":         "fetch consumer: status 500",
+			"retry_count": "1",
+		},
+	})
+	require.NoError(t, err)
+
+	event := domain.Event{
+		Name:  "payment.processed",
+		Owner: domain.Owner{TeamOwner: "payments", Contact: "#payments-oncall"},
+		Consumers: []domain.Consumer{
+			{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook", Owner: domain.Owner{TeamOwner: "finance"}},
+		},
+	}
+
+	mockStore := mockpubsubapp.NewMockDeadLetterStore(ctrl)
+	mockStore.EXPECT().GetEvent(gomock.Any(), "payment.processed").Return(event, nil)
+	mockStore.EXPECT().GetAllSchedulers(gomock.Any(), "archived").Return(nil, domain.EventNotFound)
+
+	var saved domain.DeadLetter
+	mockRecorder := mockpubsubapp.NewMockDeadLetterRecorder(ctrl)
+	mockRecorder.EXPECT().Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, dl domain.DeadLetter) error {
+			saved = dl
+			return nil
+		})
+
+	handle := NewDeadLatterQueue(mockStore, mockpubsubapp.NewMockFetcher(ctrl), mockRecorder, timeline.Discard)
+	err = handle.Handler(asyncadapter.NewAsyncCtx[pubsub.Message](context.Background(), messageBytes))
+	require.NoError(t, err)
+
+	assert.NotEqual(t, uuid.Nil, saved.ID)
+	assert.Equal(t, "test-message-id", saved.MessageID)
+	assert.Equal(t, "payment.processed", saved.EventName)
+	assert.Equal(t, event.Consumers[0], saved.Consumer)
+	assert.Equal(t, event.Owner, saved.EventOwner)
+	assert.Equal(t, "fetch consumer: status 500", saved.LastError)
+	assert.Equal(t, 1, saved.RetryCount)
+	assert.True(t, publishedAt.Equal(saved.PublishedAt))
+	assert.JSONEq(t, string(data), string(saved.Payload))
+}
+
+func TestDeadLetterQueue_Handler_NotifiesOwners(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	data, err := json.Marshal(RequestPayload{
+		EventName: "payment.processed",
+		Consumer:  domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+	})
+	require.NoError(t, err)
+
+	messageBytes, err := json.Marshal(&pubsub.Message{ID: "test-message-id", Data: data})
+	require.NoError(t, err)
+
+	owner := domain.Owner{TeamOwner: "payments", RepoURL: "github.com/example/payments", Contact: "#payments-oncall"}
+	listener := domain.Event{
+		Name:      "dlq.listener",
+		State:     "archived",
+		Consumers: []domain.Consumer{{ServiceName: "alerts", BaseUrl: "http://alerts"}},
+	}
+
+	mockStore := mockpubsubapp.NewMockDeadLetterStore(ctrl)
+	mockStore.EXPECT().GetEvent(gomock.Any(), "payment.processed").Return(domain.Event{Name: "payment.processed", Owner: owner}, nil)
+	mockStore.EXPECT().GetAllSchedulers(gomock.Any(), "archived").Return([]domain.Event{listener}, nil)
+
+	var notified map[string]any
+	mockFetcher := mockpubsubapp.NewMockFetcher(ctrl)
+	mockFetcher.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), notifyopt.HighThroughput).
+		DoAndReturn(func(_ context.Context, data map[string]any, _ map[string]string, _ domain.Consumer, _ notifyopt.Kind) error {
+			notified = data
+			return nil
+		})
+
+	handle := NewDeadLatterQueue(mockStore, mockFetcher, newSavingRecorder(ctrl), timeline.Discard)
+	err = handle.Handler(asyncadapter.NewAsyncCtx[pubsub.Message](context.Background(), messageBytes))
+	require.NoError(t, err)
+
+	deadLetter, ok := notified["dead_letter"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "payment.processed", deadLetter["event_name"])
+	assert.Equal(t, "billing", deadLetter["consumer"])
+	assert.Equal(t, owner, deadLetter["event_owner"])
+}
+
+func TestDeadLetterQueue_Handler_SaveError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messageBytes, err := json.Marshal(&pubsub.Message{ID: "test-message-id", Data: []byte(`{"test": "data"}`)})
+	require.NoError(t, err)
+
+	mockRecorder := mockpubsubapp.NewMockDeadLetterRecorder(ctrl)
+	mockRecorder.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("redis down"))
+
+	// listeners are not notified, the message is retried instead
+	handle := NewDeadLatterQueue(mockpubsubapp.NewMockDeadLetterStore(ctrl), mockpubsubapp.NewMockFetcher(ctrl), mockRecorder, timeline.Discard)
+	err = handle.Handler(asyncadapter.NewAsyncCtx[pubsub.Message](context.Background(), messageBytes))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to save dead letter")
+}
+
+func TestDeadLetterReplayer_Replay(t *testing.T) {
+	request := RequestPayload{
+		EventName: "payment.processed",
+		Consumer:  domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+		Data:      map[string]any{"amount": float64(10)},
+	}
+	payload, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	event := domain.Event{
+		Name: "payment.processed",
+		Consumers: []domain.Consumer{
+			{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+			{ServiceName: "ledger", BaseUrl: "http://ledger", Path: "/webhook"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		payload       json.RawMessage
+		allConsumers  bool
+		setupStore    func(m *mockpubsubapp.MockReplayStore)
+		wantConsumers []string
+		wantErr       error
+	}{
+		{
+			name:          "original_consumer",
+			payload:       payload,
+			setupStore:    func(m *mockpubsubapp.MockReplayStore) {},
+			wantConsumers: []string{"billing"},
+		},
+		{
+			name:         "all_consumers",
+			payload:      payload,
+			allConsumers: true,
+			setupStore: func(m *mockpubsubapp.MockReplayStore) {
+				m.EXPECT().GetInternalEvent(gomock.Any(), "payment.processed").Return(event, nil)
+			},
+			wantConsumers: []string{"billing", "ledger"},
+		},
+		{
+			name:       "payload_is_not_a_request",
+			payload:    json.RawMessage(`{"user_id": "123"}`),
+			setupStore: func(m *mockpubsubapp.MockReplayStore) {},
+			wantErr:    ErrNotReplayable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			store := mockpubsubapp.NewMockReplayStore(ctrl)
+			tt.setupStore(store)
+
+			var published []string
+			publisher := mockpubadapter.NewMockGenericPublisher(ctrl)
+			publisher.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, payload any, opts pubadapter.Opts) error {
+					input := payload.(RequestPayload)
+					assert.Equal(t, request.Data, input.Data)
+					assert.Equal(t, "1", opts.Attributes["max_retries"])
+					published = append(published, input.Consumer.ServiceName)
+					return nil
+				}).AnyTimes()
+
+			replayer := NewDeadLetterReplayer(publisher, store, timeline.Discard)
+			err := replayer.Replay(context.Background(), domain.DeadLetter{Payload: tt.payload}, tt.allConsumers)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantConsumers, published)
+		})
+	}
+}
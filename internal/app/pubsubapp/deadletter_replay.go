@@ -0,0 +1,73 @@
+package pubsubapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/pubadapter"
+)
+
+var ErrNotReplayable = errors.New("dead letter is not a consumer request")
+
+type ReplayStore interface {
+	GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error)
+}
+
+// DeadLetterReplayer publishes a dead-lettered request again, either to the consumer that
+// failed or to every consumer currently registered for the event.
+type DeadLetterReplayer struct {
+	publisher pubadapter.GenericPublisher
+	store     ReplayStore
+	timeline  TimelineRecorder
+}
+
+func NewDeadLetterReplayer(publisher pubadapter.GenericPublisher, store ReplayStore, timeline TimelineRecorder) *DeadLetterReplayer {
+	return &DeadLetterReplayer{publisher: publisher, store: store, timeline: timeline}
+}
+
+func (r *DeadLetterReplayer) Replay(ctx context.Context, dl domain.DeadLetter, allConsumers bool) error {
+	var request RequestPayload
+	if err := json.Unmarshal(dl.Payload, &request); err != nil || request.EventName == "" {
+		return ErrNotReplayable
+	}
+
+	consumers := []domain.Consumer{request.Consumer}
+	if allConsumers {
+		event, err := r.store.GetInternalEvent(ctx, request.EventName)
+		if err != nil {
+			return fmt.Errorf("get event: %w", err)
+		}
+		consumers = event.Consumers
+	}
+
+	topic := domain.ProjectFromContext(ctx).TopicName(domain.EventQueueRequestToExternal)
+	for _, consumer := range consumers {
+		// the replay keeps the message id, so it continues the timeline of the publication
+		input := RequestPayload{
+			MessageID:   request.MessageID,
+			EventName:   request.EventName,
+			Consumer:    consumer,
+			Data:        request.Data,
+			Headers:     request.Headers,
+			PublishedAt: time.Now().UnixMilli(),
+		}
+
+		if err := r.publisher.Publish(ctx, topic, input, pubadapter.Opts{Attributes: requestAttributes(topic)}); err != nil {
+			return fmt.Errorf("publish to %s: %w", consumer.ServiceName, err)
+		}
+
+		r.timeline.Record(ctx, domain.TimelineEntry{
+			MessageID: request.MessageID,
+			Stage:     domain.StageReplayed,
+			EventName: request.EventName,
+			Consumer:  consumer.ServiceName,
+			Source:    "dead_letter",
+		})
+	}
+
+	return nil
+}
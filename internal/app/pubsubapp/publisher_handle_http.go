@@ -12,7 +12,10 @@ import (
 	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
 	"github.com/IsaacDSC/gqueue/pkg/httpadapter"
 	"github.com/IsaacDSC/gqueue/pkg/pubadapter"
-	"github.com/IsaacDSC/gqueue/pkg/topicutils"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type PublisherInsights interface {
@@ -23,12 +26,20 @@ type Store interface {
 	GetEvent(ctx context.Context, eventName string) (domain.Event, error)
 }
 
+type TimelineRecorder interface {
+	Record(ctx context.Context, entry domain.TimelineEntry)
+}
+
 type RequestPayload struct {
+	// MessageID identifies the publication in the timeline, it is shared by its consumers
+	MessageID   string            `json:"message_id,omitempty"`
 	EventName   string            `json:"event_name"`
 	Consumer    domain.Consumer   `json:"consumer"`
 	Data        map[string]any    `json:"data"`
 	Headers     map[string]string `json:"headers,omitempty"`
 	PublishedAt int64             `json:"published_at,omitempty"`
+	// TraceContext carries the trace of the publication to the delivery, as traceparent and tracestate
+	TraceContext map[string]string `json:"trace_context,omitempty"`
 }
 
 func (p RequestPayload) mergeHeaders(headers map[string]string) map[string]string {
@@ -43,10 +54,19 @@ func (p RequestPayload) mergeHeaders(headers map[string]string) map[string]strin
 	return p.Headers
 }
 
+// requestAttributes are read by the retry policy of the consumer to decide when to dead-letter the message
+func requestAttributes(topic string) map[string]string {
+	return map[string]string{
+		"topic":       topic,
+		"max_retries": "1",
+	}
+}
+
 func PublisherEvent(
 	store Store,
 	adaptpub pubadapter.GenericPublisher,
 	insights PublisherInsights,
+	timeline TimelineRecorder,
 ) httpadapter.HttpHandle {
 
 	insertInsights := func(ctx context.Context, payload InternalPayload, started time.Time, isSuccess bool) {
@@ -72,20 +92,14 @@ func PublisherEvent(
 			ctx := r.Context()
 			l := ctxlogger.GetLogger(ctx)
 
-			var payload InternalPayload
-
-			defer r.Body.Close()
-			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
-
-			if err := payload.Validate(); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
+			_, span := telemetry.StartSpan(ctx, "gqueue.validate", trace.SpanKindInternal)
+			payload, status, err := decodePublication(r)
+			telemetry.EndSpan(span, err)
+			if err != nil {
+				http.Error(w, err.Error(), status)
 				return
 			}
 
-			var err error
 			defer insertInsights(ctx, payload, started, err == nil)
 
 			event, err := store.GetEvent(ctx, payload.EventName)
@@ -101,6 +115,13 @@ func PublisherEvent(
 				return
 			}
 
+			messageID := uuid.NewString()
+			timeline.Record(ctx, domain.TimelineEntry{
+				MessageID: messageID,
+				Stage:     domain.StageAccepted,
+				EventName: event.Name,
+			})
+
 			eventType := event.Type.String()
 			if eventType == "" {
 				l.Warn("event type is empty, defaulting to internal", "event_name", event.Name)
@@ -112,6 +133,7 @@ func PublisherEvent(
 
 				nowMs := time.Now().UnixMilli()
 				input := RequestPayload{
+					MessageID:   messageID,
 					EventName:   event.Name,
 					Data:        payload.Data,
 					Headers:     payload.Metadata.Headers,
@@ -124,23 +146,59 @@ func PublisherEvent(
 					},
 				}
 
-				topic := topicutils.BuildTopicName(domain.ProjectID, domain.EventQueueRequestToExternal)
+				topic := domain.ProjectFromContext(ctx).TopicName(domain.EventQueueRequestToExternal)
 				opts := pubadapter.Opts{
-					Attributes: map[string]string{
-						"topic":       topic,
-						"max_retries": "1",
-					},
-					AsynqOpts: config,
+					Attributes: requestAttributes(topic),
+					AsynqOpts:  config,
 				}
-				if err = adaptpub.Publish(ctx, topic, input, opts); err != nil {
+				enqueueCtx, span := telemetry.StartSpan(ctx, "gqueue.enqueue", trace.SpanKindProducer,
+					attribute.String("gqueue.event", event.Name),
+					attribute.String("gqueue.consumer", consumer.ServiceName),
+					attribute.String("messaging.destination.name", topic),
+				)
+				input.TraceContext = telemetry.InjectMap(enqueueCtx)
+				err = adaptpub.Publish(enqueueCtx, topic, input, opts)
+				telemetry.EndSpan(span, err)
+				if err != nil {
 					err = fmt.Errorf("publish event: %w", err)
 					l.Error("failed to publish event", "error", err.Error())
 					http.Error(w, "failed to publish event", http.StatusInternalServerError)
 					return
 				}
+
+				timeline.Record(ctx, domain.TimelineEntry{
+					MessageID: messageID,
+					Stage:     domain.StageEnqueued,
+					EventName: event.Name,
+					Consumer:  consumer.ServiceName,
+				})
 			}
 
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Message-ID", messageID)
 			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message_id": messageID})
 		},
 	}
 }
+
+// decodePublication reads and validates the publication of the request, the status is the one to
+// answer when it is invalid
+func decodePublication(r *http.Request) (InternalPayload, int, error) {
+	var payload InternalPayload
+
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return payload, http.StatusBadRequest, err
+	}
+
+	if err := payload.Validate(); err != nil {
+		return payload, http.StatusBadRequest, err
+	}
+
+	if err := domain.Authorize(r.Context(), domain.PublishScope(payload.EventName)); err != nil {
+		return payload, http.StatusForbidden, err
+	}
+
+	return payload, http.StatusOK, nil
+}
@@ -2,110 +2,235 @@ package taskapp
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/hibiken/asynq"
 )
 
-type CacheManager interface {
-	FindAllConsumers(ctx context.Context) ([]domain.Event, error)
-	FindAllQueues(ctx context.Context) ([]Queue, error)
-	FindArchivedTasks(ctx context.Context, queue string) ([]string, error)
-	GetMsgArchivedTask(ctx context.Context, queue, task string) (RawMsg, error)
-	RemoveMsgArchivedTask(ctx context.Context, queue, task string) error
-	RemoveItemsArchivedTasks(ctx context.Context, queue string, tasks ...string) error
-	SetArchivedTasks(ctx context.Context, events []domain.Event) error
+// Inspector is the subset of asynq.Inspector used to manage archived tasks
+type Inspector interface {
+	Queues() ([]string, error)
+	GetQueueInfo(queue string) (*asynq.QueueInfo, error)
+	ListArchivedTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+	GetTaskInfo(queue, id string) (*asynq.TaskInfo, error)
+	RunTask(queue, id string) error
+	DeleteTask(queue, id string) error
 }
 
-type Storer interface {
-	GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error)
+type ArchivedQueue struct {
+	Queue    string `json:"queue"`
+	Archived int    `json:"archived"`
+}
+
+type ArchivedTask struct {
+	ID           string          `json:"id"`
+	Queue        string          `json:"queue"`
+	Type         string          `json:"type"`
+	Payload      *RequestPayload `json:"payload,omitempty"`
+	RawPayload   json.RawMessage `json:"raw_payload,omitempty"`
+	LastError    string          `json:"last_error"`
+	LastFailedAt time.Time       `json:"last_failed_at"`
+	Retried      int             `json:"retried"`
+	MaxRetry     int             `json:"max_retry"`
+}
+
+type ArchivedTasksPage struct {
+	Queue string         `json:"queue"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+	Total int            `json:"total"`
+	Tasks []ArchivedTask `json:"tasks"`
+}
+
+// BatchResult reports the tasks a bulk operation handled and the reason the others failed
+type BatchResult struct {
+	Succeeded []string          `json:"succeeded"`
+	Failed    map[string]string `json:"failed,omitempty"`
 }
 
 type TaskManager struct {
-	store Storer
-	cm    CacheManager
+	inspector Inspector
+	timeline  TimelineRecorder
 }
 
-func NewTaskManager(store Storer, cm CacheManager) *TaskManager {
-	return &TaskManager{store: store, cm: cm}
+func NewTaskManager(inspector Inspector, timeline TimelineRecorder) *TaskManager {
+	return &TaskManager{inspector: inspector, timeline: timeline}
 }
 
-func (n TaskManager) GetMsgsArchived(ctx context.Context) ([]TaskArchivedData, error) {
+// ArchivedQueues returns every queue known by asynq with the number of archived tasks
+func (n TaskManager) ArchivedQueues(ctx context.Context) ([]ArchivedQueue, error) {
 	l := ctxlogger.GetLogger(ctx)
 
-	queues, err := n.cm.FindAllQueues(ctx)
-	if errors.Is(err, ErrorNotFound) {
-		l.Warn("No queues found")
-		return []TaskArchivedData{}, nil
-	}
-
+	queues, err := n.inspector.Queues()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get queues: %w", err)
 	}
 
-	archived := make(map[string][]string)
+	output := make([]ArchivedQueue, 0, len(queues))
 	for _, queue := range queues {
-		result, err := n.cm.FindArchivedTasks(ctx, queue.String())
+		info, err := n.inspector.GetQueueInfo(queue)
 		if err != nil {
-			l.Error("Failed to fetch archived tasks", "queue", queue, "error", err)
+			l.Error("Failed to fetch queue info", "queue", queue, "error", err)
 			continue
 		}
 
-		archived[queue.String()] = result
+		output = append(output, ArchivedQueue{Queue: queue, Archived: info.Archived})
 	}
 
-	var output []TaskArchivedData
-	for queue, tasks := range archived {
-		for _, task := range tasks {
-			result, err := n.cm.GetMsgArchivedTask(ctx, queue, task)
-			if err != nil {
-				l.Error("Failed to fetch archived task message", "queue", queue, "task", task, "error", err)
-				continue
-			}
-			output = append(output, TaskArchivedData{
-				ID:        task,
-				Queue:     queue,
-				Consumers: []Consumer{},
-				Msg:       result.Msg(),
-				Tasks:     tasks,
-			})
-		}
+	return output, nil
+}
+
+func (n TaskManager) ListArchived(ctx context.Context, queue string, page, limit int) (ArchivedTasksPage, error) {
+	info, err := n.inspector.GetQueueInfo(queue)
+	if err != nil {
+		return ArchivedTasksPage{}, inspectorError(err, "failed to get queue info")
+	}
+
+	tasks, err := n.inspector.ListArchivedTasks(queue, asynq.Page(page), asynq.PageSize(limit))
+	if err != nil {
+		return ArchivedTasksPage{}, inspectorError(err, "failed to list archived tasks")
+	}
+
+	output := ArchivedTasksPage{
+		Queue: queue,
+		Page:  page,
+		Limit: limit,
+		Total: info.Archived,
+		Tasks: make([]ArchivedTask, 0, len(tasks)),
+	}
+
+	for _, task := range tasks {
+		output.Tasks = append(output.Tasks, newArchivedTask(task))
 	}
 
 	return output, nil
+}
 
+func (n TaskManager) GetArchived(ctx context.Context, queue, id string) (ArchivedTask, error) {
+	task, err := n.getArchived(queue, id)
+	if err != nil {
+		return ArchivedTask{}, err
+	}
+
+	return newArchivedTask(task), nil
 }
 
-func (n TaskManager) GetAllMsgsByQueue(ctx context.Context, queue string) ([]TaskArchivedData, error) {
-	l := ctxlogger.GetLogger(ctx)
+// RequeueTask moves the archived task back to pending so it is processed again
+func (n TaskManager) RequeueTask(ctx context.Context, queue, id string) error {
+	task, err := n.getArchived(queue, id)
+	if err != nil {
+		return err
+	}
 
-	tasksResult, err := n.cm.FindArchivedTasks(ctx, queue)
-	if errors.Is(err, ErrorNotFound) {
-		return []TaskArchivedData{}, nil
+	if err := n.inspector.RunTask(queue, id); err != nil {
+		return inspectorError(err, "failed to handle archived task")
 	}
 
-	if err != nil {
-		l.Error("Failed to fetch archived tasks", "queue", queue, "error", err)
-		return nil, fmt.Errorf("failed to get archived tasks: %w", err)
+	if payload := newArchivedTask(task).Payload; payload != nil {
+		n.timeline.Record(ctx, domain.TimelineEntry{
+			MessageID: payload.MessageID,
+			Stage:     domain.StageReplayed,
+			EventName: payload.EventName,
+			Consumer:  payload.Consumer.ServiceName,
+			Source:    "asynq",
+		})
 	}
 
-	var output []TaskArchivedData
-	for _, task := range tasksResult {
-		result, err := n.cm.GetMsgArchivedTask(ctx, queue, task)
-		if err != nil {
-			l.Error("Failed to fetch archived task message", "queue", queue, "task", task, "error", err)
+	return nil
+}
+
+func (n TaskManager) DeleteTask(ctx context.Context, queue, id string) error {
+	return n.apply(queue, id, n.inspector.DeleteTask)
+}
+
+func (n TaskManager) Requeue(ctx context.Context, queue string, ids ...string) BatchResult {
+	return n.batch(ctx, queue, ids, n.RequeueTask)
+}
+
+func (n TaskManager) Delete(ctx context.Context, queue string, ids ...string) BatchResult {
+	return n.batch(ctx, queue, ids, n.DeleteTask)
+}
+
+// apply only touches tasks still archived, the inspector would also run or delete tasks
+// that are scheduled or waiting for a retry.
+func (n TaskManager) apply(queue, id string, fn func(queue, id string) error) error {
+	if _, err := n.getArchived(queue, id); err != nil {
+		return err
+	}
+
+	if err := fn(queue, id); err != nil {
+		return inspectorError(err, "failed to handle archived task")
+	}
+
+	return nil
+}
+
+func (n TaskManager) batch(ctx context.Context, queue string, ids []string, fn func(ctx context.Context, queue, id string) error) BatchResult {
+	l := ctxlogger.GetLogger(ctx)
+
+	output := BatchResult{Succeeded: make([]string, 0, len(ids))}
+	for _, id := range ids {
+		if err := fn(ctx, queue, id); err != nil {
+			l.Warn("Failed to handle archived task", "queue", queue, "task", id, "error", err)
+			if output.Failed == nil {
+				output.Failed = make(map[string]string)
+			}
+			output.Failed[id] = err.Error()
 			continue
 		}
-		output = append(output, TaskArchivedData{
-			ID:        task,
-			Queue:     queue,
-			Consumers: nil, // You might want to fetch consumers for this queue if needed
-			Msg:       result.Msg(),
-			Tasks:     tasksResult,
-		})
+
+		output.Succeeded = append(output.Succeeded, id)
 	}
 
-	return output, nil
+	return output
+}
+
+func (n TaskManager) getArchived(queue, id string) (*asynq.TaskInfo, error) {
+	task, err := n.inspector.GetTaskInfo(queue, id)
+	if err != nil {
+		return nil, inspectorError(err, "failed to get task")
+	}
+
+	if task.State != asynq.TaskStateArchived {
+		return nil, fmt.Errorf("task %s is %s: %w", id, task.State, ErrorNotFound)
+	}
+
+	return task, nil
+}
+
+func newArchivedTask(task *asynq.TaskInfo) ArchivedTask {
+	output := ArchivedTask{
+		ID:           task.ID,
+		Queue:        task.Queue,
+		Type:         task.Type,
+		LastError:    task.LastErr,
+		LastFailedAt: task.LastFailedAt,
+		Retried:      task.Retried,
+		MaxRetry:     task.MaxRetry,
+	}
+
+	// tasks not published by gqueue keep the payload as it was enqueued
+	var payload RequestPayload
+	if err := json.Unmarshal(task.Payload, &payload); err == nil && payload.EventName != "" {
+		output.Payload = &payload
+	} else if json.Valid(task.Payload) {
+		output.RawPayload = task.Payload
+	} else {
+		output.RawPayload, _ = json.Marshal(string(task.Payload))
+	}
+
+	return output
+}
+
+func inspectorError(err error, msg string) error {
+	if errors.Is(err, asynq.ErrQueueNotFound) || errors.Is(err, asynq.ErrTaskNotFound) {
+		return fmt.Errorf("%s: %w: %w", msg, ErrorNotFound, err)
+	}
+
+	return fmt.Errorf("%s: %w", msg, err)
 }
@@ -0,0 +1,140 @@
+package taskapp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
+	"github.com/IsaacDSC/gqueue/internal/timeline"
+	"github.com/IsaacDSC/gqueue/mocks/mocktaskapp"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTaskManager_ListArchived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inspector := mocktaskapp.NewMockInspector(ctrl)
+	inspector.EXPECT().GetQueueInfo("external.medium").Return(&asynq.QueueInfo{Archived: 3}, nil)
+	inspector.EXPECT().ListArchivedTasks("external.medium", gomock.Any(), gomock.Any()).Return([]*asynq.TaskInfo{
+		{
+			ID:      "task-1",
+			Queue:   "external.medium",
+			Type:    "event-queue.request-to-external",
+			Payload: []byte(`{"event_name":"payment.processed","consumer":{"service_name":"billing"},"data":{"id":"1"}}`),
+			LastErr: "fetch consumer: status 500",
+			State:   asynq.TaskStateArchived,
+		},
+		{
+			ID:      "task-2",
+			Queue:   "external.medium",
+			Type:    "custom",
+			Payload: []byte("not json"),
+			State:   asynq.TaskStateArchived,
+		},
+	}, nil)
+
+	page, err := taskapp.NewTaskManager(inspector, timeline.Discard).ListArchived(context.Background(), "external.medium", 1, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Tasks, 2)
+
+	require.NotNil(t, page.Tasks[0].Payload)
+	assert.Equal(t, "payment.processed", page.Tasks[0].Payload.EventName)
+	assert.Equal(t, "billing", page.Tasks[0].Payload.Consumer.ServiceName)
+	assert.Equal(t, "fetch consumer: status 500", page.Tasks[0].LastError)
+
+	assert.Nil(t, page.Tasks[1].Payload)
+	assert.JSONEq(t, `"not json"`, string(page.Tasks[1].RawPayload))
+}
+
+func TestTaskManager_ListArchived_QueueNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inspector := mocktaskapp.NewMockInspector(ctrl)
+	inspector.EXPECT().GetQueueInfo("unknown").Return(nil, fmt.Errorf("wrapped: %w", asynq.ErrQueueNotFound))
+
+	_, err := taskapp.NewTaskManager(inspector, timeline.Discard).ListArchived(context.Background(), "unknown", 1, 10)
+	assert.ErrorIs(t, err, taskapp.ErrorNotFound)
+}
+
+func TestTaskManager_RequeueTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(inspector *mocktaskapp.MockInspector)
+		expectErr error
+	}{
+		{
+			name: "requeues_archived_task",
+			setup: func(inspector *mocktaskapp.MockInspector) {
+				inspector.EXPECT().GetTaskInfo("q", "id").Return(&asynq.TaskInfo{State: asynq.TaskStateArchived}, nil)
+				inspector.EXPECT().RunTask("q", "id").Return(nil)
+			},
+		},
+		{
+			name: "task_not_found",
+			setup: func(inspector *mocktaskapp.MockInspector) {
+				inspector.EXPECT().GetTaskInfo("q", "id").Return(nil, asynq.ErrTaskNotFound)
+			},
+			expectErr: taskapp.ErrorNotFound,
+		},
+		{
+			name: "task_not_archived_is_not_run",
+			setup: func(inspector *mocktaskapp.MockInspector) {
+				inspector.EXPECT().GetTaskInfo("q", "id").Return(&asynq.TaskInfo{State: asynq.TaskStateRetry}, nil)
+			},
+			expectErr: taskapp.ErrorNotFound,
+		},
+		{
+			name: "inspector_error",
+			setup: func(inspector *mocktaskapp.MockInspector) {
+				inspector.EXPECT().GetTaskInfo("q", "id").Return(&asynq.TaskInfo{State: asynq.TaskStateArchived}, nil)
+				inspector.EXPECT().RunTask("q", "id").Return(errors.New("redis down"))
+			},
+			expectErr: errors.New("failed to handle archived task: redis down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			inspector := mocktaskapp.NewMockInspector(ctrl)
+			tt.setup(inspector)
+
+			err := taskapp.NewTaskManager(inspector, timeline.Discard).RequeueTask(context.Background(), "q", "id")
+			switch {
+			case tt.expectErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tt.expectErr, taskapp.ErrorNotFound):
+				assert.ErrorIs(t, err, taskapp.ErrorNotFound)
+			default:
+				assert.EqualError(t, err, tt.expectErr.Error())
+			}
+		})
+	}
+}
+
+func TestTaskManager_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inspector := mocktaskapp.NewMockInspector(ctrl)
+	inspector.EXPECT().GetTaskInfo("q", "a").Return(&asynq.TaskInfo{State: asynq.TaskStateArchived}, nil)
+	inspector.EXPECT().DeleteTask("q", "a").Return(nil)
+	inspector.EXPECT().GetTaskInfo("q", "b").Return(nil, asynq.ErrTaskNotFound)
+
+	result := taskapp.NewTaskManager(inspector, timeline.Discard).Delete(context.Background(), "q", "a", "b")
+
+	assert.Equal(t, []string{"a"}, result.Succeeded)
+	assert.Contains(t, result.Failed, "b")
+	assert.Len(t, result.Failed, 1)
+}
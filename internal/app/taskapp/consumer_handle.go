@@ -2,6 +2,7 @@ package taskapp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
 	"github.com/IsaacDSC/gqueue/pkg/telemetry"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Fetcher interface {
@@ -22,19 +24,30 @@ type ConsumerInsights interface {
 	Consumed(ctx context.Context, input domain.ConsumerMetric) error
 }
 
-func GetRequestHandle(fetch Fetcher, insights ConsumerInsights) asyncadapter.Handle[RequestPayload] {
+type PauseChecker interface {
+	DeliveryPaused(ctx context.Context, eventName, serviceName string) bool
+}
+
+type QuarantineChecker interface {
+	Quarantined(ctx context.Context, eventName, serviceName string) bool
+}
+
+func GetRequestHandle(fetch Fetcher, insights ConsumerInsights, pauses PauseChecker, quarantines QuarantineChecker, timeline TimelineRecorder) asyncadapter.Handle[RequestPayload] {
 
 	insertInsights := func(ctx context.Context, payload RequestPayload, started time.Time, isSuccess bool) {
 		l := ctxlogger.GetLogger(ctx)
 		finished := time.Now()
-		if err := insights.Consumed(ctx, domain.ConsumerMetric{
+		metric := domain.ConsumerMetric{
 			TopicName:      payload.EventName,
 			ConsumerName:   payload.Consumer.ServiceName,
 			TimeStarted:    started,
 			TimeEnded:      finished,
 			TimeDurationMs: finished.Sub(started).Milliseconds(),
 			ACK:            isSuccess,
-		}); err != nil {
+		}
+		metric.PublishedAt = payload.readyAt()
+
+		if err := insights.Consumed(ctx, metric); err != nil {
 			l.Warn("not save metric", "type", "consumer", "error", err.Error())
 		}
 
@@ -42,7 +55,7 @@ func GetRequestHandle(fetch Fetcher, insights ConsumerInsights) asyncadapter.Han
 
 	return asyncadapter.Handle[RequestPayload]{
 		EventName: domain.EventQueueRequestToExternal,
-		Handler: func(c asyncadapter.AsyncCtx[RequestPayload]) error {
+		Handler: func(c asyncadapter.AsyncCtx[RequestPayload]) (err error) {
 			started := time.Now()
 			ctx := c.Context()
 
@@ -51,10 +64,28 @@ func GetRequestHandle(fetch Fetcher, insights ConsumerInsights) asyncadapter.Han
 				return fmt.Errorf("get payload: %w", err)
 			}
 
+			ctx, span := startDelivery(ctx, payload, c.Retry())
+			defer func() {
+				endDelivery(span, err)
+				recordAttempt(ctx, timeline, payload, c, started, err)
+			}()
+
 			if err := payload.Validate(); err != nil {
 				return fmt.Errorf("validate payload: %w", err)
 			}
 
+			// the payload carries the consumer as it was when published, the pause state comes from the registry
+			if pauses.DeliveryPaused(ctx, payload.EventName, payload.Consumer.ServiceName) {
+				return fmt.Errorf("deliver to %s: %w", payload.Consumer.ServiceName, domain.DeliveryPaused)
+			}
+
+			// a quarantined consumer is probed by the backoffice, its messages are parked meanwhile
+			if quarantines.Quarantined(ctx, payload.EventName, payload.Consumer.ServiceName) {
+				return fmt.Errorf("deliver to %s: %w", payload.Consumer.ServiceName, domain.DeliveryQuarantined)
+			}
+
+			recordLag(ctx, started, payload)
+
 			headers := payload.mergeHeaders(payload.Consumer.Headers)
 			if err := fetch.Notify(ctx, payload.Data, headers, payload.Consumer, notifyopt.LongRunning); err != nil {
 				insertInsights(ctx, payload, started, false)
@@ -90,3 +121,93 @@ func recordDuration(ctx context.Context, started time.Time, payload RequestPaylo
 		attrs...,
 	)
 }
+
+// recordLag records the time the task waited in its queue until this attempt started, retries
+// included. Tasks published before their payload carried the time are not recorded.
+func recordLag(ctx context.Context, started time.Time, payload RequestPayload) {
+	readyAt := payload.readyAt()
+	if readyAt.IsZero() {
+		return
+	}
+
+	topic := domain.ProjectFromContext(ctx).TopicName(domain.EventQueueRequestToExternal)
+	telemetry.TaskConsumerLagSeconds.Record(ctx, max(started.Sub(readyAt).Seconds(), 0),
+		attribute.String("topic", topic),
+		attribute.String("consumer.service_name", payload.Consumer.ServiceName))
+}
+
+// startDelivery starts the span of an attempt to deliver the payload in the trace of its
+// publication, the attempts after the first one are retries
+func startDelivery(ctx context.Context, payload RequestPayload, retry int) (context.Context, trace.Span) {
+	name := "gqueue.deliver"
+	if retry > 0 {
+		name = "gqueue.retry"
+	}
+
+	ctx = telemetry.ExtractMap(ctx, payload.TraceContext)
+	return telemetry.StartSpan(ctx, name, trace.SpanKindConsumer,
+		attribute.String("gqueue.event", payload.EventName),
+		attribute.String("gqueue.consumer", payload.Consumer.ServiceName),
+		attribute.Int("gqueue.retry", retry),
+	)
+}
+
+// endDelivery ends the span of the attempt, a paused or quarantined delivery is deferred and not
+// failed
+func endDelivery(span trace.Span, err error) {
+	if errors.Is(err, domain.DeliveryQuarantined) {
+		span.SetAttributes(attribute.Bool("gqueue.quarantined", true))
+	}
+
+	if errors.Is(err, domain.DeliveryPaused) {
+		span.SetAttributes(attribute.Bool("gqueue.paused", true))
+		err = nil
+	}
+
+	telemetry.EndSpan(span, err)
+}
+
+// recordAttempt adds the attempt to the timeline of the message. A failed attempt is followed by a
+// retry, or by the archive of the task when it was the last one.
+func recordAttempt(ctx context.Context, timeline TimelineRecorder, payload RequestPayload, c asyncadapter.AsyncCtx[RequestPayload], started time.Time, err error) {
+	if errors.Is(err, domain.DeliveryPaused) {
+		return
+	}
+
+	entry := domain.TimelineEntry{
+		MessageID:  payload.MessageID,
+		Stage:      domain.StageDeliveryAttempt,
+		EventName:  payload.EventName,
+		Consumer:   payload.Consumer.ServiceName,
+		Attempt:    c.Retry() + 1,
+		Outcome:    domain.OutcomeSuccess,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+
+	if err != nil {
+		entry.Outcome = domain.OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	timeline.Record(ctx, entry)
+
+	if err == nil {
+		return
+	}
+
+	next := domain.TimelineEntry{
+		MessageID: payload.MessageID,
+		Stage:     domain.StageRetryScheduled,
+		EventName: payload.EventName,
+		Consumer:  payload.Consumer.ServiceName,
+		Attempt:   c.Retry() + 2,
+	}
+
+	if c.LastAttempt() {
+		next.Stage = domain.StageDeadLettered
+		next.Attempt = 0
+		next.Error = err.Error()
+	}
+
+	timeline.Record(ctx, next)
+}
@@ -3,18 +3,29 @@ package taskapp_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/internal/notifyopt"
+	"github.com/IsaacDSC/gqueue/internal/quarantine"
+	"github.com/IsaacDSC/gqueue/internal/timeline"
 	"github.com/IsaacDSC/gqueue/mocks/mocktaskapp"
 	"github.com/IsaacDSC/gqueue/pkg/asyncadapter"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/mock/gomock"
 )
 
@@ -35,7 +46,7 @@ func TestGetRequestHandle(t *testing.T) {
 
 		mockFetch := mocktaskapp.NewMockFetcher(ctrl)
 		mockInsights := mocktaskapp.NewMockConsumerInsights(ctrl)
-		handle := taskapp.GetRequestHandle(mockFetch, mockInsights)
+		handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 		assert.Equal(t, "event-queue.request-to-external", handle.EventName)
 		assert.NotNil(t, handle.Handler)
@@ -132,7 +143,7 @@ func TestGetRequestHandle_Handler(t *testing.T) {
 			}
 
 			// Get the handler
-			handle := taskapp.GetRequestHandle(mockFetcher, mockInsights)
+			handle := taskapp.GetRequestHandle(mockFetcher, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 			// Create task payload
 			taskPayload, err := json.Marshal(tt.payload)
@@ -162,7 +173,7 @@ func TestGetRequestHandle_Handler_InvalidPayload(t *testing.T) {
 
 	mockFetch := mocktaskapp.NewMockFetcher(ctrl)
 	mockInsights := mocktaskapp.NewMockConsumerInsights(ctrl)
-	handle := taskapp.GetRequestHandle(mockFetch, mockInsights)
+	handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 	// Create AsyncCtx wrapper with invalid payload
 	asyncCtx := asyncadapter.NewAsyncCtx[taskapp.RequestPayload](context.Background(), []byte("invalid json"))
@@ -269,7 +280,7 @@ func TestRequestPayload_mergeHeaders_Integration(t *testing.T) {
 				tt.setupMocks(mockInsights)
 			}
 
-			handle := taskapp.GetRequestHandle(mockFetcher, mockInsights)
+			handle := taskapp.GetRequestHandle(mockFetcher, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 			payload := taskapp.RequestPayload{
 				EventName: "user.created",
@@ -335,7 +346,7 @@ func TestMockFetcher_ErrorScenarios(t *testing.T) {
 				Return(tt.mockError).
 				Times(1)
 
-			handle := taskapp.GetRequestHandle(mockFetch, mockInsights)
+			handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 			payload := taskapp.RequestPayload{
 				EventName: "user.created",
@@ -389,7 +400,7 @@ func TestGetRequestHandle_HeaderMerging(t *testing.T) {
 		}).
 		Times(1)
 
-	handle := taskapp.GetRequestHandle(mockFetch, mockInsights)
+	handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 	payload := taskapp.RequestPayload{
 		EventName: "user.created",
@@ -455,7 +466,7 @@ func TestGetRequestHandle_DataPassing(t *testing.T) {
 		}).
 		Times(1)
 
-	handle := taskapp.GetRequestHandle(mockFetch, mockInsights)
+	handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
 
 	expectedData := map[string]any{
 		"user_id":   "123",
@@ -492,3 +503,201 @@ func TestGetRequestHandle_DataPassing(t *testing.T) {
 	assert.Equal(t, float64(42), receivedData["count"]) // JSON numbers are float64
 	assert.Equal(t, true, receivedData["is_active"])
 }
+
+func TestGetRequestHandle_Handler_DeliveryPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetch := mocktaskapp.NewMockFetcher(ctrl)
+	mockInsights := mocktaskapp.NewMockConsumerInsights(ctrl)
+	pauses := mocktaskapp.NewMockPauseChecker(ctrl)
+	pauses.EXPECT().DeliveryPaused(gomock.Any(), "payment.processed", "billing").Return(true)
+
+	handle := taskapp.GetRequestHandle(mockFetch, mockInsights, pauses, quarantine.Never, timeline.Discard)
+
+	payload, err := json.Marshal(taskapp.RequestPayload{
+		EventName: "payment.processed",
+		Consumer:  domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+		Data:      map[string]any{"id": "1"},
+	})
+	require.NoError(t, err)
+
+	// the consumer is not called and no failure is recorded
+	err = handle.Handler(asyncadapter.NewAsyncCtx[taskapp.RequestPayload](context.Background(), payload))
+	assert.ErrorIs(t, err, domain.DeliveryPaused)
+}
+
+func TestGetRequestHandle_Handler_DeliveryQuarantined(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetch := mocktaskapp.NewMockFetcher(ctrl)
+	mockInsights := mocktaskapp.NewMockConsumerInsights(ctrl)
+	quarantines := mocktaskapp.NewMockQuarantineChecker(ctrl)
+	quarantines.EXPECT().Quarantined(gomock.Any(), "payment.processed", "billing").Return(true)
+
+	handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantines, timeline.Discard)
+
+	payload, err := json.Marshal(taskapp.RequestPayload{
+		EventName: "payment.processed",
+		Consumer:  domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+		Data:      map[string]any{"id": "1"},
+	})
+	require.NoError(t, err)
+
+	// the message is parked like a paused one, without calling the consumer
+	err = handle.Handler(asyncadapter.NewAsyncCtx[taskapp.RequestPayload](context.Background(), payload))
+	assert.ErrorIs(t, err, domain.DeliveryQuarantined)
+	assert.ErrorIs(t, err, domain.DeliveryPaused)
+}
+
+func TestGetRequestHandle_Handler_LagFromScheduledTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	publishedAt := time.Now().Add(-time.Hour)
+	scheduledAt := publishedAt.Add(50 * time.Minute)
+
+	mockFetch := mocktaskapp.NewMockFetcher(ctrl)
+	mockFetch.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), notifyopt.LongRunning).Return(nil)
+
+	mockInsights := mocktaskapp.NewMockConsumerInsights(ctrl)
+	mockInsights.EXPECT().Consumed(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input domain.ConsumerMetric) error {
+			// the time the task was scheduled to wait is not lag
+			assert.Equal(t, scheduledAt.UnixMilli(), input.PublishedAt.UnixMilli())
+			assert.Less(t, input.TimeStarted.Sub(input.PublishedAt), 11*time.Minute)
+			return nil
+		})
+
+	handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
+
+	payload, err := json.Marshal(taskapp.RequestPayload{
+		EventName:   "payment.processed",
+		Consumer:    domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+		Data:        map[string]any{"id": "1"},
+		PublishedAt: publishedAt.UnixMilli(),
+		ScheduledAt: scheduledAt.UnixMilli(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, handle.Handler(asyncadapter.NewAsyncCtx[taskapp.RequestPayload](context.Background(), payload)))
+}
+
+func notPaused(ctrl *gomock.Controller) *mocktaskapp.MockPauseChecker {
+	pauses := mocktaskapp.NewMockPauseChecker(ctrl)
+	pauses.EXPECT().DeliveryPaused(gomock.Any(), gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	return pauses
+}
+
+func TestGetRequestHandle_Handler_ContinuesTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	// the span of the enqueue in the publisher
+	published, enqueue := telemetry.StartSpan(context.Background(), "gqueue.enqueue", trace.SpanKindProducer)
+	payload, err := json.Marshal(taskapp.RequestPayload{
+		EventName:    "payment.processed",
+		Consumer:     domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+		Data:         map[string]any{"id": "1"},
+		TraceContext: telemetry.InjectMap(published),
+	})
+	require.NoError(t, err)
+	enqueue.End()
+
+	ctrl := gomock.NewController(t)
+	mockFetch := mocktaskapp.NewMockFetcher(ctrl)
+	mockInsights := mocktaskapp.NewMockConsumerInsights(ctrl)
+	mockInsights.EXPECT().Consumed(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFetch.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), notifyopt.LongRunning).
+		DoAndReturn(func(ctx context.Context, _ map[string]any, _ map[string]string, _ domain.Consumer, _ notifyopt.Kind) error {
+			assert.Equal(t, enqueue.SpanContext().TraceID(), trace.SpanContextFromContext(ctx).TraceID(), "the webhook is in the trace of the publication")
+			return nil
+		})
+
+	handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, timeline.Discard)
+	require.NoError(t, handle.Handler(asyncadapter.NewAsyncCtx[taskapp.RequestPayload](context.Background(), payload)))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "gqueue.deliver", spans[1].Name())
+	assert.Equal(t, enqueue.SpanContext().SpanID(), spans[1].Parent().SpanID())
+}
+
+func TestGetRequestHandle_Handler_RecordsTimeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		retry    int
+		maxRetry int
+		fetchErr error
+		want     []domain.TimelineEntry
+	}{
+		{
+			name:     "delivered",
+			maxRetry: 3,
+			want: []domain.TimelineEntry{
+				{Stage: domain.StageDeliveryAttempt, Attempt: 1, Outcome: domain.OutcomeSuccess},
+			},
+		},
+		{
+			name:     "failed with retries left",
+			retry:    1,
+			maxRetry: 3,
+			fetchErr: errors.New("connection refused"),
+			want: []domain.TimelineEntry{
+				{Stage: domain.StageDeliveryAttempt, Attempt: 2, Outcome: domain.OutcomeFailure},
+				{Stage: domain.StageRetryScheduled, Attempt: 3},
+			},
+		},
+		{
+			name:     "last attempt failed",
+			retry:    3,
+			maxRetry: 3,
+			fetchErr: errors.New("connection refused"),
+			want: []domain.TimelineEntry{
+				{Stage: domain.StageDeliveryAttempt, Attempt: 4, Outcome: domain.OutcomeFailure},
+				{Stage: domain.StageDeadLettered},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockFetch := mocktaskapp.NewMockFetcher(ctrl)
+			mockInsights := mocktaskapp.NewMockConsumerInsights(ctrl)
+			mockInsights.EXPECT().Consumed(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			mockFetch.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.fetchErr)
+
+			var recorded []domain.TimelineEntry
+			recorder := mocktaskapp.NewMockTimelineRecorder(ctrl)
+			recorder.EXPECT().Record(gomock.Any(), gomock.Any()).Do(func(_ context.Context, entry domain.TimelineEntry) {
+				recorded = append(recorded, entry)
+			}).Times(len(tt.want))
+
+			payload, err := json.Marshal(taskapp.RequestPayload{
+				MessageID: "msg-1",
+				EventName: "payment.processed",
+				Consumer:  domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+				Data:      map[string]any{"id": "1"},
+			})
+			require.NoError(t, err)
+
+			handle := taskapp.GetRequestHandle(mockFetch, mockInsights, notPaused(ctrl), quarantine.Never, recorder)
+			err = handle.Handler(asyncadapter.NewRetryAsyncCtx[taskapp.RequestPayload](context.Background(), payload, tt.retry, tt.maxRetry))
+			assert.Equal(t, tt.fetchErr != nil, err != nil)
+
+			require.Len(t, recorded, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, "msg-1", recorded[i].MessageID)
+				assert.Equal(t, "payment.processed", recorded[i].EventName)
+				assert.Equal(t, "billing", recorded[i].Consumer)
+				assert.Equal(t, want.Stage, recorded[i].Stage)
+				assert.Equal(t, want.Attempt, recorded[i].Attempt)
+				assert.Equal(t, want.Outcome, recorded[i].Outcome)
+			}
+		})
+	}
+}
@@ -1,7 +0,0 @@
-package taskapp
-
-type Queue string
-
-func (q Queue) String() string {
-	return string(q)
-}
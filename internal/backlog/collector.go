@@ -0,0 +1,329 @@
+package backlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+	"github.com/IsaacDSC/gqueue/pkg/topicutils"
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// UnscannedEvent counts the tasks of a queue state past the scanned ones, their event is not known
+const UnscannedEvent = "unscanned"
+
+// Task states exported by the collector, active tasks are already being processed
+const (
+	StatePending   = "pending"
+	StateScheduled = "scheduled"
+	StateRetry     = "retry"
+	StateArchived  = "archived"
+)
+
+// subscribedQueues are the topics every project has a Pub/Sub subscription to
+var subscribedQueues = []string{domain.EventQueueRequestToExternal, domain.EventQueueDeadLetter}
+
+// Inspector is the subset of asynq.Inspector used to read the depth of the queues
+type Inspector interface {
+	Queues() ([]string, error)
+	GetQueueInfo(queue string) (*asynq.QueueInfo, error)
+	ListPendingTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+	ListScheduledTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+	ListRetryTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+	ListArchivedTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+}
+
+type EventStore interface {
+	ListProjects(ctx context.Context) ([]domain.Project, error)
+}
+
+// SubscriptionReader reads the messages not yet acknowledged in the Pub/Sub subscriptions, the
+// subscriptions it has no value for are left out
+type SubscriptionReader interface {
+	Backlog(ctx context.Context, subscriptions []string) (map[string]int64, error)
+}
+
+type Config struct {
+	// Interval is how often the backlog is exported
+	Interval time.Duration
+	// MaxScannedTasks is how many tasks of a queue state are read to split it by event
+	MaxScannedTasks int
+}
+
+// TaskSample is the number of tasks of an event in a state of an asynq queue
+type TaskSample struct {
+	Queue string
+	State string
+	Type  string
+	Event string
+	Size  int64
+}
+
+// SubscriptionSample is the number of messages not yet acknowledged in the Pub/Sub subscription
+// of a project topic
+type SubscriptionSample struct {
+	Project      string
+	Subscription string
+	Event        string
+	Size         int64
+}
+
+// Collector exports the backlog of the asynq queues and of the Pub/Sub subscriptions as gauges
+type Collector struct {
+	inspector     Inspector
+	events        EventStore
+	subscriptions SubscriptionReader
+	cfg           Config
+	// exported are the series of the last collection, the ones gone since are set to zero
+	exported seriesSet
+}
+
+// NewCollector creates the collector, subscriptions is nil when the Pub/Sub backlog is not exported
+func NewCollector(inspector Inspector, events EventStore, subscriptions SubscriptionReader, cfg Config) *Collector {
+	return &Collector{
+		inspector:     inspector,
+		events:        events,
+		subscriptions: subscriptions,
+		cfg:           cfg,
+		exported:      make(seriesSet),
+	}
+}
+
+// Run exports the backlog at every interval until ctx is cancelled. Every backoffice instance
+// exports the same values, aggregate them with max rather than sum.
+func (c *Collector) Run(ctx context.Context) {
+	l := ctxlogger.GetLogger(ctx)
+	ctx = telemetry.WithMeter(ctx, telemetry.Meter("backlog"))
+
+	trigger := time.NewTicker(c.cfg.Interval)
+	defer trigger.Stop()
+
+	for {
+		select {
+		case <-trigger.C:
+			if err := c.Collect(ctx); err != nil {
+				l.Error("Error collecting the backlog", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Collect exports the backlog once
+func (c *Collector) Collect(ctx context.Context) error {
+	l := ctxlogger.GetLogger(ctx)
+	collected := make(seriesSet)
+
+	tasks, err := c.TaskBacklog(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range tasks {
+		collected.add(telemetry.TaskQueueBacklog, s.Size,
+			attribute.String("queue", s.Queue),
+			attribute.String("state", s.State),
+			attribute.String("task.type", s.Type),
+			attribute.String("event", s.Event))
+	}
+
+	var subscriptionsFailed bool
+	if c.subscriptions != nil {
+		subscriptions, err := c.SubscriptionBacklog(ctx)
+		if err != nil {
+			// the task backlog is still exported, the subscriptions keep their last values
+			l.Error("Error reading the subscriptions backlog", "error", err)
+			subscriptionsFailed = true
+		}
+
+		for _, s := range subscriptions {
+			collected.add(telemetry.PubSubSubscriptionBacklog, s.Size,
+				attribute.String("project", s.Project),
+				attribute.String("subscription", s.Subscription),
+				attribute.String("event", s.Event))
+		}
+	}
+
+	for key, s := range collected {
+		s.metric.Set(ctx, s.value, s.attrs...)
+		c.exported[key] = s
+	}
+
+	for key, s := range c.exported {
+		if _, ok := collected[key]; ok {
+			continue
+		}
+
+		if subscriptionsFailed && s.metric == telemetry.PubSubSubscriptionBacklog {
+			continue
+		}
+
+		s.metric.Set(ctx, 0, s.attrs...)
+		delete(c.exported, key)
+	}
+
+	return nil
+}
+
+// TaskBacklog reads every asynq queue, the tasks of a state are split by the event of their payload
+// up to the max scanned tasks
+func (c *Collector) TaskBacklog(ctx context.Context) ([]TaskSample, error) {
+	l := ctxlogger.GetLogger(ctx)
+
+	queues, err := c.inspector.Queues()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queues: %w", err)
+	}
+
+	var samples []TaskSample
+	for _, queue := range queues {
+		info, err := c.inspector.GetQueueInfo(queue)
+		if err != nil {
+			l.Error("Failed to fetch queue info", "queue", queue, "error", err)
+			continue
+		}
+
+		states := []struct {
+			name string
+			size int
+			list func(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+		}{
+			{StatePending, info.Pending, c.inspector.ListPendingTasks},
+			{StateScheduled, info.Scheduled, c.inspector.ListScheduledTasks},
+			{StateRetry, info.Retry, c.inspector.ListRetryTasks},
+			{StateArchived, info.Archived, c.inspector.ListArchivedTasks},
+		}
+
+		for _, state := range states {
+			if state.size == 0 {
+				continue
+			}
+
+			counts, scanned, err := scan(queue, min(state.size, c.cfg.MaxScannedTasks), state.list)
+			if err != nil {
+				l.Error("Failed to list tasks", "queue", queue, "state", state.name, "error", err)
+				continue
+			}
+
+			for key, size := range counts {
+				samples = append(samples, TaskSample{Queue: queue, State: state.name, Type: key.taskType, Event: key.event, Size: size})
+			}
+
+			if unscanned := int64(state.size) - scanned; unscanned > 0 {
+				samples = append(samples, TaskSample{Queue: queue, State: state.name, Event: UnscannedEvent, Size: unscanned})
+			}
+		}
+	}
+
+	return samples, nil
+}
+
+type taskKey struct {
+	taskType string
+	event    string
+}
+
+const scanPageSize = 100
+
+// scan reads up to limit tasks of the queue and counts them by type and event
+func scan(queue string, limit int, list func(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)) (map[taskKey]int64, int64, error) {
+	counts := make(map[taskKey]int64)
+	var scanned int64
+
+	for page := 1; scanned < int64(limit); page++ {
+		tasks, err := list(queue, asynq.Page(page), asynq.PageSize(min(scanPageSize, limit)))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, task := range tasks {
+			if scanned == int64(limit) {
+				break
+			}
+
+			counts[taskKey{taskType: task.Type, event: eventOf(task)}]++
+			scanned++
+		}
+
+		if len(tasks) < min(scanPageSize, limit) {
+			break
+		}
+	}
+
+	return counts, scanned, nil
+}
+
+// eventOf is the event of the task payload, tasks without one are counted by their type
+func eventOf(task *asynq.TaskInfo) string {
+	var payload struct {
+		EventName string `json:"event_name"`
+	}
+
+	if err := json.Unmarshal(task.Payload, &payload); err != nil || payload.EventName == "" {
+		return task.Type
+	}
+
+	return payload.EventName
+}
+
+// SubscriptionBacklog reads the subscriptions of the topics of every project. A subscription is
+// shared by every event of the project, the event of a sample is the gqueue queue it consumes.
+func (c *Collector) SubscriptionBacklog(ctx context.Context) ([]SubscriptionSample, error) {
+	projects, err := c.events.ListProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var samples []SubscriptionSample
+	var names []string
+	for _, project := range projects {
+		for _, queue := range subscribedQueues {
+			name := topicutils.BuildSubscriptionName(project.TopicName(queue))
+			samples = append(samples, SubscriptionSample{Project: project.ID, Subscription: name, Event: queue})
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	backlog, err := c.subscriptions.Backlog(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the subscriptions backlog: %w", err)
+	}
+
+	output := make([]SubscriptionSample, 0, len(samples))
+	for _, sample := range samples {
+		size, ok := backlog[sample.Subscription]
+		if !ok {
+			continue
+		}
+
+		sample.Size = size
+		output = append(output, sample)
+	}
+
+	return output, nil
+}
+
+type series struct {
+	metric telemetry.Metric
+	value  int64
+	attrs  []attribute.KeyValue
+}
+
+type seriesSet map[string]series
+
+// add sets the value of the series of the metric with the attributes
+func (set seriesSet) add(metric telemetry.Metric, value int64, attrs ...attribute.KeyValue) {
+	distinct := attribute.NewSet(attrs...)
+	key := metric.Name + "|" + distinct.Encoded(attribute.DefaultEncoder())
+	set[key] = series{metric: metric, value: value, attrs: attrs}
+}
@@ -0,0 +1,137 @@
+package backlog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/backlog"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/mocks/mockbacklog"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+	"github.com/IsaacDSC/gqueue/pkg/topicutils"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/mock/gomock"
+)
+
+func task(taskType, payload string) *asynq.TaskInfo {
+	return &asynq.TaskInfo{Type: taskType, Payload: []byte(payload)}
+}
+
+func TestCollector_TaskBacklog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inspector := mockbacklog.NewMockInspector(ctrl)
+
+	inspector.EXPECT().Queues().Return([]string{"external.high", "external.low"}, nil)
+	inspector.EXPECT().GetQueueInfo("external.high").Return(&asynq.QueueInfo{Queue: "external.high", Pending: 5, Retry: 1}, nil)
+	inspector.EXPECT().GetQueueInfo("external.low").Return(nil, errors.New("redis down"))
+
+	// only the max scanned tasks are read, the others are unscanned
+	inspector.EXPECT().ListPendingTasks("external.high", gomock.Any(), gomock.Any()).Return([]*asynq.TaskInfo{
+		task("request-to-external", `{"event_name":"payment.processed"}`),
+		task("request-to-external", `{"event_name":"payment.processed"}`),
+		task("request-to-external", `{"event_name":"user.created"}`),
+	}, nil)
+	inspector.EXPECT().ListRetryTasks("external.high", gomock.Any(), gomock.Any()).Return([]*asynq.TaskInfo{
+		task("internal", `not json`),
+	}, nil)
+
+	collector := backlog.NewCollector(inspector, mockbacklog.NewMockEventStore(ctrl), nil, backlog.Config{MaxScannedTasks: 3})
+
+	samples, err := collector.TaskBacklog(context.Background())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []backlog.TaskSample{
+		{Queue: "external.high", State: backlog.StatePending, Type: "request-to-external", Event: "payment.processed", Size: 2},
+		{Queue: "external.high", State: backlog.StatePending, Type: "request-to-external", Event: "user.created", Size: 1},
+		{Queue: "external.high", State: backlog.StatePending, Event: backlog.UnscannedEvent, Size: 2},
+		{Queue: "external.high", State: backlog.StateRetry, Type: "internal", Event: "internal", Size: 1},
+	}, samples)
+}
+
+func TestCollector_SubscriptionBacklog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	events := mockbacklog.NewMockEventStore(ctrl)
+	subscriptions := mockbacklog.NewMockSubscriptionReader(ctrl)
+
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme"}
+	events.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{acme}, nil)
+
+	requests := topicutils.BuildSubscriptionName(acme.TopicName(domain.EventQueueRequestToExternal))
+	deadLetters := topicutils.BuildSubscriptionName(acme.TopicName(domain.EventQueueDeadLetter))
+
+	// the dead letter subscription has no sample yet
+	subscriptions.EXPECT().Backlog(gomock.Any(), []string{requests, deadLetters}).Return(map[string]int64{requests: 42}, nil)
+
+	collector := backlog.NewCollector(mockbacklog.NewMockInspector(ctrl), events, subscriptions, backlog.Config{})
+
+	samples, err := collector.SubscriptionBacklog(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []backlog.SubscriptionSample{
+		{Project: "acme", Subscription: requests, Event: domain.EventQueueRequestToExternal, Size: 42},
+	}, samples)
+}
+
+func TestCollector_Collect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inspector := mockbacklog.NewMockInspector(ctrl)
+	events := mockbacklog.NewMockEventStore(ctrl)
+	subscriptions := mockbacklog.NewMockSubscriptionReader(ctrl)
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	ctx := telemetry.WithMeter(context.Background(), provider.Meter("backlog"))
+
+	inspector.EXPECT().Queues().Return([]string{"external.high"}, nil).Times(2)
+	events.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject()}, nil).Times(2)
+
+	collector := backlog.NewCollector(inspector, events, subscriptions, backlog.Config{Interval: time.Minute, MaxScannedTasks: 100})
+
+	inspector.EXPECT().GetQueueInfo("external.high").Return(&asynq.QueueInfo{Pending: 1}, nil)
+	inspector.EXPECT().ListPendingTasks("external.high", gomock.Any(), gomock.Any()).Return([]*asynq.TaskInfo{
+		task("request-to-external", `{"event_name":"payment.processed"}`),
+	}, nil)
+	subscriptions.EXPECT().Backlog(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, names []string) (map[string]int64, error) {
+		return map[string]int64{names[0]: 7}, nil
+	})
+
+	require.NoError(t, collector.Collect(ctx))
+	assert.Equal(t, int64(1), gaugeSum(t, reader, telemetry.TaskQueueBacklog.Name))
+	assert.Equal(t, int64(7), gaugeSum(t, reader, telemetry.PubSubSubscriptionBacklog.Name))
+
+	// the drained queue is set to zero, the subscriptions keep their value while they can't be read
+	inspector.EXPECT().GetQueueInfo("external.high").Return(&asynq.QueueInfo{}, nil)
+	subscriptions.EXPECT().Backlog(gomock.Any(), gomock.Any()).Return(nil, errors.New("permission denied"))
+
+	require.NoError(t, collector.Collect(ctx))
+	assert.Equal(t, int64(0), gaugeSum(t, reader, telemetry.TaskQueueBacklog.Name))
+	assert.Equal(t, int64(7), gaugeSum(t, reader, telemetry.PubSubSubscriptionBacklog.Name))
+}
+
+func gaugeSum(t *testing.T, reader *sdkmetric.ManualReader, name string) int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	var sum int64
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != name {
+				continue
+			}
+
+			for _, point := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+				sum += point.Value
+			}
+		}
+	}
+
+	return sum
+}
@@ -0,0 +1,64 @@
+package backlog
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	monitoring "google.golang.org/api/monitoring/v3"
+)
+
+// undeliveredMessages is sampled by Cloud Monitoring every minute, the lookback finds the latest
+// sample even when it is late
+const (
+	undeliveredMessages = "pubsub.googleapis.com/subscription/num_undelivered_messages"
+	lookback            = 5 * time.Minute
+)
+
+// MonitoringReader reads the backlog of the subscriptions from Cloud Monitoring, the Pub/Sub
+// emulator does not report it
+type MonitoringReader struct {
+	service   *monitoring.Service
+	projectID string
+}
+
+// NewMonitoringReader reads the subscriptions of the Google Cloud project with the default credentials
+func NewMonitoringReader(ctx context.Context, projectID string) (*MonitoringReader, error) {
+	service, err := monitoring.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create monitoring service: %w", err)
+	}
+
+	return &MonitoringReader{service: service, projectID: projectID}, nil
+}
+
+func (r *MonitoringReader) Backlog(ctx context.Context, subscriptions []string) (map[string]int64, error) {
+	now := time.Now()
+	output := make(map[string]int64, len(subscriptions))
+
+	err := r.service.Projects.TimeSeries.List("projects/"+r.projectID).
+		Filter(fmt.Sprintf(`metric.type = "%s" AND resource.type = "pubsub_subscription"`, undeliveredMessages)).
+		IntervalStartTime(now.Add(-lookback).Format(time.RFC3339)).
+		IntervalEndTime(now.Format(time.RFC3339)).
+		Pages(ctx, func(page *monitoring.ListTimeSeriesResponse) error {
+			for _, series := range page.TimeSeries {
+				subscription := series.Resource.Labels["subscription_id"]
+				if !slices.Contains(subscriptions, subscription) || len(series.Points) == 0 {
+					continue
+				}
+
+				// the points are returned newest first
+				if value := series.Points[0].Value.Int64Value; value != nil {
+					output[subscription] = *value
+				}
+			}
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time series: %w", err)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,184 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	AsyncAPIV2 = "2.6.0"
+	AsyncAPIV3 = "3.0.0"
+)
+
+// invalidKey matches the characters AsyncAPI does not allow in component keys
+var invalidKey = regexp.MustCompile(`[^a-zA-Z0-9.\-_]`)
+
+type object = map[string]any
+
+// Info describes the application the AsyncAPI document is about
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// ParseAsyncAPIVersion accepts a major version or a full version of AsyncAPI, 3 when empty
+func ParseAsyncAPIVersion(version string) (string, error) {
+	switch version {
+	case "", "3", AsyncAPIV3:
+		return AsyncAPIV3, nil
+	case "2", AsyncAPIV2:
+		return AsyncAPIV2, nil
+	default:
+		return "", fmt.Errorf("unsupported asyncapi version %q, use 2 or 3", version)
+	}
+}
+
+// AsyncAPI renders the entries as an AsyncAPI document. Every event is a channel named after it,
+// its schema is the payload of its message, and its consumers and delivery options are kept in
+// x-gqueue extensions.
+func AsyncAPI(version string, info Info, entries []Entry) (map[string]any, error) {
+	version, err := ParseAsyncAPIVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := object{
+		"asyncapi":           version,
+		"info":               info.object(),
+		"defaultContentType": "application/json",
+	}
+
+	channels := object{}
+	messages := object{}
+	operations := object{}
+	for _, entry := range entries {
+		key := componentKey(entry.Name)
+		messages[key] = entry.message()
+
+		if version == AsyncAPIV2 {
+			channels[entry.Name] = entry.channelV2(key)
+			continue
+		}
+
+		channels[key] = entry.channelV3(key)
+		for name, operation := range entry.operationsV3(key) {
+			operations[name] = operation
+		}
+	}
+
+	doc["channels"] = channels
+	doc["components"] = object{"messages": messages}
+	if version == AsyncAPIV3 {
+		doc["operations"] = operations
+	}
+
+	return doc, nil
+}
+
+func (i Info) object() object {
+	info := object{"title": i.Title, "version": i.Version}
+	if i.Description != "" {
+		info["description"] = i.Description
+	}
+
+	return info
+}
+
+func (e Entry) message() object {
+	message := object{
+		"name":        e.Name,
+		"title":       e.Name,
+		"contentType": "application/json",
+	}
+
+	if e.Description != "" {
+		message["summary"] = e.Description
+	}
+
+	// events without a registered schema are documented without a payload
+	if len(e.Schema) > 0 {
+		message["payload"] = e.Schema
+	}
+
+	return message
+}
+
+// extensions are the gqueue specific fields of the channel of the event
+func (e Entry) extensions(channel object) object {
+	if e.Description != "" {
+		channel["description"] = e.Description
+	}
+
+	channel["x-gqueue-owner"] = e.Owner
+	channel["x-gqueue-delivery"] = e.Delivery
+	channel["x-gqueue-consumers"] = e.Consumers
+	channel["x-gqueue-paused"] = e.Paused
+
+	return channel
+}
+
+func (e Entry) channelV2(key string) object {
+	message := object{"$ref": "#/components/messages/" + key}
+
+	return e.extensions(object{
+		"publish": object{
+			"operationId": "publish_" + key,
+			"summary":     "Publish " + e.Name + " to gqueue",
+			"message":     message,
+		},
+		"subscribe": object{
+			"operationId": "deliver_" + key,
+			"summary":     "Deliver " + e.Name + " to " + e.consumerNames(),
+			"message":     message,
+		},
+	})
+}
+
+func (e Entry) channelV3(key string) object {
+	return e.extensions(object{
+		"address":  e.Name,
+		"messages": object{key: object{"$ref": "#/components/messages/" + key}},
+	})
+}
+
+// operationsV3 are the publication of the event and its delivery to each consumer
+func (e Entry) operationsV3(key string) object {
+	channel := object{"$ref": "#/channels/" + key}
+	messages := []object{{"$ref": "#/channels/" + key + "/messages/" + key}}
+
+	operations := object{
+		"publish_" + key: object{
+			"action":   "send",
+			"channel":  channel,
+			"summary":  "Publish " + e.Name + " to gqueue",
+			"messages": messages,
+		},
+	}
+
+	for _, consumer := range e.Consumers {
+		operations["deliver_"+key+"_"+componentKey(consumer.ServiceName)] = object{
+			"action":            "receive",
+			"channel":           channel,
+			"summary":           "Deliver " + e.Name + " to " + consumer.ServiceName,
+			"messages":          messages,
+			"x-gqueue-consumer": consumer,
+		}
+	}
+
+	return operations
+}
+
+func (e Entry) consumerNames() string {
+	names := make([]string, 0, len(e.Consumers))
+	for _, consumer := range e.Consumers {
+		names = append(names, consumer.ServiceName)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func componentKey(name string) string {
+	return invalidKey.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,110 @@
+package catalog
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+)
+
+// Entry describes a registered event: who owns it, the shape of its payload, who consumes it and
+// how it is delivered
+type Entry struct {
+	Name string `json:"name"`
+	domain.Owner
+	Schema    json.RawMessage `json:"schema,omitempty"`
+	Consumers []Consumer      `json:"consumers"`
+	Delivery  domain.Opt      `json:"delivery"`
+	Paused    bool            `json:"paused"`
+	Revision  int             `json:"revision"`
+}
+
+type Consumer struct {
+	ServiceName string `json:"service_name"`
+	URL         string `json:"url"`
+	domain.Owner
+	Paused bool `json:"paused"`
+}
+
+// Filter narrows the catalog, every field is a case insensitive substring and empty fields match
+// every event
+type Filter struct {
+	Name string `query:"name"`
+	// Owner matches the team owning the event or one of its consumers
+	Owner    string `query:"owner"`
+	Consumer string `query:"consumer"`
+}
+
+func NewEntry(event domain.Event) Entry {
+	consumers := make([]Consumer, 0, len(event.Consumers))
+	for _, consumer := range event.Consumers {
+		consumers = append(consumers, Consumer{
+			ServiceName: consumer.ServiceName,
+			URL:         consumer.GetUrl(),
+			Owner:       consumer.Owner,
+			Paused:      consumer.Paused,
+		})
+	}
+
+	return Entry{
+		Name:      event.Name,
+		Owner:     event.Owner,
+		Schema:    event.Schema,
+		Consumers: consumers,
+		Delivery:  event.Option,
+		Paused:    event.Paused,
+		Revision:  event.Revision,
+	}
+}
+
+// Search lists the events matching the filter sorted by name
+func Search(events []domain.Event, filter Filter) []Entry {
+	entries := make([]Entry, 0, len(events))
+	for _, event := range events {
+		entry := NewEntry(event)
+		if filter.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
+
+func (f Filter) Match(entry Entry) bool {
+	if !contains(entry.Name, f.Name) {
+		return false
+	}
+
+	if f.Owner != "" && !contains(entry.TeamOwner, f.Owner) && !entry.hasConsumer(func(c Consumer) bool {
+		return contains(c.TeamOwner, f.Owner)
+	}) {
+		return false
+	}
+
+	if f.Consumer != "" && !entry.hasConsumer(func(c Consumer) bool {
+		return contains(c.ServiceName, f.Consumer)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+func (e Entry) hasConsumer(match func(Consumer) bool) bool {
+	for _, consumer := range e.Consumers {
+		if match(consumer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(value, search string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(search))
+}
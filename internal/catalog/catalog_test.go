@@ -0,0 +1,128 @@
+package catalog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func catalogEvents() []domain.Event {
+	return []domain.Event{
+		{
+			Name:   "payment.processed",
+			Owner:  domain.Owner{TeamOwner: "payments", Description: "a payment was captured"},
+			Schema: json.RawMessage(`{"type":"object","required":["amount"]}`),
+			Option: domain.Opt{MaxRetries: 3, WqType: "low_throughput"},
+			Consumers: []domain.Consumer{
+				{ServiceName: "billing", BaseUrl: "http://billing/", Path: "/webhook", Owner: domain.Owner{TeamOwner: "finance"}},
+				{ServiceName: "ledger", BaseUrl: "http://ledger", Path: "events", Paused: true},
+			},
+		},
+		{
+			Name:      "order.created",
+			Owner:     domain.Owner{TeamOwner: "orders"},
+			Consumers: []domain.Consumer{{ServiceName: "shipping", BaseUrl: "http://shipping"}},
+		},
+	}
+}
+
+func TestSearch(t *testing.T) {
+	names := func(entries []Entry) []string {
+		output := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			output = append(output, entry.Name)
+		}
+		return output
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{name: "every event sorted by name", want: []string{"order.created", "payment.processed"}},
+		{name: "name", filter: Filter{Name: "PAYMENT"}, want: []string{"payment.processed"}},
+		{name: "owner of the event", filter: Filter{Owner: "orders"}, want: []string{"order.created"}},
+		{name: "owner of a consumer", filter: Filter{Owner: "finance"}, want: []string{"payment.processed"}},
+		{name: "consumer", filter: Filter{Consumer: "ship"}, want: []string{"order.created"}},
+		{name: "every filter must match", filter: Filter{Name: "order", Consumer: "billing"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, names(Search(catalogEvents(), tt.filter)))
+		})
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	entry := NewEntry(catalogEvents()[0])
+
+	assert.Equal(t, "payments", entry.TeamOwner)
+	assert.Equal(t, 3, entry.Delivery.MaxRetries)
+	require.Len(t, entry.Consumers, 2)
+	assert.Equal(t, "http://billing/webhook", entry.Consumers[0].URL)
+	assert.Equal(t, "finance", entry.Consumers[0].TeamOwner)
+	assert.True(t, entry.Consumers[1].Paused)
+}
+
+// render encodes the document and decodes it back, like a client of the endpoint reads it
+func render(t *testing.T, version string) map[string]any {
+	t.Helper()
+
+	doc, err := AsyncAPI(version, Info{Title: "Acme events", Version: "1.0.0"}, Search(catalogEvents(), Filter{}))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var output map[string]any
+	require.NoError(t, json.Unmarshal(data, &output))
+	return output
+}
+
+func TestAsyncAPI_V2(t *testing.T) {
+	doc := render(t, "2")
+
+	assert.Equal(t, AsyncAPIV2, doc["asyncapi"])
+	channel := doc["channels"].(map[string]any)["payment.processed"].(map[string]any)
+	assert.Equal(t, "a payment was captured", channel["description"])
+	assert.Equal(t, "publish_payment.processed", channel["publish"].(map[string]any)["operationId"])
+	assert.Equal(t, "Deliver payment.processed to billing, ledger", channel["subscribe"].(map[string]any)["summary"])
+	assert.Len(t, channel["x-gqueue-consumers"], 2)
+	assert.Equal(t, float64(3), channel["x-gqueue-delivery"].(map[string]any)["max_retries"])
+
+	messages := doc["components"].(map[string]any)["messages"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "object", "required": []any{"amount"}}, messages["payment.processed"].(map[string]any)["payload"])
+	assert.NotContains(t, messages["order.created"], "payload", "events without a schema have no payload")
+	assert.NotContains(t, doc, "operations")
+}
+
+func TestAsyncAPI_V3(t *testing.T) {
+	doc := render(t, "")
+
+	assert.Equal(t, AsyncAPIV3, doc["asyncapi"])
+	channel := doc["channels"].(map[string]any)["payment.processed"].(map[string]any)
+	assert.Equal(t, "payment.processed", channel["address"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/messages/payment.processed"},
+		channel["messages"].(map[string]any)["payment.processed"])
+
+	operations := doc["operations"].(map[string]any)
+	assert.Len(t, operations, 5, "one publication and one delivery per consumer")
+	assert.Equal(t, "send", operations["publish_payment.processed"].(map[string]any)["action"])
+
+	delivery := operations["deliver_payment.processed_billing"].(map[string]any)
+	assert.Equal(t, "receive", delivery["action"])
+	assert.Equal(t, map[string]any{"$ref": "#/channels/payment.processed"}, delivery["channel"])
+	assert.Equal(t, "http://billing/webhook", delivery["x-gqueue-consumer"].(map[string]any)["url"])
+}
+
+func TestAsyncAPI_Version(t *testing.T) {
+	_, err := AsyncAPI("1", Info{}, nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, "order_created_v1", componentKey("order/created v1"))
+}
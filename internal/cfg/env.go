@@ -21,6 +21,91 @@ type AsynqConfig struct {
 	Concurrency int `env:"WQ_CONCURRENCY"`
 }
 
+type SQLQueueConfig struct {
+	Enabled           bool          `env:"SQL_QUEUE_ENABLED" env-default:"false"`
+	PollInterval      time.Duration `env:"SQL_QUEUE_POLL_INTERVAL" env-default:"1s"`
+	VisibilityTimeout time.Duration `env:"SQL_QUEUE_VISIBILITY_TIMEOUT" env-default:"5m"`
+}
+
+type DeadLetterConfig struct {
+	Retention time.Duration `env:"DLQ_RETENTION" env-default:"168h"`
+}
+
+// TimelineConfig is how long the timeline of a message is kept after its last entry, and how many
+// entries wait to be written before new ones are dropped
+type TimelineConfig struct {
+	TTL    time.Duration `env:"TIMELINE_TTL" env-default:"72h"`
+	Buffer int           `env:"TIMELINE_BUFFER" env-default:"10000"`
+}
+
+// AlertsConfig is how often the backoffice evaluates the alert rules, and the webhook notified when
+// a rule starts or stops firing
+type AlertsConfig struct {
+	Interval   time.Duration `env:"ALERTS_EVALUATION_INTERVAL" env-default:"1m"`
+	WebhookURL string        `env:"ALERTS_WEBHOOK_URL"`
+}
+
+// QuarantineConfig is when a consumer failing over the window is quarantined, and how often the
+// consumers are scored and the quarantined ones probed
+type QuarantineConfig struct {
+	Enabled       bool          `env:"QUARANTINE_ENABLED" env-default:"false"`
+	Window        time.Duration `env:"QUARANTINE_WINDOW" env-default:"30m"`
+	FailureBudget float64       `env:"QUARANTINE_FAILURE_BUDGET" env-default:"0.5"`
+	MinDeliveries int64         `env:"QUARANTINE_MIN_DELIVERIES" env-default:"20"`
+	ProbeInterval time.Duration `env:"QUARANTINE_PROBE_INTERVAL" env-default:"1m"`
+}
+
+// BacklogConfig is how often the backoffice exports the depth of the queues, how many tasks of a
+// queue state are read to split it by event and if the Pub/Sub backlog is read from Cloud Monitoring
+type BacklogConfig struct {
+	Interval        time.Duration `env:"BACKLOG_COLLECT_INTERVAL" env-default:"30s"`
+	MaxScannedTasks int           `env:"BACKLOG_MAX_SCANNED_TASKS" env-default:"5000"`
+	PubSubEnabled   bool          `env:"BACKLOG_PUBSUB_ENABLED" env-default:"false"`
+}
+
+// InsightsConfig is how long the insights are kept at each resolution
+type InsightsConfig struct {
+	MinuteRetention time.Duration `env:"INSIGHTS_MINUTE_RETENTION" env-default:"48h"`
+	HourRetention   time.Duration `env:"INSIGHTS_HOUR_RETENTION" env-default:"720h"`
+	DayRetention    time.Duration `env:"INSIGHTS_DAY_RETENTION" env-default:"8760h"`
+}
+
+type PauseConfig struct {
+	// RedeliveryDelay is how long the message of a paused consumer waits before it is delivered again
+	RedeliveryDelay time.Duration `env:"PAUSE_REDELIVERY_DELAY" env-default:"30s"`
+}
+
+type ProjectsConfig struct {
+	// CacheTTL is how long a project is cached by the APIs, changes to a project apply after it
+	CacheTTL time.Duration `env:"PROJECTS_CACHE_TTL" env-default:"30s"`
+	// WatchInterval is how often the workers look for new projects to consume
+	WatchInterval time.Duration `env:"PROJECTS_WATCH_INTERVAL" env-default:"1m"`
+}
+
+type AuthConfig struct {
+	// Required rejects the requests that authenticate with neither an API key nor the secret of
+	// their project. Requests without credentials are not limited by scopes, so accepting them is
+	// an opt-in for local development.
+	Required bool `env:"AUTH_REQUIRED" env-default:"true"`
+	// APIKeysCacheTTL is how long an API key is cached by the APIs, a revoked key is rejected after it
+	APIKeysCacheTTL time.Duration `env:"API_KEYS_CACHE_TTL" env-default:"30s"`
+	JWT             JWTConfig
+}
+
+type JWTConfig struct {
+	// JWKS is the file or URL of the keys publisher tokens are signed with, tokens are not
+	// accepted when it is empty
+	JWKS        string        `env:"JWT_JWKS"`
+	JWKSRefresh time.Duration `env:"JWT_JWKS_REFRESH" env-default:"5m"`
+	Issuer      string        `env:"JWT_ISSUER"`
+	Audience    string        `env:"JWT_AUDIENCE"`
+	Leeway      time.Duration `env:"JWT_LEEWAY" env-default:"30s"`
+	// PublisherClaim, EventsClaim and ProjectClaim name the claims the publisher identity is read from
+	PublisherClaim string `env:"JWT_PUBLISHER_CLAIM" env-default:"sub"`
+	EventsClaim    string `env:"JWT_EVENTS_CLAIM" env-default:"gqueue_events"`
+	ProjectClaim   string `env:"JWT_PROJECT_CLAIM" env-default:"gqueue_project"`
+}
+
 type ServerPort int
 
 func (p ServerPort) String() string {
@@ -54,7 +139,19 @@ type Config struct {
 	ConfigDatabase ConfigDatabase
 	Cache          Cache
 	AsynqConfig    AsynqConfig
+	SQLQueue       SQLQueueConfig
+	DeadLetter     DeadLetterConfig
+	Insights       InsightsConfig
+	Timeline       TimelineConfig
+	Alerts         AlertsConfig
+	Quarantine     QuarantineConfig
+	Backlog        BacklogConfig
+	Pause          PauseConfig
+	Projects       ProjectsConfig
+	Auth           AuthConfig
 	WQ             WQ `env:"WQ"`
+	// GCPProjectID is the Google Cloud project of the Pub/Sub client
+	GCPProjectID string `env:"GOOGLE_CLOUD_PROJECT" env-default:"your-project-id"`
 	// InternalBaseURL TODO: será utilizado para buscar informações e não compartilhar banco de dados(backoffice, pubsub, task)
 	InternalBaseURL     string `env:"INTERNAL_BASE_URL"`
 	InternalServiceName string `env:"INTERNAL_SERVICE_NAME"`
@@ -68,6 +165,16 @@ type Config struct {
 	OTELExporterOTLPEndpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" env-default:""`
 	MaxConsumers             int    `env:"MAX_CONSUMERS" env-default:"10"`
 	LogLevel                 int    `env:"LOG_LEVEL" env-default:"2"` // 0: debug, 1: info, 2: warn, 3: error
+
+	// TracesSampleRatio is the share of the traces started by gqueue that are exported to
+	// OTEL_EXPORTER_OTLP_ENDPOINT
+	TracesSampleRatio float64 `env:"TRACES_SAMPLE_RATIO" env-default:"1"`
+	// MetricsExporters are prometheus, served on /metrics, and otlp, pushed to OTEL_EXPORTER_OTLP_ENDPOINT
+	MetricsExporters         []string      `env:"METRICS_EXPORTERS" env-default:"prometheus" env-separator:","`
+	OTELExporterOTLPProtocol string        `env:"OTEL_EXPORTER_OTLP_PROTOCOL" env-default:"http/protobuf"` // http/protobuf or grpc
+	MetricsExportInterval    time.Duration `env:"METRICS_EXPORT_INTERVAL" env-default:"60s"`
+	MetricsExportTimeout     time.Duration `env:"METRICS_EXPORT_TIMEOUT" env-default:"30s"`
+	MetricsTemporality       string        `env:"METRICS_TEMPORALITY" env-default:"cumulative"` // cumulative or delta
 }
 
 var cfg Config
@@ -86,6 +193,12 @@ func Get() Config {
 		panic("invalid log level")
 	}
 
+	// the durable_sql queue keeps its jobs in the Postgres database of the events, an empty driver
+	// is Postgres
+	if cfg.SQLQueue.Enabled && cfg.ConfigDatabase.Driver != "" && cfg.ConfigDatabase.Driver != "pg" {
+		panic(fmt.Sprintf("SQL_QUEUE_ENABLED requires DB_DRIVER=pg, got %q", cfg.ConfigDatabase.Driver))
+	}
+
 	return cfg
 }
 
@@ -0,0 +1,219 @@
+package dlqstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	messagePrefix = "gqueue:dlq:messages:"
+	indexKey      = "gqueue:dlq:index"
+	batchSize     = 200
+)
+
+// Store keeps dead-lettered messages in Redis. Each message has its own key expiring after the
+// retention and a sorted set indexes them by the time they were dead-lettered. The keys are
+// namespaced by the project in ctx.
+type Store struct {
+	cache     *redis.Client
+	retention time.Duration
+}
+
+func messageKey(ctx context.Context, id string) string {
+	return domain.ProjectFromContext(ctx).Namespace(messagePrefix + id)
+}
+
+func index(ctx context.Context) string {
+	return domain.ProjectFromContext(ctx).Namespace(indexKey)
+}
+
+func NewStore(cache *redis.Client, retention time.Duration) *Store {
+	return &Store{cache: cache, retention: retention}
+}
+
+func (s *Store) Save(ctx context.Context, dl domain.DeadLetter) error {
+	payload, err := json.Marshal(dl)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	expired := time.Now().Add(-s.retention).UnixMilli()
+
+	pipe := s.cache.TxPipeline()
+	pipe.Set(ctx, messageKey(ctx, dl.ID.String()), payload, s.retention)
+	pipe.ZAdd(ctx, index(ctx), redis.Z{Score: float64(dl.DeadAt.UnixMilli()), Member: dl.ID.String()})
+	pipe.ZRemRangeByScore(ctx, index(ctx), "-inf", strconv.FormatInt(expired, 10))
+	pipe.Expire(ctx, index(ctx), s.retention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id uuid.UUID) (domain.DeadLetter, error) {
+	payload, err := s.cache.Get(ctx, messageKey(ctx, id.String())).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return domain.DeadLetter{}, domain.DeadLetterNotFound
+	}
+
+	if err != nil {
+		return domain.DeadLetter{}, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+
+	var dl domain.DeadLetter
+	if err := json.Unmarshal(payload, &dl); err != nil {
+		return domain.DeadLetter{}, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+	}
+
+	return dl, nil
+}
+
+// List returns the dead letters matching the filters, newest first
+func (s *Store) List(ctx context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error) {
+	skip := 0
+	if filters.Page > 1 {
+		skip = int((filters.Page - 1) * filters.Limit)
+	}
+
+	output := make([]domain.DeadLetter, 0)
+	err := s.scan(ctx, filters, func(dl domain.DeadLetter) bool {
+		if skip > 0 {
+			skip--
+			return true
+		}
+
+		output = append(output, dl)
+		return filters.Limit == 0 || len(output) < int(filters.Limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// Delete removes the given dead letters and returns how many existed
+func (s *Store) Delete(ctx context.Context, ids ...uuid.UUID) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	members := make([]any, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, messageKey(ctx, id.String()))
+		members = append(members, id.String())
+	}
+
+	pipe := s.cache.TxPipeline()
+	deleted := pipe.Del(ctx, keys...)
+	pipe.ZRem(ctx, index(ctx), members...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to delete dead letters: %w", err)
+	}
+
+	return int(deleted.Val()), nil
+}
+
+// Purge removes every dead letter matching the filters, pagination is ignored
+func (s *Store) Purge(ctx context.Context, filters domain.FilterDeadLetters) (int, error) {
+	var ids []uuid.UUID
+	if err := s.scan(ctx, filters, func(dl domain.DeadLetter) bool {
+		ids = append(ids, dl.ID)
+		return true
+	}); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for start := 0; start < len(ids); start += batchSize {
+		end := min(start+batchSize, len(ids))
+		n, err := s.Delete(ctx, ids[start:end]...)
+		if err != nil {
+			return purged, err
+		}
+		purged += n
+	}
+
+	return purged, nil
+}
+
+// scan walks the index newest first within the time range, calling fn for every matching
+// dead letter until fn returns false. Index entries whose message expired are dropped.
+func (s *Store) scan(ctx context.Context, filters domain.FilterDeadLetters, fn func(dl domain.DeadLetter) bool) error {
+	from, to, err := filters.Range()
+	if err != nil {
+		return err
+	}
+
+	rangeBy := &redis.ZRangeBy{Min: "-inf", Max: "+inf", Count: batchSize}
+	if !from.IsZero() {
+		rangeBy.Min = strconv.FormatInt(from.UnixMilli(), 10)
+	}
+
+	if !to.IsZero() {
+		rangeBy.Max = strconv.FormatInt(to.UnixMilli(), 10)
+	}
+
+	for {
+		ids, err := s.cache.ZRevRangeByScore(ctx, index(ctx), rangeBy).Result()
+		if err != nil {
+			return fmt.Errorf("failed to list dead letters: %w", err)
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		keys := make([]string, 0, len(ids))
+		for _, id := range ids {
+			keys = append(keys, messageKey(ctx, id))
+		}
+
+		values, err := s.cache.MGet(ctx, keys...).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get dead letters: %w", err)
+		}
+
+		var expired []any
+		for i, value := range values {
+			payload, ok := value.(string)
+			if !ok {
+				expired = append(expired, ids[i])
+				continue
+			}
+
+			var dl domain.DeadLetter
+			if err := json.Unmarshal([]byte(payload), &dl); err != nil {
+				return fmt.Errorf("failed to unmarshal dead letter: %w", err)
+			}
+
+			if filters.Match(dl) && !fn(dl) {
+				return nil
+			}
+		}
+
+		if len(expired) > 0 {
+			if err := s.cache.ZRem(ctx, index(ctx), expired...).Err(); err != nil {
+				return fmt.Errorf("failed to drop expired dead letters: %w", err)
+			}
+		}
+
+		if len(ids) < batchSize {
+			return nil
+		}
+
+		// the last batch removed expired members, so the offset only counts the ones kept
+		rangeBy.Offset += int64(len(ids) - len(expired))
+	}
+}
@@ -0,0 +1,192 @@
+package dlqstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewStore(client, time.Hour), server
+}
+
+func newDeadLetter(eventName, consumer string, deadAt time.Time) domain.DeadLetter {
+	return domain.DeadLetter{
+		ID:        uuid.New(),
+		EventName: eventName,
+		Consumer:  domain.Consumer{ServiceName: consumer},
+		Payload:   []byte(`{"event_name":"` + eventName + `"}`),
+		LastError: "status 500",
+		DeadAt:    deadAt,
+	}
+}
+
+func names(dls []domain.DeadLetter) []string {
+	output := make([]string, 0, len(dls))
+	for _, dl := range dls {
+		output = append(output, dl.EventName+"/"+dl.Consumer.ServiceName)
+	}
+	return output
+}
+
+func TestStore_SaveAndGet(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	dl := newDeadLetter("payment.processed", "billing", time.Now().Truncate(time.Millisecond))
+	require.NoError(t, store.Save(ctx, dl))
+
+	got, err := store.Get(ctx, dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dl.EventName, got.EventName)
+	assert.Equal(t, dl.LastError, got.LastError)
+	assert.JSONEq(t, string(dl.Payload), string(got.Payload))
+	assert.True(t, dl.DeadAt.Equal(got.DeadAt))
+
+	_, err = store.Get(ctx, uuid.New())
+	assert.ErrorIs(t, err, domain.DeadLetterNotFound)
+}
+
+func TestStore_List(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	base := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	deadLetters := []domain.DeadLetter{
+		newDeadLetter("payment.processed", "billing", base),
+		newDeadLetter("payment.processed", "ledger", base.Add(time.Minute)),
+		newDeadLetter("order.created", "billing", base.Add(2*time.Minute)),
+		newDeadLetter("order.created", "shipping", base.Add(3*time.Minute)),
+	}
+	deadLetters[1].EventOwner.TeamOwner = "payments"
+	deadLetters[3].Consumer.TeamOwner = "payments"
+
+	for i, dl := range deadLetters {
+		require.NoError(t, store.Save(ctx, dl), i)
+	}
+
+	tests := []struct {
+		name     string
+		filters  domain.FilterDeadLetters
+		expected []string
+	}{
+		{
+			name:     "newest_first",
+			filters:  domain.FilterDeadLetters{},
+			expected: []string{"order.created/shipping", "order.created/billing", "payment.processed/ledger", "payment.processed/billing"},
+		},
+		{
+			name:     "by_event_name",
+			filters:  domain.FilterDeadLetters{EventName: []string{"payment.processed"}},
+			expected: []string{"payment.processed/ledger", "payment.processed/billing"},
+		},
+		{
+			name:     "by_consumer",
+			filters:  domain.FilterDeadLetters{Consumer: []string{"billing"}},
+			expected: []string{"order.created/billing", "payment.processed/billing"},
+		},
+		{
+			name:     "by_team_owner_of_event_or_consumer",
+			filters:  domain.FilterDeadLetters{TeamOwner: []string{"payments"}},
+			expected: []string{"order.created/shipping", "payment.processed/ledger"},
+		},
+		{
+			name: "by_time_range",
+			filters: domain.FilterDeadLetters{
+				From: base.Add(time.Minute).Format(time.RFC3339),
+				To:   base.Add(2 * time.Minute).Format(time.RFC3339),
+			},
+			expected: []string{"order.created/billing", "payment.processed/ledger"},
+		},
+		{
+			name:     "pagination",
+			filters:  domain.FilterDeadLetters{Page: 2, Limit: 3},
+			expected: []string{"payment.processed/billing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.List(ctx, tt.filters)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, names(got))
+		})
+	}
+}
+
+func TestStore_ListDropsExpired(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestStore(t)
+
+	expired := newDeadLetter("payment.processed", "billing", time.Now())
+	kept := newDeadLetter("order.created", "billing", time.Now())
+	require.NoError(t, store.Save(ctx, expired))
+	require.NoError(t, store.Save(ctx, kept))
+
+	server.Del(messagePrefix + expired.ID.String())
+
+	got, err := store.List(ctx, domain.FilterDeadLetters{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"order.created/billing"}, names(got))
+
+	members, err := server.ZMembers(indexKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{kept.ID.String()}, members)
+}
+
+func TestStore_DeleteAndPurge(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	now := time.Now()
+	first := newDeadLetter("payment.processed", "billing", now)
+	second := newDeadLetter("payment.processed", "ledger", now.Add(time.Second))
+	third := newDeadLetter("order.created", "billing", now.Add(2*time.Second))
+	for _, dl := range []domain.DeadLetter{first, second, third} {
+		require.NoError(t, store.Save(ctx, dl))
+	}
+
+	deleted, err := store.Delete(ctx, first.ID, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	purged, err := store.Purge(ctx, domain.FilterDeadLetters{EventName: []string{"payment.processed"}, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	got, err := store.List(ctx, domain.FilterDeadLetters{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"order.created/billing"}, names(got))
+}
+
+func TestStore_ProjectIsolation(t *testing.T) {
+	store, _ := newTestStore(t)
+	acme := domain.WithProject(context.Background(), domain.Project{ID: "acme"})
+
+	dl := newDeadLetter("payment.processed", "billing", time.Now())
+	require.NoError(t, store.Save(acme, dl))
+
+	_, err := store.Get(context.Background(), dl.ID)
+	assert.ErrorIs(t, err, domain.DeadLetterNotFound, "dead letters of other projects are not found")
+
+	output, err := store.List(context.Background(), domain.FilterDeadLetters{})
+	require.NoError(t, err)
+	assert.Empty(t, output)
+
+	output, err = store.List(acme, domain.FilterDeadLetters{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payment.processed/billing"}, names(output))
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
+	"github.com/google/uuid"
+)
+
+// AlertKind is what an alert rule watches
+type AlertKind string
+
+const (
+	// AlertFailureRate fires when the share of failed deliveries is above the threshold, from 0 to 1
+	AlertFailureRate AlertKind = "failure_rate"
+	// AlertDLQGrowth fires when more messages than the threshold were dead-lettered in the window
+	AlertDLQGrowth AlertKind = "dlq_growth"
+	// AlertConsumerLag fires when the p95 of the time between the publication and the delivery
+	// is above the threshold, in milliseconds
+	AlertConsumerLag AlertKind = "consumer_lag"
+	// AlertNoPublishes fires when an event was published less times than the threshold, 1 by
+	// default, in the window
+	AlertNoPublishes AlertKind = "no_publishes"
+)
+
+const (
+	minAlertWindow = time.Minute
+	// maxAlertWindow keeps the evaluation on the minute buckets of the insights
+	maxAlertWindow = 24 * time.Hour
+)
+
+// AlertRule is evaluated periodically over the last Window of the metrics of the event and
+// consumer, every event and consumer of the project when they are empty
+type AlertRule struct {
+	ID        uuid.UUID          `json:"id"`
+	ProjectID string             `json:"project_id"`
+	Name      string             `json:"name"`
+	Kind      AlertKind          `json:"kind"`
+	Event     string             `json:"event,omitempty"`
+	Consumer  string             `json:"consumer,omitempty"`
+	Threshold float64            `json:"threshold"`
+	Window    intertime.Duration `json:"window"`
+	// MinSamples is the number of deliveries a failure rate needs to be meaningful, 1 by default
+	MinSamples int64     `json:"min_samples,omitempty"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (r AlertRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	window := time.Duration(r.Window)
+	if window < minAlertWindow || window > maxAlertWindow || window%time.Minute != 0 {
+		return fmt.Errorf("window must be a whole number of minutes from %s to %s", minAlertWindow, maxAlertWindow)
+	}
+
+	if r.MinSamples < 0 {
+		return errors.New("min_samples must not be negative")
+	}
+
+	switch r.Kind {
+	case AlertFailureRate:
+		if r.Threshold <= 0 || r.Threshold >= 1 {
+			return errors.New("the threshold of a failure rate is a share between 0 and 1")
+		}
+	case AlertDLQGrowth:
+		if r.Threshold < 0 {
+			return errors.New("the threshold of a dlq growth must not be negative")
+		}
+	case AlertConsumerLag:
+		if r.Threshold <= 0 {
+			return errors.New("the threshold of a consumer lag is a positive number of milliseconds")
+		}
+	case AlertNoPublishes:
+		if r.Event == "" {
+			return errors.New("event is required to watch its publications")
+		}
+		if r.Consumer != "" {
+			return errors.New("publications have no consumer")
+		}
+		if r.Threshold < 0 {
+			return errors.New("the threshold of no publishes must not be negative")
+		}
+	default:
+		return fmt.Errorf("invalid kind %q: use %s, %s, %s or %s", r.Kind, AlertFailureRate, AlertDLQGrowth, AlertConsumerLag, AlertNoPublishes)
+	}
+
+	return nil
+}
+
+// Firing reports whether the value measured over the window breaks the rule
+func (r AlertRule) Firing(value float64) bool {
+	if r.Kind == AlertNoPublishes {
+		return value < max(r.Threshold, 1)
+	}
+
+	return value > r.Threshold
+}
+
+// AlertStatus is the result of the last evaluation of a rule
+type AlertStatus string
+
+const (
+	// AlertPending is a rule not evaluated yet
+	AlertPending AlertStatus = "pending"
+	AlertOK      AlertStatus = "ok"
+	AlertFiring  AlertStatus = "firing"
+	// AlertDisabled is a rule that is not evaluated
+	AlertDisabled AlertStatus = "disabled"
+)
+
+// AlertState is what the last evaluation of a rule measured
+type AlertState struct {
+	RuleID uuid.UUID   `json:"rule_id"`
+	Status AlertStatus `json:"status"`
+	Value  float64     `json:"value"`
+	// Since is when the rule entered its status
+	Since       time.Time `json:"since"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+	// Notified is the last status sent to the webhook, a failed notification is sent again at
+	// the next evaluation
+	Notified AlertStatus `json:"notified"`
+}
+
+// NeedsNotification reports whether the webhook has not been told about the status yet. A rule
+// that never fired has nothing to resolve.
+func (s AlertState) NeedsNotification() bool {
+	if s.Status == AlertFiring {
+		return s.Notified != AlertFiring
+	}
+
+	return s.Notified == AlertFiring
+}
+
+// AlertRuleState is a rule with the result of its last evaluation
+type AlertRuleState struct {
+	Rule        AlertRule   `json:"rule"`
+	Status      AlertStatus `json:"status"`
+	Value       float64     `json:"value"`
+	Since       *time.Time  `json:"since,omitempty"`
+	EvaluatedAt *time.Time  `json:"evaluated_at,omitempty"`
+}
+
+// NewAlertRuleState joins the rule with its state, if it was evaluated
+func NewAlertRuleState(rule AlertRule, state AlertState, evaluated bool) AlertRuleState {
+	output := AlertRuleState{Rule: rule, Status: AlertPending}
+	switch {
+	case !rule.Enabled:
+		output.Status = AlertDisabled
+	case evaluated:
+		output.Status = state.Status
+		output.Value = state.Value
+		output.Since = &state.Since
+		output.EvaluatedAt = &state.EvaluatedAt
+	}
+
+	return output
+}
+
+// AlertNotificationStatus is firing or resolved
+type AlertNotificationStatus string
+
+const (
+	AlertNotificationFiring   AlertNotificationStatus = "firing"
+	AlertNotificationResolved AlertNotificationStatus = "resolved"
+)
+
+// AlertNotification is posted to the alert webhook when a rule starts or stops firing. The owners
+// of the event and of the consumer of the rule tell who should look at it.
+type AlertNotification struct {
+	Status        AlertNotificationStatus `json:"status"`
+	ProjectID     string                  `json:"project_id"`
+	Rule          AlertRule               `json:"rule"`
+	Value         float64                 `json:"value"`
+	Since         time.Time               `json:"since"`
+	EvaluatedAt   time.Time               `json:"evaluated_at"`
+	EventOwner    *Owner                  `json:"event_owner,omitempty"`
+	ConsumerOwner *Owner                  `json:"consumer_owner,omitempty"`
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertRule_Validate(t *testing.T) {
+	valid := AlertRule{
+		Name:      "payments failing",
+		Kind:      AlertFailureRate,
+		Event:     "payment.created",
+		Threshold: 0.05,
+		Window:    intertime.Duration(5 * time.Minute),
+	}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		change func(rule *AlertRule)
+	}{
+		{"name is required", func(rule *AlertRule) { rule.Name = "" }},
+		{"window shorter than a minute", func(rule *AlertRule) { rule.Window = intertime.Duration(30 * time.Second) }},
+		{"window not in whole minutes", func(rule *AlertRule) { rule.Window = intertime.Duration(90 * time.Second) }},
+		{"window longer than a day", func(rule *AlertRule) { rule.Window = intertime.Duration(25 * time.Hour) }},
+		{"failure rate is a share", func(rule *AlertRule) { rule.Threshold = 5 }},
+		{"negative min samples", func(rule *AlertRule) { rule.MinSamples = -1 }},
+		{"unknown kind", func(rule *AlertRule) { rule.Kind = "latency" }},
+		{"lag in positive milliseconds", func(rule *AlertRule) { rule.Kind, rule.Threshold = AlertConsumerLag, 0 }},
+		{"no publishes of an event", func(rule *AlertRule) { rule.Kind, rule.Event = AlertNoPublishes, "" }},
+		{"no publishes of a consumer", func(rule *AlertRule) { rule.Kind, rule.Consumer = AlertNoPublishes, "billing" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := valid
+			tt.change(&rule)
+			assert.Error(t, rule.Validate())
+		})
+	}
+}
+
+func TestAlertRule_Firing(t *testing.T) {
+	failureRate := AlertRule{Kind: AlertFailureRate, Threshold: 0.05}
+	assert.False(t, failureRate.Firing(0.05))
+	assert.True(t, failureRate.Firing(0.06))
+
+	noPublishes := AlertRule{Kind: AlertNoPublishes}
+	assert.True(t, noPublishes.Firing(0), "nothing published fires without a threshold")
+	assert.False(t, noPublishes.Firing(1))
+
+	noPublishes.Threshold = 10
+	assert.True(t, noPublishes.Firing(9))
+	assert.False(t, noPublishes.Firing(10))
+}
+
+func TestAlertState_NeedsNotification(t *testing.T) {
+	assert.True(t, AlertState{Status: AlertFiring}.NeedsNotification())
+	assert.False(t, AlertState{Status: AlertFiring, Notified: AlertFiring}.NeedsNotification())
+	assert.True(t, AlertState{Status: AlertOK, Notified: AlertFiring}.NeedsNotification(), "a notified alert is resolved")
+	assert.False(t, AlertState{Status: AlertOK}.NeedsNotification(), "a rule that never fired has nothing to resolve")
+}
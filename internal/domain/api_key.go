@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/auth"
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key so leaked keys are easy to recognize
+const APIKeyPrefix = "gq_"
+
+// Scope is a permission granted to an API key
+type Scope string
+
+const (
+	// ScopePublish publishes every event of the project, PublishScope limits it to one event
+	ScopePublish        Scope = "publish"
+	ScopeEventsRead     Scope = "events:read"
+	ScopeEventsManage   Scope = "events:manage"
+	ScopeInsightsRead   Scope = "insights:read"
+	ScopeDLQManage      Scope = "dlq:manage"
+	ScopeAlertsManage   Scope = "alerts:manage"
+	ScopeKeysManage     Scope = "keys:manage"
+	ScopeProjectsManage Scope = "projects:manage"
+)
+
+var knownScopes = []Scope{
+	ScopePublish,
+	ScopeEventsRead,
+	ScopeEventsManage,
+	ScopeInsightsRead,
+	ScopeDLQManage,
+	ScopeAlertsManage,
+	ScopeKeysManage,
+	ScopeProjectsManage,
+}
+
+// PublishScope allows publishing a single event
+func PublishScope(eventName string) Scope {
+	return Scope(string(ScopePublish) + ":" + eventName)
+}
+
+func (s Scope) Validate() error {
+	for _, known := range knownScopes {
+		if s == known {
+			return nil
+		}
+	}
+
+	if eventName, ok := strings.CutPrefix(string(s), string(ScopePublish)+":"); ok && eventName != "" {
+		return nil
+	}
+
+	return fmt.Errorf("invalid scope %q", s)
+}
+
+// APIKey authenticates a client of a project and limits what it can do to its scopes.
+// Only the SHA-256 of its secret is stored, the key itself is shown once when created.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	ProjectID  string     `json:"project_id"`
+	Name       string     `json:"name"`
+	SecretHash string     `json:"-"`
+	Scopes     []Scope    `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewAPIKey creates a key of the project and returns it with the token clients authenticate with
+func NewAPIKey(projectID, name string, scopes []Scope, expiresAt *time.Time) (APIKey, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return APIKey{}, "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key := APIKey{
+		ID:        uuid.New(),
+		ProjectID: projectID,
+		Name:      name,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+
+	encoded := hex.EncodeToString(secret)
+	key.SecretHash = hashSecret(encoded)
+
+	return key, APIKeyPrefix + key.ID.String() + "_" + encoded, nil
+}
+
+// ParseAPIKey splits a token in the id of its key and its secret
+func ParseAPIKey(token string) (uuid.UUID, string, error) {
+	rest, ok := strings.CutPrefix(token, APIKeyPrefix)
+	if !ok {
+		return uuid.Nil, "", InvalidAPIKey
+	}
+
+	rawID, secret, ok := strings.Cut(rest, "_")
+	if !ok || secret == "" {
+		return uuid.Nil, "", InvalidAPIKey
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, "", InvalidAPIKey
+	}
+
+	return id, secret, nil
+}
+
+func (k APIKey) Validate() error {
+	if k.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(k.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+
+	for _, scope := range k.Scopes {
+		if err := scope.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckSecret reports whether the secret is the one of the key
+func (k APIKey) CheckSecret(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(k.SecretHash)) == 1
+}
+
+// Active reports whether the key can still be used
+func (k APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// Allows reports whether the key grants the scope. Publishing every event covers publishing a
+// single one and managing events covers reading them.
+func (k APIKey) Allows(scope Scope) bool {
+	for _, granted := range k.Scopes {
+		switch {
+		case granted == scope:
+			return true
+		case granted == ScopePublish && strings.HasPrefix(string(scope), string(ScopePublish)+":"):
+			return true
+		case granted == ScopeEventsManage && scope == ScopeEventsRead:
+			return true
+		}
+	}
+
+	return false
+}
+
+type apiKeyKey struct{}
+
+// WithAPIKey sets the key the request was authenticated with
+func WithAPIKey(ctx context.Context, key APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, key)
+}
+
+func APIKeyFromContext(ctx context.Context) (APIKey, bool) {
+	key, ok := ctx.Value(apiKeyKey{}).(APIKey)
+	return key, ok
+}
+
+// Authorize checks the scope against the API key or the token of the request. Tokens only allow
+// publishing the events of their identity. Requests authenticated with the secret of their
+// project, or without credentials on a server that does not require them, are not limited by
+// scopes.
+func Authorize(ctx context.Context, scope Scope) error {
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		eventName, isPublish := strings.CutPrefix(string(scope), string(ScopePublish)+":")
+		if isPublish && identity.CanPublish(eventName) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %s", MissingScope, scope)
+	}
+
+	key, ok := APIKeyFromContext(ctx)
+	if !ok || key.Allows(scope) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", MissingScope, scope)
+}
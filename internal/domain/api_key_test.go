@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key, token, err := NewAPIKey("acme", "billing", []Scope{ScopePublish}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme", key.ProjectID)
+	assert.NotContains(t, key.SecretHash, token)
+
+	keyID, secret, err := ParseAPIKey(token)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, keyID)
+	assert.True(t, key.CheckSecret(secret))
+	assert.False(t, key.CheckSecret("wrong"))
+}
+
+func TestParseAPIKey_Invalid(t *testing.T) {
+	for _, token := range []string{"", "secret", "gq_not-a-uuid_secret", "gq_7b4c3a3e-6b43-4f51-9d6f-2b6b1e0f6a1c", "gq_7b4c3a3e-6b43-4f51-9d6f-2b6b1e0f6a1c_"} {
+		_, _, err := ParseAPIKey(token)
+		assert.ErrorIs(t, err, InvalidAPIKey, token)
+	}
+}
+
+func TestScope_Validate(t *testing.T) {
+	assert.NoError(t, ScopeDLQManage.Validate())
+	assert.NoError(t, PublishScope("payment.processed").Validate())
+	assert.Error(t, Scope("publish:").Validate())
+	assert.Error(t, Scope("admin").Validate())
+}
+
+func TestAPIKey_Allows(t *testing.T) {
+	key := APIKey{Scopes: []Scope{PublishScope("payment.processed"), ScopeEventsManage}}
+
+	assert.True(t, key.Allows(PublishScope("payment.processed")))
+	assert.False(t, key.Allows(PublishScope("order.created")))
+	assert.True(t, key.Allows(ScopeEventsRead), "managing events covers reading them")
+	assert.False(t, key.Allows(ScopeDLQManage))
+
+	publisher := APIKey{Scopes: []Scope{ScopePublish}}
+	assert.True(t, publisher.Allows(PublishScope("order.created")), "publish covers every event")
+}
+
+func TestAPIKey_Active(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	assert.True(t, APIKey{}.Active(now))
+	assert.True(t, APIKey{ExpiresAt: &future}.Active(now))
+	assert.False(t, APIKey{ExpiresAt: &past}.Active(now))
+	assert.False(t, APIKey{RevokedAt: &past}.Active(now))
+}
+
+func TestAuthorize(t *testing.T) {
+	assert.NoError(t, Authorize(context.Background(), ScopeDLQManage), "requests without a key are not limited by scopes")
+
+	ctx := WithAPIKey(context.Background(), APIKey{Scopes: []Scope{ScopeInsightsRead}})
+	assert.NoError(t, Authorize(ctx, ScopeInsightsRead))
+	assert.ErrorIs(t, Authorize(ctx, ScopeDLQManage), MissingScope)
+}
+
+func TestAuthorize_Identity(t *testing.T) {
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Publisher: "billing", Events: []string{"payment.processed"}})
+
+	assert.NoError(t, Authorize(ctx, PublishScope("payment.processed")))
+	assert.ErrorIs(t, Authorize(ctx, PublishScope("order.created")), MissingScope)
+	assert.ErrorIs(t, Authorize(ctx, ScopeEventsRead), MissingScope, "tokens only publish")
+}
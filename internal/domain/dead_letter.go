@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetter is a message that ran out of retries, kept so it can be inspected and replayed
+type DeadLetter struct {
+	ID        uuid.UUID `json:"id"`
+	MessageID string    `json:"message_id"`
+	EventName string    `json:"event_name"`
+	Consumer  Consumer  `json:"consumer"`
+	// EventOwner is the owner of the event, the owner of the consumer is kept in Consumer
+	EventOwner  Owner             `json:"event_owner"`
+	Payload     json.RawMessage   `json:"payload"`
+	Attributes  map[string]string `json:"attributes"`
+	LastError   string            `json:"last_error"`
+	RetryCount  int               `json:"retry_count"`
+	PublishedAt time.Time         `json:"published_at"`
+	DeadAt      time.Time         `json:"dead_at"`
+}
+
+// SetOwners copies the current owners of the event and of the consumer that failed, the
+// consumer in the message keeps the owner it had when the message was published
+func (dl *DeadLetter) SetOwners(event Event) {
+	dl.EventOwner = event.Owner
+	if consumer, ok := event.FindConsumer(dl.Consumer.ServiceName); ok {
+		dl.Consumer.Owner = consumer.Owner
+	}
+}
+
+// Range parses the from/to bounds, a zero time means unbounded
+func (f FilterDeadLetters) Range() (from, to time.Time, err error) {
+	if f.From != "" {
+		if from, err = time.Parse(time.RFC3339, f.From); err != nil {
+			return from, to, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+
+	if f.To != "" {
+		if to, err = time.Parse(time.RFC3339, f.To); err != nil {
+			return from, to, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+
+	return from, to, nil
+}
+
+// Match reports whether the dead letter passes the event name and consumer filters
+func (f FilterDeadLetters) Match(dl DeadLetter) bool {
+	if len(f.EventName) > 0 && !slices.Contains(f.EventName, dl.EventName) {
+		return false
+	}
+
+	if len(f.Consumer) > 0 && !slices.Contains(f.Consumer, dl.Consumer.ServiceName) {
+		return false
+	}
+
+	if len(f.TeamOwner) > 0 && !slices.Contains(f.TeamOwner, dl.EventOwner.TeamOwner) && !slices.Contains(f.TeamOwner, dl.Consumer.TeamOwner) {
+		return false
+	}
+
+	return true
+}
@@ -1,5 +1,60 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var EventNotFound = errors.New("event not found")
+
+var DeadLetterNotFound = errors.New("dead letter not found")
+
+var RevisionNotFound = errors.New("revision not found")
+
+var EventNameConflict = errors.New("event name already in use")
+
+var ConsumerNotFound = errors.New("consumer not found")
+
+var ConsumerAlreadyExists = errors.New("consumer already exists")
+
+// EventVersionConflict is returned when the event changed since the revision the update was based on
+var EventVersionConflict = errors.New("event was changed by another request")
+
+// DeliveryPaused is returned by a handler when the event or consumer is paused,
+// the message is delivered again later without counting as a failed attempt
+var DeliveryPaused = errors.New("delivery paused")
+
+// DeliveryQuarantined is returned by a handler when the consumer is quarantined, the message is
+// parked like a paused one until the consumer recovers
+var DeliveryQuarantined = fmt.Errorf("consumer quarantined: %w", DeliveryPaused)
+
+var QuarantineNotFound = errors.New("consumer is not quarantined")
+
+var ProjectNotFound = errors.New("project not found")
+
+// ProjectAlreadyExists is returned when the id or the topic prefix is used by another project
+var ProjectAlreadyExists = errors.New("project id or topic prefix already in use")
+
+// ProjectNotEmpty is returned when deleting a project that still has events
+var ProjectNotEmpty = errors.New("project still has events")
+
+var ProjectQuotaExceeded = errors.New("project quota exceeded")
+
+// InvalidProjectCredentials is returned when the request does not authenticate as the project it names
+var InvalidProjectCredentials = errors.New("invalid project credentials")
+
+var APIKeyNotFound = errors.New("api key not found")
+
+// InvalidAPIKey is returned when the key is unknown, malformed, expired or revoked
+var InvalidAPIKey = errors.New("invalid api key")
+
+// MissingScope is returned when the API key or token of the request does not grant the action
+var MissingScope = errors.New("missing scope")
+
+// InsightsNotRetained is returned when the buckets of an insights window have already expired
+var InsightsNotRetained = errors.New("insights of the window are no longer retained")
+
+// TimelineNotFound is returned when nothing was recorded for a message or its timeline expired
+var TimelineNotFound = errors.New("timeline not found")
+
+var AlertRuleNotFound = errors.New("alert rule not found")
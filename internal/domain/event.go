@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -14,12 +15,20 @@ import (
 
 type Event struct {
 	ID          uuid.UUID  `json:"id" bson:"id"`
+	ProjectID   string     `json:"project_id" bson:"project_id"`
 	Name        string     `json:"name" bson:"name"`
 	ServiceName string     `json:"service_name" bson:"service_name"`
 	State       string     `json:"state" bson:"state"`
 	Type        Type       `json:"type" bson:"type"`
 	Option      Opt        `json:"option" bson:"option"`
 	Consumers   []Consumer `json:"consumers" bson:"consumers"`
+	Owner       `bson:",inline"`
+	// Schema is the JSON Schema of the payload, published in the event catalog
+	Schema json.RawMessage `json:"schema,omitempty" bson:"schema,omitempty"`
+	// Paused holds the deliveries to every consumer of the event
+	Paused bool `json:"paused" bson:"paused"`
+	// Revision is the latest revision of the event, used to detect concurrent updates
+	Revision int `json:"revision" bson:"revision"`
 }
 
 func (e *Event) Validate() error {
@@ -35,18 +44,158 @@ func (e *Event) Validate() error {
 		return fmt.Errorf("at least one consumer is required")
 	}
 
+	if len(e.Schema) > 0 {
+		var schema map[string]any
+		if err := json.Unmarshal(e.Schema, &schema); err != nil || schema == nil {
+			return fmt.Errorf("schema must be a JSON object")
+		}
+	}
+
 	if len(e.Consumers) > cfg.Get().MaxConsumers {
 		return fmt.Errorf("consumers must be less than 10")
 	}
 
+	seen := make(map[string]bool, len(e.Consumers))
+	for _, consumer := range e.Consumers {
+		if err := consumer.Validate(); err != nil {
+			return fmt.Errorf("invalid consumer %q: %w", consumer.ServiceName, err)
+		}
+
+		if seen[consumer.ServiceName] {
+			return fmt.Errorf("consumer %q is duplicated", consumer.ServiceName)
+		}
+		seen[consumer.ServiceName] = true
+	}
+
+	return nil
+}
+
+// DeliveryPaused reports whether the deliveries of the event to the consumer are on hold
+func (e Event) DeliveryPaused(serviceName string) bool {
+	if e.Paused {
+		return true
+	}
+
+	for _, consumer := range e.Consumers {
+		if consumer.ServiceName == serviceName {
+			return consumer.Paused
+		}
+	}
+
+	return false
+}
+
+// SetPaused pauses or resumes the whole event, or a single consumer when serviceName is set
+func (e *Event) SetPaused(serviceName string, paused bool) error {
+	if serviceName == "" {
+		e.Paused = paused
+		return nil
+	}
+
+	for i := range e.Consumers {
+		if e.Consumers[i].ServiceName == serviceName {
+			e.Consumers[i].Paused = paused
+			return nil
+		}
+	}
+
+	return ConsumerNotFound
+}
+
+func (e Event) FindConsumer(serviceName string) (Consumer, bool) {
+	for _, consumer := range e.Consumers {
+		if consumer.ServiceName == serviceName {
+			return consumer, true
+		}
+	}
+
+	return Consumer{}, false
+}
+
+func (e *Event) AddConsumer(consumer Consumer) error {
+	if _, exists := e.FindConsumer(consumer.ServiceName); exists {
+		return ConsumerAlreadyExists
+	}
+
+	e.Consumers = append(e.Consumers, consumer)
 	return nil
 }
 
+// ReplaceConsumer updates the consumer named serviceName, the new configuration can rename it
+func (e *Event) ReplaceConsumer(serviceName string, consumer Consumer) error {
+	if consumer.ServiceName != serviceName {
+		if _, exists := e.FindConsumer(consumer.ServiceName); exists {
+			return ConsumerAlreadyExists
+		}
+	}
+
+	for i := range e.Consumers {
+		if e.Consumers[i].ServiceName == serviceName {
+			// pauses are only changed through SetPaused
+			consumer.Paused = e.Consumers[i].Paused
+			e.Consumers[i] = consumer
+			return nil
+		}
+	}
+
+	return ConsumerNotFound
+}
+
+func (e *Event) RemoveConsumer(serviceName string) error {
+	for i := range e.Consumers {
+		if e.Consumers[i].ServiceName == serviceName {
+			e.Consumers = append(e.Consumers[:i:i], e.Consumers[i+1:]...)
+			return nil
+		}
+	}
+
+	return ConsumerNotFound
+}
+
+// KeepPauses copies the pause state of the current configuration, pauses are only
+// changed through SetPaused so registering an event again does not resume it
+func (e *Event) KeepPauses(current Event) {
+	e.Paused = current.Paused
+
+	paused := make(map[string]bool, len(current.Consumers))
+	for _, consumer := range current.Consumers {
+		paused[consumer.ServiceName] = consumer.Paused
+	}
+
+	for i := range e.Consumers {
+		e.Consumers[i].Paused = paused[e.Consumers[i].ServiceName]
+	}
+}
+
 type Consumer struct {
 	ServiceName string            `json:"service_name" bson:"service_name"`
 	BaseUrl     string            `json:"host" bson:"base_url"`
 	Path        string            `json:"path" bson:"path"`
 	Headers     map[string]string `json:"headers" bson:"headers"`
+	Owner       `bson:",inline"`
+	// Paused holds the deliveries to this consumer only
+	Paused bool `json:"paused" bson:"paused"`
+}
+
+// Owner tells who is responsible for an event or consumer, so its failures reach the right team
+type Owner struct {
+	TeamOwner string `json:"team_owner,omitempty" bson:"team_owner,omitempty"`
+	RepoURL   string `json:"repo_url,omitempty" bson:"repo_url,omitempty"`
+	// Contact is the on-call contact of the team, e.g. a pager alias or a chat channel
+	Contact     string `json:"contact,omitempty" bson:"contact,omitempty"`
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+}
+
+func (t Consumer) Validate() error {
+	if t.ServiceName == "" {
+		return fmt.Errorf("service_name is required")
+	}
+
+	if t.BaseUrl == "" {
+		return fmt.Errorf("host is required")
+	}
+
+	return nil
 }
 
 func (t *Consumer) GetUrl() string {
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvent_Pause(t *testing.T) {
+	newEvent := func() Event {
+		return Event{
+			Name: "payment.processed",
+			Consumers: []Consumer{
+				{ServiceName: "billing"},
+				{ServiceName: "ledger"},
+			},
+		}
+	}
+
+	t.Run("pausing a consumer holds only its deliveries", func(t *testing.T) {
+		event := newEvent()
+		assert.NoError(t, event.SetPaused("billing", true))
+
+		assert.True(t, event.DeliveryPaused("billing"))
+		assert.False(t, event.DeliveryPaused("ledger"))
+	})
+
+	t.Run("pausing the event holds every consumer", func(t *testing.T) {
+		event := newEvent()
+		assert.NoError(t, event.SetPaused("", true))
+
+		assert.True(t, event.DeliveryPaused("billing"))
+		assert.True(t, event.DeliveryPaused("ledger"))
+
+		assert.NoError(t, event.SetPaused("", false))
+		assert.False(t, event.DeliveryPaused("billing"))
+	})
+
+	t.Run("unknown consumer", func(t *testing.T) {
+		event := newEvent()
+		assert.ErrorIs(t, event.SetPaused("unknown", true), ConsumerNotFound)
+	})
+
+	t.Run("registering again keeps the pauses", func(t *testing.T) {
+		current := newEvent()
+		current.Paused = true
+		assert.NoError(t, current.SetPaused("ledger", true))
+
+		registered := newEvent()
+		registered.Consumers = append(registered.Consumers, Consumer{ServiceName: "audit"})
+		registered.KeepPauses(current)
+
+		assert.True(t, registered.Paused)
+		assert.False(t, registered.Consumers[0].Paused)
+		assert.True(t, registered.Consumers[1].Paused)
+		assert.False(t, registered.Consumers[2].Paused)
+	})
+}
+
+func TestEvent_Consumers(t *testing.T) {
+	newEvent := func() Event {
+		return Event{
+			Name:   "payment.processed",
+			Type:   EventTypeInternal,
+			Option: Opt{WqType: "low_throughput"},
+			Consumers: []Consumer{
+				{ServiceName: "billing", BaseUrl: "http://billing"},
+				{ServiceName: "ledger", BaseUrl: "http://ledger", Paused: true},
+			},
+		}
+	}
+
+	t.Run("add", func(t *testing.T) {
+		event := newEvent()
+		assert.NoError(t, event.AddConsumer(Consumer{ServiceName: "audit", BaseUrl: "http://audit"}))
+		assert.Len(t, event.Consumers, 3)
+		assert.ErrorIs(t, event.AddConsumer(Consumer{ServiceName: "billing"}), ConsumerAlreadyExists)
+	})
+
+	t.Run("replace keeps the pause", func(t *testing.T) {
+		event := newEvent()
+		assert.NoError(t, event.ReplaceConsumer("ledger", Consumer{ServiceName: "ledger", BaseUrl: "http://ledger-v2"}))
+
+		consumer, ok := event.FindConsumer("ledger")
+		assert.True(t, ok)
+		assert.Equal(t, "http://ledger-v2", consumer.BaseUrl)
+		assert.True(t, consumer.Paused)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		event := newEvent()
+		assert.ErrorIs(t, event.ReplaceConsumer("ledger", Consumer{ServiceName: "billing"}), ConsumerAlreadyExists)
+		assert.NoError(t, event.ReplaceConsumer("ledger", Consumer{ServiceName: "accounting", BaseUrl: "http://ledger"}))
+
+		_, ok := event.FindConsumer("ledger")
+		assert.False(t, ok)
+		_, ok = event.FindConsumer("accounting")
+		assert.True(t, ok)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		event := newEvent()
+		original := event.Consumers
+
+		assert.NoError(t, event.RemoveConsumer("billing"))
+		assert.Equal(t, []string{"ledger"}, []string{event.Consumers[0].ServiceName})
+		assert.Equal(t, "billing", original[0].ServiceName, "the previous list is not modified")
+		assert.ErrorIs(t, event.RemoveConsumer("billing"), ConsumerNotFound)
+	})
+
+	t.Run("validate rejects duplicated and incomplete consumers", func(t *testing.T) {
+		event := newEvent()
+		assert.NoError(t, event.Validate())
+
+		event.Consumers = append(event.Consumers, Consumer{ServiceName: "billing", BaseUrl: "http://other"})
+		assert.ErrorContains(t, event.Validate(), `consumer "billing" is duplicated`)
+
+		event = newEvent()
+		event.Consumers[0].BaseUrl = ""
+		assert.ErrorContains(t, event.Validate(), "host is required")
+	})
+
+	t.Run("validate accepts only a JSON object as schema", func(t *testing.T) {
+		event := newEvent()
+		event.Schema = json.RawMessage(`{"type":"object"}`)
+		assert.NoError(t, event.Validate())
+
+		for _, schema := range []string{`null`, `["object"]`, `{"type":`} {
+			event.Schema = json.RawMessage(schema)
+			assert.ErrorContains(t, event.Validate(), "schema must be a JSON object", schema)
+		}
+	})
+}
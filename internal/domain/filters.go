@@ -1,9 +1,34 @@
 package domain
 
 type FilterEvents struct {
-	TeamOwner   []string `query:"team_owner"`
-	ServiceName []string `query:"service_name"`
-	State       []string `query:"state"`
-	Page        uint     `query:"page"`
-	Limit       uint     `query:"limit"`
+	TeamOwner []string `query:"team_owner"`
+	RepoURL   []string `query:"repo_url"`
+	// ConsumerTeam matches events with at least one consumer owned by the team
+	ConsumerTeam []string `query:"consumer_team"`
+	ServiceName  []string `query:"service_name"`
+	State        []string `query:"state"`
+	Page         uint     `query:"page"`
+	Limit        uint     `query:"limit"`
+}
+
+type FilterDeadLetters struct {
+	EventName []string `query:"event_name"`
+	Consumer  []string `query:"consumer"`
+	// TeamOwner matches the team owning the event or the consumer that failed
+	TeamOwner []string `query:"team_owner"`
+	// From and To bound the time the message was dead-lettered (RFC 3339)
+	From  string `query:"from"`
+	To    string `query:"to"`
+	Page  uint   `query:"page"`
+	Limit uint   `query:"limit"`
+}
+
+// FilterInsights selects the metrics of a time window. From and To are RFC 3339, the granularity
+// is minute, hour, day or a duration in whole minutes.
+type FilterInsights struct {
+	From        string `query:"from"`
+	To          string `query:"to"`
+	Event       string `query:"event"`
+	Consumer    string `query:"consumer"`
+	Granularity string `query:"granularity"`
 }
@@ -0,0 +1,100 @@
+package domain
+
+import "time"
+
+// HealthStatus is the state of the deliveries to a consumer
+type HealthStatus string
+
+const (
+	HealthHealthy HealthStatus = "healthy"
+	// HealthFailing is a consumer that exhausted its failure budget while quarantines are disabled
+	HealthFailing     HealthStatus = "failing"
+	HealthQuarantined HealthStatus = "quarantined"
+	// HealthUnknown is a consumer with too few deliveries in the window to be scored
+	HealthUnknown HealthStatus = "unknown"
+)
+
+// HealthPolicy is when the failures of a consumer are sustained enough to quarantine it
+type HealthPolicy struct {
+	Window time.Duration
+	// FailureBudget is the share of deliveries allowed to fail over the window, from 0 to 1
+	FailureBudget float64
+	// MinDeliveries is the number of deliveries in the window a score needs to be meaningful
+	MinDeliveries int64
+}
+
+// Scored reports whether the window had enough deliveries to score the consumer
+func (p HealthPolicy) Scored(deliveries int64) bool {
+	return deliveries > 0 && deliveries >= p.MinDeliveries
+}
+
+// Exhausted reports whether the failures are above the budget of the deliveries
+func (p HealthPolicy) Exhausted(deliveries, failures int64) bool {
+	return p.Scored(deliveries) && float64(failures)/float64(deliveries) > p.FailureBudget
+}
+
+// Quarantine parks the deliveries of an event to a consumer that exhausted its failure budget,
+// until a probe request succeeds
+type Quarantine struct {
+	Event    string    `json:"event"`
+	Consumer string    `json:"consumer"`
+	Since    time.Time `json:"since"`
+	// Score is the share of successful deliveries when the consumer was quarantined
+	Score          float64    `json:"score"`
+	Probes         int        `json:"probes"`
+	LastProbeAt    *time.Time `json:"last_probe_at,omitempty"`
+	LastProbeError string     `json:"last_probe_error,omitempty"`
+	// ReleasedAt is set once the consumer recovered, its score only counts the deliveries after it
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
+
+func (q Quarantine) Active() bool {
+	return q.ReleasedAt == nil
+}
+
+// ConsumerHealth scores the deliveries of an event to a consumer over the window of the policy
+type ConsumerHealth struct {
+	Event      string `json:"event"`
+	Consumer   string `json:"consumer"`
+	Deliveries int64  `json:"deliveries"`
+	Failures   int64  `json:"failures"`
+	// Score is the share of successful deliveries from 0 to 1, null when there were too few
+	Score      *float64     `json:"score"`
+	Status     HealthStatus `json:"status"`
+	Quarantine *Quarantine  `json:"quarantine,omitempty"`
+}
+
+// NewConsumerHealth scores the deliveries, a quarantined consumer stays so whatever its score
+func NewConsumerHealth(event, consumer string, deliveries, failures int64, policy HealthPolicy, quarantine *Quarantine) ConsumerHealth {
+	health := ConsumerHealth{
+		Event:      event,
+		Consumer:   consumer,
+		Deliveries: deliveries,
+		Failures:   failures,
+		Status:     HealthUnknown,
+	}
+
+	if policy.Scored(deliveries) {
+		score := float64(deliveries-failures) / float64(deliveries)
+		health.Score = &score
+		health.Status = HealthHealthy
+		if policy.Exhausted(deliveries, failures) {
+			health.Status = HealthFailing
+		}
+	}
+
+	if quarantine != nil && quarantine.Active() {
+		health.Status = HealthQuarantined
+		health.Quarantine = quarantine
+	}
+
+	return health
+}
+
+// QuarantineNotificationStatus is quarantined or released
+type QuarantineNotificationStatus string
+
+const (
+	QuarantineNotificationQuarantined QuarantineNotificationStatus = "quarantined"
+	QuarantineNotificationReleased    QuarantineNotificationStatus = "released"
+)
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConsumerHealth(t *testing.T) {
+	policy := HealthPolicy{Window: 30 * time.Minute, FailureBudget: 0.5, MinDeliveries: 20}
+
+	unknown := NewConsumerHealth("payment.processed", "billing", 10, 10, policy, nil)
+	assert.Equal(t, HealthUnknown, unknown.Status, "too few deliveries to be scored")
+	assert.Nil(t, unknown.Score)
+
+	healthy := NewConsumerHealth("payment.processed", "billing", 40, 20, policy, nil)
+	assert.Equal(t, HealthHealthy, healthy.Status, "the budget is not exceeded")
+	require.NotNil(t, healthy.Score)
+	assert.Equal(t, 0.5, *healthy.Score)
+
+	failing := NewConsumerHealth("payment.processed", "billing", 40, 30, policy, nil)
+	assert.Equal(t, HealthFailing, failing.Status)
+	assert.Equal(t, 0.25, *failing.Score)
+
+	quarantined := NewConsumerHealth("payment.processed", "billing", 0, 0, policy, &Quarantine{Since: time.Now()})
+	assert.Equal(t, HealthQuarantined, quarantined.Status, "parked messages are not scored")
+	assert.NotNil(t, quarantined.Quarantine)
+
+	releasedAt := time.Now()
+	released := NewConsumerHealth("payment.processed", "billing", 40, 0, policy, &Quarantine{ReleasedAt: &releasedAt})
+	assert.Equal(t, HealthHealthy, released.Status)
+	assert.Nil(t, released.Quarantine)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// latencyAccuracy is the relative error of the quantiles of a LatencySketch
+const latencyAccuracy = 0.01
+
+// latencyZeroBin counts the durations under a millisecond, which are reported as 0
+const latencyZeroBin = -1
+
+var (
+	latencyGamma    = (1 + latencyAccuracy) / (1 - latencyAccuracy)
+	latencyLogGamma = math.Log(latencyGamma)
+)
+
+// LatencySketch counts durations in bins growing exponentially, like a DDSketch: every duration
+// of a bin is within 1% of the value the bin reports. Sketches of different instances, consumers
+// and periods merge exactly by adding the counts of their bins, so a quantile of a merge is as
+// accurate as one of a single sketch. A sketch of durations from 1ms to an hour has at most about
+// 760 bins.
+type LatencySketch map[int]int64
+
+// LatencyBin is the bin of the duration in a LatencySketch
+func LatencyBin(durationMs int64) int {
+	if durationMs < 1 {
+		return latencyZeroBin
+	}
+
+	return int(math.Ceil(math.Log(float64(durationMs)) / latencyLogGamma))
+}
+
+// latencyBinValue is the duration the bin reports, the one with the same relative error to both
+// bounds of the bin
+func latencyBinValue(bin int) float64 {
+	if bin <= latencyZeroBin {
+		return 0
+	}
+
+	return 2 * math.Pow(latencyGamma, float64(bin)) / (latencyGamma + 1)
+}
+
+// Add counts durations in the bin, bins below the zero bin are ignored
+func (s *LatencySketch) Add(bin int, count int64) {
+	if bin < latencyZeroBin || count <= 0 {
+		return
+	}
+
+	if *s == nil {
+		*s = make(LatencySketch)
+	}
+
+	(*s)[bin] += count
+}
+
+func (s *LatencySketch) Merge(other LatencySketch) {
+	for bin, count := range other {
+		s.Add(bin, count)
+	}
+}
+
+func (s LatencySketch) Count() int64 {
+	var total int64
+	for _, count := range s {
+		total += count
+	}
+
+	return total
+}
+
+// Quantile is the duration in milliseconds below which q of the durations are, 0 without
+// durations
+func (s LatencySketch) Quantile(q float64) float64 {
+	total := s.Count()
+	if total == 0 {
+		return 0
+	}
+
+	// nearest rank, the smallest count covering q of the durations
+	rank := int64(math.Ceil(q * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+
+	bins := make([]int, 0, len(s))
+	for bin := range s {
+		bins = append(bins, bin)
+	}
+	sort.Ints(bins)
+
+	var seen int64
+	for _, bin := range bins {
+		seen += s[bin]
+		if seen >= rank {
+			return latencyBinValue(bin)
+		}
+	}
+
+	return latencyBinValue(bins[len(bins)-1])
+}
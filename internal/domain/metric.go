@@ -2,10 +2,10 @@ package domain
 
 import (
 	"fmt"
-	"math"
 	"sort"
-	"strings"
 	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
 )
 
 type ConsumerMetric struct {
@@ -15,6 +15,9 @@ type ConsumerMetric struct {
 	TimeEnded      time.Time
 	TimeDurationMs int64
 	ACK            bool
+	// PublishedAt is when the message was published, or was due for a scheduled task; the lag is
+	// the time it waited until TimeStarted. It is zero when the message does not carry it.
+	PublishedAt time.Time
 }
 
 type PublisherMetric struct {
@@ -25,189 +28,290 @@ type PublisherMetric struct {
 	ACK            bool
 }
 
-type Metric struct {
-	TopicName      string
-	ConsumerName   string
-	TimeStarted    time.Time
-	TimeEnded      time.Time
-	TimeDurationMs int64
-	ACK            bool
-}
-
-type Metrics []Metric
+const (
+	defaultInsightsWindow = time.Hour
+	// maxInsightsBuckets bounds the series of a response, a day by minute
+	maxInsightsBuckets = 1440
+)
 
-func (m *Metrics) Insights() Insights {
-	insights := NewInsights()
-	var (
-		timeDurationsMsConsumed  = make(map[string][]float64)
-		timeDurationsMsPublished = make(map[string][]float64)
-	)
+// InsightsWindow is a resolved insights query: the metrics ended in [From, To) of the event and
+// consumer, when set, summarized in buckets of Granularity
+type InsightsWindow struct {
+	From        time.Time
+	To          time.Time
+	Event       string
+	Consumer    string
+	Granularity time.Duration
+}
 
-	for _, metric := range *m {
-		dateInMinute := time.Date(metric.TimeEnded.Year(), metric.TimeEnded.Month(), metric.TimeEnded.Day(), metric.TimeEnded.Hour(), metric.TimeEnded.Minute(), 0, 0, time.UTC)
+// Window resolves the filter, by default the hour before now by minute
+func (f FilterInsights) Window(now time.Time) (InsightsWindow, error) {
+	window := InsightsWindow{To: now.UTC(), Event: f.Event, Consumer: f.Consumer, Granularity: time.Minute}
 
-		if metric.ConsumerName != "" {
-			insights.TotalConsumed++
-			if metric.ACK {
-				insights.TotalConsumedWithSuccess++
-			} else {
-				insights.TotalConsumedWithErr++
-			}
+	if f.To != "" {
+		to, err := time.Parse(time.RFC3339, f.To)
+		if err != nil {
+			return InsightsWindow{}, fmt.Errorf("invalid to: %w", err)
+		}
+		window.To = to.UTC()
+	}
 
-			key := strings.Join([]string{metric.TopicName, metric.ConsumerName}, ".")
-			df, ok := timeDurationsMsConsumed[key]
-			if ok {
-				df = append(df, float64(metric.TimeDurationMs))
-			} else {
-				timeDurationsMsConsumed[key] = []float64{float64(metric.TimeDurationMs)}
-			}
+	window.From = window.To.Add(-defaultInsightsWindow)
+	if f.From != "" {
+		from, err := time.Parse(time.RFC3339, f.From)
+		if err != nil {
+			return InsightsWindow{}, fmt.Errorf("invalid from: %w", err)
+		}
+		window.From = from.UTC()
+	}
 
-			insights.TotalSegmentationConsumed[metric.ConsumerName]++
-			insights.RpmConsumer.add(metric.TopicName, metric.ConsumerName, dateInMinute)
-			continue
+	if !window.From.Before(window.To) {
+		return InsightsWindow{}, fmt.Errorf("from must be before to")
+	}
 
-		} else {
-			insights.TotalPublished++
-			if metric.ACK {
-				insights.TotalPublishedWithSuccess++
-			} else {
-				insights.TotalPublishedWithErr++
-			}
+	if f.Granularity != "" {
+		granularity, err := parseGranularity(f.Granularity)
+		if err != nil {
+			return InsightsWindow{}, err
+		}
+		window.Granularity = granularity
+	}
 
-			key := metric.TopicName
-			df, ok := timeDurationsMsPublished[key]
-			if ok {
-				df = append(df, float64(metric.TimeDurationMs))
-			} else {
-				timeDurationsMsPublished[key] = []float64{float64(metric.TimeDurationMs)}
-			}
+	if buckets := len(window.Buckets()); buckets > maxInsightsBuckets {
+		return InsightsWindow{}, fmt.Errorf("the window has %d buckets, at most %d are allowed: use a larger granularity", buckets, maxInsightsBuckets)
+	}
 
-			insights.TotalSegmentationPublished[metric.TopicName]++
-			insights.RpmPublisher.add(metric.TopicName, dateInMinute)
-		}
+	return window, nil
+}
 
+func parseGranularity(value string) (time.Duration, error) {
+	switch value {
+	case "minute":
+		return time.Minute, nil
+	case "hour":
+		return time.Hour, nil
+	case "day":
+		return 24 * time.Hour, nil
 	}
 
-	for key := range timeDurationsMsConsumed {
-		p99, _ := percentile(timeDurationsMsConsumed[key], 0.99)
-		p75, _ := percentile(timeDurationsMsConsumed[key], 0.75)
-		insights.P99Consumed[key] = p99
-		insights.P75Consumed[key] = p75
+	granularity, err := time.ParseDuration(value)
+	if err != nil || granularity < time.Minute || granularity%time.Minute != 0 {
+		return 0, fmt.Errorf("invalid granularity %q: use minute, hour, day or a whole number of minutes like 5m", value)
 	}
 
-	for key := range timeDurationsMsPublished {
-		p99, _ := percentile(timeDurationsMsPublished[key], 0.99)
-		p75, _ := percentile(timeDurationsMsPublished[key], 0.75)
-		insights.P99Published[key] = p99
-		insights.P75Published[key] = p75
+	return granularity, nil
+}
+
+// Buckets returns the start of every bucket of the window, aligned on the granularity
+func (w InsightsWindow) Buckets() []time.Time {
+	buckets := make([]time.Time, 0)
+	for at := w.From.Truncate(w.Granularity); at.Before(w.To); at = at.Add(w.Granularity) {
+		buckets = append(buckets, at)
+		if len(buckets) > maxInsightsBuckets {
+			break
+		}
 	}
 
-	insights.PercentageConsumedSuccess = fmt.Sprintf("%.2f%%", float64(insights.TotalConsumedWithSuccess)/float64(insights.TotalConsumed)*100)
-	insights.PercentagePublishedSuccess = fmt.Sprintf("%.2f%%", float64(insights.TotalPublishedWithSuccess)/float64(insights.TotalPublished)*100)
+	return buckets
+}
 
-	return insights
+// Match reports whether the series belongs to the event and consumer of the window. Publications
+// have no consumer, filtering by consumer leaves them out.
+func (w InsightsWindow) Match(series Series) bool {
+	if w.Event != "" && series.Event != w.Event {
+		return false
+	}
+
+	return w.Consumer == "" || series.Consumer == w.Consumer
 }
 
+// Insights summarizes the metrics of a window. Every field is always present: the series has a
+// bucket for every step of the window, events and consumers are sorted by name.
 type Insights struct {
-	// Generic infos
-	TotalPublished             int64  `json:"total_published"`
-	TotalConsumed              int64  `json:"total_consumed"`
-	PercentagePublishedSuccess string `json:"percentage_published_success"`
-	PercentageConsumedSuccess  string `json:"percentage_consumed_success"`
-	TotalPublishedWithSuccess  int64  `json:"total_published_with_success"`
-	TotalConsumedWithSuccess   int64  `json:"total_consumed_with_success"`
-	TotalPublishedWithErr      int64  `json:"total_published_with_err"`
-	TotalConsumedWithErr       int64  `json:"total_consumed_with_err"`
-	//Segmentation infos
-	TotalSegmentationPublished map[string]int64   `json:"total_segmentation_published"`
-	TotalSegmentationConsumed  map[string]int64   `json:"total_segmentation_consumed"`
-	RpmPublisher               RpmPublisher       `json:"rpm_publisher"`
-	RpmConsumer                RpmConsumer        `json:"rpm_consumer"`
-	P99Consumed                map[string]float64 `json:"consumers_p99"`  //Topic+Consumer >> value
-	P75Consumed                map[string]float64 `json:"consumers_p75"`  //Topic+Consumer >> value
-	P99Published               map[string]float64 `json:"publishers_p99"` //Topic >> value
-	P75Published               map[string]float64 `json:"publishers_p75"` //Topic >> value
-}
-
-func NewInsights() Insights {
-	return Insights{
-		TotalPublished:             0,
-		TotalConsumed:              0,
-		TotalPublishedWithSuccess:  0,
-		TotalConsumedWithSuccess:   0,
-		TotalPublishedWithErr:      0,
-		TotalConsumedWithErr:       0,
-		PercentagePublishedSuccess: "",
-		PercentageConsumedSuccess:  "",
-		TotalSegmentationPublished: make(map[string]int64),
-		TotalSegmentationConsumed:  make(map[string]int64),
-		RpmPublisher:               make(map[string]*RPM), // [{time:"", value:2}]
-		RpmConsumer:                make(map[string]*RPM),
-		P99Consumed:                make(map[string]float64),
-		P75Consumed:                make(map[string]float64),
-		P99Published:               make(map[string]float64),
-		P75Published:               make(map[string]float64),
-	}
-}
-
-type RPM struct {
-	Timeseries []time.Time `json:"timeseries"`
-	Values     []int64     `json:"values"`
-}
-
-type RpmPublisher map[string]*RPM
-
-func (rp RpmPublisher) add(topicName string, dateInMinute time.Time) {
-	if data, ok := rp[topicName]; ok {
-		lastDateTime := data.Timeseries[len(data.Timeseries)-1]
-		if lastDateTime.Equal(dateInMinute) {
-			data.Values[len(data.Values)-1]++
-			return
-		}
-		data.Timeseries = append(data.Timeseries, dateInMinute)
-		data.Values = append(data.Values, 1)
-	} else {
-		rp[topicName] = &RPM{Timeseries: []time.Time{dateInMinute}, Values: []int64{1}}
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Granularity intertime.Duration `json:"granularity"`
+	Event       string             `json:"event"`
+	Consumer    string             `json:"consumer"`
+	Published   Counter            `json:"published"`
+	Consumed    Counter            `json:"consumed"`
+	Series      []InsightsBucket   `json:"series"`
+	Events      []EventInsights    `json:"events"`
+}
+
+type Counter struct {
+	Total   int64 `json:"total"`
+	Success int64 `json:"success"`
+	Failure int64 `json:"failure"`
+	// SuccessRate is the share of successes from 0 to 1, null when there was nothing
+	SuccessRate *float64 `json:"success_rate"`
+}
+
+func (c *Counter) add(stats SeriesStats) {
+	c.Success += stats.Success
+	c.Failure += stats.Failure
+	c.Total = c.Success + c.Failure
+	if c.Total == 0 {
+		return
 	}
+
+	rate := float64(c.Success) / float64(c.Total)
+	c.SuccessRate = &rate
 }
 
-type RpmConsumer map[string]*RPM
+// Latency holds percentiles of the durations in milliseconds, within 1% of the exact ones
+type Latency struct {
+	P50  float64 `json:"p50_ms"`
+	P75  float64 `json:"p75_ms"`
+	P90  float64 `json:"p90_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	P999 float64 `json:"p999_ms"`
+}
 
-func (rc RpmConsumer) add(topicName, consumerName string, dateInMinute time.Time) {
-	key := strings.Join([]string{topicName, consumerName}, ":")
-	if data, ok := rc[key]; ok {
-		lastDateTime := data.Timeseries[len(data.Timeseries)-1]
-		if lastDateTime.Equal(dateInMinute) {
-			data.Values[len(data.Values)-1]++
-			return
-		}
-		data.Timeseries = append(data.Timeseries, dateInMinute)
-		data.Values = append(data.Values, 1)
-	} else {
-		rc[key] = &RPM{Timeseries: []time.Time{dateInMinute}, Values: []int64{1}}
+func newLatency(sketch LatencySketch) Latency {
+	return Latency{
+		P50:  sketch.Quantile(0.5),
+		P75:  sketch.Quantile(0.75),
+		P90:  sketch.Quantile(0.9),
+		P95:  sketch.Quantile(0.95),
+		P99:  sketch.Quantile(0.99),
+		P999: sketch.Quantile(0.999),
 	}
 }
 
-func percentile(data []float64, p float64) (float64, error) {
-	if len(data) == 0 {
-		return 0, fmt.Errorf("empty array")
+type InsightsBucket struct {
+	Time      time.Time `json:"time"`
+	Published Counter   `json:"published"`
+	Consumed  Counter   `json:"consumed"`
+}
+
+type EventInsights struct {
+	Event          string             `json:"event"`
+	Published      Counter            `json:"published"`
+	PublishLatency Latency            `json:"publish_latency"`
+	Consumers      []ConsumerInsights `json:"consumers"`
+}
+
+type ConsumerInsights struct {
+	Consumer string  `json:"consumer"`
+	Consumed Counter `json:"consumed"`
+	Latency  Latency `json:"latency"`
+	// Lag is the time between the publication and the start of the deliveries
+	Lag Latency `json:"lag"`
+}
+
+// Series identifies the publications of an event, or its deliveries to a consumer
+type Series struct {
+	Event string
+	// Consumer is empty for the publications
+	Consumer string
+}
+
+type SeriesStats struct {
+	Success int64
+	Failure int64
+	Latency LatencySketch
+	// Lag is only counted for deliveries
+	Lag LatencySketch
+}
+
+func (s *SeriesStats) merge(other SeriesStats) {
+	s.Success += other.Success
+	s.Failure += other.Failure
+	s.Latency.Merge(other.Latency)
+	s.Lag.Merge(other.Lag)
+}
+
+// MetricsBucket holds the aggregated metrics of every series in the period beginning at Start
+type MetricsBucket struct {
+	Start  time.Time
+	Series map[Series]SeriesStats
+}
+
+type MetricsBuckets []MetricsBucket
+
+// Insights summarizes the buckets in the window of the series it matches. The buckets must be
+// aligned on the granularity of the window or on a divisor of it.
+func (b MetricsBuckets) Insights(window InsightsWindow) Insights {
+	insights := Insights{
+		From:        window.From,
+		To:          window.To,
+		Granularity: intertime.Duration(window.Granularity),
+		Event:       window.Event,
+		Consumer:    window.Consumer,
+		Series:      make([]InsightsBucket, 0),
+		Events:      make([]EventInsights, 0),
 	}
-	if p < 0 || p > 1 {
-		return 0, fmt.Errorf("p must be between 0 and 1")
+
+	buckets := window.Buckets()
+	if len(buckets) == 0 {
+		return insights
 	}
 
-	sorted := make([]float64, len(data))
-	copy(sorted, data)
-	sort.Float64s(sorted)
+	for _, at := range buckets {
+		insights.Series = append(insights.Series, InsightsBucket{Time: at})
+	}
 
-	n := float64(len(sorted))
-	idx := int(math.Ceil(p*n) - 1)
-	if idx < 0 {
-		idx = 0
+	totals := make(map[Series]*SeriesStats)
+	for _, bucket := range b {
+		if bucket.Start.Before(buckets[0]) || !bucket.Start.Before(window.To) {
+			continue
+		}
+
+		point := &insights.Series[int(bucket.Start.Sub(buckets[0])/window.Granularity)]
+		for series, stats := range bucket.Series {
+			if !window.Match(series) {
+				continue
+			}
+
+			if series.Consumer == "" {
+				insights.Published.add(stats)
+				point.Published.add(stats)
+			} else {
+				insights.Consumed.add(stats)
+				point.Consumed.add(stats)
+			}
+
+			total, ok := totals[series]
+			if !ok {
+				total = &SeriesStats{}
+				totals[series] = total
+			}
+			total.merge(stats)
+		}
+	}
+
+	events := make(map[string]*EventInsights)
+	for series, stats := range totals {
+		event, ok := events[series.Event]
+		if !ok {
+			event = &EventInsights{Event: series.Event, Consumers: make([]ConsumerInsights, 0)}
+			events[series.Event] = event
+		}
+
+		if series.Consumer == "" {
+			event.Published.add(*stats)
+			event.PublishLatency = newLatency(stats.Latency)
+			continue
+		}
+
+		consumer := ConsumerInsights{Consumer: series.Consumer, Latency: newLatency(stats.Latency), Lag: newLatency(stats.Lag)}
+		consumer.Consumed.add(*stats)
+		event.Consumers = append(event.Consumers, consumer)
 	}
-	if idx >= len(sorted) {
-		idx = len(sorted) - 1
+
+	for _, event := range events {
+		sort.Slice(event.Consumers, func(i, j int) bool {
+			return event.Consumers[i].Consumer < event.Consumers[j].Consumer
+		})
+
+		insights.Events = append(insights.Events, *event)
 	}
 
-	return sorted[idx], nil
+	sort.Slice(insights.Events, func(i, j int) bool {
+		return insights.Events[i].Event < insights.Events[j].Event
+	})
+
+	return insights
 }
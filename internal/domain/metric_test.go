@@ -1,169 +1,255 @@
 package domain
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// stats aggregates the outcome and durations of a series like the insights store does
+func stats(success, failure int64, durationsMs ...int64) SeriesStats {
+	output := SeriesStats{Success: success, Failure: failure}
+	for _, duration := range durationsMs {
+		output.Latency.Add(LatencyBin(duration), 1)
+	}
+	return output
+}
+
 func TestInsights(t *testing.T) {
-	t.Run("Given metrics with success and error cases, when transform to insights, then should calculate correctly", func(t *testing.T) {
-		baseTime := time.Date(2025, 10, 11, 10, 7, 47, 0, time.UTC)
+	t.Run("Given buckets with success and error cases, when transform to insights, then should calculate correctly", func(t *testing.T) {
+		published := func(event string) Series { return Series{Event: event} }
+		consumed := func(consumer string) Series { return Series{Event: "payment.processed", Consumer: consumer} }
 
-		metrics := Metrics{
-			// Published metrics (ConsumerName empty) - 2 success, 1 error
-			{
-				TopicName:      "payment.processed",
-				ConsumerName:   "",
-				TimeStarted:    baseTime,
-				TimeEnded:      baseTime.Add(20 * time.Millisecond),
-				TimeDurationMs: 20,
-				ACK:            true,
-			},
+		buckets := MetricsBuckets{
 			{
-				TopicName:      "payment.processed",
-				ConsumerName:   "",
-				TimeStarted:    baseTime.Add(1 * time.Minute),
-				TimeEnded:      baseTime.Add(1*time.Minute + 15*time.Millisecond),
-				TimeDurationMs: 15,
-				ACK:            true,
+				Start: time.Date(2025, 10, 11, 10, 7, 0, 0, time.UTC),
+				Series: map[Series]SeriesStats{
+					published("payment.processed"): stats(1, 0, 20),
+					published("order.created"):     stats(1, 0, 25),
+					consumed("consumer-1"):         stats(1, 0, 5),
+					consumed("consumer-2"):         stats(1, 0, 8),
+				},
 			},
 			{
-				TopicName:      "payment.processed",
-				ConsumerName:   "",
-				TimeStarted:    baseTime.Add(2 * time.Minute),
-				TimeEnded:      baseTime.Add(2*time.Minute + 10*time.Millisecond),
-				TimeDurationMs: 10,
-				ACK:            false, // Error case
+				Start: time.Date(2025, 10, 11, 10, 8, 0, 0, time.UTC),
+				Series: map[Series]SeriesStats{
+					published("payment.processed"): stats(1, 0, 15),
+					consumed("consumer-1"):         stats(1, 0, 3),
+					consumed("consumer-2"):         stats(0, 1, 12),
+				},
 			},
 			{
-				TopicName:      "order.created",
-				ConsumerName:   "",
-				TimeStarted:    baseTime,
-				TimeEnded:      baseTime.Add(25 * time.Millisecond),
-				TimeDurationMs: 25,
-				ACK:            true,
+				Start: time.Date(2025, 10, 11, 10, 9, 0, 0, time.UTC),
+				Series: map[Series]SeriesStats{
+					published("payment.processed"): stats(0, 1, 10),
+				},
 			},
-			// Consumed metrics (ConsumerName not empty) - 3 success, 1 error
 			{
-				TopicName:      "payment.processed",
-				ConsumerName:   "consumer-1",
-				TimeStarted:    baseTime,
-				TimeEnded:      baseTime.Add(5 * time.Millisecond),
-				TimeDurationMs: 5,
-				ACK:            true,
+				// after the window
+				Start: time.Date(2025, 10, 11, 10, 10, 0, 0, time.UTC),
+				Series: map[Series]SeriesStats{
+					published("payment.processed"): stats(5, 0, 10),
+				},
 			},
+		}
+
+		window := InsightsWindow{
+			From:        time.Date(2025, 10, 11, 10, 7, 0, 0, time.UTC),
+			To:          time.Date(2025, 10, 11, 10, 10, 0, 0, time.UTC),
+			Granularity: time.Minute,
+		}
+
+		insights := buckets.Insights(window)
+
+		assert.Equal(t, Counter{Total: 4, Success: 3, Failure: 1, SuccessRate: rate(0.75)}, insights.Published)
+		assert.Equal(t, Counter{Total: 4, Success: 3, Failure: 1, SuccessRate: rate(0.75)}, insights.Consumed)
+
+		assert.Equal(t, []InsightsBucket{
 			{
-				TopicName:      "payment.processed",
-				ConsumerName:   "consumer-1",
-				TimeStarted:    baseTime.Add(1 * time.Minute),
-				TimeEnded:      baseTime.Add(1*time.Minute + 3*time.Millisecond),
-				TimeDurationMs: 3,
-				ACK:            true,
+				Time:      time.Date(2025, 10, 11, 10, 7, 0, 0, time.UTC),
+				Published: Counter{Total: 2, Success: 2, SuccessRate: rate(1)},
+				Consumed:  Counter{Total: 2, Success: 2, SuccessRate: rate(1)},
 			},
 			{
-				TopicName:      "payment.processed",
-				ConsumerName:   "consumer-2",
-				TimeStarted:    baseTime,
-				TimeEnded:      baseTime.Add(8 * time.Millisecond),
-				TimeDurationMs: 8,
-				ACK:            true,
+				Time:      time.Date(2025, 10, 11, 10, 8, 0, 0, time.UTC),
+				Published: Counter{Total: 1, Success: 1, SuccessRate: rate(1)},
+				Consumed:  Counter{Total: 2, Success: 1, Failure: 1, SuccessRate: rate(0.5)},
 			},
 			{
-				TopicName:      "payment.processed",
-				ConsumerName:   "consumer-2",
-				TimeStarted:    baseTime.Add(1 * time.Minute),
-				TimeEnded:      baseTime.Add(1*time.Minute + 12*time.Millisecond),
-				TimeDurationMs: 12,
-				ACK:            false, // Error case
+				Time:      time.Date(2025, 10, 11, 10, 9, 0, 0, time.UTC),
+				Published: Counter{Total: 1, Failure: 1, SuccessRate: rate(0)},
 			},
-		}
+		}, insights.Series)
 
-		insights := metrics.Insights()
-
-		expected := Insights{
-			TotalPublished:             4,
-			TotalConsumed:              4,
-			TotalPublishedWithSuccess:  3,
-			TotalConsumedWithSuccess:   3,
-			TotalPublishedWithErr:      1,
-			TotalConsumedWithErr:       1,
-			PercentagePublishedSuccess: "75.00%",
-			PercentageConsumedSuccess:  "75.00%",
-			TotalSegmentationPublished: map[string]int64{
-				"payment.processed": 3,
-				"order.created":     1,
-			},
-			TotalSegmentationConsumed: map[string]int64{
-				"consumer-1": 2,
-				"consumer-2": 2,
-			},
-			RpmPublisher: map[string]*RPM{
-				"payment.processed": {
-					Timeseries: []time.Time{
-						time.Date(2025, 10, 11, 10, 7, 0, 0, time.UTC),
-						time.Date(2025, 10, 11, 10, 8, 0, 0, time.UTC),
-						time.Date(2025, 10, 11, 10, 9, 0, 0, time.UTC),
-					},
-					Values: []int64{1, 1, 1},
-				},
-				"order.created": {
-					Timeseries: []time.Time{
-						time.Date(2025, 10, 11, 10, 7, 0, 0, time.UTC),
-					},
-					Values: []int64{1},
-				},
+		// [10, 15, 20] for the publications of payment.processed and [8, 12] for consumer-2
+		assertLatency(t, Latency{P50: 25, P75: 25, P90: 25, P95: 25, P99: 25, P999: 25}, insights.Events[0].PublishLatency)
+		assertLatency(t, Latency{P50: 15, P75: 20, P90: 20, P95: 20, P99: 20, P999: 20}, insights.Events[1].PublishLatency)
+		assertLatency(t, Latency{P50: 3, P75: 5, P90: 5, P95: 5, P99: 5, P999: 5}, insights.Events[1].Consumers[0].Latency)
+		assertLatency(t, Latency{P50: 8, P75: 12, P90: 12, P95: 12, P99: 12, P999: 12}, insights.Events[1].Consumers[1].Latency)
+
+		insights.Events[0].PublishLatency = Latency{}
+		insights.Events[1].PublishLatency = Latency{}
+		insights.Events[1].Consumers[0].Latency = Latency{}
+		insights.Events[1].Consumers[1].Latency = Latency{}
+		assert.Equal(t, []EventInsights{
+			{
+				Event:     "order.created",
+				Published: Counter{Total: 1, Success: 1, SuccessRate: rate(1)},
+				Consumers: []ConsumerInsights{},
 			},
-			RpmConsumer: map[string]*RPM{
-				"payment.processed:consumer-1": {
-					Timeseries: []time.Time{
-						time.Date(2025, 10, 11, 10, 7, 0, 0, time.UTC),
-						time.Date(2025, 10, 11, 10, 8, 0, 0, time.UTC),
-					},
-					Values: []int64{1, 1},
-				},
-				"payment.processed:consumer-2": {
-					Timeseries: []time.Time{
-						time.Date(2025, 10, 11, 10, 7, 0, 0, time.UTC),
-						time.Date(2025, 10, 11, 10, 8, 0, 0, time.UTC),
-					},
-					Values: []int64{1, 1},
+			{
+				Event:     "payment.processed",
+				Published: Counter{Total: 3, Success: 2, Failure: 1, SuccessRate: rate(2.0 / 3)},
+				Consumers: []ConsumerInsights{
+					{Consumer: "consumer-1", Consumed: Counter{Total: 2, Success: 2, SuccessRate: rate(1)}},
+					{Consumer: "consumer-2", Consumed: Counter{Total: 2, Success: 1, Failure: 1, SuccessRate: rate(0.5)}},
 				},
 			},
-			P99Consumed: map[string]float64{
-				"payment.processed.consumer-1": 5.0, // max of [5, 3]
-				"payment.processed.consumer-2": 8.0, // max of [8, 12]
-			},
-			P75Consumed: map[string]float64{
-				"payment.processed.consumer-1": 5.0, // 75th percentile of [5, 3]
-				"payment.processed.consumer-2": 8.0, // 75th percentile of [8, 12]
-			},
-			P99Published: map[string]float64{
-				"payment.processed": 20.0, // max of [20, 15, 10]
-				"order.created":     25.0, // only one value [25]
-			},
-			P75Published: map[string]float64{
-				"payment.processed": 20.0, // 75th percentile of [20, 15, 10]
-				"order.created":     25.0, // only one value [25]
-			},
+		}, insights.Events)
+
+		t.Run("filtered by consumer", func(t *testing.T) {
+			window := window
+			window.Consumer = "consumer-2"
+
+			insights := buckets.Insights(window)
+			assert.Equal(t, Counter{}, insights.Published, "publications have no consumer")
+			assert.Equal(t, int64(2), insights.Consumed.Total)
+			assert.Len(t, insights.Events, 1)
+		})
+
+		t.Run("metrics outside the window are left out", func(t *testing.T) {
+			window := window
+			window.To = time.Date(2025, 10, 11, 10, 8, 0, 0, time.UTC)
+			window.Event = "payment.processed"
+
+			insights := buckets.Insights(window)
+			assert.Equal(t, int64(1), insights.Published.Total)
+			assert.Equal(t, int64(2), insights.Consumed.Total)
+			assert.Len(t, insights.Series, 1)
+		})
+	})
+}
+
+func rate(value float64) *float64 {
+	return &value
+}
+
+func TestFilterInsights_Window(t *testing.T) {
+	now := time.Date(2025, 10, 11, 10, 7, 47, 0, time.UTC)
+
+	t.Run("defaults to the last hour by minute", func(t *testing.T) {
+		window, err := FilterInsights{}.Window(now)
+		assert.NoError(t, err)
+		assert.Equal(t, now.Add(-time.Hour), window.From)
+		assert.Equal(t, now, window.To)
+		assert.Equal(t, time.Minute, window.Granularity)
+		assert.Len(t, window.Buckets(), 61, "the first bucket starts before from")
+	})
+
+	t.Run("bounds and granularity", func(t *testing.T) {
+		window, err := FilterInsights{From: "2025-10-10T14:00:00Z", To: "2025-10-10T15:00:00Z", Granularity: "15m"}.Window(now)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC),
+			time.Date(2025, 10, 10, 14, 15, 0, 0, time.UTC),
+			time.Date(2025, 10, 10, 14, 30, 0, 0, time.UTC),
+			time.Date(2025, 10, 10, 14, 45, 0, 0, time.UTC),
+		}, window.Buckets())
+	})
+
+	for name, filter := range map[string]FilterInsights{
+		"invalid from":        {From: "yesterday"},
+		"from after to":       {From: "2025-10-11T11:00:00Z"},
+		"unknown granularity": {Granularity: "week"},
+		"sub minute":          {Granularity: "30s"},
+		"too many buckets":    {From: "2025-10-01T00:00:00Z"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := filter.Window(now)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("a larger granularity allows a longer window", func(t *testing.T) {
+		_, err := FilterInsights{From: "2025-10-01T00:00:00Z", Granularity: "day"}.Window(now)
+		assert.NoError(t, err)
+	})
+}
+
+func TestInsights_EmptyWindow(t *testing.T) {
+	window := InsightsWindow{
+		From:        time.Date(2025, 10, 11, 10, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 10, 11, 11, 0, 0, 0, time.UTC),
+		Granularity: 30 * time.Minute,
+	}
+
+	data, err := json.Marshal(MetricsBuckets{}.Insights(window))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"from": "2025-10-11T10:00:00Z",
+		"to": "2025-10-11T11:00:00Z",
+		"granularity": "30m0s",
+		"event": "",
+		"consumer": "",
+		"published": {"total": 0, "success": 0, "failure": 0, "success_rate": null},
+		"consumed": {"total": 0, "success": 0, "failure": 0, "success_rate": null},
+		"series": [
+			{"time": "2025-10-11T10:00:00Z", "published": {"total": 0, "success": 0, "failure": 0, "success_rate": null}, "consumed": {"total": 0, "success": 0, "failure": 0, "success_rate": null}},
+			{"time": "2025-10-11T10:30:00Z", "published": {"total": 0, "success": 0, "failure": 0, "success_rate": null}, "consumed": {"total": 0, "success": 0, "failure": 0, "success_rate": null}}
+		],
+		"events": []
+	}`, string(data))
+}
+
+// assertLatency checks every percentile is within the accuracy of the sketch of the exact one
+func assertLatency(t *testing.T, want, got Latency) {
+	t.Helper()
+
+	assert.InEpsilon(t, want.P50, got.P50, latencyAccuracy, "p50")
+	assert.InEpsilon(t, want.P75, got.P75, latencyAccuracy, "p75")
+	assert.InEpsilon(t, want.P90, got.P90, latencyAccuracy, "p90")
+	assert.InEpsilon(t, want.P95, got.P95, latencyAccuracy, "p95")
+	assert.InEpsilon(t, want.P99, got.P99, latencyAccuracy, "p99")
+	assert.InEpsilon(t, want.P999, got.P999, latencyAccuracy, "p999")
+}
+
+func TestLatencySketch(t *testing.T) {
+	var sketch LatencySketch
+	assert.Equal(t, float64(0), sketch.Quantile(0.99))
+
+	for duration := int64(1); duration <= 10000; duration++ {
+		sketch.Add(LatencyBin(duration), 1)
+	}
+
+	assert.InEpsilon(t, 5000, sketch.Quantile(0.5), latencyAccuracy)
+	assert.InEpsilon(t, 9900, sketch.Quantile(0.99), latencyAccuracy)
+	assert.InEpsilon(t, 9990, sketch.Quantile(0.999), latencyAccuracy)
+	assert.Less(t, len(sketch), 500, "bins grow with the range of the durations, not their count")
+
+	t.Run("merge is exact", func(t *testing.T) {
+		var first, second, all LatencySketch
+		for duration := int64(0); duration < 2000; duration++ {
+			all.Add(LatencyBin(duration*7), 1)
+			if duration%2 == 0 {
+				first.Add(LatencyBin(duration*7), 1)
+			} else {
+				second.Add(LatencyBin(duration*7), 1)
+			}
 		}
 
-		assert.Equal(t, expected.TotalPublished, insights.TotalPublished)
-		assert.Equal(t, expected.TotalConsumed, insights.TotalConsumed)
-		assert.Equal(t, expected.TotalPublishedWithSuccess, insights.TotalPublishedWithSuccess)
-		assert.Equal(t, expected.TotalConsumedWithSuccess, insights.TotalConsumedWithSuccess)
-		assert.Equal(t, expected.TotalPublishedWithErr, insights.TotalPublishedWithErr)
-		assert.Equal(t, expected.TotalConsumedWithErr, insights.TotalConsumedWithErr)
-		assert.Equal(t, expected.PercentagePublishedSuccess, insights.PercentagePublishedSuccess)
-		assert.Equal(t, expected.PercentageConsumedSuccess, insights.PercentageConsumedSuccess)
-		assert.Equal(t, expected.TotalSegmentationPublished, insights.TotalSegmentationPublished)
-		assert.Equal(t, expected.TotalSegmentationConsumed, insights.TotalSegmentationConsumed)
-		assert.Equal(t, expected.RpmPublisher, insights.RpmPublisher)
-		assert.Equal(t, expected.RpmConsumer, insights.RpmConsumer)
-		assert.Equal(t, expected.P99Consumed, insights.P99Consumed)
-		assert.Equal(t, expected.P75Consumed, insights.P75Consumed)
-		assert.Equal(t, expected.P99Published, insights.P99Published)
-		assert.Equal(t, expected.P75Published, insights.P75Published)
+		first.Merge(second)
+		assert.Equal(t, all, first)
+		assert.Equal(t, int64(2000), first.Count())
+	})
+
+	t.Run("durations under a millisecond", func(t *testing.T) {
+		var sketch LatencySketch
+		sketch.Add(LatencyBin(0), 3)
+		sketch.Add(LatencyBin(100), 1)
+
+		assert.Equal(t, float64(0), sketch.Quantile(0.75))
+		assert.InEpsilon(t, 100, sketch.Quantile(0.99), latencyAccuracy)
 	})
 }
@@ -1,3 +1,135 @@
 package domain
 
-const ProjectID = "your-project-id"
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/topicutils"
+)
+
+// DefaultProjectID is the project of requests that do not name one. It owns every event
+// registered before projects existed.
+const DefaultProjectID = "default"
+
+// DefaultTopicPrefix is the topic prefix of the default project, it is the prefix every topic
+// had before projects existed so their messages keep being consumed
+const DefaultTopicPrefix = "your-project-id"
+
+var projectIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Project isolates a set of events: its own registry, topics, credentials and quotas
+type Project struct {
+	ID          string `json:"id" bson:"_id"`
+	Name        string `json:"name" bson:"name"`
+	TopicPrefix string `json:"topic_prefix" bson:"topic_prefix"`
+	// SecretHash is the SHA-256 of the secret clients of the project authenticate with,
+	// empty when the project accepts requests without credentials
+	SecretHash string        `json:"-" bson:"secret_hash"`
+	Quotas     ProjectQuotas `json:"quotas" bson:"quotas"`
+	CreatedAt  time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// ProjectQuotas limits what a project can use, zero means unlimited
+type ProjectQuotas struct {
+	MaxEvents int `json:"max_events" bson:"max_events"`
+	// MaxConsumers is the limit per event, MAX_CONSUMERS still applies on top of it
+	MaxConsumers int `json:"max_consumers" bson:"max_consumers"`
+	// PublishRate is the number of events per second the project can publish on each instance
+	PublishRate float64 `json:"publish_rate" bson:"publish_rate"`
+}
+
+func DefaultProject() Project {
+	return Project{ID: DefaultProjectID, Name: "Default", TopicPrefix: DefaultTopicPrefix}
+}
+
+func (p *Project) Validate() error {
+	if !projectIDPattern.MatchString(p.ID) {
+		return fmt.Errorf("invalid project id %q: use lowercase letters, digits and hyphens", p.ID)
+	}
+
+	if p.TopicPrefix == "" {
+		return fmt.Errorf("topic_prefix is required")
+	}
+
+	if p.Quotas.MaxEvents < 0 || p.Quotas.MaxConsumers < 0 || p.Quotas.PublishRate < 0 {
+		return fmt.Errorf("quotas must not be negative")
+	}
+
+	return nil
+}
+
+// TopicName is the topic of the queue inside the project
+func (p Project) TopicName(queue string) string {
+	return topicutils.BuildTopicName(p.TopicPrefix, queue)
+}
+
+// Namespace prefixes a storage key with the project. Keys of the default project are left as
+// they were before projects existed so their data is kept.
+func (p Project) Namespace(key string) string {
+	if p.ID == DefaultProjectID || p.ID == "" {
+		return key
+	}
+
+	return "projects:" + p.ID + ":" + key
+}
+
+func (p *Project) SetSecret(secret string) {
+	if secret == "" {
+		p.SecretHash = ""
+		return
+	}
+
+	p.SecretHash = hashSecret(secret)
+}
+
+func (p Project) HasCredentials() bool {
+	return p.SecretHash != ""
+}
+
+// CheckSecret reports whether the secret is the one of the project
+func (p Project) CheckSecret(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(p.SecretHash)) == 1
+}
+
+func (q ProjectQuotas) CheckEvents(count int) error {
+	if q.MaxEvents > 0 && count > q.MaxEvents {
+		return fmt.Errorf("%w: at most %d events", ProjectQuotaExceeded, q.MaxEvents)
+	}
+
+	return nil
+}
+
+func (q ProjectQuotas) CheckConsumers(count int) error {
+	if q.MaxConsumers > 0 && count > q.MaxConsumers {
+		return fmt.Errorf("%w: at most %d consumers per event", ProjectQuotaExceeded, q.MaxConsumers)
+	}
+
+	return nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+type projectKey struct{}
+
+// WithProject sets the project the request or message belongs to
+func WithProject(ctx context.Context, project Project) context.Context {
+	return context.WithValue(ctx, projectKey{}, project)
+}
+
+// ProjectFromContext returns the project set by WithProject, the default project when none was set
+func ProjectFromContext(ctx context.Context) Project {
+	if project, ok := ctx.Value(projectKey{}).(Project); ok {
+		return project
+	}
+
+	return DefaultProject()
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProject_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		project Project
+		wantErr bool
+	}{
+		{name: "valid", project: Project{ID: "acme-eu", TopicPrefix: "acme-eu"}},
+		{name: "uppercase id", project: Project{ID: "Acme", TopicPrefix: "acme"}, wantErr: true},
+		{name: "empty id", project: Project{TopicPrefix: "acme"}, wantErr: true},
+		{name: "missing topic prefix", project: Project{ID: "acme"}, wantErr: true},
+		{name: "negative quota", project: Project{ID: "acme", TopicPrefix: "acme", Quotas: ProjectQuotas{PublishRate: -1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.project.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProject_Namespace(t *testing.T) {
+	assert.Equal(t, "gqueue:dlq:index", DefaultProject().Namespace("gqueue:dlq:index"), "the default project keeps the keys it had")
+	assert.Equal(t, "projects:acme:gqueue:dlq:index", Project{ID: "acme"}.Namespace("gqueue:dlq:index"))
+}
+
+func TestProject_TopicName(t *testing.T) {
+	assert.Equal(t, "your-project-id-event-queue-request-to-external", DefaultProject().TopicName(EventQueueRequestToExternal))
+	assert.Equal(t, "acme-event-queue-request-to-external", Project{TopicPrefix: "acme"}.TopicName(EventQueueRequestToExternal))
+}
+
+func TestProject_Secret(t *testing.T) {
+	project := Project{ID: "acme"}
+	assert.False(t, project.HasCredentials())
+
+	project.SetSecret("s3cret")
+	assert.True(t, project.HasCredentials())
+	assert.NotContains(t, project.SecretHash, "s3cret")
+	assert.True(t, project.CheckSecret("s3cret"))
+	assert.False(t, project.CheckSecret("wrong"))
+
+	project.SetSecret("")
+	assert.False(t, project.HasCredentials())
+}
+
+func TestProjectQuotas(t *testing.T) {
+	unlimited := ProjectQuotas{}
+	assert.NoError(t, unlimited.CheckEvents(1000))
+	assert.NoError(t, unlimited.CheckConsumers(1000))
+
+	quotas := ProjectQuotas{MaxEvents: 2, MaxConsumers: 1}
+	assert.NoError(t, quotas.CheckEvents(2))
+	assert.ErrorIs(t, quotas.CheckEvents(3), ProjectQuotaExceeded)
+	assert.NoError(t, quotas.CheckConsumers(1))
+	assert.ErrorIs(t, quotas.CheckConsumers(2), ProjectQuotaExceeded)
+}
+
+func TestProjectFromContext(t *testing.T) {
+	assert.Equal(t, DefaultProjectID, ProjectFromContext(context.Background()).ID)
+
+	ctx := WithProject(context.Background(), Project{ID: "acme"})
+	assert.Equal(t, "acme", ProjectFromContext(ctx).ID)
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevisionAction string
+
+const (
+	RevisionCreated    RevisionAction = "created"
+	RevisionUpdated    RevisionAction = "updated"
+	RevisionDeleted    RevisionAction = "deleted"
+	RevisionRolledBack RevisionAction = "rolled_back"
+)
+
+// EventRevision records one change of an event: who made it, when, the resulting
+// configuration and what changed compared to the previous state.
+type EventRevision struct {
+	EventID  uuid.UUID      `json:"event_id"`
+	Revision int            `json:"revision"`
+	Action   RevisionAction `json:"action"`
+	Author   string         `json:"author"`
+	// RollbackOf is the revision restored by a rollback
+	RollbackOf int       `json:"rollback_of,omitempty"`
+	Event      Event     `json:"event"`
+	Changes    []Change  `json:"changes"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Change is a field that differs between two versions of an event. The path uses dots for
+// objects and brackets for lists, consumers are identified by their service name.
+type Change struct {
+	Path string `json:"path"`
+	From any    `json:"from"`
+	To   any    `json:"to"`
+}
+
+// DiffEvents lists the fields that differ from one version of an event to another, sorted by path
+func DiffEvents(from, to Event) []Change {
+	before := flattenEvent(from)
+	after := flattenEvent(to)
+
+	changes := make([]Change, 0)
+	for path, value := range before {
+		if next, ok := after[path]; !ok || !reflect.DeepEqual(value, next) {
+			changes = append(changes, Change{Path: path, From: value, To: after[path]})
+		}
+	}
+
+	for path, value := range after {
+		if _, ok := before[path]; !ok {
+			changes = append(changes, Change{Path: path, To: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// flattenEvent maps every leaf of the event JSON to its path, the id, project and revision are not
+// part of the configuration
+func flattenEvent(event Event) map[string]any {
+	var doc map[string]any
+	b, _ := json.Marshal(event)
+	_ = json.Unmarshal(b, &doc)
+	delete(doc, "id")
+	delete(doc, "project_id")
+	delete(doc, "revision")
+
+	output := make(map[string]any)
+	flatten("", doc, output)
+	return output
+}
+
+func flatten(prefix string, value any, output map[string]any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flatten(path, item, output)
+		}
+	case []any:
+		keyed := listKeys(v)
+		for i, item := range v {
+			flatten(fmt.Sprintf("%s[%s]", prefix, keyed[i]), item, output)
+		}
+	case nil:
+		// absent and null fields are the same configuration
+	default:
+		output[prefix] = v
+	}
+}
+
+// listKeys identifies the items by service name when every item has a distinct one,
+// so reordering consumers does not show up as a change
+func listKeys(items []any) []string {
+	keys := make([]string, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		obj, _ := item.(map[string]any)
+		name, _ := obj["service_name"].(string)
+		if name == "" || seen[name] {
+			for i := range items {
+				keys[i] = fmt.Sprint(i)
+			}
+			return keys
+		}
+
+		seen[name] = true
+		keys[i] = name
+	}
+
+	return keys
+}
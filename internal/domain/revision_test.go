@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffEvents(t *testing.T) {
+	base := func() Event {
+		return Event{
+			Name:        "payment.processed",
+			ServiceName: "payments",
+			State:       "active",
+			Option:      Opt{MaxRetries: 3},
+			Consumers: []Consumer{
+				{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+				{ServiceName: "ledger", BaseUrl: "http://ledger", Path: "/webhook"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(e *Event)
+		want   []Change
+	}{
+		{
+			name:   "no changes",
+			change: func(e *Event) {},
+			want:   []Change{},
+		},
+		{
+			name:   "id is not part of the configuration",
+			change: func(e *Event) { e.ID[0] = 1 },
+			want:   []Change{},
+		},
+		{
+			name:   "scalar fields",
+			change: func(e *Event) { e.State = "archived"; e.Option.MaxRetries = 5 },
+			want: []Change{
+				{Path: "option.max_retries", From: float64(3), To: float64(5)},
+				{Path: "state", From: "active", To: "archived"},
+			},
+		},
+		{
+			name: "consumers are matched by service name",
+			change: func(e *Event) {
+				e.Consumers = []Consumer{
+					{ServiceName: "ledger", BaseUrl: "http://ledger-v2", Path: "/webhook"},
+					{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook"},
+				}
+			},
+			want: []Change{
+				{Path: "consumers[ledger].host", From: "http://ledger", To: "http://ledger-v2"},
+			},
+		},
+		{
+			name: "removed consumer",
+			change: func(e *Event) {
+				e.Consumers = e.Consumers[:1]
+			},
+			want: []Change{
+				{Path: "consumers[ledger].host", From: "http://ledger"},
+				{Path: "consumers[ledger].path", From: "/webhook"},
+				{Path: "consumers[ledger].paused", From: false},
+				{Path: "consumers[ledger].service_name", From: "ledger"},
+			},
+		},
+		{
+			name:   "paused consumer",
+			change: func(e *Event) { _ = e.SetPaused("billing", true) },
+			want: []Change{
+				{Path: "consumers[billing].paused", From: false, To: true},
+			},
+		},
+		{
+			name:   "added header",
+			change: func(e *Event) { e.Consumers[0].Headers = map[string]string{"X-Token": "abc"} },
+			want: []Change{
+				{Path: "consumers[billing].headers.X-Token", To: "abc"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := base()
+			tt.change(&to)
+			assert.Equal(t, tt.want, DiffEvents(base(), to))
+		})
+	}
+}
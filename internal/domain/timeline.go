@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// TimelineStage is a step of the lifecycle of a published message
+type TimelineStage string
+
+const (
+	// StageAccepted is the publication of the message by the publish API
+	StageAccepted TimelineStage = "accepted"
+	// StageEnqueued is the message of one consumer put in the queue
+	StageEnqueued TimelineStage = "enqueued"
+	// StageDeliveryAttempt is a call to the consumer, with its outcome
+	StageDeliveryAttempt TimelineStage = "delivery_attempt"
+	// StageRetryScheduled follows a failed attempt that will be retried
+	StageRetryScheduled TimelineStage = "retry_scheduled"
+	// StageDeadLettered is the message kept after its last failed attempt
+	StageDeadLettered TimelineStage = "dead_lettered"
+	// StageReplayed is the message published again from the dead letters or archived tasks
+	StageReplayed TimelineStage = "replayed"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// TimelineEntry is something that happened to a published message. The message id is given by
+// the publish API and kept by every copy of the message, retries and replays included.
+type TimelineEntry struct {
+	MessageID string        `json:"message_id"`
+	Stage     TimelineStage `json:"stage"`
+	At        time.Time     `json:"at"`
+	EventName string        `json:"event_name,omitempty"`
+	Consumer  string        `json:"consumer,omitempty"`
+	// Attempt is the number of the delivery attempt, 1 for the first one
+	Attempt    int    `json:"attempt,omitempty"`
+	Outcome    string `json:"outcome,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	// Source of a replay: dead_letter, asynq or durable_sql
+	Source string `json:"source,omitempty"`
+}
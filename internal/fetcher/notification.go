@@ -14,6 +14,7 @@ import (
 	"github.com/IsaacDSC/gqueue/pkg/httpclient"
 	"github.com/IsaacDSC/gqueue/pkg/telemetry"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Notification struct{}
@@ -46,7 +47,15 @@ func (n Notification) NotifyScheduler(ctx context.Context, url string, data any,
 	return fetch(ctx, url, data, headers, notifyopt.Default)
 }
 
-func fetch(ctx context.Context, url string, data any, headers map[string]string, opt notifyopt.Kind, settings ...clienthttp.Option) error {
+// fetch posts the data to the url in a client span, the trace context is sent to the consumer in
+// the traceparent and tracestate headers
+func fetch(ctx context.Context, url string, data any, headers map[string]string, opt notifyopt.Kind, settings ...clienthttp.Option) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "gqueue.webhook", trace.SpanKindClient,
+		attribute.String("http.method", http.MethodPost),
+		attribute.String("http.url", url),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	start := time.Now()
 	payload, err := json.Marshal(data)
 	if err != nil {
@@ -66,6 +75,7 @@ func fetch(ctx context.Context, url string, data any, headers map[string]string,
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
+	telemetry.InjectHeaders(ctx, req.Header)
 
 	// #nosec G704 -- SSRF is intentional: this function sends webhooks to user-configured consumers endpoints
 	resp, err := client.Do(req)
@@ -81,6 +91,8 @@ func fetch(ctx context.Context, url string, data any, headers map[string]string,
 		attribute.Int("http.status_code", resp.StatusCode),
 	}
 
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
 	duration := time.Since(start).Seconds()
 	telemetry.HTTPClientRequests.Increment(ctx, attrs...)
 	telemetry.HTTPClientRequestDuration.Record(ctx, duration, attrs...)
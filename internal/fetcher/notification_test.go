@@ -8,10 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/internal/notifyopt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func init() {
@@ -392,3 +396,28 @@ func containsString(s, substr string) bool {
 				return false
 			}()))
 }
+
+func TestNotification_Notify_Traceparent(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tracer := sdktrace.NewTracerProvider().Tracer("test")
+	ctx, span := tracer.Start(context.Background(), "gqueue.deliver")
+	defer span.End()
+
+	consumer := domain.Consumer{ServiceName: "billing", BaseUrl: server.URL, Path: "/webhook"}
+	if err := NewNotification().Notify(ctx, map[string]any{"id": "1"}, nil, consumer, notifyopt.Default); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	want := "00-" + span.SpanContext().TraceID().String()
+	if !strings.HasPrefix(traceparent, want) {
+		t.Errorf("traceparent = %q, want the trace %q", traceparent, want)
+	}
+}
@@ -0,0 +1,117 @@
+package interstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/google/uuid"
+)
+
+type Broadcaster interface {
+	Broadcast(ctx context.Context, projectID string, eventNames ...string) error
+}
+
+// BroadcastStore notifies every instance when an event changes so their MemStore is updated
+// without waiting for the periodic resync. A failed broadcast does not fail the mutation,
+// the resync still picks the change up.
+type BroadcastStore struct {
+	Repository
+	broadcaster Broadcaster
+}
+
+var _ Repository = (*BroadcastStore)(nil)
+
+func NewBroadcastStore(repo Repository, broadcaster Broadcaster) *BroadcastStore {
+	return &BroadcastStore{Repository: repo, broadcaster: broadcaster}
+}
+
+func (s *BroadcastStore) Upsert(ctx context.Context, event domain.Event) error {
+	if err := s.Repository.Upsert(ctx, event); err != nil {
+		return err
+	}
+
+	s.broadcast(ctx, event.Name)
+	return nil
+}
+
+func (s *BroadcastStore) UpdateEvent(ctx context.Context, event domain.Event) error {
+	// the update can rename the event, the old name has to be evicted as well
+	names := []string{event.Name}
+	if current, err := s.Repository.GetEventByID(ctx, event.ID); err == nil && current.Name != event.Name {
+		names = append(names, current.Name)
+	}
+
+	if err := s.Repository.UpdateEvent(ctx, event); err != nil {
+		return err
+	}
+
+	s.broadcast(ctx, names...)
+	return nil
+}
+
+func (s *BroadcastStore) UpdateEventIfRevision(ctx context.Context, event domain.Event, revision int) (domain.Event, error) {
+	current, err := s.Repository.GetEventByID(ctx, event.ID)
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	updated, err := s.Repository.UpdateEventIfRevision(ctx, event, revision)
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	names := []string{updated.Name}
+	if current.Name != updated.Name {
+		names = append(names, current.Name)
+	}
+
+	s.broadcast(ctx, names...)
+	return updated, nil
+}
+
+func (s *BroadcastStore) DisabledEvent(ctx context.Context, eventID uuid.UUID) error {
+	current, err := s.Repository.GetEventByID(ctx, eventID)
+	if err != nil && !errors.Is(err, domain.EventNotFound) {
+		return err
+	}
+
+	if err := s.Repository.DisabledEvent(ctx, eventID); err != nil {
+		return err
+	}
+
+	// an event already removed is not in any MemStore
+	if current.Name != "" {
+		s.broadcast(ctx, current.Name)
+	}
+
+	return nil
+}
+
+func (s *BroadcastStore) RollbackEvent(ctx context.Context, eventID uuid.UUID, revision int) (domain.Event, error) {
+	// the restored revision can carry another name, the current one has to be evicted as well
+	current, err := s.Repository.GetEventByID(ctx, eventID)
+	if err != nil && !errors.Is(err, domain.EventNotFound) {
+		return domain.Event{}, err
+	}
+
+	event, err := s.Repository.RollbackEvent(ctx, eventID, revision)
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	names := []string{event.Name}
+	if current.Name != "" && current.Name != event.Name {
+		names = append(names, current.Name)
+	}
+
+	s.broadcast(ctx, names...)
+	return event, nil
+}
+
+func (s *BroadcastStore) broadcast(ctx context.Context, eventNames ...string) {
+	if err := s.broadcaster.Broadcast(ctx, domain.ProjectFromContext(ctx).ID, eventNames...); err != nil {
+		ctxlogger.GetLogger(ctx).Warn("Error broadcasting event invalidation", "event_names", eventNames, "error", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 
 	"github.com/IsaacDSC/gqueue/internal/domain"
@@ -14,13 +15,20 @@ import (
 
 type PersistentStore interface {
 	GetAllEvents(ctx context.Context) ([]domain.Event, error)
+	GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error)
 }
 
 type MemStore struct {
+	// topicEvents holds the events of every project, keyed by memKey
 	topicEvents atomic.Value
 	retryTopics atomic.Value
 	tag         string
 
+	// mu serializes writers of topicEvents, readers only load the map
+	mu sync.Mutex
+	// version is the registry version the in-memory events reflect
+	version atomic.Int64
+
 	persitentStore PersistentStore
 }
 
@@ -51,7 +59,7 @@ func (ms *MemStore) GetEvent(ctx context.Context, eventName string) (domain.Even
 	l := ctxlogger.GetLogger(ctx)
 
 	eventsMap := ms.topicEvents.Load().(map[string]domain.Event)
-	event, exists := eventsMap[eventName]
+	event, exists := eventsMap[memKey(domain.ProjectFromContext(ctx).ID, eventName)]
 	if !exists {
 		l.Warn("Event not found", "event_name", eventName, "tag", ms.tag)
 
@@ -67,18 +75,81 @@ func (ms *MemStore) GetEvent(ctx context.Context, eventName string) (domain.Even
 	return event, nil
 }
 
+// DeliveryPaused reports whether the deliveries of the event to the consumer are on hold.
+// An unknown event is not paused, its deliveries fail on their own.
+func (ms *MemStore) DeliveryPaused(ctx context.Context, eventName, serviceName string) bool {
+	eventsMap := ms.topicEvents.Load().(map[string]domain.Event)
+	event, exists := eventsMap[memKey(domain.ProjectFromContext(ctx).ID, eventName)]
+	return exists && event.DeliveryPaused(serviceName)
+}
+
 // DEPRECATED GetAllSchedulers is deprecated and should not be used. It will be removed in future versions. Please use GetRetryEvent by eventName with state parameter instead.
 func (ms *MemStore) GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error) {
 	return nil, domain.EventNotFound
 }
 
+// ReloadEvents fetches the given events of the project in ctx from the persistent store and replaces
+// only those entries. Events that no longer exist or were archived are removed.
+func (ms *MemStore) ReloadEvents(ctx context.Context, eventNames ...string) error {
+	projectID := domain.ProjectFromContext(ctx).ID
+	updated := make(map[string]domain.Event, len(eventNames))
+	for _, name := range eventNames {
+		event, err := ms.persitentStore.GetInternalEvent(ctx, name)
+		if errors.Is(err, domain.EventNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("error reloading event %s: %w", name, err)
+		}
+
+		if event.State != "archived" {
+			updated[name] = event
+		}
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	current := ms.topicEvents.Load().(map[string]domain.Event)
+	eventsMap := make(map[string]domain.Event, len(current)+len(updated))
+	for name, event := range current {
+		eventsMap[name] = event
+	}
+
+	for _, name := range eventNames {
+		if event, ok := updated[name]; ok {
+			eventsMap[memKey(projectID, name)] = event
+		} else {
+			delete(eventsMap, memKey(projectID, name))
+		}
+	}
+
+	ms.topicEvents.Store(eventsMap)
+
+	ctxlogger.GetLogger(ctx).Debug("Reloaded events in-memory store", "event_names", eventNames, "tag", ms.tag)
+	return nil
+}
+
+// Version returns the registry version applied to the in-memory store
+func (ms *MemStore) Version() int64 {
+	return ms.version.Load()
+}
+
+func (ms *MemStore) SetVersion(version int64) {
+	ms.version.Store(version)
+}
+
 func (ms *MemStore) Refresh(ctx context.Context, events []domain.Event) {
 	l := ctxlogger.GetLogger(ctx)
 
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	// Convert slice of events to a map for efficient lookups
 	eventsMap := make(map[string]domain.Event)
 	for _, event := range events {
-		eventsMap[event.Name] = event
+		eventsMap[memKey(event.ProjectID, event.Name)] = event
 	}
 
 	// Update the in-memory map with the latest events
@@ -87,6 +158,15 @@ func (ms *MemStore) Refresh(ctx context.Context, events []domain.Event) {
 	l.Debug("Refreshed in-memory store", "num_events", len(events), "tag", ms.tag)
 }
 
+// memKey identifies an event among the events of every project, the event names are unique per project
+func memKey(projectID, eventName string) string {
+	if projectID == "" {
+		projectID = domain.DefaultProjectID
+	}
+
+	return projectID + "/" + eventName
+}
+
 func (ms *MemStore) RefreshRetryTopics(ctx context.Context, events []domain.Event) {
 	l := ctxlogger.GetLogger(ctx)
 
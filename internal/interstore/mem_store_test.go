@@ -2,6 +2,7 @@ package interstore
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/IsaacDSC/gqueue/internal/domain"
@@ -319,3 +320,138 @@ func TestMemStore_DuplicateEventLastWins(t *testing.T) {
 		t.Errorf("expected State 'active', got '%s'", event.State)
 	}
 }
+
+func TestMemStore_ProjectIsolation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := mockinterstore.NewMockPersistentStore(ctrl)
+	ms := NewMemStore(mockStore)
+
+	acme := domain.WithProject(context.Background(), domain.Project{ID: "acme"})
+
+	ms.Refresh(context.Background(), []domain.Event{
+		{Name: "payment.processed", ServiceName: "default-service"},
+		{Name: "payment.processed", ServiceName: "acme-service", ProjectID: "acme"},
+	})
+
+	event, err := ms.GetEvent(context.Background(), "payment.processed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ServiceName != "default-service" {
+		t.Errorf("expected ServiceName 'default-service', got '%s'", event.ServiceName)
+	}
+
+	event, err = ms.GetEvent(acme, "payment.processed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ServiceName != "acme-service" {
+		t.Errorf("expected ServiceName 'acme-service', got '%s'", event.ServiceName)
+	}
+
+	// reloading an event of a project leaves the event with the same name of the others
+	mockStore.EXPECT().GetInternalEvent(gomock.Any(), "payment.processed").Return(domain.Event{}, domain.EventNotFound)
+	if err := ms.ReloadEvents(acme, "payment.processed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := ms.GetEvent(acme, "payment.processed"); !errors.Is(err, domain.EventNotFound) {
+		t.Errorf("expected EventNotFound, got %v", err)
+	}
+	if _, err := ms.GetEvent(context.Background(), "payment.processed"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMemStore_ReloadEvents(t *testing.T) {
+	existing := domain.Event{Name: "existing-event", ServiceName: "old-service", State: "active"}
+	untouched := domain.Event{Name: "untouched-event", ServiceName: "service", State: "active"}
+
+	tests := []struct {
+		name        string
+		eventName   string
+		setupMock   func(m *mockinterstore.MockPersistentStore)
+		expectFound bool
+		expected    domain.Event
+		wantErr     bool
+	}{
+		{
+			name:      "updates_changed_event",
+			eventName: "existing-event",
+			setupMock: func(m *mockinterstore.MockPersistentStore) {
+				m.EXPECT().GetInternalEvent(gomock.Any(), "existing-event").
+					Return(domain.Event{Name: "existing-event", ServiceName: "new-service", State: "active"}, nil)
+			},
+			expectFound: true,
+			expected:    domain.Event{Name: "existing-event", ServiceName: "new-service", State: "active"},
+		},
+		{
+			name:      "adds_new_event",
+			eventName: "new-event",
+			setupMock: func(m *mockinterstore.MockPersistentStore) {
+				m.EXPECT().GetInternalEvent(gomock.Any(), "new-event").
+					Return(domain.Event{Name: "new-event", State: "active"}, nil)
+			},
+			expectFound: true,
+			expected:    domain.Event{Name: "new-event", State: "active"},
+		},
+		{
+			name:      "removes_deleted_event",
+			eventName: "existing-event",
+			setupMock: func(m *mockinterstore.MockPersistentStore) {
+				m.EXPECT().GetInternalEvent(gomock.Any(), "existing-event").Return(domain.Event{}, domain.EventNotFound)
+			},
+			expectFound: false,
+		},
+		{
+			name:      "removes_archived_event",
+			eventName: "existing-event",
+			setupMock: func(m *mockinterstore.MockPersistentStore) {
+				m.EXPECT().GetInternalEvent(gomock.Any(), "existing-event").
+					Return(domain.Event{Name: "existing-event", State: "archived"}, nil)
+			},
+			expectFound: false,
+		},
+		{
+			name:      "keeps_store_on_error",
+			eventName: "existing-event",
+			setupMock: func(m *mockinterstore.MockPersistentStore) {
+				m.EXPECT().GetInternalEvent(gomock.Any(), "existing-event").Return(domain.Event{}, errors.New("connection refused"))
+			},
+			expectFound: true,
+			expected:    existing,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := mockinterstore.NewMockPersistentStore(ctrl)
+			tt.setupMock(mockStore)
+
+			ctx := context.Background()
+			ms := NewMemStore(mockStore)
+			ms.Refresh(ctx, []domain.Event{existing, untouched})
+
+			err := ms.ReloadEvents(ctx, tt.eventName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			event, err := ms.GetEvent(ctx, tt.eventName)
+			if tt.expectFound != (err == nil) {
+				t.Fatalf("expected found %v, got error %v", tt.expectFound, err)
+			}
+
+			if tt.expectFound && event.ServiceName != tt.expected.ServiceName {
+				t.Errorf("expected ServiceName %v, got %v", tt.expected.ServiceName, event.ServiceName)
+			}
+
+			if _, err := ms.GetEvent(ctx, untouched.Name); err != nil {
+				t.Errorf("expected untouched event to be kept, got %v", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package interstore
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/google/uuid"
@@ -9,11 +10,16 @@ import (
 
 type ModelEvent struct {
 	ID          uuid.UUID
+	ProjectID   string
 	Name        string
 	ServiceName string
 	State       string
 	Consumers   []byte
 	Option      []byte
+	Paused      bool
+	Revision    int
+	Schema      []byte
+	domain.Owner
 }
 
 func (m ModelEvent) ToDomain() domain.Event {
@@ -25,10 +31,56 @@ func (m ModelEvent) ToDomain() domain.Event {
 
 	return domain.Event{
 		ID:          m.ID,
+		ProjectID:   m.ProjectID,
 		Name:        m.Name,
 		ServiceName: m.ServiceName,
 		State:       m.State,
 		Consumers:   consumers,
 		Option:      option,
+		Paused:      m.Paused,
+		Revision:    m.Revision,
+		Owner:       m.Owner,
+		Schema:      m.Schema,
 	}
 }
+
+type MongoModelEvent struct {
+	ID          string            `bson:"_id"`
+	ProjectID   string            `bson:"project_id"`
+	Name        string            `bson:"name"`
+	ServiceName string            `bson:"service_name"`
+	State       string            `bson:"state"`
+	Consumers   []domain.Consumer `bson:"consumers"`
+	Option      domain.Opt        `bson:"option"`
+	Paused      bool              `bson:"paused"`
+	Revision    int               `bson:"revision"`
+	Owner       domain.Owner      `bson:",inline"`
+	// Schema is kept as JSON text, JSON Schema keywords like $ref are not valid field names
+	Schema    string     `bson:"payload_schema,omitempty"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+	DeletedAt *time.Time `bson:"deleted_at"`
+}
+
+func (m MongoModelEvent) ToDomain() domain.Event {
+	id, _ := uuid.Parse(m.ID)
+
+	event := domain.Event{
+		ID:          id,
+		ProjectID:   m.ProjectID,
+		Name:        m.Name,
+		ServiceName: m.ServiceName,
+		State:       m.State,
+		Consumers:   m.Consumers,
+		Option:      m.Option,
+		Paused:      m.Paused,
+		Revision:    m.Revision,
+		Owner:       m.Owner,
+	}
+
+	if m.Schema != "" {
+		event.Schema = json.RawMessage(m.Schema)
+	}
+
+	return event
+}
@@ -0,0 +1,166 @@
+package interstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const collectionAlertRules = "alert_rules"
+
+type MongoModelAlertRule struct {
+	ID            string           `bson:"_id"`
+	ProjectID     string           `bson:"project_id"`
+	Name          string           `bson:"name"`
+	Kind          domain.AlertKind `bson:"kind"`
+	Event         string           `bson:"event_name"`
+	Consumer      string           `bson:"consumer"`
+	Threshold     float64          `bson:"threshold"`
+	WindowSeconds int64            `bson:"window_seconds"`
+	MinSamples    int64            `bson:"min_samples"`
+	Enabled       bool             `bson:"enabled"`
+	CreatedAt     time.Time        `bson:"created_at"`
+	UpdatedAt     time.Time        `bson:"updated_at"`
+}
+
+func newMongoModelAlertRule(rule domain.AlertRule) MongoModelAlertRule {
+	return MongoModelAlertRule{
+		ID:            rule.ID.String(),
+		ProjectID:     rule.ProjectID,
+		Name:          rule.Name,
+		Kind:          rule.Kind,
+		Event:         rule.Event,
+		Consumer:      rule.Consumer,
+		Threshold:     rule.Threshold,
+		WindowSeconds: windowSeconds(rule.Window),
+		MinSamples:    rule.MinSamples,
+		Enabled:       rule.Enabled,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+}
+
+func (m MongoModelAlertRule) ToDomain() domain.AlertRule {
+	id, _ := uuid.Parse(m.ID)
+
+	return domain.AlertRule{
+		ID:         id,
+		ProjectID:  m.ProjectID,
+		Name:       m.Name,
+		Kind:       m.Kind,
+		Event:      m.Event,
+		Consumer:   m.Consumer,
+		Threshold:  m.Threshold,
+		Window:     intertime.Duration(time.Duration(m.WindowSeconds) * time.Second),
+		MinSamples: m.MinSamples,
+		Enabled:    m.Enabled,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func (r *MongoStore) CreateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error) {
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	model := newMongoModelAlertRule(rule)
+	if _, err := r.alertRules.InsertOne(ctx, model); err != nil {
+		return domain.AlertRule{}, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	return model.ToDomain(), nil
+}
+
+func (r *MongoStore) GetAlertRule(ctx context.Context, ruleID uuid.UUID) (domain.AlertRule, error) {
+	var model MongoModelAlertRule
+	err := r.alertRules.FindOne(ctx, alertRuleFilter(ctx, ruleID)).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.AlertRule{}, domain.AlertRuleNotFound
+	}
+
+	if err != nil {
+		return domain.AlertRule{}, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+
+	return model.ToDomain(), nil
+}
+
+func (r *MongoStore) ListAlertRules(ctx context.Context) ([]domain.AlertRule, error) {
+	cursor, err := r.alertRules.Find(ctx,
+		bson.D{{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	var models []MongoModelAlertRule
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, fmt.Errorf("failed to decode alert rules: %w", err)
+	}
+
+	rules := make([]domain.AlertRule, 0, len(models))
+	for _, model := range models {
+		rules = append(rules, model.ToDomain())
+	}
+
+	return rules, nil
+}
+
+func (r *MongoStore) UpdateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error) {
+	var model MongoModelAlertRule
+	err := r.alertRules.FindOneAndUpdate(ctx, alertRuleFilter(ctx, rule.ID),
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "name", Value: rule.Name},
+			{Key: "kind", Value: rule.Kind},
+			{Key: "event_name", Value: rule.Event},
+			{Key: "consumer", Value: rule.Consumer},
+			{Key: "threshold", Value: rule.Threshold},
+			{Key: "window_seconds", Value: windowSeconds(rule.Window)},
+			{Key: "min_samples", Value: rule.MinSamples},
+			{Key: "enabled", Value: rule.Enabled},
+			{Key: "updated_at", Value: time.Now()},
+		}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.AlertRule{}, domain.AlertRuleNotFound
+	}
+
+	if err != nil {
+		return domain.AlertRule{}, fmt.Errorf("failed to update alert rule: %w", err)
+	}
+
+	return model.ToDomain(), nil
+}
+
+func (r *MongoStore) DeleteAlertRule(ctx context.Context, ruleID uuid.UUID) error {
+	result, err := r.alertRules.DeleteOne(ctx, alertRuleFilter(ctx, ruleID))
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.AlertRuleNotFound
+	}
+
+	return nil
+}
+
+func alertRuleFilter(ctx context.Context, ruleID uuid.UUID) bson.D {
+	return bson.D{
+		{Key: "_id", Value: ruleID.String()},
+		{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID},
+	}
+}
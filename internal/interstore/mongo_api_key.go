@@ -0,0 +1,141 @@
+package interstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const collectionAPIKeys = "api_keys"
+
+type MongoModelAPIKey struct {
+	ID         string         `bson:"_id"`
+	ProjectID  string         `bson:"project_id"`
+	Name       string         `bson:"name"`
+	SecretHash string         `bson:"secret_hash"`
+	Scopes     []domain.Scope `bson:"scopes"`
+	ExpiresAt  *time.Time     `bson:"expires_at"`
+	LastUsedAt *time.Time     `bson:"last_used_at"`
+	RevokedAt  *time.Time     `bson:"revoked_at"`
+	CreatedAt  time.Time      `bson:"created_at"`
+}
+
+func (m MongoModelAPIKey) ToDomain() domain.APIKey {
+	id, _ := uuid.Parse(m.ID)
+
+	return domain.APIKey{
+		ID:         id,
+		ProjectID:  m.ProjectID,
+		Name:       m.Name,
+		SecretHash: m.SecretHash,
+		Scopes:     m.Scopes,
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func (r *MongoStore) CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error) {
+	model := MongoModelAPIKey{
+		ID:         key.ID.String(),
+		ProjectID:  key.ProjectID,
+		Name:       key.Name,
+		SecretHash: key.SecretHash,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		CreatedAt:  time.Now(),
+	}
+
+	if _, err := r.apiKeys.InsertOne(ctx, model); err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return model.ToDomain(), nil
+}
+
+func (r *MongoStore) GetAPIKey(ctx context.Context, keyID uuid.UUID) (domain.APIKey, error) {
+	var model MongoModelAPIKey
+	err := r.apiKeys.FindOne(ctx, bson.D{{Key: "_id", Value: keyID.String()}}).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.APIKey{}, domain.APIKeyNotFound
+	}
+
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return model.ToDomain(), nil
+}
+
+func (r *MongoStore) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	cursor, err := r.apiKeys.Find(ctx,
+		bson.D{{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	var models []MongoModelAPIKey
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, fmt.Errorf("failed to decode api keys: %w", err)
+	}
+
+	keys := make([]domain.APIKey, 0, len(models))
+	for _, model := range models {
+		keys = append(keys, model.ToDomain())
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey stops accepting the key, it is kept so its last use can still be audited
+func (r *MongoStore) RevokeAPIKey(ctx context.Context, keyID uuid.UUID) (domain.APIKey, error) {
+	filter := bson.D{
+		{Key: "_id", Value: keyID.String()},
+		{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID},
+	}
+
+	var model MongoModelAPIKey
+	err := r.apiKeys.FindOne(ctx, filter).Decode(&model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.APIKey{}, domain.APIKeyNotFound
+	}
+
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	if model.RevokedAt != nil {
+		return model.ToDomain(), nil
+	}
+
+	now := time.Now()
+	if _, err := r.apiKeys.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: bson.D{{Key: "revoked_at", Value: now}}}}); err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	model.RevokedAt = &now
+	return model.ToDomain(), nil
+}
+
+func (r *MongoStore) TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+	if _, err := r.apiKeys.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: keyID.String()}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "last_used_at", Value: usedAt}}}},
+	); err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+
+	return nil
+}
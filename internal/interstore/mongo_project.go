@@ -0,0 +1,189 @@
+package interstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// migrateProjects mirrors the 0007_create_projects migration: the default project is created and
+// owns the events registered before projects existed
+func (r *MongoStore) migrateProjects(ctx context.Context) error {
+	project := domain.DefaultProject()
+	now := time.Now()
+	_, err := r.projects.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: project.ID}},
+		bson.D{{Key: "$setOnInsert", Value: bson.D{
+			{Key: "name", Value: project.Name},
+			{Key: "topic_prefix", Value: project.TopicPrefix},
+			{Key: "secret_hash", Value: ""},
+			{Key: "quotas", Value: project.Quotas},
+			{Key: "created_at", Value: now},
+			{Key: "updated_at", Value: now},
+		}}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create default project: %w", err)
+	}
+
+	if _, err := r.events.UpdateMany(ctx,
+		bson.D{{Key: "project_id", Value: bson.D{{Key: "$exists", Value: false}}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "project_id", Value: project.ID}}}},
+	); err != nil {
+		return fmt.Errorf("failed to set project of events: %w", err)
+	}
+
+	// the event name is unique per project now
+	if err := r.events.Indexes().DropOne(ctx, "name_1"); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("failed to drop events name index: %w", err)
+	}
+
+	projectIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "topic_prefix", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	if _, err := r.projects.Indexes().CreateOne(ctx, projectIndex); err != nil {
+		return fmt.Errorf("failed to create project index: %w", err)
+	}
+
+	return nil
+}
+
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 27 || cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound")
+}
+
+func (r *MongoStore) CreateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	now := time.Now()
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	_, err := r.projects.InsertOne(ctx, project)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.Project{}, domain.ProjectAlreadyExists
+	}
+
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	return project, nil
+}
+
+func (r *MongoStore) GetProject(ctx context.Context, projectID string) (domain.Project, error) {
+	var project domain.Project
+	err := r.projects.FindOne(ctx, bson.D{{Key: "_id", Value: projectID}}).Decode(&project)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Project{}, domain.ProjectNotFound
+	}
+
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+func (r *MongoStore) ListProjects(ctx context.Context) ([]domain.Project, error) {
+	cursor, err := r.projects.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	projects := make([]domain.Project, 0)
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, fmt.Errorf("failed to decode projects: %w", err)
+	}
+
+	return projects, nil
+}
+
+// UpdateProject changes the name, credentials and quotas. The topic prefix is kept, the messages
+// already published to the topics of the project would no longer be consumed.
+func (r *MongoStore) UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: project.Name},
+		{Key: "secret_hash", Value: project.SecretHash},
+		{Key: "quotas", Value: project.Quotas},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+
+	var output domain.Project
+	err := r.projects.FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: project.ID}},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&output)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Project{}, domain.ProjectNotFound
+	}
+
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return output, nil
+}
+
+// DeleteProject removes a project without events, the events it deleted and its API keys are
+// removed with it
+func (r *MongoStore) DeleteProject(ctx context.Context, projectID string) error {
+	alive, err := r.events.CountDocuments(ctx,
+		bson.D{{Key: "project_id", Value: projectID}, {Key: "deleted_at", Value: nil}},
+		options.Count().SetLimit(1),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to check project events: %w", err)
+	}
+
+	if alive > 0 {
+		return domain.ProjectNotEmpty
+	}
+
+	result, err := r.projects.DeleteOne(ctx, bson.D{{Key: "_id", Value: projectID}})
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ProjectNotFound
+	}
+
+	filter := bson.D{{Key: "project_id", Value: projectID}}
+	var eventIDs []string
+	if err := r.events.Distinct(ctx, "_id", filter).Decode(&eventIDs); err != nil {
+		return fmt.Errorf("failed to list project events: %w", err)
+	}
+
+	// the revisions are removed with their events, like the foreign key of the events table does
+	if len(eventIDs) > 0 {
+		if _, err := r.revisions.DeleteMany(ctx, bson.D{{Key: "event_id", Value: bson.D{{Key: "$in", Value: eventIDs}}}}); err != nil {
+			return fmt.Errorf("failed to delete project revisions: %w", err)
+		}
+	}
+
+	if _, err := r.events.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete project events: %w", err)
+	}
+
+	if _, err := r.apiKeys.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete project api keys: %w", err)
+	}
+
+	if _, err := r.alertRules.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("failed to delete project alert rules: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package interstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const collectionEventRevisions = "event_revisions"
+
+type MongoModelRevision struct {
+	EventID    string                `bson:"event_id"`
+	Revision   int                   `bson:"revision"`
+	Action     domain.RevisionAction `bson:"action"`
+	Author     string                `bson:"author"`
+	RollbackOf int                   `bson:"rollback_of,omitempty"`
+	Event      MongoModelEvent       `bson:"snapshot"`
+	Changes    []domain.Change       `bson:"changes"`
+	CreatedAt  time.Time             `bson:"created_at"`
+}
+
+func (m MongoModelRevision) ToDomain() domain.EventRevision {
+	id, _ := uuid.Parse(m.EventID)
+
+	return domain.EventRevision{
+		EventID:    id,
+		Revision:   m.Revision,
+		Action:     m.Action,
+		Author:     m.Author,
+		RollbackOf: m.RollbackOf,
+		Event:      m.Event.ToDomain(),
+		Changes:    m.Changes,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// mongoMutation describes a change applied by mutate. filter selects the document being changed
+// (deleted or not), apply changes it and reports whether a document matched. apply receives the
+// state read before the change, empty when the event does not exist or was deleted. When
+// expectRevision is set the change only applies to that revision, apply must filter on it as well.
+type mongoMutation struct {
+	filter         bson.D
+	action         domain.RevisionAction
+	rollbackOf     int
+	expectRevision *int
+	apply          func(current domain.Event) (bool, error)
+}
+
+// mutate applies the change and records the resulting event as a new revision. MongoDB only
+// supports transactions on replica sets, so the revision is written right after the change.
+func (r *MongoStore) mutate(ctx context.Context, m mongoMutation) (domain.Event, error) {
+	var before MongoModelEvent
+	err := r.events.FindOne(ctx, m.filter).Decode(&before)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Event{}, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	// a deleted event is revived as a new one, so it is diffed against an empty event
+	alive := err == nil && before.DeletedAt == nil
+	previous := domain.Event{}
+	if alive {
+		previous = before.ToDomain()
+	}
+
+	if m.expectRevision != nil {
+		if !alive {
+			return domain.Event{}, domain.EventNotFound
+		}
+
+		if before.Revision != *m.expectRevision {
+			return domain.Event{}, domain.EventVersionConflict
+		}
+	}
+
+	matched, err := m.apply(previous)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.Event{}, domain.EventNameConflict
+	}
+
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	// the event existed with the expected revision, so it was changed in the meantime
+	if !matched && m.expectRevision != nil {
+		return domain.Event{}, domain.EventVersionConflict
+	}
+
+	if !matched {
+		return domain.Event{}, domain.EventNotFound
+	}
+
+	var after MongoModelEvent
+	if before.ID != "" {
+		err = r.events.FindOne(ctx, bson.D{{Key: "_id", Value: before.ID}}).Decode(&after)
+	} else {
+		err = r.events.FindOne(ctx, m.filter).Decode(&after)
+	}
+
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("failed to read changed event: %w", err)
+	}
+
+	action := m.action
+	if action == domain.RevisionUpdated && !alive {
+		action = domain.RevisionCreated
+	}
+
+	changes := domain.DiffEvents(previous, after.ToDomain())
+	if len(changes) == 0 && action != domain.RevisionDeleted {
+		return after.ToDomain(), nil
+	}
+
+	var counter struct {
+		Revision int `bson:"revision"`
+	}
+
+	if err := r.events.FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: after.ID}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "revision", Value: 1}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.D{{Key: "revision", Value: 1}}),
+	).Decode(&counter); err != nil {
+		return domain.Event{}, fmt.Errorf("failed to increment revision: %w", err)
+	}
+
+	after.Revision = counter.Revision
+
+	revision := MongoModelRevision{
+		EventID:    after.ID,
+		Revision:   counter.Revision,
+		Action:     action,
+		Author:     revisionAuthor(ctx),
+		RollbackOf: m.rollbackOf,
+		Event:      after,
+		Changes:    changes,
+		CreatedAt:  time.Now(),
+	}
+
+	if _, err := r.revisions.InsertOne(ctx, revision); err != nil {
+		return domain.Event{}, fmt.Errorf("failed to insert revision: %w", err)
+	}
+
+	return after.ToDomain(), nil
+}
+
+func (r *MongoStore) ListRevisions(ctx context.Context, eventID uuid.UUID) ([]domain.EventRevision, error) {
+	owned, err := r.ownsEvent(ctx, eventID)
+	if err != nil || !owned {
+		return make([]domain.EventRevision, 0), err
+	}
+
+	cursor, err := r.revisions.Find(ctx,
+		bson.D{{Key: "event_id", Value: eventID.String()}},
+		options.Find().SetSort(bson.D{{Key: "revision", Value: -1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	revisions := make([]domain.EventRevision, 0)
+	for cursor.Next(ctx) {
+		var revision MongoModelRevision
+		if err := cursor.Decode(&revision); err != nil {
+			return nil, fmt.Errorf("failed to decode revision: %w", err)
+		}
+		revisions = append(revisions, revision.ToDomain())
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+func (r *MongoStore) GetRevision(ctx context.Context, eventID uuid.UUID, revision int) (domain.EventRevision, error) {
+	owned, err := r.ownsEvent(ctx, eventID)
+	if err != nil {
+		return domain.EventRevision{}, err
+	}
+
+	if !owned {
+		return domain.EventRevision{}, domain.RevisionNotFound
+	}
+
+	filter := bson.D{{Key: "event_id", Value: eventID.String()}, {Key: "revision", Value: revision}}
+
+	var output MongoModelRevision
+	err = r.revisions.FindOne(ctx, filter).Decode(&output)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.EventRevision{}, domain.RevisionNotFound
+	}
+
+	if err != nil {
+		return domain.EventRevision{}, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return output.ToDomain(), nil
+}
+
+// RollbackEvent restores the configuration of an earlier revision, reviving the event if it
+// was deleted. The rollback is recorded as a new revision.
+func (r *MongoStore) RollbackEvent(ctx context.Context, eventID uuid.UUID, revision int) (domain.Event, error) {
+	target, err := r.GetRevision(ctx, eventID, revision)
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	filter := eventFilter(ctx, eventID)
+
+	return r.mutate(ctx, mongoMutation{
+		filter:     filter,
+		action:     domain.RevisionRolledBack,
+		rollbackOf: revision,
+		apply: func(_ domain.Event) (bool, error) {
+			update := bson.D{{Key: "$set", Value: bson.D{
+				{Key: "name", Value: target.Event.Name},
+				{Key: "service_name", Value: target.Event.ServiceName},
+				{Key: "state", Value: target.Event.State},
+				{Key: "consumers", Value: target.Event.Consumers},
+				{Key: "option", Value: target.Event.Option},
+				{Key: "paused", Value: target.Event.Paused},
+				{Key: "team_owner", Value: target.Event.TeamOwner},
+				{Key: "repo_url", Value: target.Event.RepoURL},
+				{Key: "contact", Value: target.Event.Contact},
+				{Key: "description", Value: target.Event.Description},
+				{Key: "payload_schema", Value: string(target.Event.Schema)},
+				{Key: "updated_at", Value: time.Now()},
+				{Key: "deleted_at", Value: nil},
+			}}}
+
+			return updateMatched(r.events.UpdateOne(ctx, filter, update))
+		},
+	})
+}
+
+// ownsEvent reports whether the event, deleted or not, belongs to the project of the context
+func (r *MongoStore) ownsEvent(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	count, err := r.events.CountDocuments(ctx, eventFilter(ctx, eventID), options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func updateMatched(result *mongo.UpdateResult, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0 || result.UpsertedCount > 0, nil
+}
@@ -1,70 +1,396 @@
 package interstore
 
-// type Event struct {
-// 	ID uuid.UUID `bson:"id"`
-// 	domain.Event
-// 	CreatedAt time.Time `bson:"createdAt"`
-// 	UpdatedAt time.Time `bson:"updatedAt"`
-// 	DeletedAt time.Time `bson:"deletedAt"`
-// }
-
-// const dbName = "webhook_service"
-// const collectionInternalEvent = "internal_events"
-// const collectionExternalEvent = "external_events"
-
-// type MongoStore struct {
-// 	collectionInternalEvent *mongo.Collection
-// 	collectionExternalEvent *mongo.Collection
-// }
-
-// func NewMongoStore(client *mongo.Client) *MongoStore {
-// 	db := client.Database(dbName)
-// 	return &MongoStore{
-// 		collectionInternalEvent: db.Collection(collectionInternalEvent),
-// 		collectionExternalEvent: db.Collection(collectionExternalEvent),
-// 	}
-// }
-
-// func (r MongoStore) GetInternalEvent(ctx context.Context, eventName, serviceName string, eventType string, state string) ([]domain.Event, error) {
-// 	l := ctxlogger.GetLogger(ctx)
-// 	filter := bson.D{{Key: "event.name", Value: eventName}}
-// 	var result Event
-
-// 	if err := r.collectionInternalEvent.FindOne(ctx, filter).Decode(&result); err != nil {
-// 		if errors.Is(mongo.ErrNoDocuments, err) {
-// 			l.Warn("No documents found", "eventName", eventName)
-// 			return nil, domain.EventNotFound
-// 		}
-
-// 		return nil, err
-// 	}
-
-// 	return []domain.Event{result.Event}, nil
-// }
-
-// func (r MongoStore) Save(ctx context.Context, event domain.Event) error {
-// 	l := ctxlogger.GetLogger(ctx)
-// 	if _, err := r.collectionInternalEvent.InsertOne(ctx, Event{
-// 		ID:        uuid.New(),
-// 		Event:     event,
-// 		CreatedAt: time.Now(),
-// 		UpdatedAt: time.Now(),
-// 	}); err != nil {
-// 		l.Error("Error on create internal event", "error", err)
-// 		return fmt.Errorf("failed to create internal event: %w", err)
-// 	}
-
-// 	return nil
-// }
-
-// // func (r MongoStore) SaveInternalEvent(ctx context.Context, event intersvc.InternalEvent) error {
-// // 	filter := bson.D{{Key: "name", Value: bson.D{{Key: "$eq", Value: event.Name}}}}
-// // 	update := bson.D{{Key: "$set", Value: event}}
-
-// // 	if _, err := r.collectionInternalEvent.UpdateOne(ctx, filter, update); err != nil {
-// // 		fmt.Println("Error updating internal event:", err)
-// // 		return err
-// // 	}
-
-// // 	return nil
-// // }
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/connstring"
+)
+
+const (
+	defaultMongoDatabase = "gqueue"
+	collectionEvents     = "events"
+	collectionProjects   = "projects"
+)
+
+type MongoStore struct {
+	client     *mongo.Client
+	events     *mongo.Collection
+	revisions  *mongo.Collection
+	projects   *mongo.Collection
+	apiKeys    *mongo.Collection
+	alertRules *mongo.Collection
+}
+
+// NewMongoStoreFromURI connects to the database in the URI (gqueue when omitted) and creates the indexes
+func NewMongoStoreFromURI(ctx context.Context, uri string) (*MongoStore, error) {
+	cs, err := connstring.ParseAndValidate(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mongo uri: %w", err)
+	}
+
+	dbName := cs.Database
+	if dbName == "" {
+		dbName = defaultMongoDatabase
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mongo connection: %w", err)
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to ping mongo: %w", err)
+	}
+
+	store := NewMongoStore(client, dbName)
+	if err := store.migrateProjects(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := store.CreateIndexes(ctx); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func NewMongoStore(client *mongo.Client, dbName string) *MongoStore {
+	return &MongoStore{
+		client:     client,
+		events:     client.Database(dbName).Collection(collectionEvents),
+		revisions:  client.Database(dbName).Collection(collectionEventRevisions),
+		projects:   client.Database(dbName).Collection(collectionProjects),
+		apiKeys:    client.Database(dbName).Collection(collectionAPIKeys),
+		alertRules: client.Database(dbName).Collection(collectionAlertRules),
+	}
+}
+
+var _ Repository = (*MongoStore)(nil)
+
+// CreateIndexes mirrors the indexes of the events table
+func (r *MongoStore) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "project_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "service_name", Value: 1}}},
+		{Keys: bson.D{{Key: "state", Value: 1}}},
+		{Keys: bson.D{{Key: "team_owner", Value: 1}}},
+	}
+
+	if _, err := r.events.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	revisionIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "event_id", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	if _, err := r.revisions.Indexes().CreateOne(ctx, revisionIndex); err != nil {
+		return fmt.Errorf("failed to create revision index: %w", err)
+	}
+
+	apiKeyIndex := mongo.IndexModel{Keys: bson.D{{Key: "project_id", Value: 1}}}
+	if _, err := r.apiKeys.Indexes().CreateOne(ctx, apiKeyIndex); err != nil {
+		return fmt.Errorf("failed to create api key index: %w", err)
+	}
+
+	alertRuleIndex := mongo.IndexModel{Keys: bson.D{{Key: "project_id", Value: 1}}}
+	if _, err := r.alertRules.Indexes().CreateOne(ctx, alertRuleIndex); err != nil {
+		return fmt.Errorf("failed to create alert rule index: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoStore) Close(ctx context.Context) error {
+	return r.client.Disconnect(ctx)
+}
+
+func (r *MongoStore) GetAllEvents(ctx context.Context) ([]domain.Event, error) {
+	filter := bson.D{
+		{Key: "deleted_at", Value: nil},
+		{Key: "state", Value: bson.D{{Key: "$ne", Value: "archived"}}},
+	}
+
+	return r.find(ctx, filter)
+}
+
+func (r *MongoStore) GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error) {
+	l := ctxlogger.GetLogger(ctx)
+
+	filter := bson.D{
+		{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID},
+		{Key: "name", Value: eventName},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	var event MongoModelEvent
+	err := r.events.FindOne(ctx, filter).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		l.Warn("No documents found", "event_name", eventName)
+		return domain.Event{}, domain.EventNotFound
+	}
+
+	if err != nil {
+		l.Error("Error on get internal event by event_name", "error", err)
+		return domain.Event{}, fmt.Errorf("failed to get internal event: %w", err)
+	}
+
+	return event.ToDomain(), nil
+}
+
+func (r *MongoStore) GetInternalEvents(ctx context.Context, filters domain.FilterEvents) ([]domain.Event, error) {
+	filter := bson.D{{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID}, {Key: "deleted_at", Value: nil}}
+
+	if len(filters.State) > 0 {
+		filter = append(filter, bson.E{Key: "state", Value: bson.D{{Key: "$in", Value: filters.State}}})
+	}
+
+	if len(filters.TeamOwner) > 0 {
+		filter = append(filter, bson.E{Key: "team_owner", Value: bson.D{{Key: "$in", Value: filters.TeamOwner}}})
+	}
+
+	if len(filters.RepoURL) > 0 {
+		filter = append(filter, bson.E{Key: "repo_url", Value: bson.D{{Key: "$in", Value: filters.RepoURL}}})
+	}
+
+	if len(filters.ConsumerTeam) > 0 {
+		filter = append(filter, bson.E{Key: "consumers.team_owner", Value: bson.D{{Key: "$in", Value: filters.ConsumerTeam}}})
+	}
+
+	if len(filters.ServiceName) > 0 {
+		filter = append(filter, bson.E{Key: "service_name", Value: bson.D{{Key: "$in", Value: filters.ServiceName}}})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	if filters.Limit > 0 {
+		opts.SetLimit(int64(filters.Limit)).SetSkip(int64(pageOffset(filters)))
+	}
+
+	return r.find(ctx, filter, opts)
+}
+
+// Upsert registers the event by name. Pauses are only changed through the pause endpoints, so an
+// existing event keeps its own and a new one starts unpaused.
+func (r *MongoStore) Upsert(ctx context.Context, event domain.Event) error {
+	l := ctxlogger.GetLogger(ctx)
+
+	if event.State == "" {
+		event.State = "active"
+	}
+
+	now := time.Now()
+	projectID := domain.ProjectFromContext(ctx).ID
+	filter := bson.D{{Key: "project_id", Value: projectID}, {Key: "name", Value: event.Name}}
+
+	_, err := r.mutate(ctx, mongoMutation{
+		filter: filter,
+		action: domain.RevisionUpdated,
+		apply: func(current domain.Event) (bool, error) {
+			event.KeepPauses(current)
+			update := bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "service_name", Value: event.ServiceName},
+					{Key: "state", Value: event.State},
+					{Key: "consumers", Value: event.Consumers},
+					{Key: "option", Value: event.Option},
+					{Key: "paused", Value: event.Paused},
+					{Key: "team_owner", Value: event.TeamOwner},
+					{Key: "repo_url", Value: event.RepoURL},
+					{Key: "contact", Value: event.Contact},
+					{Key: "description", Value: event.Description},
+					{Key: "payload_schema", Value: string(event.Schema)},
+					{Key: "updated_at", Value: now},
+					{Key: "deleted_at", Value: nil},
+				}},
+				{Key: "$setOnInsert", Value: bson.D{
+					{Key: "_id", Value: uuid.New().String()},
+					{Key: "project_id", Value: projectID},
+					{Key: "created_at", Value: now},
+				}},
+			}
+
+			return updateMatched(r.events.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true)))
+		},
+	})
+
+	if err != nil {
+		l.Error("Error on upsert internal event", "error", err)
+		return fmt.Errorf("failed to upsert internal event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoStore) GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error) {
+	l := ctxlogger.GetLogger(ctx)
+
+	filter := bson.D{
+		{Key: "_id", Value: eventID.String()},
+		{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	var event MongoModelEvent
+	err := r.events.FindOne(ctx, filter).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		l.Warn("Not found event", "tag", "MongoStore.GetEventByID")
+		return domain.Event{}, domain.EventNotFound
+	}
+
+	if err != nil {
+		l.Error("Error on get event by id", "tag", "MongoStore.GetEventByID", "error", err)
+		return domain.Event{}, fmt.Errorf("failed to get event by id: %w", err)
+	}
+
+	return event.ToDomain(), nil
+}
+
+// State: archived | active
+func (r *MongoStore) GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error) {
+	filter := bson.D{
+		{Key: "state", Value: state},
+		{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID},
+		{Key: "deleted_at", Value: nil},
+	}
+
+	events, err := r.find(ctx, filter)
+	if err != nil {
+		ctxlogger.GetLogger(ctx).Error("Error on get all schedulers", "tag", "MongoStore.GetAllSchedulers", "error", err)
+		return nil, fmt.Errorf("failed to get all schedulers: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *MongoStore) DisabledEvent(ctx context.Context, eventID uuid.UUID) error {
+	event := eventFilter(ctx, eventID)
+	filter := append(event, bson.E{Key: "deleted_at", Value: nil})
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "state", Value: "disabled"},
+		{Key: "deleted_at", Value: time.Now()},
+	}}}
+
+	_, err := r.mutate(ctx, mongoMutation{
+		filter: event,
+		action: domain.RevisionDeleted,
+		apply: func(_ domain.Event) (bool, error) {
+			return updateMatched(r.events.UpdateOne(ctx, filter, update))
+		},
+	})
+
+	// disabling an event that does not exist is a no-op
+	if err != nil && !errors.Is(err, domain.EventNotFound) {
+		return fmt.Errorf("failed to disable event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoStore) UpdateEvent(ctx context.Context, event domain.Event) error {
+	filter := append(eventFilter(ctx, event.ID), bson.E{Key: "deleted_at", Value: nil})
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: event.Name},
+		{Key: "service_name", Value: event.ServiceName},
+		{Key: "state", Value: event.State},
+		{Key: "consumers", Value: event.Consumers},
+		{Key: "option", Value: event.Option},
+		{Key: "paused", Value: event.Paused},
+		{Key: "team_owner", Value: event.TeamOwner},
+		{Key: "repo_url", Value: event.RepoURL},
+		{Key: "contact", Value: event.Contact},
+		{Key: "description", Value: event.Description},
+		{Key: "payload_schema", Value: string(event.Schema)},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+
+	_, err := r.mutate(ctx, mongoMutation{
+		filter: eventFilter(ctx, event.ID),
+		action: domain.RevisionUpdated,
+		apply: func(_ domain.Event) (bool, error) {
+			return updateMatched(r.events.UpdateOne(ctx, filter, update))
+		},
+	})
+
+	// updating an event that does not exist is a no-op
+	if err != nil && !errors.Is(err, domain.EventNotFound) {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateEventIfRevision updates the event only while its latest revision is still the given one
+func (r *MongoStore) UpdateEventIfRevision(ctx context.Context, event domain.Event, revision int) (domain.Event, error) {
+	filter := append(eventFilter(ctx, event.ID),
+		bson.E{Key: "deleted_at", Value: nil},
+		bson.E{Key: "revision", Value: revision},
+	)
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: event.Name},
+		{Key: "service_name", Value: event.ServiceName},
+		{Key: "state", Value: event.State},
+		{Key: "consumers", Value: event.Consumers},
+		{Key: "option", Value: event.Option},
+		{Key: "paused", Value: event.Paused},
+		{Key: "team_owner", Value: event.TeamOwner},
+		{Key: "repo_url", Value: event.RepoURL},
+		{Key: "contact", Value: event.Contact},
+		{Key: "description", Value: event.Description},
+		{Key: "payload_schema", Value: string(event.Schema)},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+
+	return r.mutate(ctx, mongoMutation{
+		filter:         eventFilter(ctx, event.ID),
+		action:         domain.RevisionUpdated,
+		expectRevision: &revision,
+		apply: func(_ domain.Event) (bool, error) {
+			return updateMatched(r.events.UpdateOne(ctx, filter, update))
+		},
+	})
+}
+
+// eventFilter selects the event, deleted or not, among the events of the project
+func eventFilter(ctx context.Context, eventID uuid.UUID) bson.D {
+	return bson.D{
+		{Key: "_id", Value: eventID.String()},
+		{Key: "project_id", Value: domain.ProjectFromContext(ctx).ID},
+	}
+}
+
+func (r *MongoStore) find(ctx context.Context, filter bson.D, opts ...options.Lister[options.FindOptions]) ([]domain.Event, error) {
+	cursor, err := r.events.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	events := make([]domain.Event, 0)
+	for cursor.Next(ctx) {
+		var event MongoModelEvent
+		if err := cursor.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode event: %w", err)
+		}
+		events = append(events, event.ToDomain())
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,150 @@
+package interstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
+	"github.com/google/uuid"
+)
+
+const alertRuleFields = `
+	id,
+	project_id,
+	name,
+	kind,
+	event_name,
+	consumer,
+	threshold,
+	window_seconds,
+	min_samples,
+	enabled,
+	created_at,
+	updated_at
+`
+
+func (r *PostgresStore) CreateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO alert_rules (id, project_id, name, kind, event_name, consumer, threshold, window_seconds, min_samples, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		RETURNING %s`, alertRuleFields)
+
+	output, err := scanAlertRule(r.db.QueryRowContext(ctx, query,
+		rule.ID, rule.ProjectID, rule.Name, rule.Kind, rule.Event, rule.Consumer, rule.Threshold,
+		windowSeconds(rule.Window), rule.MinSamples, rule.Enabled,
+	))
+	if err != nil {
+		return domain.AlertRule{}, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	return output, nil
+}
+
+func (r *PostgresStore) GetAlertRule(ctx context.Context, ruleID uuid.UUID) (domain.AlertRule, error) {
+	query := fmt.Sprintf(`SELECT %s FROM alert_rules WHERE id = $1 AND project_id = $2`, alertRuleFields)
+
+	output, err := scanAlertRule(r.db.QueryRowContext(ctx, query, ruleID, domain.ProjectFromContext(ctx).ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.AlertRule{}, domain.AlertRuleNotFound
+	}
+
+	if err != nil {
+		return domain.AlertRule{}, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+
+	return output, nil
+}
+
+func (r *PostgresStore) ListAlertRules(ctx context.Context) ([]domain.AlertRule, error) {
+	query := fmt.Sprintf(`SELECT %s FROM alert_rules WHERE project_id = $1 ORDER BY created_at`, alertRuleFields)
+
+	rows, err := r.db.QueryContext(ctx, query, domain.ProjectFromContext(ctx).ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+
+	defer rows.Close()
+
+	rules := make([]domain.AlertRule, 0)
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over alert rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *PostgresStore) UpdateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error) {
+	query := fmt.Sprintf(`
+		UPDATE alert_rules
+		SET name = $3, kind = $4, event_name = $5, consumer = $6, threshold = $7, window_seconds = $8,
+			min_samples = $9, enabled = $10, updated_at = NOW()
+		WHERE id = $1 AND project_id = $2
+		RETURNING %s`, alertRuleFields)
+
+	output, err := scanAlertRule(r.db.QueryRowContext(ctx, query,
+		rule.ID, domain.ProjectFromContext(ctx).ID, rule.Name, rule.Kind, rule.Event, rule.Consumer,
+		rule.Threshold, windowSeconds(rule.Window), rule.MinSamples, rule.Enabled,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.AlertRule{}, domain.AlertRuleNotFound
+	}
+
+	if err != nil {
+		return domain.AlertRule{}, fmt.Errorf("failed to update alert rule: %w", err)
+	}
+
+	return output, nil
+}
+
+func (r *PostgresStore) DeleteAlertRule(ctx context.Context, ruleID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1 AND project_id = $2`, ruleID, domain.ProjectFromContext(ctx).ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return domain.AlertRuleNotFound
+	}
+
+	return nil
+}
+
+func scanAlertRule(row interface{ Scan(dest ...any) error }) (domain.AlertRule, error) {
+	var rule domain.AlertRule
+	var window int64
+	if err := row.Scan(
+		&rule.ID,
+		&rule.ProjectID,
+		&rule.Name,
+		&rule.Kind,
+		&rule.Event,
+		&rule.Consumer,
+		&rule.Threshold,
+		&window,
+		&rule.MinSamples,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return domain.AlertRule{}, err
+	}
+
+	rule.Window = intertime.Duration(time.Duration(window) * time.Second)
+	return rule, nil
+}
+
+func windowSeconds(window intertime.Duration) int64 {
+	return int64(time.Duration(window) / time.Second)
+}
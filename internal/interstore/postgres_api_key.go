@@ -0,0 +1,144 @@
+package interstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const apiKeyFields = `
+	id,
+	project_id,
+	name,
+	secret_hash,
+	scopes,
+	expires_at,
+	last_used_at,
+	revoked_at,
+	created_at
+`
+
+func (r *PostgresStore) CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO api_keys (id, project_id, name, secret_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING %s`, apiKeyFields)
+
+	output, err := scanAPIKey(r.db.QueryRowContext(ctx, query,
+		key.ID, key.ProjectID, key.Name, key.SecretHash, pq.Array(scopeNames(key.Scopes)), key.ExpiresAt,
+	))
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return output, nil
+}
+
+func (r *PostgresStore) GetAPIKey(ctx context.Context, keyID uuid.UUID) (domain.APIKey, error) {
+	query := fmt.Sprintf(`SELECT %s FROM api_keys WHERE id = $1`, apiKeyFields)
+
+	output, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIKey{}, domain.APIKeyNotFound
+	}
+
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return output, nil
+}
+
+func (r *PostgresStore) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	query := fmt.Sprintf(`SELECT %s FROM api_keys WHERE project_id = $1 ORDER BY created_at`, apiKeyFields)
+
+	rows, err := r.db.QueryContext(ctx, query, domain.ProjectFromContext(ctx).ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+
+	defer rows.Close()
+
+	keys := make([]domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey stops accepting the key, it is kept so its last use can still be audited
+func (r *PostgresStore) RevokeAPIKey(ctx context.Context, keyID uuid.UUID) (domain.APIKey, error) {
+	query := fmt.Sprintf(`
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1 AND project_id = $2
+		RETURNING %s`, apiKeyFields)
+
+	output, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyID, domain.ProjectFromContext(ctx).ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIKey{}, domain.APIKeyNotFound
+	}
+
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return output, nil
+}
+
+func (r *PostgresStore) TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, keyID, usedAt); err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes pq.StringArray
+	if err := row.Scan(
+		&key.ID,
+		&key.ProjectID,
+		&key.Name,
+		&key.SecretHash,
+		&scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	key.Scopes = make([]domain.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, domain.Scope(scope))
+	}
+
+	return key, nil
+}
+
+func scopeNames(scopes []domain.Scope) []string {
+	names := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		names = append(names, string(scope))
+	}
+
+	return names
+}
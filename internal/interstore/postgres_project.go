@@ -0,0 +1,179 @@
+package interstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/lib/pq"
+)
+
+const projectFields = `
+	id,
+	name,
+	topic_prefix,
+	secret_hash,
+	quotas,
+	created_at,
+	updated_at
+`
+
+func (r *PostgresStore) CreateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	quotasJSON, err := json.Marshal(project.Quotas)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to marshal project quotas: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO projects (id, name, topic_prefix, secret_hash, quotas, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING %s`, projectFields)
+
+	output, err := scanProject(r.db.QueryRowContext(ctx, query,
+		project.ID, project.Name, project.TopicPrefix, project.SecretHash, quotasJSON,
+	))
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation {
+		return domain.Project{}, domain.ProjectAlreadyExists
+	}
+
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	return output, nil
+}
+
+func (r *PostgresStore) GetProject(ctx context.Context, projectID string) (domain.Project, error) {
+	query := fmt.Sprintf(`SELECT %s FROM projects WHERE id = $1`, projectFields)
+
+	output, err := scanProject(r.db.QueryRowContext(ctx, query, projectID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Project{}, domain.ProjectNotFound
+	}
+
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return output, nil
+}
+
+func (r *PostgresStore) ListProjects(ctx context.Context) ([]domain.Project, error) {
+	query := fmt.Sprintf(`SELECT %s FROM projects ORDER BY id`, projectFields)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+
+	defer rows.Close()
+
+	projects := make([]domain.Project, 0)
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over projects: %w", err)
+	}
+
+	return projects, nil
+}
+
+// UpdateProject changes the name, credentials and quotas. The topic prefix is kept, the messages
+// already published to the topics of the project would no longer be consumed.
+func (r *PostgresStore) UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	quotasJSON, err := json.Marshal(project.Quotas)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to marshal project quotas: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE projects
+		SET name = $2, secret_hash = $3, quotas = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING %s`, projectFields)
+
+	output, err := scanProject(r.db.QueryRowContext(ctx, query, project.ID, project.Name, project.SecretHash, quotasJSON))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Project{}, domain.ProjectNotFound
+	}
+
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return output, nil
+}
+
+// DeleteProject removes a project without events, the events it deleted, its API keys and alert
+// rules are removed with it
+func (r *PostgresStore) DeleteProject(ctx context.Context, projectID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	var alive bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM events WHERE project_id = $1 AND deleted_at IS NULL)`, projectID,
+	).Scan(&alive); err != nil {
+		return fmt.Errorf("failed to check project events: %w", err)
+	}
+
+	if alive {
+		return domain.ProjectNotEmpty
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE project_id = $1`, projectID); err != nil {
+		return fmt.Errorf("failed to delete project events: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM projects WHERE id = $1`, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return domain.ProjectNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func scanProject(row interface{ Scan(dest ...any) error }) (domain.Project, error) {
+	var project domain.Project
+	var quotas []byte
+	if err := row.Scan(
+		&project.ID,
+		&project.Name,
+		&project.TopicPrefix,
+		&project.SecretHash,
+		&quotas,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	); err != nil {
+		return domain.Project{}, err
+	}
+
+	if err := json.Unmarshal(quotas, &project.Quotas); err != nil {
+		return domain.Project{}, fmt.Errorf("failed to unmarshal project quotas: %w", err)
+	}
+
+	return project, nil
+}
@@ -0,0 +1,316 @@
+package interstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// pgUniqueViolation is the Postgres error code of a unique constraint violation
+const pgUniqueViolation = "23505"
+
+// pgMutation describes a change applied by mutate. lock selects the row being changed so the
+// previous state is read under the same lock, apply changes it and returns its id or uuid.Nil
+// when nothing matched. apply receives the state read under the lock, empty when the event does
+// not exist or was deleted. When expectRevision is set the change only applies to that revision.
+type pgMutation struct {
+	lock           string
+	lockArgs       []any
+	action         domain.RevisionAction
+	rollbackOf     int
+	expectRevision *int
+	apply          func(tx *sql.Tx, current domain.Event) (uuid.UUID, error)
+}
+
+// mutate applies the change and records the resulting event as a new revision in the same
+// transaction, so an event never changes without its history.
+func (r *PostgresStore) mutate(ctx context.Context, m pgMutation) (domain.Event, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	// a deleted event is revived as a new one, so it is diffed against an empty event
+	var before domain.Event
+	var alive bool
+	query := fmt.Sprintf(`SELECT %s, deleted_at IS NULL FROM events WHERE %s FOR UPDATE`, modelEventFields, m.lock)
+	before, alive, err = scanEventAlive(tx.QueryRowContext(ctx, query, m.lockArgs...))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return domain.Event{}, fmt.Errorf("failed to lock event: %w", err)
+	}
+
+	if !alive {
+		before = domain.Event{}
+	}
+
+	if m.expectRevision != nil {
+		if !alive {
+			return domain.Event{}, domain.EventNotFound
+		}
+
+		if before.Revision != *m.expectRevision {
+			return domain.Event{}, domain.EventVersionConflict
+		}
+	}
+
+	id, err := m.apply(tx, before)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation {
+			return domain.Event{}, domain.EventNameConflict
+		}
+		return domain.Event{}, err
+	}
+
+	if id == uuid.Nil {
+		return domain.Event{}, domain.EventNotFound
+	}
+
+	query = fmt.Sprintf(`SELECT %s FROM events WHERE id = $1`, modelEventFields)
+	after, err := scanEvent(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("failed to read changed event: %w", err)
+	}
+
+	action := m.action
+	if action == domain.RevisionUpdated && !alive {
+		action = domain.RevisionCreated
+	}
+
+	changes := domain.DiffEvents(before, after)
+	if len(changes) > 0 || action == domain.RevisionDeleted {
+		revision, err := r.insertRevision(ctx, tx, action, m.rollbackOf, after, changes)
+		if err != nil {
+			return domain.Event{}, err
+		}
+		after.Revision = revision
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Event{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return after, nil
+}
+
+// insertRevision records the event as its next revision and returns the revision number
+func (r *PostgresStore) insertRevision(ctx context.Context, tx *sql.Tx, action domain.RevisionAction, rollbackOf int, event domain.Event, changes []domain.Change) (int, error) {
+	var revision int
+	if err := tx.QueryRowContext(ctx,
+		`UPDATE events SET revision = revision + 1 WHERE id = $1 RETURNING revision`, event.ID,
+	).Scan(&revision); err != nil {
+		return 0, fmt.Errorf("failed to increment revision: %w", err)
+	}
+
+	event.Revision = revision
+	snapshot, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal revision snapshot: %w", err)
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal revision changes: %w", err)
+	}
+
+	var rollback *int
+	if rollbackOf > 0 {
+		rollback = &rollbackOf
+	}
+
+	query := `
+		INSERT INTO event_revisions (event_id, revision, action, author, rollback_of, snapshot, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+
+	if _, err := tx.ExecContext(ctx, query, event.ID, revision, action, revisionAuthor(ctx), rollback, snapshot, changesJSON); err != nil {
+		return 0, fmt.Errorf("failed to insert revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+// revisionOfProject limits the revisions to the events of the project, $2 being the project id
+const revisionOfProject = `EXISTS (SELECT 1 FROM events e WHERE e.id = event_id AND e.project_id = $2)`
+
+const revisionFields = `
+	event_id,
+	revision,
+	action,
+	author,
+	rollback_of,
+	snapshot,
+	changes,
+	created_at
+`
+
+func (r *PostgresStore) ListRevisions(ctx context.Context, eventID uuid.UUID) ([]domain.EventRevision, error) {
+	query := fmt.Sprintf(`SELECT %s FROM event_revisions WHERE event_id = $1 AND %s ORDER BY revision DESC`, revisionFields, revisionOfProject)
+
+	rows, err := r.db.QueryContext(ctx, query, eventID, domain.ProjectFromContext(ctx).ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+
+	defer rows.Close()
+
+	revisions := make([]domain.EventRevision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+func (r *PostgresStore) GetRevision(ctx context.Context, eventID uuid.UUID, revision int) (domain.EventRevision, error) {
+	query := fmt.Sprintf(`SELECT %s FROM event_revisions WHERE event_id = $1 AND %s AND revision = $3`, revisionFields, revisionOfProject)
+
+	output, err := scanRevision(r.db.QueryRowContext(ctx, query, eventID, domain.ProjectFromContext(ctx).ID, revision))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.EventRevision{}, domain.RevisionNotFound
+	}
+
+	if err != nil {
+		return domain.EventRevision{}, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return output, nil
+}
+
+// RollbackEvent restores the configuration of an earlier revision, reviving the event if it
+// was deleted. The rollback is recorded as a new revision.
+func (r *PostgresStore) RollbackEvent(ctx context.Context, eventID uuid.UUID, revision int) (domain.Event, error) {
+	target, err := r.GetRevision(ctx, eventID, revision)
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	consumersJSON, err := json.Marshal(target.Event.Consumers)
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("failed to marshal consumers: %w", err)
+	}
+
+	optsJSON, err := json.Marshal(target.Event.Option)
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("failed to marshal event option: %w", err)
+	}
+
+	projectID := domain.ProjectFromContext(ctx).ID
+
+	return r.mutate(ctx, pgMutation{
+		lock:       "id = $1 AND project_id = $2",
+		lockArgs:   []any{eventID, projectID},
+		action:     domain.RevisionRolledBack,
+		rollbackOf: revision,
+		apply: func(tx *sql.Tx, _ domain.Event) (uuid.UUID, error) {
+			query := `
+				UPDATE events
+				SET name = $2, service_name = $3, state = $4, consumers = $5, opts = $6, paused = $7,
+					team_owner = $8, repo_url = $9, contact = $10, description = $11, payload_schema = $13, updated_at = NOW(), deleted_at = NULL
+				WHERE id = $1 AND project_id = $12
+				RETURNING id`
+
+			return returningID(tx.QueryRowContext(ctx, query,
+				eventID, target.Event.Name, target.Event.ServiceName, target.Event.State, consumersJSON, optsJSON, target.Event.Paused,
+				target.Event.TeamOwner, target.Event.RepoURL, target.Event.Contact, target.Event.Description, projectID, nullJSON(target.Event.Schema),
+			))
+		},
+	})
+}
+
+func scanEvent(row interface{ Scan(dest ...any) error }) (domain.Event, error) {
+	var event ModelEvent
+	if err := row.Scan(
+		&event.ID,
+		&event.Name,
+		&event.ServiceName,
+		&event.State,
+		&event.Consumers,
+		&event.Option,
+		&event.Paused,
+		&event.Revision,
+		&event.TeamOwner,
+		&event.RepoURL,
+		&event.Contact,
+		&event.Description,
+		&event.ProjectID,
+		&event.Schema,
+	); err != nil {
+		return domain.Event{}, err
+	}
+
+	return event.ToDomain(), nil
+}
+
+func scanEventAlive(row interface{ Scan(dest ...any) error }) (domain.Event, bool, error) {
+	var event ModelEvent
+	var alive bool
+	if err := row.Scan(
+		&event.ID,
+		&event.Name,
+		&event.ServiceName,
+		&event.State,
+		&event.Consumers,
+		&event.Option,
+		&event.Paused,
+		&event.Revision,
+		&event.TeamOwner,
+		&event.RepoURL,
+		&event.Contact,
+		&event.Description,
+		&event.ProjectID,
+		&event.Schema,
+		&alive,
+	); err != nil {
+		return domain.Event{}, false, err
+	}
+
+	return event.ToDomain(), alive, nil
+}
+
+func scanRevision(row interface{ Scan(dest ...any) error }) (domain.EventRevision, error) {
+	var output domain.EventRevision
+	var rollbackOf *int
+	var snapshot, changes []byte
+	if err := row.Scan(
+		&output.EventID,
+		&output.Revision,
+		&output.Action,
+		&output.Author,
+		&rollbackOf,
+		&snapshot,
+		&changes,
+		&output.CreatedAt,
+	); err != nil {
+		return domain.EventRevision{}, err
+	}
+
+	if rollbackOf != nil {
+		output.RollbackOf = *rollbackOf
+	}
+
+	if err := json.Unmarshal(snapshot, &output.Event); err != nil {
+		return domain.EventRevision{}, fmt.Errorf("failed to unmarshal revision snapshot: %w", err)
+	}
+
+	if err := json.Unmarshal(changes, &output.Changes); err != nil {
+		return domain.EventRevision{}, fmt.Errorf("failed to unmarshal revision changes: %w", err)
+	}
+
+	return output, nil
+}
@@ -12,7 +12,7 @@ import (
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type PostgresStore struct {
@@ -61,6 +61,14 @@ func (r *PostgresStore) GetAllEvents(ctx context.Context) ([]domain.Event, error
 			&event.State,
 			&event.Consumers,
 			&event.Option,
+			&event.Paused,
+			&event.Revision,
+			&event.TeamOwner,
+			&event.RepoURL,
+			&event.Contact,
+			&event.Description,
+			&event.ProjectID,
+			&event.Schema,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
@@ -78,19 +86,29 @@ func (r *PostgresStore) GetInternalEvent(ctx context.Context, eventName string)
 	l := ctxlogger.GetLogger(ctx)
 
 	query := `
-			SELECT id, name, service_name, consumers, opts
+			SELECT id, name, service_name, state, consumers, opts, paused, revision, team_owner, repo_url, contact, description, project_id, payload_schema
 			FROM events
-			WHERE name = $1 AND deleted_at IS NULL
+			WHERE project_id = $1 AND name = $2 AND deleted_at IS NULL
 		`
 	var event domain.Event
 	var consumersJSON []byte
 	var optsJSON []byte
-	err := r.db.QueryRowContext(ctx, query, eventName).Scan(
+	var schema []byte
+	err := r.db.QueryRowContext(ctx, query, domain.ProjectFromContext(ctx).ID, eventName).Scan(
 		&event.ID,
 		&event.Name,
 		&event.ServiceName,
+		&event.State,
 		&consumersJSON,
 		&optsJSON,
+		&event.Paused,
+		&event.Revision,
+		&event.TeamOwner,
+		&event.RepoURL,
+		&event.Contact,
+		&event.Description,
+		&event.ProjectID,
+		&schema,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -111,27 +129,47 @@ func (r *PostgresStore) GetInternalEvent(ctx context.Context, eventName string)
 		return domain.Event{}, fmt.Errorf("failed to unmarshal event option: %w", err)
 	}
 
+	event.Schema = schema
+
 	return event, nil
 }
 
 func (r *PostgresStore) GetInternalEvents(ctx context.Context, filters domain.FilterEvents) ([]domain.Event, error) {
-	var sqlFilter string
+	conditions := []string{"project_id = $1", "deleted_at IS NULL"}
+	args := []any{domain.ProjectFromContext(ctx).ID}
 
 	if len(filters.State) > 0 {
-		sqlFilter += fmt.Sprintf("state IN ('%s')", strings.Join(filters.State, "', '"))
+		args = append(args, pq.Array(filters.State))
+		conditions = append(conditions, fmt.Sprintf("state = ANY($%d)", len(args)))
 	}
 
 	if len(filters.TeamOwner) > 0 {
-		sqlFilter += fmt.Sprintf("AND team_owner IN ('%s')", strings.Join(filters.TeamOwner, "', '"))
+		args = append(args, pq.Array(filters.TeamOwner))
+		conditions = append(conditions, fmt.Sprintf("team_owner = ANY($%d)", len(args)))
+	}
+
+	if len(filters.RepoURL) > 0 {
+		args = append(args, pq.Array(filters.RepoURL))
+		conditions = append(conditions, fmt.Sprintf("repo_url = ANY($%d)", len(args)))
+	}
+
+	if len(filters.ConsumerTeam) > 0 {
+		args = append(args, pq.Array(filters.ConsumerTeam))
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(consumers) AS c WHERE c->>'team_owner' = ANY($%d))", len(args)))
 	}
 
 	if len(filters.ServiceName) > 0 {
-		sqlFilter += fmt.Sprintf("AND service_name IN ('%s')", strings.Join(filters.ServiceName, "', '"))
+		args = append(args, pq.Array(filters.ServiceName))
+		conditions = append(conditions, fmt.Sprintf("service_name = ANY($%d)", len(args)))
 	}
 
-	query := fmt.Sprintf(`SELECT %s FROM events WHERE %s LIMIT %d OFFSET %d`, modelEventFields, sqlFilter, filters.Limit, filters.Page-1)
+	query := fmt.Sprintf(`SELECT %s FROM events WHERE %s ORDER BY name`, modelEventFields, strings.Join(conditions, " AND "))
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit, pageOffset(filters))
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events: %w", err)
 	}
@@ -148,6 +186,14 @@ func (r *PostgresStore) GetInternalEvents(ctx context.Context, filters domain.Fi
 			&event.State,
 			&event.Consumers,
 			&event.Option,
+			&event.Paused,
+			&event.Revision,
+			&event.TeamOwner,
+			&event.RepoURL,
+			&event.Contact,
+			&event.Description,
+			&event.ProjectID,
+			&event.Schema,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
@@ -161,14 +207,11 @@ func (r *PostgresStore) GetInternalEvents(ctx context.Context, filters domain.Fi
 	return events, nil
 }
 
+// Upsert registers the event by name. Pauses are only changed through the pause endpoints, so an
+// existing event keeps its own and a new one starts unpaused.
 func (r *PostgresStore) Upsert(ctx context.Context, event domain.Event) error {
 	l := ctxlogger.GetLogger(ctx)
 
-	consumersJSON, err := json.Marshal(event.Consumers)
-	if err != nil {
-		return fmt.Errorf("failed to marshal consumers: %w", err)
-	}
-
 	optsJSON, err := json.Marshal(event.Option)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event option: %w", err)
@@ -179,28 +222,63 @@ func (r *PostgresStore) Upsert(ctx context.Context, event domain.Event) error {
 	}
 
 	query := `
-		INSERT INTO events (id, name, service_name, state, consumers, opts, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		ON CONFLICT (name)
+		INSERT INTO events (id, name, service_name, state, consumers, opts, paused, team_owner, repo_url, contact, description, created_at, updated_at, project_id, payload_schema)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (project_id, name)
 		DO UPDATE SET
 			service_name = EXCLUDED.service_name,
 			state = EXCLUDED.state,
 			consumers = EXCLUDED.consumers,
 			opts = EXCLUDED.opts,
-			updated_at = EXCLUDED.updated_at
+			paused = CASE WHEN events.deleted_at IS NULL THEN events.paused ELSE EXCLUDED.paused END,
+			team_owner = EXCLUDED.team_owner,
+			repo_url = EXCLUDED.repo_url,
+			contact = EXCLUDED.contact,
+			description = EXCLUDED.description,
+			payload_schema = EXCLUDED.payload_schema,
+			updated_at = EXCLUDED.updated_at,
+			deleted_at = NULL
+		RETURNING id
 	`
 
-	now := time.Now()
-	_, err = r.db.ExecContext(ctx, query,
-		uuid.New(),
-		event.Name,
-		event.ServiceName,
-		event.State,
-		consumersJSON,
-		optsJSON,
-		now,
-		now,
-	)
+	projectID := domain.ProjectFromContext(ctx).ID
+
+	_, err = r.mutate(ctx, pgMutation{
+		lock:     "project_id = $1 AND name = $2",
+		lockArgs: []any{projectID, event.Name},
+		action:   domain.RevisionUpdated,
+		apply: func(tx *sql.Tx, current domain.Event) (uuid.UUID, error) {
+			// the pauses are read under the lock, so a concurrent pause is not overwritten
+			event.KeepPauses(current)
+			consumersJSON, err := json.Marshal(event.Consumers)
+			if err != nil {
+				return uuid.Nil, fmt.Errorf("failed to marshal consumers: %w", err)
+			}
+
+			now := time.Now()
+
+			var id uuid.UUID
+			err = tx.QueryRowContext(ctx, query,
+				uuid.New(),
+				event.Name,
+				event.ServiceName,
+				event.State,
+				consumersJSON,
+				optsJSON,
+				event.Paused,
+				event.TeamOwner,
+				event.RepoURL,
+				event.Contact,
+				event.Description,
+				now,
+				now,
+				projectID,
+				nullJSON(event.Schema),
+			).Scan(&id)
+
+			return id, err
+		},
+	})
 
 	if err != nil {
 		l.Error("Error on upsert internal event", "error", err)
@@ -216,22 +294,38 @@ const modelEventFields = `
 	service_name,
 	state,
 	consumers,
-	opts
+	opts,
+	paused,
+	revision,
+	team_owner,
+	repo_url,
+	contact,
+	description,
+	project_id,
+	payload_schema
 `
 
 func (r *PostgresStore) GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error) {
 	l := ctxlogger.GetLogger(ctx)
 
-	query := fmt.Sprintf(`SELECT %s FROM events WHERE id = $1 AND deleted_at IS NULL`, modelEventFields)
+	query := fmt.Sprintf(`SELECT %s FROM events WHERE id = $1 AND project_id = $2 AND deleted_at IS NULL`, modelEventFields)
 
 	var event ModelEvent
-	err := r.db.QueryRowContext(ctx, query, eventID).Scan(
+	err := r.db.QueryRowContext(ctx, query, eventID, domain.ProjectFromContext(ctx).ID).Scan(
 		&event.ID,
 		&event.Name,
 		&event.ServiceName,
 		&event.State,
 		&event.Consumers,
 		&event.Option,
+		&event.Paused,
+		&event.Revision,
+		&event.TeamOwner,
+		&event.RepoURL,
+		&event.Contact,
+		&event.Description,
+		&event.ProjectID,
+		&event.Schema,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -251,9 +345,9 @@ func (r *PostgresStore) GetEventByID(ctx context.Context, eventID uuid.UUID) (do
 func (r *PostgresStore) GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error) {
 	l := ctxlogger.GetLogger(ctx)
 
-	query := fmt.Sprintf(`SELECT %s FROM events WHERE state = $1 AND deleted_at IS NULL`, modelEventFields)
+	query := fmt.Sprintf(`SELECT %s FROM events WHERE state = $1 AND project_id = $2 AND deleted_at IS NULL`, modelEventFields)
 
-	rows, err := r.db.QueryContext(ctx, query, state)
+	rows, err := r.db.QueryContext(ctx, query, state, domain.ProjectFromContext(ctx).ID)
 	if errors.Is(err, sql.ErrNoRows) {
 		l.Warn("Not found schedulers", "tag", "PostgresStore.GetAllSchedulers")
 		return nil, domain.EventNotFound
@@ -276,6 +370,14 @@ func (r *PostgresStore) GetAllSchedulers(ctx context.Context, state string) ([]d
 			&event.State,
 			&event.Consumers,
 			&event.Option,
+			&event.Paused,
+			&event.Revision,
+			&event.TeamOwner,
+			&event.RepoURL,
+			&event.Contact,
+			&event.Description,
+			&event.ProjectID,
+			&event.Schema,
 		); err != nil {
 			l.Error("Error on scan row", "error", err)
 			return nil, fmt.Errorf("failed to scan row: %w", err)
@@ -292,9 +394,20 @@ func (r *PostgresStore) GetAllSchedulers(ctx context.Context, state string) ([]d
 }
 
 func (r *PostgresStore) DisabledEvent(ctx context.Context, eventID uuid.UUID) error {
-	query := `UPDATE events SET state = 'disabled', deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL;`
-	_, err := r.db.Exec(query, eventID)
-	if err != nil {
+	projectID := domain.ProjectFromContext(ctx).ID
+
+	_, err := r.mutate(ctx, pgMutation{
+		lock:     "id = $1 AND project_id = $2",
+		lockArgs: []any{eventID, projectID},
+		action:   domain.RevisionDeleted,
+		apply: func(tx *sql.Tx, _ domain.Event) (uuid.UUID, error) {
+			query := `UPDATE events SET state = 'disabled', deleted_at = NOW() WHERE id = $1 AND project_id = $2 AND deleted_at IS NULL RETURNING id`
+			return returningID(tx.QueryRowContext(ctx, query, eventID, projectID))
+		},
+	})
+
+	// disabling an event that does not exist is a no-op
+	if err != nil && !errors.Is(err, domain.EventNotFound) {
 		return fmt.Errorf("failed to disable event: %w", err)
 	}
 
@@ -308,8 +421,16 @@ func (r *PostgresStore) UpdateEvent(ctx context.Context, event domain.Event) err
 	service_name = $3,
 	state = $4,
 	consumers = $5,
-	opts = $6
-	WHERE id = $1 AND deleted_at IS NULL;`
+	opts = $6,
+	paused = $7,
+	team_owner = $8,
+	repo_url = $9,
+	contact = $10,
+	description = $11,
+	payload_schema = $13,
+	updated_at = NOW()
+	WHERE id = $1 AND project_id = $12 AND deleted_at IS NULL
+	RETURNING id;`
 
 	consumersJSON, err := json.Marshal(event.Consumers)
 	if err != nil {
@@ -321,9 +442,77 @@ func (r *PostgresStore) UpdateEvent(ctx context.Context, event domain.Event) err
 		return fmt.Errorf("failed to marshal event option: %w", err)
 	}
 
-	if _, err := r.db.ExecContext(ctx, query, event.ID, event.Name, event.ServiceName, event.State, consumersJSON, optsJSON); err != nil {
+	projectID := domain.ProjectFromContext(ctx).ID
+
+	_, err = r.mutate(ctx, pgMutation{
+		lock:     "id = $1 AND project_id = $2",
+		lockArgs: []any{event.ID, projectID},
+		action:   domain.RevisionUpdated,
+		apply: func(tx *sql.Tx, _ domain.Event) (uuid.UUID, error) {
+			return returningID(tx.QueryRowContext(ctx, query, event.ID, event.Name, event.ServiceName, event.State, consumersJSON, optsJSON, event.Paused,
+				event.TeamOwner, event.RepoURL, event.Contact, event.Description, projectID, nullJSON(event.Schema),
+			))
+		},
+	})
+
+	// updating an event that does not exist is a no-op
+	if err != nil && !errors.Is(err, domain.EventNotFound) {
 		return fmt.Errorf("failed to update event: %w", err)
 	}
 
 	return nil
 }
+
+// UpdateEventIfRevision updates the event only while its latest revision is still the given one
+func (r *PostgresStore) UpdateEventIfRevision(ctx context.Context, event domain.Event, revision int) (domain.Event, error) {
+	query := `
+	UPDATE events
+	SET name = $2, service_name = $3, state = $4, consumers = $5, opts = $6, paused = $7,
+		team_owner = $8, repo_url = $9, contact = $10, description = $11, payload_schema = $13, updated_at = NOW()
+	WHERE id = $1 AND project_id = $12 AND deleted_at IS NULL
+	RETURNING id`
+
+	consumersJSON, err := json.Marshal(event.Consumers)
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("failed to marshal consumers: %w", err)
+	}
+
+	optsJSON, err := json.Marshal(event.Option)
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("failed to marshal event option: %w", err)
+	}
+
+	projectID := domain.ProjectFromContext(ctx).ID
+
+	return r.mutate(ctx, pgMutation{
+		lock:           "id = $1 AND project_id = $2",
+		lockArgs:       []any{event.ID, projectID},
+		action:         domain.RevisionUpdated,
+		expectRevision: &revision,
+		apply: func(tx *sql.Tx, _ domain.Event) (uuid.UUID, error) {
+			return returningID(tx.QueryRowContext(ctx, query, event.ID, event.Name, event.ServiceName, event.State, consumersJSON, optsJSON, event.Paused,
+				event.TeamOwner, event.RepoURL, event.Contact, event.Description, projectID, nullJSON(event.Schema),
+			))
+		},
+	})
+}
+
+// returningID reads the id of a RETURNING clause, uuid.Nil when no row matched
+func returningID(row *sql.Row) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := row.Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+
+	return id, err
+}
+
+// nullJSON stores an empty JSON document as NULL
+func nullJSON(doc json.RawMessage) any {
+	if len(doc) == 0 {
+		return nil
+	}
+
+	return []byte(doc)
+}
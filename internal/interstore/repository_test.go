@@ -0,0 +1,446 @@
+package interstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/migrations"
+	"github.com/IsaacDSC/gqueue/pkg/intertime"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// repositories returns every backend reachable from the test environment, each one empty.
+// Postgres uses DB_CONNECTION_STRING and MongoDB uses MONGO_CONNECTION_STRING.
+func repositories(t *testing.T) map[string]Repository {
+	t.Helper()
+
+	ctx := context.Background()
+	repos := make(map[string]Repository)
+
+	if dsn := os.Getenv("DB_CONNECTION_STRING"); dsn != "" {
+		if db, err := sql.Open("postgres", dsn); err == nil && db.PingContext(ctx) == nil {
+			m, err := migrations.New(db)
+			require.NoError(t, err)
+			_, err = m.Up(ctx)
+			require.NoError(t, err)
+			_, err = db.ExecContext(ctx, "TRUNCATE events CASCADE")
+			require.NoError(t, err)
+			_, err = db.ExecContext(ctx, "DELETE FROM projects WHERE id <> 'default'")
+			require.NoError(t, err)
+			_, err = db.ExecContext(ctx, "DELETE FROM api_keys")
+			require.NoError(t, err)
+			_, err = db.ExecContext(ctx, "DELETE FROM alert_rules")
+			require.NoError(t, err)
+
+			t.Cleanup(func() { db.Close() })
+			repos[DriverPostgres] = NewPostgresStore(db)
+		}
+	}
+
+	if uri := os.Getenv("MONGO_CONNECTION_STRING"); uri != "" {
+		client, err := mongo.Connect(options.Client().ApplyURI(uri).SetServerSelectionTimeout(2 * time.Second))
+		if err == nil && client.Ping(ctx, nil) == nil {
+			store := NewMongoStore(client, "gqueue_test")
+			require.NoError(t, store.events.Drop(ctx))
+			require.NoError(t, store.revisions.Drop(ctx))
+			require.NoError(t, store.projects.Drop(ctx))
+			require.NoError(t, store.apiKeys.Drop(ctx))
+			require.NoError(t, store.alertRules.Drop(ctx))
+			require.NoError(t, store.migrateProjects(ctx))
+			require.NoError(t, store.CreateIndexes(ctx))
+
+			t.Cleanup(func() { client.Disconnect(ctx) })
+			repos[DriverMongo] = store
+		}
+	}
+
+	if len(repos) == 0 {
+		t.Skip("no database available, set DB_CONNECTION_STRING or MONGO_CONNECTION_STRING")
+	}
+
+	return repos
+}
+
+func newEvent(name, serviceName, state string) domain.Event {
+	return domain.Event{
+		Name:        name,
+		ServiceName: serviceName,
+		State:       state,
+		Option:      domain.Opt{MaxRetries: 3},
+		Consumers: []domain.Consumer{
+			{ServiceName: "consumer", BaseUrl: "http://localhost:8080", Path: "/webhook"},
+		},
+	}
+}
+
+func TestRepository_Behavior(t *testing.T) {
+	for driver, repo := range repositories(t) {
+		t.Run(driver, func(t *testing.T) {
+			ctx := context.Background()
+
+			t.Run("upsert_creates_and_updates_by_name", func(t *testing.T) {
+				require.NoError(t, repo.Upsert(ctx, newEvent("upsert.event", "svc-a", "")))
+
+				created, err := repo.GetInternalEvent(ctx, "upsert.event")
+				require.NoError(t, err)
+				assert.Equal(t, "active", created.State)
+				assert.Equal(t, "svc-a", created.ServiceName)
+				assert.Len(t, created.Consumers, 1)
+				assert.Equal(t, 3, created.Option.MaxRetries)
+
+				require.NoError(t, repo.Upsert(ctx, newEvent("upsert.event", "svc-b", "active")))
+
+				updated, err := repo.GetInternalEvent(ctx, "upsert.event")
+				require.NoError(t, err)
+				assert.Equal(t, created.ID, updated.ID)
+				assert.Equal(t, "svc-b", updated.ServiceName)
+			})
+
+			t.Run("upsert_keeps_pauses", func(t *testing.T) {
+				registered := newEvent("paused.event", "svc-a", "active")
+				registered.Paused = true
+				registered.Consumers[0].Paused = true
+				require.NoError(t, repo.Upsert(ctx, registered))
+
+				created, err := repo.GetInternalEvent(ctx, "paused.event")
+				require.NoError(t, err)
+				assert.False(t, created.Paused, "a new event starts unpaused")
+				assert.False(t, created.Consumers[0].Paused)
+
+				created.Paused = true
+				require.NoError(t, created.SetPaused("consumer", true))
+				require.NoError(t, repo.UpdateEvent(ctx, created))
+
+				require.NoError(t, repo.Upsert(ctx, newEvent("paused.event", "svc-b", "active")))
+
+				got, err := repo.GetInternalEvent(ctx, "paused.event")
+				require.NoError(t, err)
+				assert.Equal(t, "svc-b", got.ServiceName)
+				assert.True(t, got.Paused)
+				assert.True(t, got.Consumers[0].Paused)
+			})
+
+			t.Run("get_unknown_event_returns_not_found", func(t *testing.T) {
+				_, err := repo.GetInternalEvent(ctx, "unknown.event")
+				assert.True(t, errors.Is(err, domain.EventNotFound))
+			})
+
+			t.Run("update_event_by_id", func(t *testing.T) {
+				require.NoError(t, repo.Upsert(ctx, newEvent("update.event", "svc-a", "active")))
+				event, err := repo.GetInternalEvent(ctx, "update.event")
+				require.NoError(t, err)
+
+				event.ServiceName = "svc-updated"
+				event.Consumers = append(event.Consumers, domain.Consumer{ServiceName: "other", BaseUrl: "http://other", Path: "/"})
+				event.Paused = true
+				require.NoError(t, event.SetPaused("other", true))
+				require.NoError(t, repo.UpdateEvent(ctx, event))
+
+				got, err := repo.GetEventByID(ctx, event.ID)
+				require.NoError(t, err)
+				assert.Equal(t, "svc-updated", got.ServiceName)
+				assert.Len(t, got.Consumers, 2)
+				assert.True(t, got.Paused)
+				assert.True(t, got.Consumers[1].Paused)
+			})
+
+			t.Run("disabled_event_is_soft_deleted", func(t *testing.T) {
+				require.NoError(t, repo.Upsert(ctx, newEvent("disabled.event", "svc-a", "active")))
+				event, err := repo.GetInternalEvent(ctx, "disabled.event")
+				require.NoError(t, err)
+
+				require.NoError(t, repo.DisabledEvent(ctx, event.ID))
+
+				_, err = repo.GetInternalEvent(ctx, "disabled.event")
+				assert.True(t, errors.Is(err, domain.EventNotFound))
+				_, err = repo.GetEventByID(ctx, event.ID)
+				assert.True(t, errors.Is(err, domain.EventNotFound))
+
+				all, err := repo.GetAllEvents(ctx)
+				require.NoError(t, err)
+				for _, e := range all {
+					assert.NotEqual(t, "disabled.event", e.Name)
+				}
+
+				// registering the event again restores it
+				require.NoError(t, repo.Upsert(ctx, newEvent("disabled.event", "svc-a", "active")))
+				_, err = repo.GetInternalEvent(ctx, "disabled.event")
+				assert.NoError(t, err)
+			})
+
+			t.Run("state_queries", func(t *testing.T) {
+				require.NoError(t, repo.Upsert(ctx, newEvent("archived.event", "svc-c", "archived")))
+
+				archived, err := repo.GetAllSchedulers(ctx, "archived")
+				require.NoError(t, err)
+				require.Len(t, archived, 1)
+				assert.Equal(t, "archived.event", archived[0].Name)
+
+				all, err := repo.GetAllEvents(ctx)
+				require.NoError(t, err)
+				for _, e := range all {
+					assert.NotEqual(t, "archived", e.State)
+				}
+			})
+
+			t.Run("filters_and_pagination", func(t *testing.T) {
+				events, err := repo.GetInternalEvents(ctx, domain.FilterEvents{ServiceName: []string{"svc-a"}})
+				require.NoError(t, err)
+				names := make([]string, 0, len(events))
+				for _, e := range events {
+					names = append(names, e.Name)
+				}
+				assert.Equal(t, []string{"disabled.event"}, names)
+
+				events, err = repo.GetInternalEvents(ctx, domain.FilterEvents{State: []string{"archived"}})
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				assert.Equal(t, "archived.event", events[0].Name)
+
+				firstPage, err := repo.GetInternalEvents(ctx, domain.FilterEvents{Page: 1, Limit: 2})
+				require.NoError(t, err)
+				secondPage, err := repo.GetInternalEvents(ctx, domain.FilterEvents{Page: 2, Limit: 2})
+				require.NoError(t, err)
+				assert.Len(t, firstPage, 2)
+				assert.Len(t, secondPage, 2)
+				assert.NotEqual(t, firstPage[0].Name, secondPage[0].Name)
+			})
+
+			t.Run("revisions_record_changes_and_rollback", func(t *testing.T) {
+				require.NoError(t, repo.Upsert(ctx, newEvent("revision.event", "svc-r", "active")))
+				event, err := repo.GetInternalEvent(ctx, "revision.event")
+				require.NoError(t, err)
+
+				// an upsert without changes is not a revision
+				require.NoError(t, repo.Upsert(ctx, newEvent("revision.event", "svc-r", "active")))
+
+				event.Option.MaxRetries = 10
+				require.NoError(t, repo.UpdateEvent(ctx, event))
+				require.NoError(t, repo.DisabledEvent(ctx, event.ID))
+
+				revisions, err := repo.ListRevisions(ctx, event.ID)
+				require.NoError(t, err)
+				require.Len(t, revisions, 3)
+				assert.Equal(t, domain.RevisionDeleted, revisions[0].Action)
+				assert.Equal(t, domain.RevisionUpdated, revisions[1].Action)
+				assert.Equal(t, []domain.Change{{Path: "option.max_retries", From: float64(3), To: float64(10)}}, revisions[1].Changes)
+				assert.Equal(t, domain.RevisionCreated, revisions[2].Action)
+				assert.Equal(t, "anonymous", revisions[2].Author)
+
+				restored, err := repo.RollbackEvent(ctx, event.ID, 1)
+				require.NoError(t, err)
+				assert.Equal(t, 3, restored.Option.MaxRetries)
+
+				got, err := repo.GetInternalEvent(ctx, "revision.event")
+				require.NoError(t, err)
+				assert.Equal(t, 3, got.Option.MaxRetries)
+
+				latest, err := repo.GetRevision(ctx, event.ID, 4)
+				require.NoError(t, err)
+				assert.Equal(t, domain.RevisionRolledBack, latest.Action)
+				assert.Equal(t, 1, latest.RollbackOf)
+
+				_, err = repo.GetRevision(ctx, event.ID, 99)
+				assert.True(t, errors.Is(err, domain.RevisionNotFound))
+			})
+
+			t.Run("update_if_revision", func(t *testing.T) {
+				require.NoError(t, repo.Upsert(ctx, newEvent("versioned.event", "svc-v", "active")))
+				event, err := repo.GetInternalEvent(ctx, "versioned.event")
+				require.NoError(t, err)
+				require.Equal(t, 1, event.Revision)
+
+				event.Option.MaxRetries = 4
+				updated, err := repo.UpdateEventIfRevision(ctx, event, event.Revision)
+				require.NoError(t, err)
+				assert.Equal(t, 2, updated.Revision)
+
+				// the update was based on a revision that is no longer the latest
+				event.Option.MaxRetries = 5
+				_, err = repo.UpdateEventIfRevision(ctx, event, event.Revision)
+				assert.True(t, errors.Is(err, domain.EventVersionConflict))
+
+				got, err := repo.GetEventByID(ctx, event.ID)
+				require.NoError(t, err)
+				assert.Equal(t, 4, got.Option.MaxRetries)
+				assert.Equal(t, 2, got.Revision)
+			})
+
+			t.Run("ownership_is_persisted_and_filterable", func(t *testing.T) {
+				event := newEvent("owned.event", "svc-o", "active")
+				event.Owner = domain.Owner{TeamOwner: "payments", RepoURL: "github.com/example/payments", Contact: "#payments-oncall", Description: "payments done"}
+				event.Consumers[0].TeamOwner = "finance"
+				require.NoError(t, repo.Upsert(ctx, event))
+
+				got, err := repo.GetInternalEvent(ctx, "owned.event")
+				require.NoError(t, err)
+				assert.Equal(t, event.Owner, got.Owner)
+				assert.Equal(t, "finance", got.Consumers[0].TeamOwner)
+
+				for _, filters := range []domain.FilterEvents{
+					{TeamOwner: []string{"payments"}},
+					{RepoURL: []string{"github.com/example/payments"}},
+					{ConsumerTeam: []string{"finance"}},
+				} {
+					events, err := repo.GetInternalEvents(ctx, filters)
+					require.NoError(t, err)
+					require.Len(t, events, 1, filters)
+					assert.Equal(t, "owned.event", events[0].Name)
+				}
+
+				got.Contact = "#payments-oncall-v2"
+				require.NoError(t, repo.UpdateEvent(ctx, got))
+				got, err = repo.GetEventByID(ctx, got.ID)
+				require.NoError(t, err)
+				assert.Equal(t, "#payments-oncall-v2", got.Contact)
+			})
+
+			t.Run("payload_schema_is_persisted", func(t *testing.T) {
+				event := newEvent("schema.event", "svc-s", "active")
+				event.Schema = json.RawMessage(`{"type":"object","properties":{"$ref":{"type":"string"}}}`)
+				require.NoError(t, repo.Upsert(ctx, event))
+
+				got, err := repo.GetInternalEvent(ctx, "schema.event")
+				require.NoError(t, err)
+				assert.JSONEq(t, string(event.Schema), string(got.Schema))
+
+				got.Schema = nil
+				require.NoError(t, repo.UpdateEvent(ctx, got))
+				got, err = repo.GetEventByID(ctx, got.ID)
+				require.NoError(t, err)
+				assert.Empty(t, got.Schema)
+			})
+
+			t.Run("projects_isolate_events", func(t *testing.T) {
+				project := domain.Project{ID: "acme", Name: "Acme", TopicPrefix: "acme", Quotas: domain.ProjectQuotas{MaxEvents: 5}}
+				project.SetSecret("s3cret")
+
+				created, err := repo.CreateProject(ctx, project)
+				require.NoError(t, err)
+				assert.Equal(t, project.Quotas, created.Quotas)
+
+				_, err = repo.CreateProject(ctx, domain.Project{ID: "acme-2", TopicPrefix: "acme"})
+				assert.ErrorIs(t, err, domain.ProjectAlreadyExists, "topic prefixes are unique")
+
+				acme := domain.WithProject(ctx, created)
+				require.NoError(t, repo.Upsert(acme, newEvent("upsert.event", "svc-acme", "active")))
+
+				got, err := repo.GetInternalEvent(acme, "upsert.event")
+				require.NoError(t, err)
+				assert.Equal(t, "acme", got.ProjectID)
+				assert.Equal(t, "svc-acme", got.ServiceName)
+
+				_, err = repo.GetEventByID(ctx, got.ID)
+				assert.ErrorIs(t, err, domain.EventNotFound, "events of other projects are not found")
+
+				fromDefault, err := repo.GetInternalEvent(ctx, "upsert.event")
+				require.NoError(t, err)
+				assert.NotEqual(t, got.ID, fromDefault.ID, "the same name is a different event in every project")
+
+				assert.ErrorIs(t, repo.DeleteProject(ctx, "acme"), domain.ProjectNotEmpty)
+
+				created.Name = "Acme Corp"
+				updated, err := repo.UpdateProject(ctx, created)
+				require.NoError(t, err)
+				assert.Equal(t, "Acme Corp", updated.Name)
+				assert.True(t, updated.CheckSecret("s3cret"))
+
+				require.NoError(t, repo.DisabledEvent(acme, got.ID))
+				require.NoError(t, repo.DeleteProject(ctx, "acme"))
+
+				_, err = repo.GetProject(ctx, "acme")
+				assert.ErrorIs(t, err, domain.ProjectNotFound)
+			})
+
+			t.Run("api_keys", func(t *testing.T) {
+				expiresAt := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+				key, _, err := domain.NewAPIKey(domain.DefaultProjectID, "billing", []domain.Scope{domain.PublishScope("upsert.event")}, &expiresAt)
+				require.NoError(t, err)
+
+				created, err := repo.CreateAPIKey(ctx, key)
+				require.NoError(t, err)
+				assert.Equal(t, key.SecretHash, created.SecretHash)
+
+				got, err := repo.GetAPIKey(ctx, key.ID)
+				require.NoError(t, err)
+				assert.Equal(t, key.Scopes, got.Scopes)
+				assert.WithinDuration(t, expiresAt, *got.ExpiresAt, time.Second)
+				assert.Nil(t, got.LastUsedAt)
+
+				usedAt := time.Now()
+				require.NoError(t, repo.TouchAPIKey(ctx, key.ID, usedAt))
+
+				keys, err := repo.ListAPIKeys(ctx)
+				require.NoError(t, err)
+				require.Len(t, keys, 1)
+				require.NotNil(t, keys[0].LastUsedAt)
+				assert.WithinDuration(t, usedAt, *keys[0].LastUsedAt, time.Second)
+
+				other := domain.WithProject(ctx, domain.Project{ID: "other"})
+				_, err = repo.RevokeAPIKey(other, key.ID)
+				assert.ErrorIs(t, err, domain.APIKeyNotFound, "keys of other projects cannot be revoked")
+
+				revoked, err := repo.RevokeAPIKey(ctx, key.ID)
+				require.NoError(t, err)
+				assert.False(t, revoked.Active(time.Now()))
+
+				_, err = repo.GetAPIKey(ctx, uuid.New())
+				assert.ErrorIs(t, err, domain.APIKeyNotFound)
+			})
+
+			t.Run("alert_rules", func(t *testing.T) {
+				rule := domain.AlertRule{
+					ID:        uuid.New(),
+					ProjectID: domain.DefaultProjectID,
+					Name:      "payments failing",
+					Kind:      domain.AlertFailureRate,
+					Event:     "payment.created",
+					Threshold: 0.05,
+					Window:    intertime.Duration(5 * time.Minute),
+					Enabled:   true,
+				}
+
+				created, err := repo.CreateAlertRule(ctx, rule)
+				require.NoError(t, err)
+				assert.Equal(t, rule.Window, created.Window)
+				assert.False(t, created.CreatedAt.IsZero())
+
+				got, err := repo.GetAlertRule(ctx, rule.ID)
+				require.NoError(t, err)
+				assert.Equal(t, "payment.created", got.Event)
+				assert.Equal(t, 0.05, got.Threshold)
+
+				got.Threshold = 0.1
+				got.Enabled = false
+				updated, err := repo.UpdateAlertRule(ctx, got)
+				require.NoError(t, err)
+				assert.Equal(t, 0.1, updated.Threshold)
+				assert.False(t, updated.Enabled)
+
+				other := domain.WithProject(ctx, domain.Project{ID: "other"})
+				rules, err := repo.ListAlertRules(other)
+				require.NoError(t, err)
+				assert.Empty(t, rules, "rules of other projects are not listed")
+				assert.ErrorIs(t, repo.DeleteAlertRule(other, rule.ID), domain.AlertRuleNotFound)
+
+				rules, err = repo.ListAlertRules(ctx)
+				require.NoError(t, err)
+				require.Len(t, rules, 1)
+
+				require.NoError(t, repo.DeleteAlertRule(ctx, rule.ID))
+				_, err = repo.GetAlertRule(ctx, rule.ID)
+				assert.ErrorIs(t, err, domain.AlertRuleNotFound)
+			})
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package interstore
+
+import (
+	"context"
+
+	"github.com/IsaacDSC/gqueue/pkg/auth"
+)
+
+// anonymousAuthor is recorded when the change was made without an authenticated user
+const anonymousAuthor = "anonymous"
+
+func revisionAuthor(ctx context.Context) string {
+	if user, ok := auth.UserFromContext(ctx); ok && user != "" {
+		return user
+	}
+
+	return anonymousAuthor
+}
@@ -2,6 +2,8 @@ package interstore
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/google/uuid"
@@ -16,4 +18,67 @@ type Repository interface {
 	GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error)
 	GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error)
 	GetAllEvents(ctx context.Context) ([]domain.Event, error)
+	ListRevisions(ctx context.Context, eventID uuid.UUID) ([]domain.EventRevision, error)
+	GetRevision(ctx context.Context, eventID uuid.UUID, revision int) (domain.EventRevision, error)
+	RollbackEvent(ctx context.Context, eventID uuid.UUID, revision int) (domain.Event, error)
+	UpdateEventIfRevision(ctx context.Context, event domain.Event, revision int) (domain.Event, error)
+	ProjectRepository
+	APIKeyRepository
+	AlertRuleRepository
+}
+
+// ProjectRepository stores the projects events are registered in
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project domain.Project) (domain.Project, error)
+	GetProject(ctx context.Context, projectID string) (domain.Project, error)
+	ListProjects(ctx context.Context) ([]domain.Project, error)
+	UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error)
+	DeleteProject(ctx context.Context, projectID string) error
+}
+
+// APIKeyRepository stores the API keys clients of a project authenticate with. Keys are read by
+// id from every project, the key tells the project of the request; the other methods are scoped
+// to the project of the context.
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error)
+	GetAPIKey(ctx context.Context, keyID uuid.UUID) (domain.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID uuid.UUID) (domain.APIKey, error)
+	TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error
+}
+
+// AlertRuleRepository stores the alert rules of a project, every method is scoped to the project
+// of the context
+type AlertRuleRepository interface {
+	CreateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error)
+	GetAlertRule(ctx context.Context, ruleID uuid.UUID) (domain.AlertRule, error)
+	ListAlertRules(ctx context.Context) ([]domain.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, ruleID uuid.UUID) error
+}
+
+const (
+	DriverPostgres = "pg"
+	DriverMongo    = "mongo"
+)
+
+// NewRepository opens the persistent store selected by DB_DRIVER, postgres being the default
+func NewRepository(ctx context.Context, driver, conn string) (Repository, error) {
+	switch driver {
+	case DriverPostgres, "":
+		return NewPostgresStoreFromDSN(conn)
+	case DriverMongo:
+		return NewMongoStoreFromURI(ctx, conn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
+
+// pageOffset converts the 1-based page of the filters into a row offset
+func pageOffset(filters domain.FilterEvents) uint {
+	if filters.Page <= 1 {
+		return 0
+	}
+
+	return (filters.Page - 1) * filters.Limit
 }
@@ -0,0 +1,268 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+var ErrSchemaMismatch = errors.New("schema version mismatch")
+
+// lockID serializes migrations run by concurrent instances
+const lockID = 7264823519
+
+// Status describes whether a migration was applied to the database
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// Open connects to the database in the DSN and loads the embedded migrations
+func Open(dsn string) (*Migrator, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	m, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Latest is the schema version expected by this binary
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Version returns the schema version of the database, 0 when no migration was applied. It only
+// reads the database, schema_migrations is not created when it is missing.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	migrated, err := m.tableExists(ctx)
+	if err != nil || !migrated {
+		return 0, err
+	}
+
+	return currentVersion(ctx, m.db)
+}
+
+// Check fails with ErrSchemaMismatch when the database is not at the version expected by the binary.
+// It only reads the database, so it can run with the credentials of the APIs.
+func (m *Migrator) Check(ctx context.Context) error {
+	migrated, err := m.tableExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !migrated {
+		return fmt.Errorf("%w: database is not migrated, binary expects %d", ErrSchemaMismatch, m.Latest())
+	}
+
+	version, err := currentVersion(ctx, m.db)
+	if err != nil {
+		return err
+	}
+
+	if version != m.Latest() {
+		return fmt.Errorf("%w: database is at version %d, binary expects %d", ErrSchemaMismatch, version, m.Latest())
+	}
+
+	return nil
+}
+
+// Up applies every pending migration, each one in its own transaction
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range m.migrations {
+		ok, err := m.apply(ctx, func(tx *sql.Tx, version int) (bool, error) {
+			if version >= migration.Version {
+				return false, nil
+			}
+
+			if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+				return false, err
+			}
+
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+			return true, err
+		})
+
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		if ok {
+			applied = append(applied, migration)
+		}
+	}
+
+	return applied, nil
+}
+
+// Down rolls back the last applied migration, returning false when there is nothing to roll back
+func (m *Migrator) Down(ctx context.Context) (Migration, bool, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return Migration{}, false, err
+	}
+
+	var reverted Migration
+	ok, err := m.apply(ctx, func(tx *sql.Tx, version int) (bool, error) {
+		if version == 0 {
+			return false, nil
+		}
+
+		if version > len(m.migrations) {
+			return false, fmt.Errorf("database version %d is newer than the binary (%d)", version, len(m.migrations))
+		}
+
+		reverted = m.migrations[version-1]
+		if _, err := tx.ExecContext(ctx, reverted.Down); err != nil {
+			return false, err
+		}
+
+		_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, version)
+		return true, err
+	})
+
+	if err != nil {
+		return Migration{}, false, fmt.Errorf("failed to roll back migration: %w", err)
+	}
+
+	return reverted, ok, nil
+}
+
+// Status lists every embedded migration and when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema migrations: %w", err)
+	}
+
+	defer rows.Close()
+
+	appliedAt := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan schema migration: %w", err)
+		}
+		appliedAt[version] = at
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over schema migrations: %w", err)
+	}
+
+	output := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if at, ok := appliedAt[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		output = append(output, status)
+	}
+
+	return output, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`
+
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return nil
+}
+
+// tableExists tells if schema_migrations exists, a database without it was never migrated
+func (m *Migrator) tableExists(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to look for schema_migrations: %w", err)
+	}
+
+	return exists, nil
+}
+
+// apply runs fn in a transaction holding the migration lock, passing the version read under that lock
+func (m *Migrator) apply(ctx context.Context, fn func(tx *sql.Tx, version int) (bool, error)) (bool, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, lockID); err != nil {
+		return false, err
+	}
+
+	version, err := currentVersion(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := fn(tx, version)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func currentVersion(ctx context.Context, q interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}) (int, error) {
+	var version int
+	if err := q.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// freshDB returns a connection to an empty schema of the database in DB_CONNECTION_STRING,
+// skipping the test when it is not set
+func freshDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("DB_CONNECTION_STRING")
+	if dsn == "" {
+		t.Skip("DB_CONNECTION_STRING is not set")
+	}
+
+	ctx := context.Background()
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	require.NoError(t, db.PingContext(ctx))
+
+	// a single connection keeps the search path on every query
+	db.SetMaxOpenConns(1)
+	schema := fmt.Sprintf("migrator_test_%d", time.Now().UnixNano())
+	_, err = db.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "SET search_path TO "+schema)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		db.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		db.Close()
+	})
+
+	return db
+}
+
+func TestMigrator_Check(t *testing.T) {
+	ctx := context.Background()
+	db := freshDB(t)
+
+	m, err := New(db)
+	require.NoError(t, err)
+
+	err = m.Check(ctx)
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+	assert.ErrorContains(t, err, "not migrated")
+
+	version, err := m.Version(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, version)
+
+	migrated, err := m.tableExists(ctx)
+	require.NoError(t, err)
+	assert.False(t, migrated, "Check must not create schema_migrations")
+
+	require.NoError(t, m.ensureTable(ctx))
+	_, err = db.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, 'latest')", m.Latest())
+	require.NoError(t, err)
+
+	assert.NoError(t, m.Check(ctx))
+}
@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version)
+		assert.NotEmpty(t, m.Up)
+		assert.NotEmpty(t, m.Down)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+
+	tests := []struct {
+		name     string
+		fs       fstest.MapFS
+		expected []Migration
+		wantErr  bool
+	}{
+		{
+			name: "orders_migrations_by_version",
+			fs: fstest.MapFS{
+				"sql/0002_second.up.sql":   file("UP 2"),
+				"sql/0002_second.down.sql": file("DOWN 2"),
+				"sql/0001_first.up.sql":    file("UP 1"),
+				"sql/0001_first.down.sql":  file("DOWN 1"),
+			},
+			expected: []Migration{
+				{Version: 1, Name: "first", Up: "UP 1", Down: "DOWN 1"},
+				{Version: 2, Name: "second", Up: "UP 2", Down: "DOWN 2"},
+			},
+		},
+		{
+			name: "missing_down_script",
+			fs: fstest.MapFS{
+				"sql/0001_first.up.sql": file("UP 1"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "gap_in_versions",
+			fs: fstest.MapFS{
+				"sql/0001_first.up.sql":   file("UP 1"),
+				"sql/0001_first.down.sql": file("DOWN 1"),
+				"sql/0003_third.up.sql":   file("UP 3"),
+				"sql/0003_third.down.sql": file("DOWN 3"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_file_name",
+			fs: fstest.MapFS{
+				"sql/first.sql": file("UP 1"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "conflicting_names",
+			fs: fstest.MapFS{
+				"sql/0001_first.up.sql":   file("UP 1"),
+				"sql/0001_other.down.sql": file("DOWN 1"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := load(tt.fs, "sql")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -4,7 +4,7 @@ CREATE TABLE IF NOT EXISTS events (
     name VARCHAR(255) NOT NULL UNIQUE,
     service_name VARCHAR(255) NOT NULL,
     state VARCHAR(100) NOT NULL,
-    consumers JSONB NOT NULL,         
+    consumers JSONB NOT NULL,
     opts JSONB NOT NULL,
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
@@ -14,4 +14,4 @@ CREATE TABLE IF NOT EXISTS events (
 -- Indexes for performance
 CREATE INDEX IF NOT EXISTS idx_events_name ON events(name) WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_events_service_name ON events(service_name) WHERE deleted_at IS NULL;
-CREATE INDEX IF NOT EXISTS idx_events_state ON events(state) WHERE deleted_at IS NULL;
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_events_state ON events(state) WHERE deleted_at IS NULL;
@@ -0,0 +1,18 @@
+-- Durable SQL work queue (wq_type = durable_sql)
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY,
+    queue VARCHAR(255) NOT NULL DEFAULT 'default',
+    topic VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_retries INT NOT NULL DEFAULT 3,
+    run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP NULL,
+    last_error TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_claim ON jobs(topic, run_at) WHERE state IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS idx_jobs_state ON jobs(state);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_events_team_owner;
+
+ALTER TABLE events DROP COLUMN IF EXISTS team_owner;
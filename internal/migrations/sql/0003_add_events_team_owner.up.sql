@@ -0,0 +1,3 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS team_owner VARCHAR(255) NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_team_owner ON events(team_owner) WHERE deleted_at IS NULL;
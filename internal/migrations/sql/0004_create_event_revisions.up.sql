@@ -0,0 +1,14 @@
+-- Every change to an event is kept as a revision, events.revision is the latest one
+ALTER TABLE events ADD COLUMN IF NOT EXISTS revision INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS event_revisions (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    rollback_of INT NULL,
+    snapshot JSONB NOT NULL,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, revision)
+);
@@ -0,0 +1,2 @@
+-- A paused event holds the deliveries to all of its consumers
+ALTER TABLE events ADD COLUMN IF NOT EXISTS paused BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,6 @@
+ALTER TABLE events DROP COLUMN IF EXISTS description;
+ALTER TABLE events DROP COLUMN IF EXISTS contact;
+ALTER TABLE events DROP COLUMN IF EXISTS repo_url;
+
+ALTER TABLE events ALTER COLUMN team_owner DROP NOT NULL;
+ALTER TABLE events ALTER COLUMN team_owner DROP DEFAULT;
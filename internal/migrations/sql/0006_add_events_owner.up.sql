@@ -0,0 +1,8 @@
+-- Ownership metadata of the event, the owners of the consumers are kept in the consumers column
+UPDATE events SET team_owner = '' WHERE team_owner IS NULL;
+ALTER TABLE events ALTER COLUMN team_owner SET DEFAULT '';
+ALTER TABLE events ALTER COLUMN team_owner SET NOT NULL;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS repo_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS contact TEXT NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_events_project_name;
+ALTER TABLE events ADD CONSTRAINT events_name_key UNIQUE (name);
+CREATE INDEX IF NOT EXISTS idx_events_name ON events(name) WHERE deleted_at IS NULL;
+
+ALTER TABLE events DROP COLUMN IF EXISTS project_id;
+
+DROP TABLE IF EXISTS projects;
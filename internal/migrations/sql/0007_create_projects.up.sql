@@ -0,0 +1,21 @@
+-- Projects isolate their events, topics, credentials and quotas
+CREATE TABLE IF NOT EXISTS projects (
+    id VARCHAR(63) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    topic_prefix VARCHAR(255) NOT NULL UNIQUE,
+    secret_hash VARCHAR(64) NOT NULL DEFAULT '',
+    quotas JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- the default project owns the events registered before projects existed and keeps their topics
+INSERT INTO projects (id, name, topic_prefix) VALUES ('default', 'Default', 'your-project-id')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS project_id VARCHAR(63) NOT NULL DEFAULT 'default' REFERENCES projects(id);
+
+-- event names are unique inside a project
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_name_key;
+DROP INDEX IF EXISTS idx_events_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_project_name ON events(project_id, name);
@@ -0,0 +1,2 @@
+-- JSON Schema of the payload of the event, published in the event catalog
+ALTER TABLE events ADD COLUMN IF NOT EXISTS payload_schema JSONB;
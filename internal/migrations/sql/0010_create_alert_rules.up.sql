@@ -0,0 +1,17 @@
+-- Alert rules are evaluated by the backoffice over the insights and dead letters of their project
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id UUID PRIMARY KEY,
+    project_id VARCHAR(63) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    event_name VARCHAR(255) NOT NULL DEFAULT '',
+    consumer VARCHAR(255) NOT NULL DEFAULT '',
+    threshold DOUBLE PRECISION NOT NULL DEFAULT 0,
+    window_seconds BIGINT NOT NULL,
+    min_samples BIGINT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_project ON alert_rules(project_id);
@@ -0,0 +1,40 @@
+package projects
+
+import (
+	"math"
+	"sync"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"golang.org/x/time/rate"
+)
+
+// Limiter enforces the publish rate quota of every project on this instance
+type Limiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{limiters: make(map[string]*rate.Limiter)}
+}
+
+// Allow reports whether the project can publish one more event now. A quota changed since the
+// last call applies right away, a project without a publish rate is never limited.
+func (l *Limiter) Allow(project domain.Project) bool {
+	limit := project.Quotas.PublishRate
+	if limit <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[project.ID]
+	if !ok || limiter.Limit() != rate.Limit(limit) {
+		// the burst lets a project publish a second worth of events at once
+		limiter = rate.NewLimiter(rate.Limit(limit), int(math.Max(1, math.Ceil(limit))))
+		l.limiters[project.ID] = limiter
+	}
+
+	return limiter.Allow()
+}
@@ -0,0 +1,120 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/auth"
+)
+
+// HeaderProjectID names the project of a request sent without credentials
+const HeaderProjectID = "X-Project-ID"
+
+const defaultCacheTTL = 30 * time.Second
+
+type Store interface {
+	GetProject(ctx context.Context, projectID string) (domain.Project, error)
+	ListProjects(ctx context.Context) ([]domain.Project, error)
+}
+
+type cachedProject struct {
+	project   domain.Project
+	expiresAt time.Time
+}
+
+// Resolver finds the project of a request. Projects are cached for a short time so a request does
+// not query the persistent store, changes to a project apply once the entry expires.
+type Resolver struct {
+	store              Store
+	ttl                time.Duration
+	requireCredentials bool
+
+	mu    sync.RWMutex
+	cache map[string]cachedProject
+}
+
+// NewResolver creates a resolver. When requireCredentials is set, requests must authenticate with
+// an API key or the secret of their project.
+func NewResolver(store Store, ttl time.Duration, requireCredentials bool) *Resolver {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	return &Resolver{
+		store:              store,
+		ttl:                ttl,
+		requireCredentials: requireCredentials,
+		cache:              make(map[string]cachedProject),
+	}
+}
+
+// Project returns the project from the cache or the persistent store
+func (r *Resolver) Project(ctx context.Context, projectID string) (domain.Project, error) {
+	r.mu.RLock()
+	cached, ok := r.cache[projectID]
+	r.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.project, nil
+	}
+
+	project, err := r.store.GetProject(ctx, projectID)
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	r.mu.Lock()
+	r.cache[projectID] = cachedProject{project: project, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return project, nil
+}
+
+// Resolve returns the project of the request. A request authenticated with an API key or a token
+// belongs to the project of the key or of the token identity. Otherwise Basic auth carries the
+// project id as username and its secret as password; without credentials the project comes from
+// the X-Project-ID header and requests naming none belong to the default project. A project with
+// a secret only accepts requests authenticated with it.
+func (r *Resolver) Resolve(req *http.Request) (domain.Project, error) {
+	if key, ok := domain.APIKeyFromContext(req.Context()); ok {
+		return r.Project(req.Context(), key.ProjectID)
+	}
+
+	if identity, ok := auth.IdentityFromContext(req.Context()); ok {
+		projectID := identity.Project
+		if projectID == "" {
+			projectID = domain.DefaultProjectID
+		}
+
+		return r.Project(req.Context(), projectID)
+	}
+
+	projectID, secret, authenticated := req.BasicAuth()
+	if !authenticated {
+		projectID = req.Header.Get(HeaderProjectID)
+	}
+
+	if projectID == "" {
+		projectID = domain.DefaultProjectID
+	}
+
+	project, err := r.Project(req.Context(), projectID)
+	if errors.Is(err, domain.ProjectNotFound) && projectID == domain.DefaultProjectID {
+		// stores created before projects existed may not have the default project yet
+		project, err = domain.DefaultProject(), nil
+	}
+
+	if err != nil {
+		return domain.Project{}, err
+	}
+
+	if (project.HasCredentials() || r.requireCredentials) && (!authenticated || !project.CheckSecret(secret)) {
+		return domain.Project{}, domain.InvalidProjectCredentials
+	}
+
+	return project, nil
+}
@@ -0,0 +1,193 @@
+package projects_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/projects"
+	"github.com/IsaacDSC/gqueue/mocks/mockprojects"
+	"github.com/IsaacDSC/gqueue/pkg/auth"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme"}
+	acme.SetSecret("s3cret")
+	open := domain.Project{ID: "open", TopicPrefix: "open"}
+
+	tests := []struct {
+		name      string
+		header    string
+		username  string
+		password  string
+		apiKey    *domain.APIKey
+		identity  *auth.Identity
+		required  bool
+		wantID    string
+		wantError error
+	}{
+		{name: "default project without header", wantID: domain.DefaultProjectID},
+		{name: "header of a project without credentials", header: "open", wantID: "open"},
+		{name: "basic auth", username: "acme", password: "s3cret", wantID: "acme"},
+		{name: "wrong secret", username: "acme", password: "wrong", wantError: domain.InvalidProjectCredentials},
+		{name: "header of a project with credentials", header: "acme", wantError: domain.InvalidProjectCredentials},
+		{name: "unknown project", header: "missing", wantError: domain.ProjectNotFound},
+		{name: "api key", header: "acme", apiKey: &domain.APIKey{ProjectID: "open"}, required: true, wantID: "open"},
+		{name: "token identity", identity: &auth.Identity{Publisher: "billing", Project: "open"}, required: true, wantID: "open"},
+		{name: "token identity without project", identity: &auth.Identity{Publisher: "billing"}, required: true, wantID: domain.DefaultProjectID},
+		{name: "required credentials without them", header: "open", required: true, wantError: domain.InvalidProjectCredentials},
+		{name: "required credentials of the default project", required: true, wantError: domain.InvalidProjectCredentials},
+		{name: "required credentials with the project secret", username: "acme", password: "s3cret", required: true, wantID: "acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockprojects.NewMockStore(ctrl)
+			store.EXPECT().GetProject(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string) (domain.Project, error) {
+				switch id {
+				case "acme":
+					return acme, nil
+				case "open":
+					return open, nil
+				case domain.DefaultProjectID:
+					return domain.DefaultProject(), nil
+				}
+				return domain.Project{}, domain.ProjectNotFound
+			})
+
+			req := httptest.NewRequest("POST", "/api/v1/pubsub", nil)
+			if tt.header != "" {
+				req.Header.Set(projects.HeaderProjectID, tt.header)
+			}
+			if tt.username != "" {
+				req.SetBasicAuth(tt.username, tt.password)
+			}
+			if tt.apiKey != nil {
+				req = req.WithContext(domain.WithAPIKey(req.Context(), *tt.apiKey))
+			}
+			if tt.identity != nil {
+				req = req.WithContext(auth.WithIdentity(req.Context(), *tt.identity))
+			}
+
+			project, err := projects.NewResolver(store, time.Minute, tt.required).Resolve(req)
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, project.ID)
+		})
+	}
+}
+
+func TestResolver_Cache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockprojects.NewMockStore(ctrl)
+	store.EXPECT().GetProject(gomock.Any(), "acme").Return(domain.Project{ID: "acme"}, nil).Times(1)
+	store.EXPECT().GetProject(gomock.Any(), "broken").Return(domain.Project{}, errors.New("connection refused")).Times(2)
+
+	resolver := projects.NewResolver(store, time.Minute, false)
+	for range 3 {
+		_, err := resolver.Project(context.Background(), "acme")
+		require.NoError(t, err)
+	}
+
+	// errors are not cached
+	for range 2 {
+		_, err := resolver.Project(context.Background(), "broken")
+		assert.Error(t, err)
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	limiter := projects.NewLimiter()
+
+	unlimited := domain.Project{ID: "open"}
+	for range 100 {
+		assert.True(t, limiter.Allow(unlimited))
+	}
+
+	limited := domain.Project{ID: "acme", Quotas: domain.ProjectQuotas{PublishRate: 2}}
+	assert.True(t, limiter.Allow(limited))
+	assert.True(t, limiter.Allow(limited))
+	assert.False(t, limiter.Allow(limited), "the burst is one second worth of events")
+	assert.True(t, limiter.Allow(unlimited), "projects are limited independently")
+
+	limited.Quotas.PublishRate = 10
+	assert.True(t, limiter.Allow(limited), "a new rate applies right away")
+}
+
+func TestWatcher_Sync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockprojects.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject()}, nil),
+		store.EXPECT().ListProjects(gomock.Any()).Return(nil, errors.New("connection refused")),
+		store.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject(), {ID: "acme"}}, nil),
+	)
+
+	var started []string
+	watcher := projects.NewWatcher(store, time.Minute, func(_ context.Context, project domain.Project) {
+		started = append(started, project.ID)
+	})
+
+	require.NoError(t, watcher.Sync(context.Background()))
+	assert.Error(t, watcher.Sync(context.Background()))
+	require.NoError(t, watcher.Sync(context.Background()))
+
+	assert.Equal(t, []string{domain.DefaultProjectID, "acme"}, started, "every project is started once")
+}
+
+func TestWatcher_SyncReusedPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme"}
+	renamed := domain.Project{ID: "acme-2", TopicPrefix: "acme"}
+
+	store := mockprojects.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject(), acme}, nil),
+		store.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject()}, nil),
+		store.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject(), renamed}, nil),
+	)
+
+	// asynq panics when a pattern is registered twice, like the task worker would
+	mux := asynq.NewServeMux()
+	var started []string
+	watcher := projects.NewWatcher(store, time.Minute, func(_ context.Context, project domain.Project) {
+		started = append(started, project.ID)
+		mux.HandleFunc(project.TopicName("queue"), func(context.Context, *asynq.Task) error { return nil })
+	})
+
+	require.NoError(t, watcher.Sync(context.Background()))
+	project, ok := watcher.Project("acme")
+	require.True(t, ok)
+	assert.Equal(t, "acme", project.ID)
+
+	require.NoError(t, watcher.Sync(context.Background()))
+	_, ok = watcher.Project("acme")
+	assert.False(t, ok, "the prefix of a deleted project has no project")
+
+	require.NotPanics(t, func() { require.NoError(t, watcher.Sync(context.Background())) })
+	project, ok = watcher.Project("acme")
+	require.True(t, ok)
+	assert.Equal(t, "acme-2", project.ID, "the prefix belongs to the new project")
+
+	assert.Equal(t, []string{domain.DefaultProjectID, "acme"}, started, "every prefix is started once")
+}
@@ -0,0 +1,95 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+)
+
+// Watcher calls a function once for every topic prefix, the workers use it to consume the topics
+// of the projects created while they run. A deleted project frees its prefix for a new project, so
+// the handlers started for a prefix look up the project owning it with Project on every delivery.
+type Watcher struct {
+	store    Store
+	interval time.Duration
+	fn       func(ctx context.Context, project domain.Project)
+
+	mu       sync.Mutex
+	seen     map[string]bool
+	prefixes map[string]domain.Project
+}
+
+func NewWatcher(store Store, interval time.Duration, fn func(ctx context.Context, project domain.Project)) *Watcher {
+	return &Watcher{
+		store:    store,
+		interval: interval,
+		fn:       fn,
+		seen:     make(map[string]bool),
+		prefixes: make(map[string]domain.Project),
+	}
+}
+
+// Project returns the project owning the topic prefix since the last sync, false once it was deleted
+func (w *Watcher) Project(topicPrefix string) (domain.Project, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	project, ok := w.prefixes[topicPrefix]
+	return project, ok
+}
+
+// Sync refreshes the projects owning the prefixes and calls the function for the prefixes it was
+// not called for yet
+func (w *Watcher) Sync(ctx context.Context) error {
+	projects, err := w.store.ListProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	if len(projects) == 0 {
+		// stores created before projects existed may not have the default project yet
+		projects = []domain.Project{domain.DefaultProject()}
+	}
+
+	w.mu.Lock()
+	w.prefixes = make(map[string]domain.Project, len(projects))
+	var started []domain.Project
+	for _, project := range projects {
+		w.prefixes[project.TopicPrefix] = project
+		if w.seen[project.TopicPrefix] {
+			continue
+		}
+
+		w.seen[project.TopicPrefix] = true
+		started = append(started, project)
+	}
+	w.mu.Unlock()
+
+	// the function registers handlers calling Project, it runs without the lock
+	for _, project := range started {
+		w.fn(ctx, project)
+	}
+
+	return nil
+}
+
+// Run syncs every interval until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Sync(ctx); err != nil {
+				ctxlogger.GetLogger(ctx).Error("Error watching projects", "error", err)
+			}
+		}
+	}
+}
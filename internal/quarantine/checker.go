@@ -0,0 +1,73 @@
+package quarantine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+)
+
+// checkerTTL is how long a worker keeps the quarantines of a project before reading them again, a
+// quarantine or a release applies within it
+const checkerTTL = 5 * time.Second
+
+type cached struct {
+	active   map[string]bool
+	loadedAt time.Time
+}
+
+// Checker tells the workers which consumers are quarantined. It reads the quarantines of a project
+// at most once per checkerTTL; when Redis is unreachable the last ones read are kept, and none
+// before the first read, so deliveries are attempted rather than parked.
+type Checker struct {
+	store *Store
+
+	mu       sync.Mutex
+	projects map[string]cached
+}
+
+func NewChecker(store *Store) *Checker {
+	return &Checker{store: store, projects: make(map[string]cached)}
+}
+
+func (c *Checker) Quarantined(ctx context.Context, eventName, serviceName string) bool {
+	projectID := domain.ProjectFromContext(ctx).ID
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.projects[projectID]
+	if !ok || time.Since(entry.loadedAt) > checkerTTL {
+		entry = c.load(ctx, entry)
+		c.projects[projectID] = entry
+	}
+
+	return entry.active[field(eventName, serviceName)]
+}
+
+func (c *Checker) load(ctx context.Context, previous cached) cached {
+	quarantines, err := c.store.List(ctx)
+	if err != nil {
+		ctxlogger.GetLogger(ctx).Warn("failed to read quarantines, keeping the last ones", "error", err)
+		previous.loadedAt = time.Now()
+		return previous
+	}
+
+	active := make(map[string]bool, len(quarantines))
+	for _, q := range quarantines {
+		if q.Active() {
+			active[field(q.Event, q.Consumer)] = true
+		}
+	}
+
+	return cached{active: active, loadedAt: time.Now()}
+}
+
+type never struct{}
+
+func (never) Quarantined(context.Context, string, string) bool { return false }
+
+// Never quarantines nothing, for processes that do not check quarantines
+var Never = never{}
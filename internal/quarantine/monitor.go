@@ -0,0 +1,356 @@
+package quarantine
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/notifyopt"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ProbeHeader marks the probe requests, consumers should answer them without processing the body
+const ProbeHeader = "X-Gqueue-Probe"
+
+type EventStore interface {
+	ListProjects(ctx context.Context) ([]domain.Project, error)
+	GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error)
+	GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error)
+}
+
+type InsightsStore interface {
+	GetRange(ctx context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error)
+}
+
+type Fetcher interface {
+	Notify(ctx context.Context, data map[string]any, headers map[string]string, consumer domain.Consumer, opt notifyopt.Kind) error
+}
+
+type Config struct {
+	// Enabled quarantines the consumers exhausting their budget, they are only scored otherwise
+	Enabled bool
+	// Interval is how often the consumers are scored and the quarantined ones probed
+	Interval time.Duration
+	Policy   domain.HealthPolicy
+}
+
+// Monitor scores the consumers of every project from the insights of the window of the policy,
+// quarantines the ones exhausting their failure budget and probes the quarantined ones until they
+// recover. The listeners of archived events are told about both.
+type Monitor struct {
+	events   EventStore
+	insights InsightsStore
+	store    *Store
+	fetch    Fetcher
+	cfg      Config
+}
+
+func NewMonitor(events EventStore, insights InsightsStore, store *Store, fetch Fetcher, cfg Config) *Monitor {
+	return &Monitor{events: events, insights: insights, store: store, fetch: fetch, cfg: cfg}
+}
+
+// Run monitors the consumers at every interval until ctx is cancelled. Every backoffice instance
+// runs it, the lock lets a single one monitor an interval.
+func (m *Monitor) Run(ctx context.Context) {
+	l := ctxlogger.GetLogger(ctx)
+	ctx = telemetry.WithMeter(ctx, telemetry.Meter("quarantine"))
+
+	trigger := time.NewTicker(m.cfg.Interval)
+	defer trigger.Stop()
+
+	for {
+		select {
+		case <-trigger.C:
+			locked, err := m.store.TryLock(ctx, m.cfg.Interval/2)
+			if err != nil {
+				l.Error("Error locking the quarantine monitor", "error", err)
+				continue
+			}
+
+			if !locked {
+				continue
+			}
+
+			if err := m.Check(ctx, time.Now()); err != nil {
+				l.Error("Error monitoring consumers", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check probes the quarantined consumers of every project, then quarantines the ones exhausting
+// their budget at now
+func (m *Monitor) Check(ctx context.Context, now time.Time) error {
+	l := ctxlogger.GetLogger(ctx)
+
+	projects, err := m.events.ListProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	for _, project := range projects {
+		projectCtx := domain.WithProject(ctx, project)
+		if err := m.checkProject(projectCtx, now); err != nil {
+			l.Error("Error monitoring the consumers of a project", "project", project.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Monitor) checkProject(ctx context.Context, now time.Time) error {
+	l := ctxlogger.GetLogger(ctx)
+
+	quarantines, err := m.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range quarantines {
+		switch {
+		case q.Active():
+			m.probe(ctx, q, now)
+		case now.Sub(*q.ReleasedAt) > m.cfg.Policy.Window:
+			// the window no longer has deliveries from before the release
+			if err := m.store.Delete(ctx, q.Event, q.Consumer); err != nil {
+				l.Warn("failed to prune released quarantine", "event", q.Event, "consumer", q.Consumer, "error", err)
+			}
+		}
+	}
+
+	if !m.cfg.Enabled {
+		return nil
+	}
+
+	health, err := m.Health(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range health {
+		if h.Status != domain.HealthFailing {
+			continue
+		}
+
+		q := domain.Quarantine{Event: h.Event, Consumer: h.Consumer, Since: now, Score: *h.Score}
+		if err := m.store.Save(ctx, q); err != nil {
+			l.Error("Error quarantining consumer", "event", q.Event, "consumer", q.Consumer, "error", err)
+			continue
+		}
+
+		l.Warn("Consumer quarantined", "event", q.Event, "consumer", q.Consumer, "score", q.Score)
+		telemetry.ConsumerQuarantined.Count(ctx, 1,
+			attribute.String("event", q.Event),
+			attribute.String("consumer.service_name", q.Consumer))
+		m.notify(ctx, domain.QuarantineNotificationQuarantined, q)
+	}
+
+	return nil
+}
+
+// Health scores every consumer with deliveries in the window ended at now, and every quarantined
+// one. The deliveries before the last release of a consumer are not counted.
+func (m *Monitor) Health(ctx context.Context, now time.Time) ([]domain.ConsumerHealth, error) {
+	quarantines, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byConsumer := make(map[domain.Series]domain.Quarantine, len(quarantines))
+	for _, q := range quarantines {
+		byConsumer[domain.Series{Event: q.Event, Consumer: q.Consumer}] = q
+	}
+
+	buckets, err := m.insights.GetRange(ctx, domain.InsightsWindow{
+		From:        now.UTC().Add(-m.cfg.Policy.Window),
+		To:          now.UTC(),
+		Granularity: time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[domain.Series]domain.SeriesStats)
+	for series := range byConsumer {
+		stats[series] = domain.SeriesStats{}
+	}
+
+	for _, bucket := range buckets {
+		for series, s := range bucket.Series {
+			if series.Consumer == "" {
+				continue
+			}
+
+			if q, ok := byConsumer[series]; ok && q.ReleasedAt != nil && bucket.Start.Before(*q.ReleasedAt) {
+				continue
+			}
+
+			total := stats[series]
+			total.Success += s.Success
+			total.Failure += s.Failure
+			stats[series] = total
+		}
+	}
+
+	series := slices.Collect(maps.Keys(stats))
+	slices.SortFunc(series, func(a, b domain.Series) int {
+		return cmp.Or(cmp.Compare(a.Event, b.Event), cmp.Compare(a.Consumer, b.Consumer))
+	})
+
+	output := make([]domain.ConsumerHealth, 0, len(series))
+	for _, s := range series {
+		var quarantine *domain.Quarantine
+		if q, ok := byConsumer[s]; ok {
+			quarantine = &q
+		}
+
+		total := stats[s]
+		output = append(output, domain.NewConsumerHealth(
+			s.Event, s.Consumer, total.Success+total.Failure, total.Failure, m.cfg.Policy, quarantine,
+		))
+	}
+
+	return output, nil
+}
+
+// Release ends the quarantine of the consumer of the event, its parked messages are delivered again
+func (m *Monitor) Release(ctx context.Context, event, consumer string, now time.Time) error {
+	quarantines, err := m.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range quarantines {
+		if q.Event == event && q.Consumer == consumer && q.Active() {
+			return m.release(ctx, q, now)
+		}
+	}
+
+	return domain.QuarantineNotFound
+}
+
+func (m *Monitor) release(ctx context.Context, q domain.Quarantine, now time.Time) error {
+	q.ReleasedAt = &now
+	if err := m.store.Save(ctx, q); err != nil {
+		return err
+	}
+
+	ctxlogger.GetLogger(ctx).Info("Consumer released from quarantine", "event", q.Event, "consumer", q.Consumer)
+	m.notify(ctx, domain.QuarantineNotificationReleased, q)
+	return nil
+}
+
+// probe sends a request to the consumer and releases it when it succeeds. A consumer removed
+// from its event has nothing left to deliver and is released too.
+func (m *Monitor) probe(ctx context.Context, q domain.Quarantine, now time.Time) {
+	l := ctxlogger.GetLogger(ctx)
+
+	consumer, err := m.consumer(ctx, q)
+	if errors.Is(err, domain.EventNotFound) || errors.Is(err, domain.ConsumerNotFound) {
+		if err := m.store.Delete(ctx, q.Event, q.Consumer); err != nil {
+			l.Warn("failed to delete quarantine of a removed consumer", "event", q.Event, "consumer", q.Consumer, "error", err)
+		}
+		return
+	}
+
+	if err != nil {
+		l.Error("Error loading quarantined consumer", "event", q.Event, "consumer", q.Consumer, "error", err)
+		return
+	}
+
+	headers := make(map[string]string, len(consumer.Headers)+1)
+	maps.Copy(headers, consumer.Headers)
+	headers[ProbeHeader] = "true"
+
+	err = m.fetch.Notify(ctx, map[string]any{
+		"probe":             true,
+		"event":             q.Event,
+		"consumer":          q.Consumer,
+		"quarantined_since": q.Since,
+	}, headers, consumer, notifyopt.Default)
+
+	if err == nil {
+		if err := m.release(ctx, q, now); err != nil {
+			l.Error("Error releasing consumer", "event", q.Event, "consumer", q.Consumer, "error", err)
+		}
+		return
+	}
+
+	q.Probes++
+	q.LastProbeAt = &now
+	q.LastProbeError = err.Error()
+	if err := m.store.Save(ctx, q); err != nil {
+		l.Error("Error saving probe of quarantined consumer", "event", q.Event, "consumer", q.Consumer, "error", err)
+	}
+}
+
+func (m *Monitor) consumer(ctx context.Context, q domain.Quarantine) (domain.Consumer, error) {
+	event, err := m.events.GetInternalEvent(ctx, q.Event)
+	if err != nil {
+		return domain.Consumer{}, err
+	}
+
+	for _, consumer := range event.Consumers {
+		if consumer.ServiceName == q.Consumer {
+			return consumer, nil
+		}
+	}
+
+	return domain.Consumer{}, domain.ConsumerNotFound
+}
+
+// notify tells the consumers of the archived events, like for the dead letters, with the owners
+// of the event and consumer so the right team is reached
+func (m *Monitor) notify(ctx context.Context, status domain.QuarantineNotificationStatus, q domain.Quarantine) {
+	l := ctxlogger.GetLogger(ctx)
+
+	listeners, err := m.events.GetAllSchedulers(ctx, "archived")
+	if errors.Is(err, domain.EventNotFound) || len(listeners) == 0 {
+		return
+	}
+
+	if err != nil {
+		l.Warn("Not found listeners events when quarantined", "event", q.Event, "consumer", q.Consumer, "error", err)
+		return
+	}
+
+	quarantine := map[string]any{
+		"status":     status,
+		"event_name": q.Event,
+		"consumer":   q.Consumer,
+		"score":      q.Score,
+		"since":      q.Since,
+	}
+	if q.ReleasedAt != nil {
+		quarantine["released_at"] = q.ReleasedAt
+	}
+
+	if event, err := m.events.GetInternalEvent(ctx, q.Event); err == nil {
+		quarantine["event_owner"] = event.Owner
+		for _, consumer := range event.Consumers {
+			if consumer.ServiceName == q.Consumer {
+				quarantine["consumer_owner"] = consumer.Owner
+			}
+		}
+	}
+
+	for _, listener := range listeners {
+		for _, consumer := range listener.Consumers {
+			if err := m.fetch.Notify(ctx, map[string]any{
+				"event":      listener.Name,
+				"quarantine": quarantine,
+			}, consumer.Headers, consumer, notifyopt.HighThroughput); err != nil {
+				l.Warn("failed to notify quarantine listener", "listener", consumer.ServiceName, "error", err)
+			}
+		}
+	}
+}
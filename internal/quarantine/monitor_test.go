@@ -0,0 +1,165 @@
+package quarantine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/notifyopt"
+	"github.com/IsaacDSC/gqueue/internal/quarantine"
+	"github.com/IsaacDSC/gqueue/mocks/mockquarantine"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var policy = domain.HealthPolicy{Window: 30 * time.Minute, FailureBudget: 0.5, MinDeliveries: 20}
+
+type monitorMocks struct {
+	events   *mockquarantine.MockEventStore
+	insights *mockquarantine.MockInsightsStore
+	fetch    *mockquarantine.MockFetcher
+}
+
+func newTestMonitor(t *testing.T, enabled bool) (*quarantine.Monitor, *quarantine.Store, monitorMocks) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	ctrl := gomock.NewController(t)
+	mocks := monitorMocks{
+		events:   mockquarantine.NewMockEventStore(ctrl),
+		insights: mockquarantine.NewMockInsightsStore(ctrl),
+		fetch:    mockquarantine.NewMockFetcher(ctrl),
+	}
+
+	store := quarantine.NewStore(client)
+	monitor := quarantine.NewMonitor(mocks.events, mocks.insights, store, mocks.fetch, quarantine.Config{
+		Enabled:  enabled,
+		Interval: time.Minute,
+		Policy:   policy,
+	})
+
+	return monitor, store, mocks
+}
+
+func deliveries(start time.Time, success, failure int64) domain.MetricsBuckets {
+	return domain.MetricsBuckets{{
+		Start: start,
+		Series: map[domain.Series]domain.SeriesStats{
+			{Event: "payment.processed"}:                      {Success: 100},
+			{Event: "payment.processed", Consumer: "billing"}: {Success: success, Failure: failure},
+			{Event: "payment.processed", Consumer: "ledger"}:  {Success: 100},
+		},
+	}}
+}
+
+func TestMonitor_QuarantinesAndReleases(t *testing.T) {
+	ctx := context.Background()
+	monitor, store, mocks := newTestMonitor(t, true)
+
+	billing := domain.Consumer{ServiceName: "billing", BaseUrl: "http://billing", Path: "/webhook", Owner: domain.Owner{TeamOwner: "billing"}}
+	event := domain.Event{Name: "payment.processed", Owner: domain.Owner{TeamOwner: "payments"}, Consumers: []domain.Consumer{billing}}
+	listener := domain.Event{Name: "failures", State: "archived", Consumers: []domain.Consumer{{ServiceName: "oncall", BaseUrl: "http://oncall", Path: "/hook"}}}
+
+	mocks.events.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject()}, nil).AnyTimes()
+	mocks.events.EXPECT().GetInternalEvent(gomock.Any(), "payment.processed").Return(event, nil).AnyTimes()
+	mocks.events.EXPECT().GetAllSchedulers(gomock.Any(), "archived").Return([]domain.Event{listener}, nil).AnyTimes()
+
+	now := time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC)
+	failing := deliveries(now.Add(-10*time.Minute), 5, 35)
+
+	// billing failed 35 of 40 deliveries, ledger is healthy
+	mocks.insights.EXPECT().GetRange(gomock.Any(), gomock.Any()).Return(failing, nil)
+	mocks.fetch.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), listener.Consumers[0], notifyopt.HighThroughput).DoAndReturn(
+		func(_ context.Context, data map[string]any, _ map[string]string, _ domain.Consumer, _ notifyopt.Kind) error {
+			q := data["quarantine"].(map[string]any)
+			assert.Equal(t, domain.QuarantineNotificationQuarantined, q["status"])
+			assert.Equal(t, "billing", q["consumer"])
+			assert.Equal(t, domain.Owner{TeamOwner: "payments"}, q["event_owner"])
+			return nil
+		})
+
+	require.NoError(t, monitor.Check(ctx, now))
+
+	checker := quarantine.NewChecker(store)
+	assert.True(t, checker.Quarantined(ctx, "payment.processed", "billing"))
+	assert.False(t, checker.Quarantined(ctx, "payment.processed", "ledger"))
+
+	// the probe still fails
+	mocks.fetch.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), billing, notifyopt.Default).DoAndReturn(
+		func(_ context.Context, data map[string]any, headers map[string]string, _ domain.Consumer, _ notifyopt.Kind) error {
+			assert.Equal(t, true, data["probe"])
+			assert.Equal(t, "true", headers[quarantine.ProbeHeader])
+			return errors.New("503 service unavailable")
+		})
+	mocks.insights.EXPECT().GetRange(gomock.Any(), gomock.Any()).Return(failing, nil)
+
+	require.NoError(t, monitor.Check(ctx, now.Add(time.Minute)))
+
+	quarantines, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, quarantines, 1)
+	assert.Equal(t, 1, quarantines[0].Probes)
+	assert.Equal(t, "503 service unavailable", quarantines[0].LastProbeError)
+
+	// the probe succeeds: the failures before the release are not counted again
+	mocks.fetch.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), billing, notifyopt.Default).Return(nil)
+	mocks.fetch.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), listener.Consumers[0], notifyopt.HighThroughput).DoAndReturn(
+		func(_ context.Context, data map[string]any, _ map[string]string, _ domain.Consumer, _ notifyopt.Kind) error {
+			assert.Equal(t, domain.QuarantineNotificationReleased, data["quarantine"].(map[string]any)["status"])
+			return nil
+		})
+	mocks.insights.EXPECT().GetRange(gomock.Any(), gomock.Any()).Return(failing, nil).Times(2)
+
+	released := now.Add(2 * time.Minute)
+	require.NoError(t, monitor.Check(ctx, released))
+
+	health, err := monitor.Health(ctx, released)
+	require.NoError(t, err)
+	require.Len(t, health, 2)
+	assert.Equal(t, "billing", health[0].Consumer)
+	assert.Equal(t, domain.HealthUnknown, health[0].Status)
+	assert.Equal(t, int64(0), health[0].Deliveries)
+	assert.Equal(t, domain.HealthHealthy, health[1].Status)
+}
+
+func TestMonitor_Disabled(t *testing.T) {
+	ctx := context.Background()
+	monitor, store, mocks := newTestMonitor(t, false)
+
+	now := time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC)
+	mocks.events.EXPECT().ListProjects(gomock.Any()).Return([]domain.Project{domain.DefaultProject()}, nil)
+	mocks.insights.EXPECT().GetRange(gomock.Any(), gomock.Any()).Return(deliveries(now.Add(-time.Minute), 5, 35), nil)
+
+	require.NoError(t, monitor.Check(ctx, now))
+
+	quarantines, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, quarantines, "consumers are only scored")
+
+	health, err := monitor.Health(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, health, 2)
+	assert.Equal(t, domain.HealthFailing, health[0].Status)
+	assert.Equal(t, 0.125, *health[0].Score)
+}
+
+func TestMonitor_Release(t *testing.T) {
+	ctx := context.Background()
+	monitor, store, mocks := newTestMonitor(t, true)
+
+	assert.ErrorIs(t, monitor.Release(ctx, "payment.processed", "billing", time.Now()), domain.QuarantineNotFound)
+
+	require.NoError(t, store.Save(ctx, domain.Quarantine{Event: "payment.processed", Consumer: "billing", Since: time.Now()}))
+	mocks.events.EXPECT().GetAllSchedulers(gomock.Any(), "archived").Return(nil, domain.EventNotFound)
+
+	require.NoError(t, monitor.Release(ctx, "payment.processed", "billing", time.Now()))
+	assert.False(t, quarantine.NewChecker(store).Quarantined(ctx, "payment.processed", "billing"))
+}
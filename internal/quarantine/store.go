@@ -0,0 +1,86 @@
+package quarantine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	quarantinePrefix = "gqueue:quarantine"
+	// lockKey is shared by every project: a single backoffice instance monitors an interval
+	lockKey = "gqueue:quarantine:lock"
+)
+
+// Store keeps the quarantines of a project in a Redis hash by event and consumer. The keys are
+// namespaced by the project in ctx.
+type Store struct {
+	cache *redis.Client
+}
+
+func NewStore(cache *redis.Client) *Store {
+	return &Store{cache: cache}
+}
+
+func key(ctx context.Context) string {
+	return domain.ProjectFromContext(ctx).Namespace(quarantinePrefix)
+}
+
+func field(event, consumer string) string {
+	return event + ":" + consumer
+}
+
+// List returns the quarantines of the project, the released ones included until they are pruned
+func (s *Store) List(ctx context.Context) ([]domain.Quarantine, error) {
+	values, err := s.cache.HGetAll(ctx, key(ctx)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantines: %w", err)
+	}
+
+	quarantines := make([]domain.Quarantine, 0, len(values))
+	for _, value := range values {
+		var q domain.Quarantine
+		if err := json.Unmarshal([]byte(value), &q); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal quarantine: %w", err)
+		}
+		quarantines = append(quarantines, q)
+	}
+
+	return quarantines, nil
+}
+
+func (s *Store) Save(ctx context.Context, q domain.Quarantine) error {
+	payload, err := json.Marshal(q)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quarantine: %w", err)
+	}
+
+	if err := s.cache.HSet(ctx, key(ctx), field(q.Event, q.Consumer), payload).Err(); err != nil {
+		return fmt.Errorf("failed to save quarantine: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Delete(ctx context.Context, event, consumer string) error {
+	if err := s.cache.HDel(ctx, key(ctx), field(event, consumer)).Err(); err != nil {
+		return fmt.Errorf("failed to delete quarantine: %w", err)
+	}
+
+	return nil
+}
+
+// TryLock reports whether the caller holds the monitor for the next ttl. The lock is not
+// released: it expires before the next interval.
+func (s *Store) TryLock(ctx context.Context, ttl time.Duration) (bool, error) {
+	ok, err := s.cache.SetNX(ctx, lockKey, time.Now().UTC().Format(time.RFC3339), ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to lock the quarantine monitor: %w", err)
+	}
+
+	return ok, nil
+}
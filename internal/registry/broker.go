@@ -0,0 +1,180 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	channel        = "gqueue:registry:invalidations"
+	versionKey     = "gqueue:registry:version"
+	instancePrefix = "gqueue:registry:instances:"
+
+	// the version only has to outlive the instances, every broadcast renews it
+	versionTTL = 30 * 24 * time.Hour
+	// instances report at least once per resync, so a missing key means the instance is gone
+	InstanceTTL = 3 * time.Minute
+)
+
+// Invalidation tells every instance which events of the project changed. Version is the registry
+// version after the change, so a subscriber can detect that it missed a message.
+type Invalidation struct {
+	Version int64 `json:"version"`
+	// ProjectID is empty in invalidations sent before projects existed, they refer to the default project
+	ProjectID  string   `json:"project_id,omitempty"`
+	EventNames []string `json:"event_names"`
+}
+
+type InstanceStatus struct {
+	ID         string    `json:"id"`
+	Version    int64     `json:"version"`
+	ReportedAt time.Time `json:"reported_at"`
+	Converged  bool      `json:"converged"`
+}
+
+type Status struct {
+	Version   int64            `json:"version"`
+	Converged bool             `json:"converged"`
+	Instances []InstanceStatus `json:"instances"`
+}
+
+// Broker broadcasts registry changes through Redis pub/sub and keeps the version counters
+type Broker struct {
+	client *redis.Client
+}
+
+func NewBroker(client *redis.Client) *Broker {
+	return &Broker{client: client}
+}
+
+// Broadcast bumps the registry version and notifies the subscribers that the events of the project changed
+func (b *Broker) Broadcast(ctx context.Context, projectID string, eventNames ...string) error {
+	pipe := b.client.TxPipeline()
+	incr := pipe.Incr(ctx, versionKey)
+	pipe.Expire(ctx, versionKey, versionTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to increment registry version: %w", err)
+	}
+
+	payload, err := json.Marshal(Invalidation{Version: incr.Val(), ProjectID: projectID, EventNames: eventNames})
+	if err != nil {
+		return fmt.Errorf("failed to marshal invalidation: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish invalidation: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe calls fn for every invalidation until ctx is cancelled.
+// Messages published while the connection is down are lost, subscribers must rely on the version to notice it.
+func (b *Broker) Subscribe(ctx context.Context, fn func(ctx context.Context, inv Invalidation)) error {
+	l := ctxlogger.GetLogger(ctx)
+
+	sub := b.client.Subscribe(ctx, channel)
+	defer sub.Close()
+
+	// wait for the subscription to be confirmed so no broadcast sent after this call is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to invalidations: %w", err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var inv Invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				l.Error("Error decoding registry invalidation", "error", err)
+				continue
+			}
+
+			fn(ctx, inv)
+		}
+	}
+}
+
+// Version returns the current registry version, 0 when nothing was broadcast yet
+func (b *Broker) Version(ctx context.Context) (int64, error) {
+	version, err := b.client.Get(ctx, versionKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get registry version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Report records the registry version applied by an instance
+func (b *Broker) Report(ctx context.Context, instanceID string, version int64) error {
+	payload, err := json.Marshal(InstanceStatus{ID: instanceID, Version: version, ReportedAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal instance status: %w", err)
+	}
+
+	if err := b.client.Set(ctx, instancePrefix+instanceID, payload, InstanceTTL).Err(); err != nil {
+		return fmt.Errorf("failed to report instance version: %w", err)
+	}
+
+	return nil
+}
+
+// Status compares the version applied by every live instance with the registry version
+func (b *Broker) Status(ctx context.Context) (Status, error) {
+	version, err := b.Version(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Version: version, Converged: true, Instances: make([]InstanceStatus, 0)}
+
+	iter := b.client.Scan(ctx, 0, instancePrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		payload, err := b.client.Get(ctx, iter.Val()).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			return Status{}, fmt.Errorf("failed to get instance status: %w", err)
+		}
+
+		var instance InstanceStatus
+		if err := json.Unmarshal(payload, &instance); err != nil {
+			return Status{}, fmt.Errorf("failed to unmarshal instance status: %w", err)
+		}
+
+		instance.Converged = instance.Version == version
+		status.Converged = status.Converged && instance.Converged
+		status.Instances = append(status.Instances, instance)
+	}
+
+	if err := iter.Err(); err != nil {
+		return Status{}, fmt.Errorf("failed to scan instances: %w", err)
+	}
+
+	sort.Slice(status.Instances, func(i, j int) bool {
+		return strings.Compare(status.Instances[i].ID, status.Instances[j].ID) < 0
+	})
+
+	return status, nil
+}
@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBroker(t *testing.T) *Broker {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewBroker(client)
+}
+
+func TestBroker_BroadcastAndSubscribe(t *testing.T) {
+	broker := newTestBroker(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan Invalidation, 2)
+	done := make(chan error, 1)
+	go func() {
+		done <- broker.Subscribe(ctx, func(_ context.Context, inv Invalidation) {
+			received <- inv
+		})
+	}()
+
+	// the subscription is confirmed asynchronously, retry until the first message arrives
+	require.Eventually(t, func() bool {
+		require.NoError(t, broker.Broadcast(ctx, "default", "payment.created"))
+		select {
+		case inv := <-received:
+			assert.Equal(t, []string{"payment.created"}, inv.EventNames)
+			return true
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, broker.Broadcast(ctx, "default", "order.created", "order.renamed"))
+
+	select {
+	case inv := <-received:
+		version, err := broker.Version(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, version, inv.Version)
+		assert.Equal(t, []string{"order.created", "order.renamed"}, inv.EventNames)
+		assert.Equal(t, "default", inv.ProjectID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("invalidation not received")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestBroker_Status(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		broadcasts    int
+		instances     map[string]int64
+		wantConverged bool
+	}{
+		{
+			name:          "no_instances",
+			broadcasts:    1,
+			wantConverged: true,
+		},
+		{
+			name:          "all_instances_converged",
+			broadcasts:    2,
+			instances:     map[string]int64{"a": 2, "b": 2},
+			wantConverged: true,
+		},
+		{
+			name:          "instance_behind",
+			broadcasts:    3,
+			instances:     map[string]int64{"a": 3, "b": 1},
+			wantConverged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := newTestBroker(t)
+
+			for range tt.broadcasts {
+				require.NoError(t, broker.Broadcast(ctx, "default", "event"))
+			}
+
+			for id, version := range tt.instances {
+				require.NoError(t, broker.Report(ctx, id, version))
+			}
+
+			status, err := broker.Status(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(tt.broadcasts), status.Version)
+			assert.Equal(t, tt.wantConverged, status.Converged)
+			require.Len(t, status.Instances, len(tt.instances))
+
+			for _, instance := range status.Instances {
+				assert.Equal(t, tt.instances[instance.ID], instance.Version)
+				assert.Equal(t, instance.Version == status.Version, instance.Converged)
+			}
+		})
+	}
+}
+
+func TestBroker_VersionWithoutBroadcast(t *testing.T) {
+	version, err := newTestBroker(t).Version(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, version)
+}
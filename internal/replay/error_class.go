@@ -0,0 +1,54 @@
+package replay
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ErrorClass groups delivery errors so a replay can target one kind of incident
+type ErrorClass string
+
+const (
+	ErrorClassTimeout     ErrorClass = "timeout"
+	ErrorClassConnection  ErrorClass = "connection"
+	ErrorClassServerError ErrorClass = "http_5xx"
+	ErrorClassClientError ErrorClass = "http_4xx"
+	ErrorClassOther       ErrorClass = "other"
+)
+
+func (c ErrorClass) Valid() bool {
+	switch c {
+	case ErrorClassTimeout, ErrorClassConnection, ErrorClassServerError, ErrorClassClientError, ErrorClassOther:
+		return true
+	}
+
+	return false
+}
+
+var statusCodePattern = regexp.MustCompile(`status code: (\d{3})`)
+
+// ClassifyError maps the last error recorded for a delivery to its class.
+// The messages are the ones returned by fetcher.Notification.
+func ClassifyError(msg string) ErrorClass {
+	if match := statusCodePattern.FindStringSubmatch(msg); match != nil {
+		switch match[1][0] {
+		case '5':
+			return ErrorClassServerError
+		case '4':
+			return ErrorClassClientError
+		}
+	}
+
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "deadline exceeded"), strings.Contains(lower, "timeout"):
+		return ErrorClassTimeout
+	case strings.Contains(lower, "connection refused"),
+		strings.Contains(lower, "connection reset"),
+		strings.Contains(lower, "no such host"),
+		strings.Contains(lower, "eof"):
+		return ErrorClassConnection
+	}
+
+	return ErrorClassOther
+}
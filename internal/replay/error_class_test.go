@@ -0,0 +1,30 @@
+package replay_test
+
+import (
+	"testing"
+
+	"github.com/IsaacDSC/gqueue/internal/replay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected replay.ErrorClass
+	}{
+		{msg: "fetch consumer: unexpected status code: 503", expected: replay.ErrorClassServerError},
+		{msg: "fetch consumer: unexpected status code: 404", expected: replay.ErrorClassClientError},
+		{msg: `fetch consumer: post request: Post "http://billing/hook": context deadline exceeded`, expected: replay.ErrorClassTimeout},
+		{msg: `post request: Post "http://billing/hook": net/http: request canceled (Client.Timeout exceeded while awaiting headers)`, expected: replay.ErrorClassTimeout},
+		{msg: `post request: Post "http://billing/hook": dial tcp 10.0.0.1:80: connect: connection refused`, expected: replay.ErrorClassConnection},
+		{msg: `post request: Post "http://billing/hook": dial tcp: lookup billing: no such host`, expected: replay.ErrorClassConnection},
+		{msg: "validate payload: event_name is required", expected: replay.ErrorClassOther},
+		{msg: "", expected: replay.ErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.expected)+"/"+tt.msg, func(t *testing.T) {
+			assert.Equal(t, tt.expected, replay.ClassifyError(tt.msg))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrJobNotFound = errors.New("replay job not found")
+	ErrJobFinished = errors.New("replay job already finished")
+	ErrInvalidJob  = errors.New("invalid replay job")
+)
+
+type State string
+
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StateCompleted State = "completed"
+	StateCancelled State = "cancelled"
+	StateFailed    State = "failed"
+)
+
+func (s State) Finished() bool {
+	return s == StateCompleted || s == StateCancelled || s == StateFailed
+}
+
+// Filter selects the failed deliveries a job replays. Consumer and the time window are
+// required so a job never redelivers everything by accident.
+type Filter struct {
+	EventName  string     `json:"event_name,omitempty"`
+	Consumer   string     `json:"consumer"`
+	From       time.Time  `json:"from"`
+	To         time.Time  `json:"to"`
+	ErrorClass ErrorClass `json:"error_class,omitempty"`
+	// Sources limits the stores searched, empty means every store
+	Sources []string `json:"sources,omitempty"`
+}
+
+func (f Filter) Validate() error {
+	if f.Consumer == "" {
+		return errors.New("consumer is required")
+	}
+
+	if f.From.IsZero() || f.To.IsZero() {
+		return errors.New("from and to are required")
+	}
+
+	if !f.From.Before(f.To) {
+		return errors.New("from must be before to")
+	}
+
+	if f.ErrorClass != "" && !f.ErrorClass.Valid() {
+		return fmt.Errorf("invalid error_class %q", f.ErrorClass)
+	}
+
+	return nil
+}
+
+// Match reports whether the failed delivery is selected by the filter
+func (f Filter) Match(c Candidate) bool {
+	if f.EventName != "" && c.EventName != f.EventName {
+		return false
+	}
+
+	if c.Consumer != f.Consumer {
+		return false
+	}
+
+	if c.FailedAt.Before(f.From) || c.FailedAt.After(f.To) {
+		return false
+	}
+
+	return f.ErrorClass == "" || ClassifyError(c.LastError) == f.ErrorClass
+}
+
+// Job tracks the progress of a replay. It is kept in Redis so every backoffice instance can read
+// and cancel it, although it runs in the instance that started it.
+type Job struct {
+	ID uuid.UUID `json:"id"`
+	// ProjectID is the project the job replays the deliveries of, empty for jobs started
+	// before projects existed
+	ProjectID     string     `json:"project_id,omitempty"`
+	Filter        Filter     `json:"filter"`
+	RatePerSecond float64    `json:"rate_per_second,omitempty"`
+	State         State      `json:"state"`
+	Matched       int        `json:"matched"`
+	Replayed      int        `json:"replayed"`
+	Failed        int        `json:"failed"`
+	Errors        []string   `json:"errors,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// maxJobErrors bounds the failures kept in the job, the counters keep the totals
+const maxJobErrors = 20
+
+func (j *Job) fail(c Candidate, err error) {
+	j.Failed++
+	if len(j.Errors) < maxJobErrors {
+		j.Errors = append(j.Errors, fmt.Sprintf("%s %s: %s", c.Source, c.ID, err))
+	}
+}
+
+func (j *Job) finish(state State) {
+	now := time.Now()
+	j.State = state
+	j.FinishedAt = &now
+}
+
+// Candidate is a failed delivery selected for replay
+type Candidate struct {
+	ID        string
+	Source    string
+	Queue     string
+	EventName string
+	Consumer  string
+	LastError string
+	FailedAt  time.Time
+	// MessageID is the one of the publication, when the source keeps it
+	MessageID string
+}
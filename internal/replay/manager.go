@@ -0,0 +1,254 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+// Source is a store of failed deliveries that can be replayed
+type Source interface {
+	Name() string
+	// Find returns the failed deliveries matching the filter
+	Find(ctx context.Context, filter Filter) ([]Candidate, error)
+	Replay(ctx context.Context, c Candidate) error
+}
+
+// progressInterval is how often a running job saves its progress and checks for cancellation
+const progressInterval = time.Second
+
+const defaultListLimit = 50
+
+type Manager struct {
+	store   *Store
+	sources []Source
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewManager(store *Store, sources ...Source) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{store: store, sources: sources, ctx: ctx, cancel: cancel}
+}
+
+func (m *Manager) SourceNames() []string {
+	names := make([]string, 0, len(m.sources))
+	for _, source := range m.sources {
+		names = append(names, source.Name())
+	}
+	return names
+}
+
+// Start validates the filter, stores the job and runs it in the background in the project of ctx.
+// ratePerSecond limits how many deliveries are replayed per second, zero means unlimited.
+func (m *Manager) Start(ctx context.Context, filter Filter, ratePerSecond float64) (Job, error) {
+	if err := filter.Validate(); err != nil {
+		return Job{}, fmt.Errorf("%w: %w", ErrInvalidJob, err)
+	}
+
+	if ratePerSecond < 0 {
+		return Job{}, fmt.Errorf("%w: rate_per_second must not be negative", ErrInvalidJob)
+	}
+
+	for _, name := range filter.Sources {
+		if !slices.Contains(m.SourceNames(), name) {
+			return Job{}, fmt.Errorf("%w: unknown source %q, available: %v", ErrInvalidJob, name, m.SourceNames())
+		}
+	}
+
+	project := domain.ProjectFromContext(ctx)
+	job := Job{
+		ID:            uuid.New(),
+		ProjectID:     project.ID,
+		Filter:        filter,
+		RatePerSecond: ratePerSecond,
+		State:         StatePending,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := m.store.Save(ctx, job); err != nil {
+		return Job{}, err
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.run(domain.WithProject(m.ctx, project), job)
+	}()
+
+	return job, nil
+}
+
+// Get returns the job, the jobs of other projects are not found
+func (m *Manager) Get(ctx context.Context, id uuid.UUID) (Job, error) {
+	job, err := m.store.Get(ctx, id)
+	if err != nil {
+		return Job{}, err
+	}
+
+	if !ownedBy(job, domain.ProjectFromContext(ctx)) {
+		return Job{}, ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+// List returns the latest jobs of the project in ctx
+func (m *Manager) List(ctx context.Context) ([]Job, error) {
+	jobs, err := m.store.List(ctx, defaultListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	project := domain.ProjectFromContext(ctx)
+	output := make([]Job, 0, len(jobs))
+	for _, job := range jobs {
+		if ownedBy(job, project) {
+			output = append(output, job)
+		}
+	}
+
+	return output, nil
+}
+
+func (m *Manager) Cancel(ctx context.Context, id uuid.UUID) (Job, error) {
+	if _, err := m.Get(ctx, id); err != nil {
+		return Job{}, err
+	}
+
+	if err := m.store.RequestCancel(ctx, id); err != nil {
+		return Job{}, err
+	}
+
+	return m.store.Get(ctx, id)
+}
+
+// Close stops the running jobs, they are saved as cancelled
+func (m *Manager) Close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *Manager) run(ctx context.Context, job Job) {
+	l := ctxlogger.GetLogger(ctx).With("replay_job_id", job.ID)
+
+	save := func() {
+		// the final state is saved even when the manager is closing
+		saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := m.store.Save(saveCtx, job); err != nil {
+			l.Error("Error saving replay job", "error", err)
+		}
+	}
+
+	now := time.Now()
+	job.State = StateRunning
+	job.StartedAt = &now
+	save()
+
+	candidates, err := m.find(ctx, job.Filter)
+	if err != nil {
+		l.Error("Error selecting deliveries to replay", "error", err)
+		job.Errors = append(job.Errors, err.Error())
+		job.finish(StateFailed)
+		save()
+		return
+	}
+
+	job.Matched = len(candidates)
+	l.Info("Replay job started", "matched", job.Matched)
+
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if job.RatePerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(job.RatePerSecond), 1)
+	}
+
+	sources := make(map[string]Source, len(m.sources))
+	for _, source := range m.sources {
+		sources[source.Name()] = source
+	}
+
+	lastProgress := time.Time{}
+	for _, c := range candidates {
+		if time.Since(lastProgress) >= progressInterval {
+			lastProgress = time.Now()
+			save()
+
+			if m.cancelled(ctx, job.ID) {
+				job.finish(StateCancelled)
+				save()
+				l.Info("Replay job cancelled", "replayed", job.Replayed)
+				return
+			}
+		}
+
+		if err := limiter.Wait(ctx); err != nil {
+			job.Errors = append(job.Errors, "interrupted by shutdown")
+			job.finish(StateCancelled)
+			save()
+			return
+		}
+
+		if err := sources[c.Source].Replay(ctx, c); err != nil {
+			l.Warn("Error replaying delivery", "source", c.Source, "id", c.ID, "error", err)
+			job.fail(c, err)
+			continue
+		}
+
+		job.Replayed++
+	}
+
+	job.finish(StateCompleted)
+	save()
+	l.Info("Replay job finished", "replayed", job.Replayed, "failed", job.Failed)
+}
+
+func (m *Manager) find(ctx context.Context, filter Filter) ([]Candidate, error) {
+	var output []Candidate
+	for _, source := range m.sources {
+		if len(filter.Sources) > 0 && !slices.Contains(filter.Sources, source.Name()) {
+			continue
+		}
+
+		candidates, err := source.Find(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find deliveries in %s: %w", source.Name(), err)
+		}
+
+		output = append(output, candidates...)
+	}
+
+	// deliveries are replayed in the order they failed
+	slices.SortStableFunc(output, func(a, b Candidate) int {
+		return a.FailedAt.Compare(b.FailedAt)
+	})
+
+	return output, nil
+}
+
+func ownedBy(job Job, project domain.Project) bool {
+	if job.ProjectID == "" {
+		return project.ID == domain.DefaultProjectID
+	}
+
+	return job.ProjectID == project.ID
+}
+
+func (m *Manager) cancelled(ctx context.Context, id uuid.UUID) bool {
+	cancelled, err := m.store.CancelRequested(ctx, id)
+	if err != nil {
+		ctxlogger.GetLogger(ctx).Warn("Error checking replay job cancellation", "replay_job_id", id, "error", err)
+		return false
+	}
+
+	return cancelled
+}
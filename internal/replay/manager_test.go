@@ -0,0 +1,242 @@
+package replay_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/replay"
+	"github.com/IsaacDSC/gqueue/mocks/mockreplay"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestStore(t *testing.T) *replay.Store {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return replay.NewStore(client)
+}
+
+func newFilter() replay.Filter {
+	to := time.Now()
+	return replay.Filter{Consumer: "billing", From: to.Add(-time.Hour), To: to}
+}
+
+func newSource(ctrl *gomock.Controller, name string, candidates []replay.Candidate) *mockreplay.MockSource {
+	source := mockreplay.NewMockSource(ctrl)
+	source.EXPECT().Name().Return(name).AnyTimes()
+	source.EXPECT().Find(gomock.Any(), gomock.Any()).Return(candidates, nil).AnyTimes()
+	return source
+}
+
+func waitFinished(t *testing.T, manager *replay.Manager, id uuid.UUID) replay.Job {
+	t.Helper()
+
+	var job replay.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = manager.Get(context.Background(), id)
+		require.NoError(t, err)
+		return job.State.Finished()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return job
+}
+
+func TestManager_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	deadLetters := newSource(ctrl, replay.SourceDeadLetter, []replay.Candidate{
+		{ID: "dl-2", Source: replay.SourceDeadLetter, FailedAt: now.Add(-time.Minute)},
+	})
+	tasks := newSource(ctrl, replay.SourceAsynq, []replay.Candidate{
+		{ID: "task-1", Source: replay.SourceAsynq, FailedAt: now.Add(-2 * time.Minute)},
+		{ID: "task-3", Source: replay.SourceAsynq, FailedAt: now.Add(-30 * time.Second)},
+	})
+
+	var order []string
+	gomock.InOrder(
+		tasks.EXPECT().Replay(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c replay.Candidate) error {
+			order = append(order, c.ID)
+			return nil
+		}),
+		deadLetters.EXPECT().Replay(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c replay.Candidate) error {
+			order = append(order, c.ID)
+			return errors.New("publish failed")
+		}),
+		tasks.EXPECT().Replay(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c replay.Candidate) error {
+			order = append(order, c.ID)
+			return nil
+		}),
+	)
+
+	manager := replay.NewManager(newTestStore(t), deadLetters, tasks)
+	defer manager.Close()
+
+	job, err := manager.Start(context.Background(), newFilter(), 0)
+	require.NoError(t, err)
+
+	job = waitFinished(t, manager, job.ID)
+	assert.Equal(t, replay.StateCompleted, job.State)
+	assert.Equal(t, 3, job.Matched)
+	assert.Equal(t, 2, job.Replayed)
+	assert.Equal(t, 1, job.Failed)
+	assert.Equal(t, []string{"dead_letter dl-2: publish failed"}, job.Errors)
+	assert.Equal(t, []string{"task-1", "dl-2", "task-3"}, order, "replayed in the order they failed")
+}
+
+func TestManager_RunSelectedSources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deadLetters := mockreplay.NewMockSource(ctrl)
+	deadLetters.EXPECT().Name().Return(replay.SourceDeadLetter).AnyTimes()
+	tasks := newSource(ctrl, replay.SourceAsynq, []replay.Candidate{{ID: "task-1", Source: replay.SourceAsynq}})
+	tasks.EXPECT().Replay(gomock.Any(), gomock.Any()).Return(nil)
+
+	manager := replay.NewManager(newTestStore(t), deadLetters, tasks)
+	defer manager.Close()
+
+	filter := newFilter()
+	filter.Sources = []string{replay.SourceAsynq}
+
+	job, err := manager.Start(context.Background(), filter, 0)
+	require.NoError(t, err)
+
+	job = waitFinished(t, manager, job.ID)
+	assert.Equal(t, replay.StateCompleted, job.State)
+	assert.Equal(t, 1, job.Replayed)
+}
+
+func TestManager_RunFindError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	source := mockreplay.NewMockSource(ctrl)
+	source.EXPECT().Name().Return(replay.SourceDurableSQL).AnyTimes()
+	source.EXPECT().Find(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	manager := replay.NewManager(newTestStore(t), source)
+	defer manager.Close()
+
+	job, err := manager.Start(context.Background(), newFilter(), 0)
+	require.NoError(t, err)
+
+	job = waitFinished(t, manager, job.ID)
+	assert.Equal(t, replay.StateFailed, job.State)
+	assert.Equal(t, []string{"failed to find deliveries in durable_sql: connection refused"}, job.Errors)
+}
+
+func TestManager_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	candidates := make([]replay.Candidate, 10)
+	for i := range candidates {
+		candidates[i] = replay.Candidate{ID: fmt.Sprint(i), Source: replay.SourceAsynq}
+	}
+
+	source := newSource(ctrl, replay.SourceAsynq, candidates)
+	source.EXPECT().Replay(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	manager := replay.NewManager(newTestStore(t), source)
+	defer manager.Close()
+
+	// two deliveries per second keeps the job running long enough to cancel it
+	job, err := manager.Start(context.Background(), newFilter(), 2)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		job, err := manager.Get(context.Background(), job.ID)
+		require.NoError(t, err)
+		return job.State == replay.StateRunning
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, err = manager.Cancel(context.Background(), job.ID)
+	require.NoError(t, err)
+
+	job = waitFinished(t, manager, job.ID)
+	assert.Equal(t, replay.StateCancelled, job.State)
+	assert.Less(t, job.Replayed, len(candidates))
+
+	_, err = manager.Cancel(context.Background(), job.ID)
+	assert.ErrorIs(t, err, replay.ErrJobFinished)
+
+	_, err = manager.Cancel(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, replay.ErrJobNotFound)
+}
+
+func TestManager_ProjectIsolation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	acme := domain.Project{ID: "acme", TopicPrefix: "acme"}
+
+	source := newSource(ctrl, replay.SourceAsynq, nil)
+	manager := replay.NewManager(newTestStore(t), source)
+	defer manager.Close()
+
+	job, err := manager.Start(domain.WithProject(context.Background(), acme), newFilter(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", job.ProjectID)
+
+	require.Eventually(t, func() bool {
+		job, err := manager.Get(domain.WithProject(context.Background(), acme), job.ID)
+		require.NoError(t, err)
+		return job.State.Finished()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = manager.Get(context.Background(), job.ID)
+	assert.ErrorIs(t, err, replay.ErrJobNotFound, "jobs of other projects are not found")
+
+	jobs, err := manager.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	jobs, err = manager.List(domain.WithProject(context.Background(), acme))
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestManager_StartInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager := replay.NewManager(newTestStore(t), newSource(ctrl, replay.SourceAsynq, nil))
+	defer manager.Close()
+
+	valid := newFilter()
+
+	tests := []struct {
+		name   string
+		filter func(f replay.Filter) replay.Filter
+		rate   float64
+	}{
+		{name: "missing_consumer", filter: func(f replay.Filter) replay.Filter { f.Consumer = ""; return f }},
+		{name: "missing_window", filter: func(f replay.Filter) replay.Filter { f.From = time.Time{}; return f }},
+		{name: "inverted_window", filter: func(f replay.Filter) replay.Filter { f.From, f.To = f.To, f.From; return f }},
+		{name: "unknown_error_class", filter: func(f replay.Filter) replay.Filter { f.ErrorClass = "dns"; return f }},
+		{name: "unknown_source", filter: func(f replay.Filter) replay.Filter { f.Sources = []string{"kafka"}; return f }},
+		{name: "negative_rate", filter: func(f replay.Filter) replay.Filter { return f }, rate: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manager.Start(context.Background(), tt.filter(valid), tt.rate)
+			assert.ErrorIs(t, err, replay.ErrInvalidJob)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/pgqueue"
+	"github.com/google/uuid"
+)
+
+const (
+	SourceDeadLetter = "dead_letter"
+	SourceAsynq      = "asynq"
+	SourceDurableSQL = "durable_sql"
+)
+
+// pageSize is how many entries a source reads at a time while searching
+const pageSize = 500
+
+type DeadLetterStore interface {
+	List(ctx context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error)
+	Get(ctx context.Context, id uuid.UUID) (domain.DeadLetter, error)
+	Delete(ctx context.Context, ids ...uuid.UUID) (int, error)
+}
+
+type DeadLetterReplayer interface {
+	Replay(ctx context.Context, dl domain.DeadLetter, allConsumers bool) error
+}
+
+// DeadLetterSource replays the Pub/Sub messages kept by the dead-letter store
+type DeadLetterSource struct {
+	store    DeadLetterStore
+	replayer DeadLetterReplayer
+}
+
+func NewDeadLetterSource(store DeadLetterStore, replayer DeadLetterReplayer) *DeadLetterSource {
+	return &DeadLetterSource{store: store, replayer: replayer}
+}
+
+func (s *DeadLetterSource) Name() string { return SourceDeadLetter }
+
+func (s *DeadLetterSource) Find(ctx context.Context, filter Filter) ([]Candidate, error) {
+	filters := domain.FilterDeadLetters{
+		Consumer: []string{filter.Consumer},
+		From:     filter.From.Format(time.RFC3339Nano),
+		To:       filter.To.Format(time.RFC3339Nano),
+	}
+
+	if filter.EventName != "" {
+		filters.EventName = []string{filter.EventName}
+	}
+
+	deadLetters, err := s.store.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []Candidate
+	for _, dl := range deadLetters {
+		c := Candidate{
+			ID:        dl.ID.String(),
+			Source:    SourceDeadLetter,
+			EventName: dl.EventName,
+			Consumer:  dl.Consumer.ServiceName,
+			LastError: dl.LastError,
+			FailedAt:  dl.DeadAt,
+		}
+
+		if filter.Match(c) {
+			output = append(output, c)
+		}
+	}
+
+	return output, nil
+}
+
+// Replay publishes the message to the consumer that failed and removes it from the store
+func (s *DeadLetterSource) Replay(ctx context.Context, c Candidate) error {
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return fmt.Errorf("invalid dead letter id: %w", err)
+	}
+
+	dl, err := s.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.replayer.Replay(ctx, dl, false); err != nil {
+		return err
+	}
+
+	if _, err := s.store.Delete(ctx, id); err != nil {
+		return fmt.Errorf("replayed but not removed: %w", err)
+	}
+
+	return nil
+}
+
+type ArchivedTaskManager interface {
+	ArchivedQueues(ctx context.Context) ([]taskapp.ArchivedQueue, error)
+	ListArchived(ctx context.Context, queue string, page, limit int) (taskapp.ArchivedTasksPage, error)
+	RequeueTask(ctx context.Context, queue, id string) error
+}
+
+// ArchivedTaskSource requeues the tasks archived by asynq
+type ArchivedTaskSource struct {
+	manager ArchivedTaskManager
+}
+
+func NewArchivedTaskSource(manager ArchivedTaskManager) *ArchivedTaskSource {
+	return &ArchivedTaskSource{manager: manager}
+}
+
+func (s *ArchivedTaskSource) Name() string { return SourceAsynq }
+
+func (s *ArchivedTaskSource) Find(ctx context.Context, filter Filter) ([]Candidate, error) {
+	queues, err := s.manager.ArchivedQueues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := domain.ProjectFromContext(ctx).TopicName(domain.EventQueueRequestToExternal)
+
+	var output []Candidate
+	for _, queue := range queues {
+		if queue.Archived == 0 {
+			continue
+		}
+
+		for page := 1; ; page++ {
+			result, err := s.manager.ListArchived(ctx, queue.Queue, page, pageSize)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, task := range result.Tasks {
+				// tasks not published by gqueue have no consumer to match, the ones of
+				// other projects were published to their own topic
+				if task.Payload == nil || task.Type != topic {
+					continue
+				}
+
+				c := Candidate{
+					ID:        task.ID,
+					Source:    SourceAsynq,
+					Queue:     task.Queue,
+					EventName: task.Payload.EventName,
+					Consumer:  task.Payload.Consumer.ServiceName,
+					LastError: task.LastError,
+					FailedAt:  task.LastFailedAt,
+				}
+
+				if filter.Match(c) {
+					output = append(output, c)
+				}
+			}
+
+			if len(result.Tasks) < pageSize {
+				break
+			}
+		}
+	}
+
+	return output, nil
+}
+
+func (s *ArchivedTaskSource) Replay(ctx context.Context, c Candidate) error {
+	return s.manager.RequeueTask(ctx, c.Queue, c.ID)
+}
+
+type JobStore interface {
+	ListJobs(ctx context.Context, state pgqueue.State, limit, offset int) ([]pgqueue.Job, error)
+	Requeue(ctx context.Context, id uuid.UUID) error
+}
+
+type TimelineRecorder interface {
+	Record(ctx context.Context, entry domain.TimelineEntry)
+}
+
+// DurableSQLSource requeues the jobs archived in the durable_sql queue
+type DurableSQLSource struct {
+	jobs     JobStore
+	timeline TimelineRecorder
+}
+
+func NewDurableSQLSource(jobs JobStore, timeline TimelineRecorder) *DurableSQLSource {
+	return &DurableSQLSource{jobs: jobs, timeline: timeline}
+}
+
+func (s *DurableSQLSource) Name() string { return SourceDurableSQL }
+
+func (s *DurableSQLSource) Find(ctx context.Context, filter Filter) ([]Candidate, error) {
+	topic := domain.ProjectFromContext(ctx).TopicName(domain.EventQueueRequestToExternal)
+
+	var output []Candidate
+	for offset := 0; ; offset += pageSize {
+		jobs, err := s.jobs.ListJobs(ctx, pgqueue.StateArchived, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, job := range jobs {
+			if job.Topic != topic {
+				continue
+			}
+
+			var payload taskapp.RequestPayload
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				continue
+			}
+
+			// a job is archived by its last failed attempt, so updated_at is when it failed
+			c := Candidate{
+				ID:        job.ID.String(),
+				Source:    SourceDurableSQL,
+				Queue:     job.Queue,
+				EventName: payload.EventName,
+				Consumer:  payload.Consumer.ServiceName,
+				LastError: job.LastError,
+				FailedAt:  job.UpdatedAt,
+				MessageID: payload.MessageID,
+			}
+
+			if filter.Match(c) {
+				output = append(output, c)
+			}
+		}
+
+		if len(jobs) < pageSize {
+			return output, nil
+		}
+	}
+}
+
+func (s *DurableSQLSource) Replay(ctx context.Context, c Candidate) error {
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return fmt.Errorf("invalid job id: %w", err)
+	}
+
+	if err := s.jobs.Requeue(ctx, id); err != nil {
+		return err
+	}
+
+	s.timeline.Record(ctx, domain.TimelineEntry{
+		MessageID: c.MessageID,
+		Stage:     domain.StageReplayed,
+		EventName: c.EventName,
+		Consumer:  c.Consumer,
+		Source:    SourceDurableSQL,
+	})
+
+	return nil
+}
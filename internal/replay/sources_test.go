@@ -0,0 +1,143 @@
+package replay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/app/taskapp"
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/internal/replay"
+	"github.com/IsaacDSC/gqueue/internal/timeline"
+	"github.com/IsaacDSC/gqueue/mocks/mockreplay"
+	"github.com/IsaacDSC/gqueue/pkg/pgqueue"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func ids(candidates []replay.Candidate) []string {
+	output := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		output = append(output, c.ID)
+	}
+	return output
+}
+
+func TestDeadLetterSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := newFilter()
+	filter.ErrorClass = replay.ErrorClassServerError
+
+	serverError := domain.DeadLetter{
+		ID:        uuid.New(),
+		EventName: "payment.processed",
+		Consumer:  domain.Consumer{ServiceName: "billing"},
+		LastError: "fetch consumer: unexpected status code: 502",
+		DeadAt:    filter.To.Add(-time.Minute),
+	}
+	clientError := serverError
+	clientError.ID = uuid.New()
+	clientError.LastError = "fetch consumer: unexpected status code: 400"
+
+	store := mockreplay.NewMockDeadLetterStore(ctrl)
+	store.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error) {
+		assert.Equal(t, []string{"billing"}, filters.Consumer)
+		assert.Empty(t, filters.EventName)
+		return []domain.DeadLetter{serverError, clientError}, nil
+	})
+
+	replayer := mockreplay.NewMockDeadLetterReplayer(ctrl)
+	source := replay.NewDeadLetterSource(store, replayer)
+
+	candidates, err := source.Find(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, []string{serverError.ID.String()}, ids(candidates))
+
+	store.EXPECT().Get(gomock.Any(), serverError.ID).Return(serverError, nil)
+	replayer.EXPECT().Replay(gomock.Any(), serverError, false).Return(nil)
+	store.EXPECT().Delete(gomock.Any(), serverError.ID).Return(1, nil)
+
+	require.NoError(t, source.Replay(context.Background(), candidates[0]))
+}
+
+func TestArchivedTaskSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := newFilter()
+	filter.EventName = "payment.processed"
+
+	payload := func(eventName, consumer string) *taskapp.RequestPayload {
+		return &taskapp.RequestPayload{EventName: eventName, Consumer: domain.Consumer{ServiceName: consumer}}
+	}
+
+	topic := domain.DefaultProject().TopicName(domain.EventQueueRequestToExternal)
+	otherProject := domain.Project{ID: "acme", TopicPrefix: "acme"}
+
+	manager := mockreplay.NewMockArchivedTaskManager(ctrl)
+	manager.EXPECT().ArchivedQueues(gomock.Any()).Return([]taskapp.ArchivedQueue{
+		{Queue: "external.medium", Archived: 4},
+		{Queue: "external.low", Archived: 0},
+	}, nil)
+	manager.EXPECT().ListArchived(gomock.Any(), "external.medium", 1, gomock.Any()).Return(taskapp.ArchivedTasksPage{
+		Tasks: []taskapp.ArchivedTask{
+			{ID: "match", Queue: "external.medium", Type: topic, Payload: payload("payment.processed", "billing"), LastFailedAt: filter.To.Add(-time.Minute)},
+			{ID: "other_consumer", Queue: "external.medium", Type: topic, Payload: payload("payment.processed", "ledger"), LastFailedAt: filter.To.Add(-time.Minute)},
+			{ID: "outside_window", Queue: "external.medium", Type: topic, Payload: payload("payment.processed", "billing"), LastFailedAt: filter.From.Add(-time.Minute)},
+			{ID: "other_project", Queue: "external.medium", Type: otherProject.TopicName(domain.EventQueueRequestToExternal), Payload: payload("payment.processed", "billing"), LastFailedAt: filter.To.Add(-time.Minute)},
+			{ID: "foreign_task", Queue: "external.medium", Type: "email:send", LastFailedAt: filter.To.Add(-time.Minute)},
+		},
+	}, nil)
+
+	source := replay.NewArchivedTaskSource(manager)
+
+	candidates, err := source.Find(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, []string{"match"}, ids(candidates))
+
+	manager.EXPECT().RequeueTask(gomock.Any(), "external.medium", "match").Return(nil)
+	require.NoError(t, source.Replay(context.Background(), candidates[0]))
+}
+
+func TestDurableSQLSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	filter := newFilter()
+	topic := domain.DefaultProject().TopicName(domain.EventQueueRequestToExternal)
+	matched := pgqueue.Job{
+		ID:        uuid.New(),
+		Topic:     topic,
+		Payload:   []byte(`{"event_name":"payment.processed","consumer":{"service_name":"billing"}}`),
+		LastError: "fetch consumer: unexpected status code: 500",
+		UpdatedAt: filter.To.Add(-time.Minute),
+	}
+	otherConsumer := pgqueue.Job{
+		ID:        uuid.New(),
+		Topic:     topic,
+		Payload:   []byte(`{"event_name":"payment.processed","consumer":{"service_name":"ledger"}}`),
+		UpdatedAt: filter.To.Add(-time.Minute),
+	}
+	otherProject := pgqueue.Job{
+		ID:        uuid.New(),
+		Topic:     domain.Project{TopicPrefix: "acme"}.TopicName(domain.EventQueueRequestToExternal),
+		Payload:   matched.Payload,
+		UpdatedAt: filter.To.Add(-time.Minute),
+	}
+
+	jobs := mockreplay.NewMockJobStore(ctrl)
+	jobs.EXPECT().ListJobs(gomock.Any(), pgqueue.StateArchived, gomock.Any(), 0).Return([]pgqueue.Job{matched, otherConsumer, otherProject}, nil)
+
+	source := replay.NewDurableSQLSource(jobs, timeline.Discard)
+
+	candidates, err := source.Find(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, []string{matched.ID.String()}, ids(candidates))
+
+	jobs.EXPECT().Requeue(gomock.Any(), matched.ID).Return(nil)
+	require.NoError(t, source.Replay(context.Background(), candidates[0]))
+}
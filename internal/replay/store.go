@@ -0,0 +1,117 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	jobPrefix   = "gqueue:replay:jobs:"
+	cancelKey   = ":cancel"
+	jobIndexKey = "gqueue:replay:jobs"
+	// JobTTL is how long a job, finished or not, is kept
+	JobTTL = 7 * 24 * time.Hour
+)
+
+type Store struct {
+	cache *redis.Client
+}
+
+func NewStore(cache *redis.Client) *Store {
+	return &Store{cache: cache}
+}
+
+func (s *Store) Save(ctx context.Context, job Job) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal replay job: %w", err)
+	}
+
+	expired := time.Now().Add(-JobTTL).UnixMilli()
+
+	pipe := s.cache.TxPipeline()
+	pipe.Set(ctx, jobPrefix+job.ID.String(), payload, JobTTL)
+	pipe.ZAdd(ctx, jobIndexKey, redis.Z{Score: float64(job.CreatedAt.UnixMilli()), Member: job.ID.String()})
+	pipe.ZRemRangeByScore(ctx, jobIndexKey, "-inf", fmt.Sprint(expired))
+	pipe.Expire(ctx, jobIndexKey, JobTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save replay job: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id uuid.UUID) (Job, error) {
+	payload, err := s.cache.Get(ctx, jobPrefix+id.String()).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Job{}, ErrJobNotFound
+	}
+
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to get replay job: %w", err)
+	}
+
+	var job Job
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return Job{}, fmt.Errorf("failed to unmarshal replay job: %w", err)
+	}
+
+	return job, nil
+}
+
+// List returns the most recent jobs, newest first
+func (s *Store) List(ctx context.Context, limit int) ([]Job, error) {
+	ids, err := s.cache.ZRevRange(ctx, jobIndexKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replay jobs: %w", err)
+	}
+
+	output := make([]Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := s.Get(ctx, uuid.MustParse(id))
+		if errors.Is(err, ErrJobNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, job)
+	}
+
+	return output, nil
+}
+
+// RequestCancel flags the job so the instance running it stops at its next progress update
+func (s *Store) RequestCancel(ctx context.Context, id uuid.UUID) error {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if job.State.Finished() {
+		return ErrJobFinished
+	}
+
+	if err := s.cache.Set(ctx, jobPrefix+id.String()+cancelKey, "1", JobTTL).Err(); err != nil {
+		return fmt.Errorf("failed to cancel replay job: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) CancelRequested(ctx context.Context, id uuid.UUID) (bool, error) {
+	n, err := s.cache.Exists(ctx, jobPrefix+id.String()+cancelKey).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check replay job cancellation: %w", err)
+	}
+
+	return n > 0, nil
+}
@@ -2,40 +2,117 @@ package storests
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/IsaacDSC/gqueue/internal/cfg"
+	"github.com/IsaacDSC/gqueue/internal/domain"
 	"github.com/redis/go-redis/v9"
 )
 
 const (
 	insightsPrefix = "gqueue:insights"
 	separator      = ":"
+	// fieldSeparator splits the event, consumer and counter of a hash field, event and
+	// consumer names can contain the key separator
+	fieldSeparator = "\x1f"
+
+	fieldSuccess = "ok"
+	fieldFailure = "err"
+	// fieldLatency prefixes the counters of the bins of the latency sketch, e.g. "s342"
+	fieldLatency = "s"
+	// fieldLag prefixes the counters of the bins of the lag sketch of the deliveries
+	fieldLag = "l"
 )
 
+// level is a resolution the metrics are aggregated at, each one is kept for its own retention
+type level struct {
+	name       string
+	resolution time.Duration
+	layout     string
+	retention  time.Duration
+}
+
+// Retention is how long the buckets of each level are kept
+type Retention struct {
+	Minute time.Duration
+	Hour   time.Duration
+	Day    time.Duration
+}
+
+// Store aggregates the metrics as they are written in buckets of a minute, an hour and a day.
+// A bucket is a Redis hash with counters for every event and consumer, so its size depends on
+// the number of events and consumers and not on the traffic.
 type Store struct {
 	cache *redis.Client
+	// levels are sorted from the coarsest to the finest
+	levels []level
 }
 
-func NewStore(cache *redis.Client) *Store {
-	return &Store{cache: cache}
+func NewStore(cache *redis.Client, retention Retention) *Store {
+	return &Store{
+		cache: cache,
+		levels: []level{
+			{name: "day", resolution: 24 * time.Hour, layout: "20060102", retention: retention.Day},
+			{name: "hour", resolution: time.Hour, layout: "2006010215", retention: retention.Hour},
+			{name: "minute", resolution: time.Minute, layout: "200601021504", retention: retention.Minute},
+		},
+	}
 }
 
-func (s *Store) key(typeEvent string, values ...string) string {
-	v := []string{insightsPrefix, typeEvent}
-	v = append(v, values...)
-	v = append(v, strconv.Itoa(time.Now().UTC().Day()))
+// key is namespaced by the project in ctx, every project has its own insights
+func (s *Store) key(ctx context.Context, l level, start time.Time) string {
+	v := []string{domain.ProjectFromContext(ctx).Namespace(insightsPrefix), l.name, start.UTC().Format(l.layout)}
 	return strings.Join(v, separator)
 }
 
-func (s *Store) groupInsights(ctx context.Context, key string) error {
-	k := s.key("group-insights")
-	if err := s.cache.SAdd(ctx, k, key).Err(); err != nil {
-		return err
+// record adds the outcome of a publication or delivery to the bucket of every level, with the
+// bins of its sketches
+func (s *Store) record(ctx context.Context, series domain.Series, endedAt time.Time, ack bool, bins ...string) error {
+	if endedAt.IsZero() {
+		endedAt = time.Now()
+	}
+
+	outcome := fieldSuccess
+	if !ack {
+		outcome = fieldFailure
+	}
+
+	pipe := s.cache.Pipeline()
+	for _, l := range s.levels {
+		start := endedAt.UTC().Truncate(l.resolution)
+		key := s.key(ctx, l, start)
+		pipe.HIncrBy(ctx, key, field(series, outcome), 1)
+		for _, bin := range bins {
+			pipe.HIncrBy(ctx, key, field(series, bin), 1)
+		}
+		pipe.ExpireAt(ctx, key, start.Add(l.resolution+l.retention))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record insights: %w", err)
+	}
+
+	return nil
+}
+
+// sketchBin is the counter of the bin of the duration in the sketch of the prefix
+func sketchBin(prefix string, durationMs int64) string {
+	return prefix + strconv.Itoa(domain.LatencyBin(durationMs))
+}
+
+func field(series domain.Series, counter string) string {
+	return strings.Join([]string{series.Event, series.Consumer, counter}, fieldSeparator)
+}
+
+// parseField reads the series and counter of a hash field
+func parseField(f string) (domain.Series, string, error) {
+	parts := strings.Split(f, fieldSeparator)
+	if len(parts) != 3 {
+		return domain.Series{}, "", errors.New("invalid insights field")
 	}
 
-	conf := cfg.Get()
-	return s.cache.Expire(ctx, k, conf.Cache.DefaultTTL).Err()
+	return domain.Series{Event: parts[0], Consumer: parts[1]}, parts[2], nil
 }
@@ -0,0 +1,115 @@
+package storests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// GetRange reads the buckets of the window from the coarsest level the granularity of the window
+// is a multiple of. The cost of a read depends on the number of buckets and not on the traffic.
+func (s *Store) GetRange(ctx context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error) {
+	buckets := window.Buckets()
+	if len(buckets) == 0 {
+		return domain.MetricsBuckets{}, nil
+	}
+
+	l, err := s.level(window, buckets[0])
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := s.cache.Pipeline()
+	starts := make([]time.Time, 0)
+	results := make([]*redis.MapStringStringCmd, 0)
+	for start := buckets[0]; start.Before(window.To); start = start.Add(l.resolution) {
+		starts = append(starts, start)
+		results = append(results, pipe.HGetAll(ctx, s.key(ctx, l, start)))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get insights: %w", err)
+	}
+
+	output := make(domain.MetricsBuckets, 0, len(results))
+	for i, result := range results {
+		if len(result.Val()) == 0 {
+			continue
+		}
+
+		bucket, err := parseBucket(starts[i], result.Val(), window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse insights of %s: %w", starts[i], err)
+		}
+
+		output = append(output, bucket)
+	}
+
+	return output, nil
+}
+
+// level picks the coarsest level the granularity is a multiple of, it must still hold the
+// beginning of the window
+func (s *Store) level(window domain.InsightsWindow, first time.Time) (level, error) {
+	for _, l := range s.levels {
+		if window.Granularity%l.resolution != 0 {
+			continue
+		}
+
+		if oldest := time.Now().UTC().Add(-l.retention).Truncate(l.resolution); first.Before(oldest) {
+			return level{}, fmt.Errorf("%w: %s buckets are kept for %s, use a larger granularity or a later from", domain.InsightsNotRetained, l.name, l.retention)
+		}
+
+		return l, nil
+	}
+
+	return level{}, fmt.Errorf("granularity %s is not a multiple of a minute", window.Granularity)
+}
+
+func parseBucket(start time.Time, fields map[string]string, window domain.InsightsWindow) (domain.MetricsBucket, error) {
+	bucket := domain.MetricsBucket{Start: start, Series: make(map[domain.Series]domain.SeriesStats)}
+	for f, value := range fields {
+		series, counter, err := parseField(f)
+		if err != nil {
+			return domain.MetricsBucket{}, err
+		}
+
+		if !window.Match(series) {
+			continue
+		}
+
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return domain.MetricsBucket{}, fmt.Errorf("invalid counter %q: %w", f, err)
+		}
+
+		stats := bucket.Series[series]
+		switch {
+		case counter == fieldSuccess:
+			stats.Success += count
+		case counter == fieldFailure:
+			stats.Failure += count
+		case strings.HasPrefix(counter, fieldLatency):
+			bin, err := strconv.Atoi(strings.TrimPrefix(counter, fieldLatency))
+			if err != nil {
+				return domain.MetricsBucket{}, fmt.Errorf("invalid latency bin %q: %w", f, err)
+			}
+			stats.Latency.Add(bin, count)
+		case strings.HasPrefix(counter, fieldLag):
+			bin, err := strconv.Atoi(strings.TrimPrefix(counter, fieldLag))
+			if err != nil {
+				return domain.MetricsBucket{}, fmt.Errorf("invalid lag bin %q: %w", f, err)
+			}
+			stats.Lag.Add(bin, count)
+		}
+		bucket.Series[series] = stats
+	}
+
+	return bucket, nil
+}
@@ -0,0 +1,109 @@
+package storests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewStore(client, Retention{Minute: 48 * time.Hour, Hour: 30 * 24 * time.Hour, Day: 365 * 24 * time.Hour}), server
+}
+
+func TestStore_GetRange(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestStore(t)
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	earlier := now.Add(-90 * time.Minute)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, store.Published(ctx, domain.PublisherMetric{TopicName: "payment.processed", TimeEnded: now, TimeDurationMs: 4, ACK: i > 0}))
+	}
+	require.NoError(t, store.Published(ctx, domain.PublisherMetric{TopicName: "payment.processed", TimeEnded: earlier, TimeDurationMs: 40, ACK: true}))
+	require.NoError(t, store.Consumed(ctx, domain.ConsumerMetric{TopicName: "payment.processed", ConsumerName: "billing:v2", TimeStarted: now, TimeEnded: now, TimeDurationMs: 300, ACK: false, PublishedAt: now.Add(-1500 * time.Millisecond)}))
+	require.NoError(t, store.Published(ctx, domain.PublisherMetric{TopicName: "order.created", TimeEnded: now, ACK: true}))
+
+	t.Run("traffic does not grow the buckets", func(t *testing.T) {
+		key := store.key(ctx, store.levels[2], now)
+		fields, err := server.HKeys(key)
+		require.NoError(t, err)
+		assert.Len(t, fields, 8, "outcome and latency of 3 series, lag of the delivery")
+		assert.Equal(t, "99", server.HGet(key, field(domain.Series{Event: "payment.processed"}, fieldSuccess)))
+	})
+
+	t.Run("minute buckets of the window", func(t *testing.T) {
+		window := domain.InsightsWindow{From: now.Add(-time.Minute), To: now.Add(time.Minute), Granularity: time.Minute}
+		buckets, err := store.GetRange(ctx, window)
+		require.NoError(t, err)
+		require.Len(t, buckets, 1)
+
+		insights := buckets.Insights(window)
+		assert.Equal(t, int64(101), insights.Published.Total)
+		assert.Equal(t, int64(1), insights.Published.Failure)
+		assert.Equal(t, int64(1), insights.Consumed.Total)
+		require.Len(t, insights.Events, 2)
+		assert.Equal(t, "billing:v2", insights.Events[1].Consumers[0].Consumer)
+		assert.InEpsilon(t, 300, insights.Events[1].Consumers[0].Latency.P99, 0.01)
+		assert.InEpsilon(t, 1500, insights.Events[1].Consumers[0].Lag.P95, 0.01)
+	})
+
+	t.Run("hour buckets roll up the minutes", func(t *testing.T) {
+		window := domain.InsightsWindow{From: now.Add(-2 * time.Hour), To: now.Add(time.Minute), Granularity: time.Hour, Event: "payment.processed"}
+		buckets, err := store.GetRange(ctx, window)
+		require.NoError(t, err)
+
+		insights := buckets.Insights(window)
+		assert.Equal(t, int64(101), insights.Published.Total)
+		require.Len(t, insights.Events, 1)
+		assert.InEpsilon(t, 4, insights.Events[0].PublishLatency.P75, 0.01)
+		assert.InEpsilon(t, 40, insights.Events[0].PublishLatency.P999, 0.01)
+	})
+
+	t.Run("consumer", func(t *testing.T) {
+		window := domain.InsightsWindow{From: now.Add(-time.Minute), To: now.Add(time.Minute), Granularity: 2 * time.Minute, Consumer: "billing:v2"}
+		buckets, err := store.GetRange(ctx, window)
+		require.NoError(t, err)
+
+		insights := buckets.Insights(window)
+		assert.Equal(t, int64(0), insights.Published.Total)
+		assert.Equal(t, int64(1), insights.Consumed.Failure)
+	})
+
+	t.Run("expired level", func(t *testing.T) {
+		from := now.Add(-72 * time.Hour)
+		window := domain.InsightsWindow{From: from, To: from.Add(time.Hour), Granularity: time.Minute}
+		_, err := store.GetRange(ctx, window)
+		assert.ErrorIs(t, err, domain.InsightsNotRetained)
+
+		window.Granularity = time.Hour
+		_, err = store.GetRange(ctx, window)
+		assert.NoError(t, err)
+	})
+
+	t.Run("buckets expire after their retention", func(t *testing.T) {
+		key := store.key(ctx, store.levels[2], now)
+		ttl := server.TTL(key)
+		assert.True(t, ttl > 47*time.Hour && ttl <= 48*time.Hour+time.Minute, ttl)
+	})
+
+	t.Run("projects are isolated", func(t *testing.T) {
+		acme := domain.WithProject(ctx, domain.Project{ID: "acme", TopicPrefix: "acme"})
+
+		buckets, err := store.GetRange(acme, domain.InsightsWindow{From: earlier, To: now.Add(time.Minute), Granularity: time.Minute})
+		require.NoError(t, err)
+		assert.Empty(t, buckets)
+	})
+}
@@ -0,0 +1,84 @@
+package timeline
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/IsaacDSC/gqueue/pkg/logs"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+)
+
+// writeTimeout bounds the write of one entry, a slow Redis delays the timelines and not the messages
+const writeTimeout = 2 * time.Second
+
+type Writer interface {
+	Append(ctx context.Context, entry domain.TimelineEntry) error
+}
+
+type record struct {
+	project domain.Project
+	entry   domain.TimelineEntry
+}
+
+// Recorder writes timeline entries in the background. Record never blocks: when the buffer is
+// full the entry is dropped and counted, a timeline is a support tool and must not slow down
+// publications or deliveries.
+type Recorder struct {
+	writer  Writer
+	records chan record
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+func NewRecorder(writer Writer, buffer int) *Recorder {
+	r := &Recorder{writer: writer, records: make(chan record, buffer)}
+
+	r.wg.Add(1)
+	go r.run()
+
+	return r
+}
+
+// Record queues the entry in the project of ctx, entries without a message id are ignored
+func (r *Recorder) Record(ctx context.Context, entry domain.TimelineEntry) {
+	if entry.MessageID == "" {
+		return
+	}
+
+	if entry.At.IsZero() {
+		entry.At = time.Now().UTC()
+	}
+
+	select {
+	case r.records <- record{project: domain.ProjectFromContext(ctx), entry: entry}:
+	default:
+		telemetry.TimelineDropped.Count(ctx, 1)
+	}
+}
+
+// Close writes the queued entries and stops the recorder, Record must not be called after it
+func (r *Recorder) Close() {
+	r.once.Do(func() { close(r.records) })
+	r.wg.Wait()
+}
+
+func (r *Recorder) run() {
+	defer r.wg.Done()
+
+	for rec := range r.records {
+		ctx, cancel := context.WithTimeout(domain.WithProject(context.Background(), rec.project), writeTimeout)
+		if err := r.writer.Append(ctx, rec.entry); err != nil {
+			logs.Warn("failed to record timeline entry", "message_id", rec.entry.MessageID, "stage", rec.entry.Stage, "error", err)
+		}
+		cancel()
+	}
+}
+
+type discard struct{}
+
+func (discard) Record(context.Context, domain.TimelineEntry) {}
+
+// Discard records nothing, for processes that keep no timelines
+var Discard = discard{}
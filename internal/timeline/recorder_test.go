@@ -0,0 +1,57 @@
+package timeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	store, _ := newTestStore(t)
+	recorder := NewRecorder(store, 10)
+
+	acme := domain.WithProject(context.Background(), domain.Project{ID: "acme", TopicPrefix: "acme"})
+	recorder.Record(acme, domain.TimelineEntry{MessageID: "msg-1", Stage: domain.StageAccepted})
+	recorder.Record(acme, domain.TimelineEntry{Stage: domain.StageAccepted})
+	recorder.Close()
+
+	timeline, err := store.Get(acme, "msg-1")
+	require.NoError(t, err)
+	require.Len(t, timeline, 1, "entries without a message id are ignored")
+	assert.False(t, timeline[0].At.IsZero())
+}
+
+type blockedWriter struct {
+	release chan struct{}
+}
+
+func (w blockedWriter) Append(context.Context, domain.TimelineEntry) error {
+	<-w.release
+	return nil
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	writer := blockedWriter{release: make(chan struct{})}
+	recorder := NewRecorder(writer, 1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			recorder.Record(context.Background(), domain.TimelineEntry{MessageID: "msg-1", Stage: domain.StageAccepted})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a full buffer")
+	}
+
+	close(writer.release)
+	recorder.Close()
+}
@@ -0,0 +1,81 @@
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	timelinePrefix = "gqueue:timeline:"
+	// maxEntries bounds the timeline of a message retried or replayed endlessly, the oldest
+	// entries are dropped first
+	maxEntries = 500
+)
+
+// Store keeps the timeline of every message in a Redis list expiring ttl after its last entry.
+// The keys are namespaced by the project in ctx.
+type Store struct {
+	cache *redis.Client
+	ttl   time.Duration
+}
+
+func NewStore(cache *redis.Client, ttl time.Duration) *Store {
+	return &Store{cache: cache, ttl: ttl}
+}
+
+func key(ctx context.Context, messageID string) string {
+	return domain.ProjectFromContext(ctx).Namespace(timelinePrefix + messageID)
+}
+
+func (s *Store) Append(ctx context.Context, entry domain.TimelineEntry) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal timeline entry: %w", err)
+	}
+
+	k := key(ctx, entry.MessageID)
+
+	pipe := s.cache.Pipeline()
+	pipe.RPush(ctx, k, payload)
+	pipe.LTrim(ctx, k, -maxEntries, -1)
+	pipe.Expire(ctx, k, s.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to append timeline entry: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the timeline of the message sorted by time. Entries are written asynchronously by
+// several instances, so the order they were appended in is not the order they happened in.
+func (s *Store) Get(ctx context.Context, messageID string) ([]domain.TimelineEntry, error) {
+	values, err := s.cache.LRange(ctx, key(ctx, messageID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timeline: %w", err)
+	}
+
+	if len(values) == 0 {
+		return nil, domain.TimelineNotFound
+	}
+
+	entries := make([]domain.TimelineEntry, 0, len(values))
+	for _, value := range values {
+		var entry domain.TimelineEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal timeline entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+
+	return entries, nil
+}
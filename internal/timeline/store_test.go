@@ -0,0 +1,98 @@
+package timeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/domain"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewStore(client, 72*time.Hour), server
+}
+
+func TestStore_Get(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestStore(t)
+
+	published := time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC)
+
+	// entries of several instances are appended out of order
+	entries := []domain.TimelineEntry{
+		{MessageID: "msg-1", Stage: domain.StageDeliveryAttempt, At: published.Add(2 * time.Second), Consumer: "billing", Attempt: 1, Outcome: domain.OutcomeFailure},
+		{MessageID: "msg-1", Stage: domain.StageAccepted, At: published, EventName: "payment.processed"},
+		{MessageID: "msg-1", Stage: domain.StageEnqueued, At: published.Add(time.Millisecond), Consumer: "billing"},
+		{MessageID: "msg-2", Stage: domain.StageAccepted, At: published},
+	}
+	for _, entry := range entries {
+		require.NoError(t, store.Append(ctx, entry))
+	}
+
+	t.Run("sorted by time", func(t *testing.T) {
+		timeline, err := store.Get(ctx, "msg-1")
+		require.NoError(t, err)
+		require.Len(t, timeline, 3)
+		assert.Equal(t, domain.StageAccepted, timeline[0].Stage)
+		assert.Equal(t, domain.StageEnqueued, timeline[1].Stage)
+		assert.Equal(t, domain.StageDeliveryAttempt, timeline[2].Stage)
+		assert.Equal(t, domain.OutcomeFailure, timeline[2].Outcome)
+	})
+
+	t.Run("expires after the last entry", func(t *testing.T) {
+		assert.Equal(t, 72*time.Hour, server.TTL(key(ctx, "msg-1")))
+
+		server.FastForward(73 * time.Hour)
+		_, err := store.Get(ctx, "msg-1")
+		assert.ErrorIs(t, err, domain.TimelineNotFound)
+	})
+
+	t.Run("unknown message", func(t *testing.T) {
+		_, err := store.Get(ctx, "unknown")
+		assert.ErrorIs(t, err, domain.TimelineNotFound)
+	})
+}
+
+func TestStore_AppendKeepsLastEntries(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStore(t)
+
+	started := time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC)
+	for i := 0; i < maxEntries+10; i++ {
+		require.NoError(t, store.Append(ctx, domain.TimelineEntry{
+			MessageID: "msg-1",
+			Stage:     domain.StageDeliveryAttempt,
+			At:        started.Add(time.Duration(i) * time.Second),
+			Attempt:   i + 1,
+		}))
+	}
+
+	timeline, err := store.Get(ctx, "msg-1")
+	require.NoError(t, err)
+	require.Len(t, timeline, maxEntries)
+	assert.Equal(t, 11, timeline[0].Attempt)
+}
+
+func TestStore_ProjectIsolation(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	acme := domain.WithProject(context.Background(), domain.Project{ID: "acme", TopicPrefix: "acme"})
+	require.NoError(t, store.Append(acme, domain.TimelineEntry{MessageID: "msg-1", Stage: domain.StageAccepted, At: time.Now()}))
+
+	_, err := store.Get(context.Background(), "msg-1")
+	assert.ErrorIs(t, err, domain.TimelineNotFound)
+
+	timeline, err := store.Get(acme, "msg-1")
+	require.NoError(t, err)
+	assert.Len(t, timeline, 1)
+}
@@ -0,0 +1,203 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/alerting/evaluator.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/alerting/evaluator.go -destination=./mocks/mockalerting/mock_evaluator.go -package=mockalerting
+//
+
+// Package mockalerting is a generated GoMock package.
+package mockalerting
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRuleStore is a mock of RuleStore interface.
+type MockRuleStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRuleStoreMockRecorder is the mock recorder for MockRuleStore.
+type MockRuleStoreMockRecorder struct {
+	mock *MockRuleStore
+}
+
+// NewMockRuleStore creates a new mock instance.
+func NewMockRuleStore(ctrl *gomock.Controller) *MockRuleStore {
+	mock := &MockRuleStore{ctrl: ctrl}
+	mock.recorder = &MockRuleStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleStore) EXPECT() *MockRuleStoreMockRecorder {
+	return m.recorder
+}
+
+// GetInternalEvent mocks base method.
+func (m *MockRuleStore) GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalEvent", ctx, eventName)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalEvent indicates an expected call of GetInternalEvent.
+func (mr *MockRuleStoreMockRecorder) GetInternalEvent(ctx, eventName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalEvent", reflect.TypeOf((*MockRuleStore)(nil).GetInternalEvent), ctx, eventName)
+}
+
+// ListAlertRules mocks base method.
+func (m *MockRuleStore) ListAlertRules(ctx context.Context) ([]domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertRules", ctx)
+	ret0, _ := ret[0].([]domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertRules indicates an expected call of ListAlertRules.
+func (mr *MockRuleStoreMockRecorder) ListAlertRules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertRules", reflect.TypeOf((*MockRuleStore)(nil).ListAlertRules), ctx)
+}
+
+// ListProjects mocks base method.
+func (m *MockRuleStore) ListProjects(ctx context.Context) ([]domain.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjects", ctx)
+	ret0, _ := ret[0].([]domain.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjects indicates an expected call of ListProjects.
+func (mr *MockRuleStoreMockRecorder) ListProjects(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockRuleStore)(nil).ListProjects), ctx)
+}
+
+// MockInsightsStore is a mock of InsightsStore interface.
+type MockInsightsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockInsightsStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockInsightsStoreMockRecorder is the mock recorder for MockInsightsStore.
+type MockInsightsStoreMockRecorder struct {
+	mock *MockInsightsStore
+}
+
+// NewMockInsightsStore creates a new mock instance.
+func NewMockInsightsStore(ctrl *gomock.Controller) *MockInsightsStore {
+	mock := &MockInsightsStore{ctrl: ctrl}
+	mock.recorder = &MockInsightsStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInsightsStore) EXPECT() *MockInsightsStoreMockRecorder {
+	return m.recorder
+}
+
+// GetRange mocks base method.
+func (m *MockInsightsStore) GetRange(ctx context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRange", ctx, window)
+	ret0, _ := ret[0].(domain.MetricsBuckets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRange indicates an expected call of GetRange.
+func (mr *MockInsightsStoreMockRecorder) GetRange(ctx, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockInsightsStore)(nil).GetRange), ctx, window)
+}
+
+// MockDeadLetterStore is a mock of DeadLetterStore interface.
+type MockDeadLetterStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterStoreMockRecorder is the mock recorder for MockDeadLetterStore.
+type MockDeadLetterStoreMockRecorder struct {
+	mock *MockDeadLetterStore
+}
+
+// NewMockDeadLetterStore creates a new mock instance.
+func NewMockDeadLetterStore(ctrl *gomock.Controller) *MockDeadLetterStore {
+	mock := &MockDeadLetterStore{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterStore) EXPECT() *MockDeadLetterStoreMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockDeadLetterStore) List(ctx context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filters)
+	ret0, _ := ret[0].([]domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeadLetterStoreMockRecorder) List(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeadLetterStore)(nil).List), ctx, filters)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// NotifyScheduler mocks base method.
+func (m *MockNotifier) NotifyScheduler(ctx context.Context, url string, data any, headers map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyScheduler", ctx, url, data, headers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyScheduler indicates an expected call of NotifyScheduler.
+func (mr *MockNotifierMockRecorder) NotifyScheduler(ctx, url, data, headers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyScheduler", reflect.TypeOf((*MockNotifier)(nil).NotifyScheduler), ctx, url, data, headers)
+}
@@ -0,0 +1,231 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/backlog/collector.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/backlog/collector.go -destination=./mocks/mockbacklog/mock_collector.go -package=mockbacklog
+//
+
+// Package mockbacklog is a generated GoMock package.
+package mockbacklog
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	asynq "github.com/hibiken/asynq"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInspector is a mock of Inspector interface.
+type MockInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockInspectorMockRecorder
+	isgomock struct{}
+}
+
+// MockInspectorMockRecorder is the mock recorder for MockInspector.
+type MockInspectorMockRecorder struct {
+	mock *MockInspector
+}
+
+// NewMockInspector creates a new mock instance.
+func NewMockInspector(ctrl *gomock.Controller) *MockInspector {
+	mock := &MockInspector{ctrl: ctrl}
+	mock.recorder = &MockInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInspector) EXPECT() *MockInspectorMockRecorder {
+	return m.recorder
+}
+
+// GetQueueInfo mocks base method.
+func (m *MockInspector) GetQueueInfo(queue string) (*asynq.QueueInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueueInfo", queue)
+	ret0, _ := ret[0].(*asynq.QueueInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueueInfo indicates an expected call of GetQueueInfo.
+func (mr *MockInspectorMockRecorder) GetQueueInfo(queue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueInfo", reflect.TypeOf((*MockInspector)(nil).GetQueueInfo), queue)
+}
+
+// ListArchivedTasks mocks base method.
+func (m *MockInspector) ListArchivedTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{queue}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListArchivedTasks", varargs...)
+	ret0, _ := ret[0].([]*asynq.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchivedTasks indicates an expected call of ListArchivedTasks.
+func (mr *MockInspectorMockRecorder) ListArchivedTasks(queue any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{queue}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchivedTasks", reflect.TypeOf((*MockInspector)(nil).ListArchivedTasks), varargs...)
+}
+
+// ListPendingTasks mocks base method.
+func (m *MockInspector) ListPendingTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{queue}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPendingTasks", varargs...)
+	ret0, _ := ret[0].([]*asynq.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingTasks indicates an expected call of ListPendingTasks.
+func (mr *MockInspectorMockRecorder) ListPendingTasks(queue any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{queue}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingTasks", reflect.TypeOf((*MockInspector)(nil).ListPendingTasks), varargs...)
+}
+
+// ListRetryTasks mocks base method.
+func (m *MockInspector) ListRetryTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{queue}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListRetryTasks", varargs...)
+	ret0, _ := ret[0].([]*asynq.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRetryTasks indicates an expected call of ListRetryTasks.
+func (mr *MockInspectorMockRecorder) ListRetryTasks(queue any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{queue}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRetryTasks", reflect.TypeOf((*MockInspector)(nil).ListRetryTasks), varargs...)
+}
+
+// ListScheduledTasks mocks base method.
+func (m *MockInspector) ListScheduledTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{queue}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListScheduledTasks", varargs...)
+	ret0, _ := ret[0].([]*asynq.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTasks indicates an expected call of ListScheduledTasks.
+func (mr *MockInspectorMockRecorder) ListScheduledTasks(queue any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{queue}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTasks", reflect.TypeOf((*MockInspector)(nil).ListScheduledTasks), varargs...)
+}
+
+// Queues mocks base method.
+func (m *MockInspector) Queues() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Queues")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Queues indicates an expected call of Queues.
+func (mr *MockInspectorMockRecorder) Queues() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queues", reflect.TypeOf((*MockInspector)(nil).Queues))
+}
+
+// MockEventStore is a mock of EventStore interface.
+type MockEventStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockEventStoreMockRecorder is the mock recorder for MockEventStore.
+type MockEventStoreMockRecorder struct {
+	mock *MockEventStore
+}
+
+// NewMockEventStore creates a new mock instance.
+func NewMockEventStore(ctrl *gomock.Controller) *MockEventStore {
+	mock := &MockEventStore{ctrl: ctrl}
+	mock.recorder = &MockEventStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStore) EXPECT() *MockEventStoreMockRecorder {
+	return m.recorder
+}
+
+// ListProjects mocks base method.
+func (m *MockEventStore) ListProjects(ctx context.Context) ([]domain.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjects", ctx)
+	ret0, _ := ret[0].([]domain.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjects indicates an expected call of ListProjects.
+func (mr *MockEventStoreMockRecorder) ListProjects(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockEventStore)(nil).ListProjects), ctx)
+}
+
+// MockSubscriptionReader is a mock of SubscriptionReader interface.
+type MockSubscriptionReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockSubscriptionReaderMockRecorder is the mock recorder for MockSubscriptionReader.
+type MockSubscriptionReaderMockRecorder struct {
+	mock *MockSubscriptionReader
+}
+
+// NewMockSubscriptionReader creates a new mock instance.
+func NewMockSubscriptionReader(ctrl *gomock.Controller) *MockSubscriptionReader {
+	mock := &MockSubscriptionReader{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionReader) EXPECT() *MockSubscriptionReaderMockRecorder {
+	return m.recorder
+}
+
+// Backlog mocks base method.
+func (m *MockSubscriptionReader) Backlog(ctx context.Context, subscriptions []string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backlog", ctx, subscriptions)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backlog indicates an expected call of Backlog.
+func (mr *MockSubscriptionReaderMockRecorder) Backlog(ctx, subscriptions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backlog", reflect.TypeOf((*MockSubscriptionReader)(nil).Backlog), ctx, subscriptions)
+}
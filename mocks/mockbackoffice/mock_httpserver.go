@@ -41,17 +41,17 @@ func (m *MockInsightsStore) EXPECT() *MockInsightsStoreMockRecorder {
 	return m.recorder
 }
 
-// GetAll mocks base method.
-func (m *MockInsightsStore) GetAll(ctx context.Context) (domain.Metrics, error) {
+// GetRange mocks base method.
+func (m *MockInsightsStore) GetRange(ctx context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx)
-	ret0, _ := ret[0].(domain.Metrics)
+	ret := m.ctrl.Call(m, "GetRange", ctx, window)
+	ret0, _ := ret[0].(domain.MetricsBuckets)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockInsightsStoreMockRecorder) GetAll(ctx any) *gomock.Call {
+// GetRange indicates an expected call of GetRange.
+func (mr *MockInsightsStoreMockRecorder) GetRange(ctx, window any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockInsightsStore)(nil).GetAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockInsightsStore)(nil).GetRange), ctx, window)
 }
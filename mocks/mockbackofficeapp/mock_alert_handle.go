@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/alert_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/alert_handle.go -destination=./mocks/mockbackofficeapp/mock_alert_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAlertRuleRepository is a mock of AlertRuleRepository interface.
+type MockAlertRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertRuleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertRuleRepositoryMockRecorder is the mock recorder for MockAlertRuleRepository.
+type MockAlertRuleRepositoryMockRecorder struct {
+	mock *MockAlertRuleRepository
+}
+
+// NewMockAlertRuleRepository creates a new mock instance.
+func NewMockAlertRuleRepository(ctrl *gomock.Controller) *MockAlertRuleRepository {
+	mock := &MockAlertRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockAlertRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertRuleRepository) EXPECT() *MockAlertRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAlertRule mocks base method.
+func (m *MockAlertRuleRepository) CreateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlertRule", ctx, rule)
+	ret0, _ := ret[0].(domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAlertRule indicates an expected call of CreateAlertRule.
+func (mr *MockAlertRuleRepositoryMockRecorder) CreateAlertRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlertRule", reflect.TypeOf((*MockAlertRuleRepository)(nil).CreateAlertRule), ctx, rule)
+}
+
+// DeleteAlertRule mocks base method.
+func (m *MockAlertRuleRepository) DeleteAlertRule(ctx context.Context, ruleID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlertRule", ctx, ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlertRule indicates an expected call of DeleteAlertRule.
+func (mr *MockAlertRuleRepositoryMockRecorder) DeleteAlertRule(ctx, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlertRule", reflect.TypeOf((*MockAlertRuleRepository)(nil).DeleteAlertRule), ctx, ruleID)
+}
+
+// GetAlertRule mocks base method.
+func (m *MockAlertRuleRepository) GetAlertRule(ctx context.Context, ruleID uuid.UUID) (domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlertRule", ctx, ruleID)
+	ret0, _ := ret[0].(domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlertRule indicates an expected call of GetAlertRule.
+func (mr *MockAlertRuleRepositoryMockRecorder) GetAlertRule(ctx, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlertRule", reflect.TypeOf((*MockAlertRuleRepository)(nil).GetAlertRule), ctx, ruleID)
+}
+
+// ListAlertRules mocks base method.
+func (m *MockAlertRuleRepository) ListAlertRules(ctx context.Context) ([]domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAlertRules", ctx)
+	ret0, _ := ret[0].([]domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAlertRules indicates an expected call of ListAlertRules.
+func (mr *MockAlertRuleRepositoryMockRecorder) ListAlertRules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAlertRules", reflect.TypeOf((*MockAlertRuleRepository)(nil).ListAlertRules), ctx)
+}
+
+// UpdateAlertRule mocks base method.
+func (m *MockAlertRuleRepository) UpdateAlertRule(ctx context.Context, rule domain.AlertRule) (domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlertRule", ctx, rule)
+	ret0, _ := ret[0].(domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAlertRule indicates an expected call of UpdateAlertRule.
+func (mr *MockAlertRuleRepositoryMockRecorder) UpdateAlertRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlertRule", reflect.TypeOf((*MockAlertRuleRepository)(nil).UpdateAlertRule), ctx, rule)
+}
+
+// MockAlertStateStore is a mock of AlertStateStore interface.
+type MockAlertStateStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertStateStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertStateStoreMockRecorder is the mock recorder for MockAlertStateStore.
+type MockAlertStateStoreMockRecorder struct {
+	mock *MockAlertStateStore
+}
+
+// NewMockAlertStateStore creates a new mock instance.
+func NewMockAlertStateStore(ctrl *gomock.Controller) *MockAlertStateStore {
+	mock := &MockAlertStateStore{ctrl: ctrl}
+	mock.recorder = &MockAlertStateStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertStateStore) EXPECT() *MockAlertStateStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockAlertStateStore) Delete(ctx context.Context, ruleID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAlertStateStoreMockRecorder) Delete(ctx, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAlertStateStore)(nil).Delete), ctx, ruleID)
+}
+
+// List mocks base method.
+func (m *MockAlertStateStore) List(ctx context.Context) (map[uuid.UUID]domain.AlertState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].(map[uuid.UUID]domain.AlertState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAlertStateStoreMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAlertStateStore)(nil).List), ctx)
+}
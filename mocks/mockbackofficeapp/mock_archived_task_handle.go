@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/archived_task_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/archived_task_handle.go -destination=./mocks/mockbackofficeapp/mock_archived_task_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	taskapp "github.com/IsaacDSC/gqueue/internal/app/taskapp"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArchivedTaskManager is a mock of ArchivedTaskManager interface.
+type MockArchivedTaskManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchivedTaskManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockArchivedTaskManagerMockRecorder is the mock recorder for MockArchivedTaskManager.
+type MockArchivedTaskManagerMockRecorder struct {
+	mock *MockArchivedTaskManager
+}
+
+// NewMockArchivedTaskManager creates a new mock instance.
+func NewMockArchivedTaskManager(ctrl *gomock.Controller) *MockArchivedTaskManager {
+	mock := &MockArchivedTaskManager{ctrl: ctrl}
+	mock.recorder = &MockArchivedTaskManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchivedTaskManager) EXPECT() *MockArchivedTaskManagerMockRecorder {
+	return m.recorder
+}
+
+// ArchivedQueues mocks base method.
+func (m *MockArchivedTaskManager) ArchivedQueues(ctx context.Context) ([]taskapp.ArchivedQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchivedQueues", ctx)
+	ret0, _ := ret[0].([]taskapp.ArchivedQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchivedQueues indicates an expected call of ArchivedQueues.
+func (mr *MockArchivedTaskManagerMockRecorder) ArchivedQueues(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchivedQueues", reflect.TypeOf((*MockArchivedTaskManager)(nil).ArchivedQueues), ctx)
+}
+
+// Delete mocks base method.
+func (m *MockArchivedTaskManager) Delete(ctx context.Context, queue string, ids ...string) taskapp.BatchResult {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, queue}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(taskapp.BatchResult)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockArchivedTaskManagerMockRecorder) Delete(ctx, queue any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, queue}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArchivedTaskManager)(nil).Delete), varargs...)
+}
+
+// DeleteTask mocks base method.
+func (m *MockArchivedTaskManager) DeleteTask(ctx context.Context, queue, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, queue, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockArchivedTaskManagerMockRecorder) DeleteTask(ctx, queue, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockArchivedTaskManager)(nil).DeleteTask), ctx, queue, id)
+}
+
+// GetArchived mocks base method.
+func (m *MockArchivedTaskManager) GetArchived(ctx context.Context, queue, id string) (taskapp.ArchivedTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchived", ctx, queue, id)
+	ret0, _ := ret[0].(taskapp.ArchivedTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchived indicates an expected call of GetArchived.
+func (mr *MockArchivedTaskManagerMockRecorder) GetArchived(ctx, queue, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchived", reflect.TypeOf((*MockArchivedTaskManager)(nil).GetArchived), ctx, queue, id)
+}
+
+// ListArchived mocks base method.
+func (m *MockArchivedTaskManager) ListArchived(ctx context.Context, queue string, page, limit int) (taskapp.ArchivedTasksPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArchived", ctx, queue, page, limit)
+	ret0, _ := ret[0].(taskapp.ArchivedTasksPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchived indicates an expected call of ListArchived.
+func (mr *MockArchivedTaskManagerMockRecorder) ListArchived(ctx, queue, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchived", reflect.TypeOf((*MockArchivedTaskManager)(nil).ListArchived), ctx, queue, page, limit)
+}
+
+// Requeue mocks base method.
+func (m *MockArchivedTaskManager) Requeue(ctx context.Context, queue string, ids ...string) taskapp.BatchResult {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, queue}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Requeue", varargs...)
+	ret0, _ := ret[0].(taskapp.BatchResult)
+	return ret0
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockArchivedTaskManagerMockRecorder) Requeue(ctx, queue any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, queue}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockArchivedTaskManager)(nil).Requeue), varargs...)
+}
+
+// RequeueTask mocks base method.
+func (m *MockArchivedTaskManager) RequeueTask(ctx context.Context, queue, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueTask", ctx, queue, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueTask indicates an expected call of RequeueTask.
+func (mr *MockArchivedTaskManagerMockRecorder) RequeueTask(ctx, queue, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueTask", reflect.TypeOf((*MockArchivedTaskManager)(nil).RequeueTask), ctx, queue, id)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/consumer_health_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/consumer_health_handle.go -destination=./mocks/mockbackofficeapp/mock_consumer_health_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConsumerHealthMonitor is a mock of ConsumerHealthMonitor interface.
+type MockConsumerHealthMonitor struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerHealthMonitorMockRecorder
+	isgomock struct{}
+}
+
+// MockConsumerHealthMonitorMockRecorder is the mock recorder for MockConsumerHealthMonitor.
+type MockConsumerHealthMonitorMockRecorder struct {
+	mock *MockConsumerHealthMonitor
+}
+
+// NewMockConsumerHealthMonitor creates a new mock instance.
+func NewMockConsumerHealthMonitor(ctrl *gomock.Controller) *MockConsumerHealthMonitor {
+	mock := &MockConsumerHealthMonitor{ctrl: ctrl}
+	mock.recorder = &MockConsumerHealthMonitorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumerHealthMonitor) EXPECT() *MockConsumerHealthMonitorMockRecorder {
+	return m.recorder
+}
+
+// Health mocks base method.
+func (m *MockConsumerHealthMonitor) Health(ctx context.Context, now time.Time) ([]domain.ConsumerHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health", ctx, now)
+	ret0, _ := ret[0].([]domain.ConsumerHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockConsumerHealthMonitorMockRecorder) Health(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockConsumerHealthMonitor)(nil).Health), ctx, now)
+}
+
+// Release mocks base method.
+func (m *MockConsumerHealthMonitor) Release(ctx context.Context, event, consumer string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, event, consumer, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockConsumerHealthMonitorMockRecorder) Release(ctx, event, consumer, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockConsumerHealthMonitor)(nil).Release), ctx, event, consumer, now)
+}
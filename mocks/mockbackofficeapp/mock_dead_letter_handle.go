@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/dead_letter_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/dead_letter_handle.go -destination=./mocks/mockbackofficeapp/mock_dead_letter_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeadLetterStore is a mock of DeadLetterStore interface.
+type MockDeadLetterStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterStoreMockRecorder is the mock recorder for MockDeadLetterStore.
+type MockDeadLetterStoreMockRecorder struct {
+	mock *MockDeadLetterStore
+}
+
+// NewMockDeadLetterStore creates a new mock instance.
+func NewMockDeadLetterStore(ctrl *gomock.Controller) *MockDeadLetterStore {
+	mock := &MockDeadLetterStore{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterStore) EXPECT() *MockDeadLetterStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockDeadLetterStore) Delete(ctx context.Context, ids ...uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeadLetterStoreMockRecorder) Delete(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeadLetterStore)(nil).Delete), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockDeadLetterStore) Get(ctx context.Context, id uuid.UUID) (domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeadLetterStoreMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeadLetterStore)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockDeadLetterStore) List(ctx context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filters)
+	ret0, _ := ret[0].([]domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeadLetterStoreMockRecorder) List(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeadLetterStore)(nil).List), ctx, filters)
+}
+
+// Purge mocks base method.
+func (m *MockDeadLetterStore) Purge(ctx context.Context, filters domain.FilterDeadLetters) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, filters)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockDeadLetterStoreMockRecorder) Purge(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockDeadLetterStore)(nil).Purge), ctx, filters)
+}
+
+// MockDeadLetterReplayer is a mock of DeadLetterReplayer interface.
+type MockDeadLetterReplayer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterReplayerMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterReplayerMockRecorder is the mock recorder for MockDeadLetterReplayer.
+type MockDeadLetterReplayerMockRecorder struct {
+	mock *MockDeadLetterReplayer
+}
+
+// NewMockDeadLetterReplayer creates a new mock instance.
+func NewMockDeadLetterReplayer(ctrl *gomock.Controller) *MockDeadLetterReplayer {
+	mock := &MockDeadLetterReplayer{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterReplayerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterReplayer) EXPECT() *MockDeadLetterReplayerMockRecorder {
+	return m.recorder
+}
+
+// Replay mocks base method.
+func (m *MockDeadLetterReplayer) Replay(ctx context.Context, dl domain.DeadLetter, allConsumers bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, dl, allConsumers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockDeadLetterReplayerMockRecorder) Replay(ctx, dl, allConsumers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockDeadLetterReplayer)(nil).Replay), ctx, dl, allConsumers)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/event_consumer_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/event_consumer_handle.go -destination=./mocks/mockbackofficeapp/mock_event_consumer_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConsumerRepository is a mock of ConsumerRepository interface.
+type MockConsumerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConsumerRepositoryMockRecorder is the mock recorder for MockConsumerRepository.
+type MockConsumerRepositoryMockRecorder struct {
+	mock *MockConsumerRepository
+}
+
+// NewMockConsumerRepository creates a new mock instance.
+func NewMockConsumerRepository(ctrl *gomock.Controller) *MockConsumerRepository {
+	mock := &MockConsumerRepository{ctrl: ctrl}
+	mock.recorder = &MockConsumerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumerRepository) EXPECT() *MockConsumerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetEventByID mocks base method.
+func (m *MockConsumerRepository) GetEventByID(ctx context.Context, eventID uuid.UUID) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventByID", ctx, eventID)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventByID indicates an expected call of GetEventByID.
+func (mr *MockConsumerRepositoryMockRecorder) GetEventByID(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockConsumerRepository)(nil).GetEventByID), ctx, eventID)
+}
+
+// UpdateEventIfRevision mocks base method.
+func (m *MockConsumerRepository) UpdateEventIfRevision(ctx context.Context, event domain.Event, revision int) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventIfRevision", ctx, event, revision)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEventIfRevision indicates an expected call of UpdateEventIfRevision.
+func (mr *MockConsumerRepositoryMockRecorder) UpdateEventIfRevision(ctx, event, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventIfRevision", reflect.TypeOf((*MockConsumerRepository)(nil).UpdateEventIfRevision), ctx, event, revision)
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/event_revision_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/event_revision_handle.go -destination=./mocks/mockbackofficeapp/mock_event_revision_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRevisionRepository is a mock of RevisionRepository interface.
+type MockRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRevisionRepositoryMockRecorder is the mock recorder for MockRevisionRepository.
+type MockRevisionRepositoryMockRecorder struct {
+	mock *MockRevisionRepository
+}
+
+// NewMockRevisionRepository creates a new mock instance.
+func NewMockRevisionRepository(ctrl *gomock.Controller) *MockRevisionRepository {
+	mock := &MockRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionRepository) EXPECT() *MockRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetRevision mocks base method.
+func (m *MockRevisionRepository) GetRevision(ctx context.Context, eventID uuid.UUID, revision int) (domain.EventRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, eventID, revision)
+	ret0, _ := ret[0].(domain.EventRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockRevisionRepositoryMockRecorder) GetRevision(ctx, eventID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockRevisionRepository)(nil).GetRevision), ctx, eventID, revision)
+}
+
+// ListRevisions mocks base method.
+func (m *MockRevisionRepository) ListRevisions(ctx context.Context, eventID uuid.UUID) ([]domain.EventRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, eventID)
+	ret0, _ := ret[0].([]domain.EventRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockRevisionRepositoryMockRecorder) ListRevisions(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockRevisionRepository)(nil).ListRevisions), ctx, eventID)
+}
+
+// RollbackEvent mocks base method.
+func (m *MockRevisionRepository) RollbackEvent(ctx context.Context, eventID uuid.UUID, revision int) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackEvent", ctx, eventID, revision)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackEvent indicates an expected call of RollbackEvent.
+func (mr *MockRevisionRepositoryMockRecorder) RollbackEvent(ctx, eventID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackEvent", reflect.TypeOf((*MockRevisionRepository)(nil).RollbackEvent), ctx, eventID, revision)
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/registry_status_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/registry_status_handle.go -destination=./mocks/mockbackofficeapp/mock_registry_status_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	registry "github.com/IsaacDSC/gqueue/internal/registry"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRegistryStatusReader is a mock of RegistryStatusReader interface.
+type MockRegistryStatusReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryStatusReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockRegistryStatusReaderMockRecorder is the mock recorder for MockRegistryStatusReader.
+type MockRegistryStatusReaderMockRecorder struct {
+	mock *MockRegistryStatusReader
+}
+
+// NewMockRegistryStatusReader creates a new mock instance.
+func NewMockRegistryStatusReader(ctrl *gomock.Controller) *MockRegistryStatusReader {
+	mock := &MockRegistryStatusReader{ctrl: ctrl}
+	mock.recorder = &MockRegistryStatusReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistryStatusReader) EXPECT() *MockRegistryStatusReaderMockRecorder {
+	return m.recorder
+}
+
+// Status mocks base method.
+func (m *MockRegistryStatusReader) Status(ctx context.Context) (registry.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx)
+	ret0, _ := ret[0].(registry.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockRegistryStatusReaderMockRecorder) Status(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockRegistryStatusReader)(nil).Status), ctx)
+}
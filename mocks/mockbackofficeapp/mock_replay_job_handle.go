@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/replay_job_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/replay_job_handle.go -destination=./mocks/mockbackofficeapp/mock_replay_job_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	replay "github.com/IsaacDSC/gqueue/internal/replay"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReplayJobManager is a mock of ReplayJobManager interface.
+type MockReplayJobManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplayJobManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockReplayJobManagerMockRecorder is the mock recorder for MockReplayJobManager.
+type MockReplayJobManagerMockRecorder struct {
+	mock *MockReplayJobManager
+}
+
+// NewMockReplayJobManager creates a new mock instance.
+func NewMockReplayJobManager(ctrl *gomock.Controller) *MockReplayJobManager {
+	mock := &MockReplayJobManager{ctrl: ctrl}
+	mock.recorder = &MockReplayJobManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplayJobManager) EXPECT() *MockReplayJobManagerMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockReplayJobManager) Cancel(ctx context.Context, id uuid.UUID) (replay.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(replay.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockReplayJobManagerMockRecorder) Cancel(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockReplayJobManager)(nil).Cancel), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockReplayJobManager) Get(ctx context.Context, id uuid.UUID) (replay.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(replay.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockReplayJobManagerMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockReplayJobManager)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockReplayJobManager) List(ctx context.Context) ([]replay.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]replay.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockReplayJobManagerMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockReplayJobManager)(nil).List), ctx)
+}
+
+// Start mocks base method.
+func (m *MockReplayJobManager) Start(ctx context.Context, filter replay.Filter, ratePerSecond float64) (replay.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, filter, ratePerSecond)
+	ret0, _ := ret[0].(replay.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockReplayJobManagerMockRecorder) Start(ctx, filter, ratePerSecond any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockReplayJobManager)(nil).Start), ctx, filter, ratePerSecond)
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/backofficeapp/timeline_handle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/backofficeapp/timeline_handle.go -destination=./mocks/mockbackofficeapp/mock_timeline_handle.go -package=mockbackofficeapp
+//
+
+// Package mockbackofficeapp is a generated GoMock package.
+package mockbackofficeapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTimelineStore is a mock of TimelineStore interface.
+type MockTimelineStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTimelineStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTimelineStoreMockRecorder is the mock recorder for MockTimelineStore.
+type MockTimelineStoreMockRecorder struct {
+	mock *MockTimelineStore
+}
+
+// NewMockTimelineStore creates a new mock instance.
+func NewMockTimelineStore(ctrl *gomock.Controller) *MockTimelineStore {
+	mock := &MockTimelineStore{ctrl: ctrl}
+	mock.recorder = &MockTimelineStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTimelineStore) EXPECT() *MockTimelineStoreMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockTimelineStore) Get(ctx context.Context, messageID string) ([]domain.TimelineEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, messageID)
+	ret0, _ := ret[0].([]domain.TimelineEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTimelineStoreMockRecorder) Get(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTimelineStore)(nil).Get), ctx, messageID)
+}
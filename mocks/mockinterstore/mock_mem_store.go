@@ -55,3 +55,18 @@ func (mr *MockPersistentStoreMockRecorder) GetAllEvents(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllEvents", reflect.TypeOf((*MockPersistentStore)(nil).GetAllEvents), ctx)
 }
+
+// GetInternalEvent mocks base method.
+func (m *MockPersistentStore) GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalEvent", ctx, eventName)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalEvent indicates an expected call of GetInternalEvent.
+func (mr *MockPersistentStoreMockRecorder) GetInternalEvent(ctx, eventName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalEvent", reflect.TypeOf((*MockPersistentStore)(nil).GetInternalEvent), ctx, eventName)
+}
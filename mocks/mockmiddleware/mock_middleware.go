@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/setup/middleware/middleware.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cmd/setup/middleware/middleware.go -destination=./mocks/mockmiddleware/mock_middleware.go -package=mockmiddleware
+//
+
+// Package mockmiddleware is a generated GoMock package.
+package mockmiddleware
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPIKeyAuthenticator is a mock of APIKeyAuthenticator interface.
+type MockAPIKeyAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyAuthenticatorMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyAuthenticatorMockRecorder is the mock recorder for MockAPIKeyAuthenticator.
+type MockAPIKeyAuthenticatorMockRecorder struct {
+	mock *MockAPIKeyAuthenticator
+}
+
+// NewMockAPIKeyAuthenticator creates a new mock instance.
+func NewMockAPIKeyAuthenticator(ctrl *gomock.Controller) *MockAPIKeyAuthenticator {
+	mock := &MockAPIKeyAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyAuthenticator) EXPECT() *MockAPIKeyAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAPIKeyAuthenticator) Authenticate(ctx context.Context, token string) (domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAPIKeyAuthenticatorMockRecorder) Authenticate(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAPIKeyAuthenticator)(nil).Authenticate), ctx, token)
+}
+
+// MockProjectResolver is a mock of ProjectResolver interface.
+type MockProjectResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockProjectResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockProjectResolverMockRecorder is the mock recorder for MockProjectResolver.
+type MockProjectResolverMockRecorder struct {
+	mock *MockProjectResolver
+}
+
+// NewMockProjectResolver creates a new mock instance.
+func NewMockProjectResolver(ctrl *gomock.Controller) *MockProjectResolver {
+	mock := &MockProjectResolver{ctrl: ctrl}
+	mock.recorder = &MockProjectResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProjectResolver) EXPECT() *MockProjectResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockProjectResolver) Resolve(r *http.Request) (domain.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", r)
+	ret0, _ := ret[0].(domain.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockProjectResolverMockRecorder) Resolve(r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockProjectResolver)(nil).Resolve), r)
+}
@@ -55,3 +55,56 @@ func (mr *MockDeadLetterStoreMockRecorder) GetAllSchedulers(ctx, state any) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSchedulers", reflect.TypeOf((*MockDeadLetterStore)(nil).GetAllSchedulers), ctx, state)
 }
+
+// GetEvent mocks base method.
+func (m *MockDeadLetterStore) GetEvent(ctx context.Context, eventName string) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", ctx, eventName)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockDeadLetterStoreMockRecorder) GetEvent(ctx, eventName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockDeadLetterStore)(nil).GetEvent), ctx, eventName)
+}
+
+// MockDeadLetterRecorder is a mock of DeadLetterRecorder interface.
+type MockDeadLetterRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterRecorderMockRecorder is the mock recorder for MockDeadLetterRecorder.
+type MockDeadLetterRecorderMockRecorder struct {
+	mock *MockDeadLetterRecorder
+}
+
+// NewMockDeadLetterRecorder creates a new mock instance.
+func NewMockDeadLetterRecorder(ctrl *gomock.Controller) *MockDeadLetterRecorder {
+	mock := &MockDeadLetterRecorder{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterRecorder) EXPECT() *MockDeadLetterRecorderMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockDeadLetterRecorder) Save(ctx context.Context, dl domain.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, dl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockDeadLetterRecorderMockRecorder) Save(ctx, dl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDeadLetterRecorder)(nil).Save), ctx, dl)
+}
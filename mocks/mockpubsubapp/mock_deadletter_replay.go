@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/app/pubsubapp/deadletter_replay.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/app/pubsubapp/deadletter_replay.go -destination=./mocks/mockpubsubapp/mock_deadletter_replay.go -package=mockpubsubapp
+//
+
+// Package mockpubsubapp is a generated GoMock package.
+package mockpubsubapp
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReplayStore is a mock of ReplayStore interface.
+type MockReplayStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplayStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockReplayStoreMockRecorder is the mock recorder for MockReplayStore.
+type MockReplayStoreMockRecorder struct {
+	mock *MockReplayStore
+}
+
+// NewMockReplayStore creates a new mock instance.
+func NewMockReplayStore(ctrl *gomock.Controller) *MockReplayStore {
+	mock := &MockReplayStore{ctrl: ctrl}
+	mock.recorder = &MockReplayStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplayStore) EXPECT() *MockReplayStoreMockRecorder {
+	return m.recorder
+}
+
+// GetInternalEvent mocks base method.
+func (m *MockReplayStore) GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalEvent", ctx, eventName)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalEvent indicates an expected call of GetInternalEvent.
+func (mr *MockReplayStoreMockRecorder) GetInternalEvent(ctx, eventName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalEvent", reflect.TypeOf((*MockReplayStore)(nil).GetInternalEvent), ctx, eventName)
+}
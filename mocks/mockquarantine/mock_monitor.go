@@ -0,0 +1,165 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/quarantine/monitor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/quarantine/monitor.go -destination=./mocks/mockquarantine/mock_monitor.go -package=mockquarantine
+//
+
+// Package mockquarantine is a generated GoMock package.
+package mockquarantine
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	notifyopt "github.com/IsaacDSC/gqueue/internal/notifyopt"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventStore is a mock of EventStore interface.
+type MockEventStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockEventStoreMockRecorder is the mock recorder for MockEventStore.
+type MockEventStoreMockRecorder struct {
+	mock *MockEventStore
+}
+
+// NewMockEventStore creates a new mock instance.
+func NewMockEventStore(ctrl *gomock.Controller) *MockEventStore {
+	mock := &MockEventStore{ctrl: ctrl}
+	mock.recorder = &MockEventStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStore) EXPECT() *MockEventStoreMockRecorder {
+	return m.recorder
+}
+
+// GetAllSchedulers mocks base method.
+func (m *MockEventStore) GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllSchedulers", ctx, state)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllSchedulers indicates an expected call of GetAllSchedulers.
+func (mr *MockEventStoreMockRecorder) GetAllSchedulers(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSchedulers", reflect.TypeOf((*MockEventStore)(nil).GetAllSchedulers), ctx, state)
+}
+
+// GetInternalEvent mocks base method.
+func (m *MockEventStore) GetInternalEvent(ctx context.Context, eventName string) (domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalEvent", ctx, eventName)
+	ret0, _ := ret[0].(domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalEvent indicates an expected call of GetInternalEvent.
+func (mr *MockEventStoreMockRecorder) GetInternalEvent(ctx, eventName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalEvent", reflect.TypeOf((*MockEventStore)(nil).GetInternalEvent), ctx, eventName)
+}
+
+// ListProjects mocks base method.
+func (m *MockEventStore) ListProjects(ctx context.Context) ([]domain.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjects", ctx)
+	ret0, _ := ret[0].([]domain.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjects indicates an expected call of ListProjects.
+func (mr *MockEventStoreMockRecorder) ListProjects(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockEventStore)(nil).ListProjects), ctx)
+}
+
+// MockInsightsStore is a mock of InsightsStore interface.
+type MockInsightsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockInsightsStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockInsightsStoreMockRecorder is the mock recorder for MockInsightsStore.
+type MockInsightsStoreMockRecorder struct {
+	mock *MockInsightsStore
+}
+
+// NewMockInsightsStore creates a new mock instance.
+func NewMockInsightsStore(ctrl *gomock.Controller) *MockInsightsStore {
+	mock := &MockInsightsStore{ctrl: ctrl}
+	mock.recorder = &MockInsightsStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInsightsStore) EXPECT() *MockInsightsStoreMockRecorder {
+	return m.recorder
+}
+
+// GetRange mocks base method.
+func (m *MockInsightsStore) GetRange(ctx context.Context, window domain.InsightsWindow) (domain.MetricsBuckets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRange", ctx, window)
+	ret0, _ := ret[0].(domain.MetricsBuckets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRange indicates an expected call of GetRange.
+func (mr *MockInsightsStoreMockRecorder) GetRange(ctx, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockInsightsStore)(nil).GetRange), ctx, window)
+}
+
+// MockFetcher is a mock of Fetcher interface.
+type MockFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockFetcherMockRecorder
+	isgomock struct{}
+}
+
+// MockFetcherMockRecorder is the mock recorder for MockFetcher.
+type MockFetcherMockRecorder struct {
+	mock *MockFetcher
+}
+
+// NewMockFetcher creates a new mock instance.
+func NewMockFetcher(ctrl *gomock.Controller) *MockFetcher {
+	mock := &MockFetcher{ctrl: ctrl}
+	mock.recorder = &MockFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFetcher) EXPECT() *MockFetcherMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockFetcher) Notify(ctx context.Context, data map[string]any, headers map[string]string, consumer domain.Consumer, opt notifyopt.Kind) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, data, headers, consumer, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockFetcherMockRecorder) Notify(ctx, data, headers, consumer, opt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockFetcher)(nil).Notify), ctx, data, headers, consumer, opt)
+}
@@ -0,0 +1,290 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/replay/sources.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/replay/sources.go -destination=./mocks/mockreplay/mock_sources.go -package=mockreplay
+//
+
+// Package mockreplay is a generated GoMock package.
+package mockreplay
+
+import (
+	context "context"
+	reflect "reflect"
+
+	taskapp "github.com/IsaacDSC/gqueue/internal/app/taskapp"
+	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	pgqueue "github.com/IsaacDSC/gqueue/pkg/pgqueue"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeadLetterStore is a mock of DeadLetterStore interface.
+type MockDeadLetterStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterStoreMockRecorder is the mock recorder for MockDeadLetterStore.
+type MockDeadLetterStoreMockRecorder struct {
+	mock *MockDeadLetterStore
+}
+
+// NewMockDeadLetterStore creates a new mock instance.
+func NewMockDeadLetterStore(ctrl *gomock.Controller) *MockDeadLetterStore {
+	mock := &MockDeadLetterStore{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterStore) EXPECT() *MockDeadLetterStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockDeadLetterStore) Delete(ctx context.Context, ids ...uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeadLetterStoreMockRecorder) Delete(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeadLetterStore)(nil).Delete), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockDeadLetterStore) Get(ctx context.Context, id uuid.UUID) (domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeadLetterStoreMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeadLetterStore)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockDeadLetterStore) List(ctx context.Context, filters domain.FilterDeadLetters) ([]domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filters)
+	ret0, _ := ret[0].([]domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeadLetterStoreMockRecorder) List(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeadLetterStore)(nil).List), ctx, filters)
+}
+
+// MockDeadLetterReplayer is a mock of DeadLetterReplayer interface.
+type MockDeadLetterReplayer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterReplayerMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterReplayerMockRecorder is the mock recorder for MockDeadLetterReplayer.
+type MockDeadLetterReplayerMockRecorder struct {
+	mock *MockDeadLetterReplayer
+}
+
+// NewMockDeadLetterReplayer creates a new mock instance.
+func NewMockDeadLetterReplayer(ctrl *gomock.Controller) *MockDeadLetterReplayer {
+	mock := &MockDeadLetterReplayer{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterReplayerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterReplayer) EXPECT() *MockDeadLetterReplayerMockRecorder {
+	return m.recorder
+}
+
+// Replay mocks base method.
+func (m *MockDeadLetterReplayer) Replay(ctx context.Context, dl domain.DeadLetter, allConsumers bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, dl, allConsumers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockDeadLetterReplayerMockRecorder) Replay(ctx, dl, allConsumers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockDeadLetterReplayer)(nil).Replay), ctx, dl, allConsumers)
+}
+
+// MockArchivedTaskManager is a mock of ArchivedTaskManager interface.
+type MockArchivedTaskManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchivedTaskManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockArchivedTaskManagerMockRecorder is the mock recorder for MockArchivedTaskManager.
+type MockArchivedTaskManagerMockRecorder struct {
+	mock *MockArchivedTaskManager
+}
+
+// NewMockArchivedTaskManager creates a new mock instance.
+func NewMockArchivedTaskManager(ctrl *gomock.Controller) *MockArchivedTaskManager {
+	mock := &MockArchivedTaskManager{ctrl: ctrl}
+	mock.recorder = &MockArchivedTaskManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchivedTaskManager) EXPECT() *MockArchivedTaskManagerMockRecorder {
+	return m.recorder
+}
+
+// ArchivedQueues mocks base method.
+func (m *MockArchivedTaskManager) ArchivedQueues(ctx context.Context) ([]taskapp.ArchivedQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchivedQueues", ctx)
+	ret0, _ := ret[0].([]taskapp.ArchivedQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchivedQueues indicates an expected call of ArchivedQueues.
+func (mr *MockArchivedTaskManagerMockRecorder) ArchivedQueues(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchivedQueues", reflect.TypeOf((*MockArchivedTaskManager)(nil).ArchivedQueues), ctx)
+}
+
+// ListArchived mocks base method.
+func (m *MockArchivedTaskManager) ListArchived(ctx context.Context, queue string, page, limit int) (taskapp.ArchivedTasksPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArchived", ctx, queue, page, limit)
+	ret0, _ := ret[0].(taskapp.ArchivedTasksPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArchived indicates an expected call of ListArchived.
+func (mr *MockArchivedTaskManagerMockRecorder) ListArchived(ctx, queue, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchived", reflect.TypeOf((*MockArchivedTaskManager)(nil).ListArchived), ctx, queue, page, limit)
+}
+
+// RequeueTask mocks base method.
+func (m *MockArchivedTaskManager) RequeueTask(ctx context.Context, queue, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueTask", ctx, queue, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueTask indicates an expected call of RequeueTask.
+func (mr *MockArchivedTaskManagerMockRecorder) RequeueTask(ctx, queue, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueTask", reflect.TypeOf((*MockArchivedTaskManager)(nil).RequeueTask), ctx, queue, id)
+}
+
+// MockJobStore is a mock of JobStore interface.
+type MockJobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockJobStoreMockRecorder is the mock recorder for MockJobStore.
+type MockJobStoreMockRecorder struct {
+	mock *MockJobStore
+}
+
+// NewMockJobStore creates a new mock instance.
+func NewMockJobStore(ctrl *gomock.Controller) *MockJobStore {
+	mock := &MockJobStore{ctrl: ctrl}
+	mock.recorder = &MockJobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobStore) EXPECT() *MockJobStoreMockRecorder {
+	return m.recorder
+}
+
+// ListJobs mocks base method.
+func (m *MockJobStore) ListJobs(ctx context.Context, state pgqueue.State, limit, offset int) ([]pgqueue.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", ctx, state, limit, offset)
+	ret0, _ := ret[0].([]pgqueue.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockJobStoreMockRecorder) ListJobs(ctx, state, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockJobStore)(nil).ListJobs), ctx, state, limit, offset)
+}
+
+// Requeue mocks base method.
+func (m *MockJobStore) Requeue(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockJobStoreMockRecorder) Requeue(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockJobStore)(nil).Requeue), ctx, id)
+}
+
+// MockTimelineRecorder is a mock of TimelineRecorder interface.
+type MockTimelineRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockTimelineRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockTimelineRecorderMockRecorder is the mock recorder for MockTimelineRecorder.
+type MockTimelineRecorderMockRecorder struct {
+	mock *MockTimelineRecorder
+}
+
+// NewMockTimelineRecorder creates a new mock instance.
+func NewMockTimelineRecorder(ctrl *gomock.Controller) *MockTimelineRecorder {
+	mock := &MockTimelineRecorder{ctrl: ctrl}
+	mock.recorder = &MockTimelineRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTimelineRecorder) EXPECT() *MockTimelineRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockTimelineRecorder) Record(ctx context.Context, entry domain.TimelineEntry) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", ctx, entry)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockTimelineRecorderMockRecorder) Record(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockTimelineRecorder)(nil).Record), ctx, entry)
+}
@@ -10,180 +10,125 @@
 package mocktaskapp
 
 import (
-	context "context"
 	reflect "reflect"
 
-	taskapp "github.com/IsaacDSC/gqueue/internal/app/taskapp"
-	domain "github.com/IsaacDSC/gqueue/internal/domain"
+	asynq "github.com/hibiken/asynq"
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockCacheManager is a mock of CacheManager interface.
-type MockCacheManager struct {
+// MockInspector is a mock of Inspector interface.
+type MockInspector struct {
 	ctrl     *gomock.Controller
-	recorder *MockCacheManagerMockRecorder
+	recorder *MockInspectorMockRecorder
 	isgomock struct{}
 }
 
-// MockCacheManagerMockRecorder is the mock recorder for MockCacheManager.
-type MockCacheManagerMockRecorder struct {
-	mock *MockCacheManager
+// MockInspectorMockRecorder is the mock recorder for MockInspector.
+type MockInspectorMockRecorder struct {
+	mock *MockInspector
 }
 
-// NewMockCacheManager creates a new mock instance.
-func NewMockCacheManager(ctrl *gomock.Controller) *MockCacheManager {
-	mock := &MockCacheManager{ctrl: ctrl}
-	mock.recorder = &MockCacheManagerMockRecorder{mock}
+// NewMockInspector creates a new mock instance.
+func NewMockInspector(ctrl *gomock.Controller) *MockInspector {
+	mock := &MockInspector{ctrl: ctrl}
+	mock.recorder = &MockInspectorMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockCacheManager) EXPECT() *MockCacheManagerMockRecorder {
+func (m *MockInspector) EXPECT() *MockInspectorMockRecorder {
 	return m.recorder
 }
 
-// FindAllConsumers mocks base method.
-func (m *MockCacheManager) FindAllConsumers(ctx context.Context) ([]domain.Event, error) {
+// DeleteTask mocks base method.
+func (m *MockInspector) DeleteTask(queue, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllConsumers", ctx)
-	ret0, _ := ret[0].([]domain.Event)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// FindAllConsumers indicates an expected call of FindAllConsumers.
-func (mr *MockCacheManagerMockRecorder) FindAllConsumers(ctx any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllConsumers", reflect.TypeOf((*MockCacheManager)(nil).FindAllConsumers), ctx)
-}
-
-// FindAllQueues mocks base method.
-func (m *MockCacheManager) FindAllQueues(ctx context.Context) ([]taskapp.Queue, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllQueues", ctx)
-	ret0, _ := ret[0].([]taskapp.Queue)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteTask", queue, id)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// FindAllQueues indicates an expected call of FindAllQueues.
-func (mr *MockCacheManagerMockRecorder) FindAllQueues(ctx any) *gomock.Call {
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockInspectorMockRecorder) DeleteTask(queue, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllQueues", reflect.TypeOf((*MockCacheManager)(nil).FindAllQueues), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockInspector)(nil).DeleteTask), queue, id)
 }
 
-// FindArchivedTasks mocks base method.
-func (m *MockCacheManager) FindArchivedTasks(ctx context.Context, queue string) ([]string, error) {
+// GetQueueInfo mocks base method.
+func (m *MockInspector) GetQueueInfo(queue string) (*asynq.QueueInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindArchivedTasks", ctx, queue)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "GetQueueInfo", queue)
+	ret0, _ := ret[0].(*asynq.QueueInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindArchivedTasks indicates an expected call of FindArchivedTasks.
-func (mr *MockCacheManagerMockRecorder) FindArchivedTasks(ctx, queue any) *gomock.Call {
+// GetQueueInfo indicates an expected call of GetQueueInfo.
+func (mr *MockInspectorMockRecorder) GetQueueInfo(queue any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindArchivedTasks", reflect.TypeOf((*MockCacheManager)(nil).FindArchivedTasks), ctx, queue)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueInfo", reflect.TypeOf((*MockInspector)(nil).GetQueueInfo), queue)
 }
 
-// GetMsgArchivedTask mocks base method.
-func (m *MockCacheManager) GetMsgArchivedTask(ctx context.Context, queue, task string) (taskapp.RawMsg, error) {
+// GetTaskInfo mocks base method.
+func (m *MockInspector) GetTaskInfo(queue, id string) (*asynq.TaskInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMsgArchivedTask", ctx, queue, task)
-	ret0, _ := ret[0].(taskapp.RawMsg)
+	ret := m.ctrl.Call(m, "GetTaskInfo", queue, id)
+	ret0, _ := ret[0].(*asynq.TaskInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMsgArchivedTask indicates an expected call of GetMsgArchivedTask.
-func (mr *MockCacheManagerMockRecorder) GetMsgArchivedTask(ctx, queue, task any) *gomock.Call {
+// GetTaskInfo indicates an expected call of GetTaskInfo.
+func (mr *MockInspectorMockRecorder) GetTaskInfo(queue, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMsgArchivedTask", reflect.TypeOf((*MockCacheManager)(nil).GetMsgArchivedTask), ctx, queue, task)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskInfo", reflect.TypeOf((*MockInspector)(nil).GetTaskInfo), queue, id)
 }
 
-// RemoveItemsArchivedTasks mocks base method.
-func (m *MockCacheManager) RemoveItemsArchivedTasks(ctx context.Context, queue string, tasks ...string) error {
+// ListArchivedTasks mocks base method.
+func (m *MockInspector) ListArchivedTasks(queue string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, queue}
-	for _, a := range tasks {
+	varargs := []any{queue}
+	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "RemoveItemsArchivedTasks", varargs...)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListArchivedTasks", varargs...)
+	ret0, _ := ret[0].([]*asynq.TaskInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// RemoveItemsArchivedTasks indicates an expected call of RemoveItemsArchivedTasks.
-func (mr *MockCacheManagerMockRecorder) RemoveItemsArchivedTasks(ctx, queue any, tasks ...any) *gomock.Call {
+// ListArchivedTasks indicates an expected call of ListArchivedTasks.
+func (mr *MockInspectorMockRecorder) ListArchivedTasks(queue any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, queue}, tasks...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItemsArchivedTasks", reflect.TypeOf((*MockCacheManager)(nil).RemoveItemsArchivedTasks), varargs...)
+	varargs := append([]any{queue}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArchivedTasks", reflect.TypeOf((*MockInspector)(nil).ListArchivedTasks), varargs...)
 }
 
-// RemoveMsgArchivedTask mocks base method.
-func (m *MockCacheManager) RemoveMsgArchivedTask(ctx context.Context, queue, task string) error {
+// Queues mocks base method.
+func (m *MockInspector) Queues() ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveMsgArchivedTask", ctx, queue, task)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Queues")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// RemoveMsgArchivedTask indicates an expected call of RemoveMsgArchivedTask.
-func (mr *MockCacheManagerMockRecorder) RemoveMsgArchivedTask(ctx, queue, task any) *gomock.Call {
+// Queues indicates an expected call of Queues.
+func (mr *MockInspectorMockRecorder) Queues() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMsgArchivedTask", reflect.TypeOf((*MockCacheManager)(nil).RemoveMsgArchivedTask), ctx, queue, task)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queues", reflect.TypeOf((*MockInspector)(nil).Queues))
 }
 
-// SetArchivedTasks mocks base method.
-func (m *MockCacheManager) SetArchivedTasks(ctx context.Context, events []domain.Event) error {
+// RunTask mocks base method.
+func (m *MockInspector) RunTask(queue, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetArchivedTasks", ctx, events)
+	ret := m.ctrl.Call(m, "RunTask", queue, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetArchivedTasks indicates an expected call of SetArchivedTasks.
-func (mr *MockCacheManagerMockRecorder) SetArchivedTasks(ctx, events any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArchivedTasks", reflect.TypeOf((*MockCacheManager)(nil).SetArchivedTasks), ctx, events)
-}
-
-// MockStorer is a mock of Storer interface.
-type MockStorer struct {
-	ctrl     *gomock.Controller
-	recorder *MockStorerMockRecorder
-	isgomock struct{}
-}
-
-// MockStorerMockRecorder is the mock recorder for MockStorer.
-type MockStorerMockRecorder struct {
-	mock *MockStorer
-}
-
-// NewMockStorer creates a new mock instance.
-func NewMockStorer(ctrl *gomock.Controller) *MockStorer {
-	mock := &MockStorer{ctrl: ctrl}
-	mock.recorder = &MockStorerMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockStorer) EXPECT() *MockStorerMockRecorder {
-	return m.recorder
-}
-
-// GetAllSchedulers mocks base method.
-func (m *MockStorer) GetAllSchedulers(ctx context.Context, state string) ([]domain.Event, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllSchedulers", ctx, state)
-	ret0, _ := ret[0].([]domain.Event)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetAllSchedulers indicates an expected call of GetAllSchedulers.
-func (mr *MockStorerMockRecorder) GetAllSchedulers(ctx, state any) *gomock.Call {
+// RunTask indicates an expected call of RunTask.
+func (mr *MockInspectorMockRecorder) RunTask(queue, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSchedulers", reflect.TypeOf((*MockStorer)(nil).GetAllSchedulers), ctx, state)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTask", reflect.TypeOf((*MockInspector)(nil).RunTask), queue, id)
 }
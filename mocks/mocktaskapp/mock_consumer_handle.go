@@ -93,3 +93,79 @@ func (mr *MockConsumerInsightsMockRecorder) Consumed(ctx, input any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consumed", reflect.TypeOf((*MockConsumerInsights)(nil).Consumed), ctx, input)
 }
+
+// MockPauseChecker is a mock of PauseChecker interface.
+type MockPauseChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockPauseCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockPauseCheckerMockRecorder is the mock recorder for MockPauseChecker.
+type MockPauseCheckerMockRecorder struct {
+	mock *MockPauseChecker
+}
+
+// NewMockPauseChecker creates a new mock instance.
+func NewMockPauseChecker(ctrl *gomock.Controller) *MockPauseChecker {
+	mock := &MockPauseChecker{ctrl: ctrl}
+	mock.recorder = &MockPauseCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPauseChecker) EXPECT() *MockPauseCheckerMockRecorder {
+	return m.recorder
+}
+
+// DeliveryPaused mocks base method.
+func (m *MockPauseChecker) DeliveryPaused(ctx context.Context, eventName, serviceName string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliveryPaused", ctx, eventName, serviceName)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// DeliveryPaused indicates an expected call of DeliveryPaused.
+func (mr *MockPauseCheckerMockRecorder) DeliveryPaused(ctx, eventName, serviceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliveryPaused", reflect.TypeOf((*MockPauseChecker)(nil).DeliveryPaused), ctx, eventName, serviceName)
+}
+
+// MockQuarantineChecker is a mock of QuarantineChecker interface.
+type MockQuarantineChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuarantineCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockQuarantineCheckerMockRecorder is the mock recorder for MockQuarantineChecker.
+type MockQuarantineCheckerMockRecorder struct {
+	mock *MockQuarantineChecker
+}
+
+// NewMockQuarantineChecker creates a new mock instance.
+func NewMockQuarantineChecker(ctrl *gomock.Controller) *MockQuarantineChecker {
+	mock := &MockQuarantineChecker{ctrl: ctrl}
+	mock.recorder = &MockQuarantineCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuarantineChecker) EXPECT() *MockQuarantineCheckerMockRecorder {
+	return m.recorder
+}
+
+// Quarantined mocks base method.
+func (m *MockQuarantineChecker) Quarantined(ctx context.Context, eventName, serviceName string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quarantined", ctx, eventName, serviceName)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Quarantined indicates an expected call of Quarantined.
+func (mr *MockQuarantineCheckerMockRecorder) Quarantined(ctx, eventName, serviceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quarantined", reflect.TypeOf((*MockQuarantineChecker)(nil).Quarantined), ctx, eventName, serviceName)
+}
@@ -93,3 +93,39 @@ func (mr *MockStoreMockRecorder) GetEvent(ctx, eventName any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockStore)(nil).GetEvent), ctx, eventName)
 }
+
+// MockTimelineRecorder is a mock of TimelineRecorder interface.
+type MockTimelineRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockTimelineRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockTimelineRecorderMockRecorder is the mock recorder for MockTimelineRecorder.
+type MockTimelineRecorderMockRecorder struct {
+	mock *MockTimelineRecorder
+}
+
+// NewMockTimelineRecorder creates a new mock instance.
+func NewMockTimelineRecorder(ctrl *gomock.Controller) *MockTimelineRecorder {
+	mock := &MockTimelineRecorder{ctrl: ctrl}
+	mock.recorder = &MockTimelineRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTimelineRecorder) EXPECT() *MockTimelineRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockTimelineRecorder) Record(ctx context.Context, entry domain.TimelineEntry) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", ctx, entry)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockTimelineRecorderMockRecorder) Record(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockTimelineRecorder)(nil).Record), ctx, entry)
+}
@@ -12,9 +12,13 @@ func (h Handle[T]) ToAsynqHandler() asynqsvc.AsynqHandle {
 	return asynqsvc.AsynqHandle{
 		TopicName: h.EventName,
 		Handler: func(ctx context.Context, task *asynq.Task) error {
+			retry, _ := asynq.GetRetryCount(ctx)
+			maxRetry, _ := asynq.GetMaxRetry(ctx)
 			if err := h.Handler(AsyncCtx[T]{
 				ctx:         ctx,
 				bytePayload: task.Payload(),
+				retry:       retry,
+				maxRetry:    maxRetry,
 			}); err != nil {
 				return fmt.Errorf("handle task: %w", err)
 			}
@@ -12,6 +12,10 @@ type AsyncCtx[T any] struct {
 	ctx         context.Context
 	payload     T
 	bytePayload []byte
+	// retry is the number of attempts made before this one
+	retry int
+	// maxRetry is the number of retries after the first attempt
+	maxRetry int
 }
 
 func (c AsyncCtx[T]) Bytes() []byte {
@@ -32,6 +36,16 @@ func (c AsyncCtx[T]) Context() context.Context {
 	return c.ctx
 }
 
+// Retry is the number of failed attempts to handle the message before this one, 0 on the first
+func (c AsyncCtx[T]) Retry() int {
+	return c.retry
+}
+
+// LastAttempt reports whether a failure of this attempt is not retried
+func (c AsyncCtx[T]) LastAttempt() bool {
+	return c.retry >= c.maxRetry
+}
+
 type Handle[T any] struct {
 	EventName string
 	Handler   func(c AsyncCtx[T]) error
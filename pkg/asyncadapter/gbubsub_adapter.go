@@ -2,6 +2,8 @@ package asyncadapter
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strconv"
 	"time"
 
@@ -10,7 +12,6 @@ import (
 	"github.com/IsaacDSC/gqueue/pkg/gpubsub"
 	"github.com/IsaacDSC/gqueue/pkg/pubadapter"
 	"github.com/IsaacDSC/gqueue/pkg/telemetry"
-	"github.com/IsaacDSC/gqueue/pkg/topicutils"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -18,7 +19,7 @@ func (h Handle[T]) ToGPubSubHandler(pub pubadapter.GenericPublisher) gpubsub.Han
 
 	archivedMsg := func(ctx context.Context, msg *pubsub.Message) {
 		defer msg.Ack()
-		topic := topicutils.BuildTopicName(domain.ProjectID, domain.EventQueueDeadLetter)
+		topic := domain.ProjectFromContext(ctx).TopicName(domain.EventQueueDeadLetter)
 		if err := pub.Publish(ctx, topic, msg, pubadapter.Opts{
 			Attributes: msg.Attributes,
 		}); err != nil {
@@ -70,7 +71,8 @@ func (h Handle[T]) ToGPubSubHandler(pub pubadapter.GenericPublisher) gpubsub.Han
 			return
 		}
 
-		if err := pub.Publish(ctx, topic, msg, pubadapter.Opts{
+		// republish the original data, publishing msg itself would nest the message in its own payload
+		if err := pub.Publish(ctx, topic, json.RawMessage(msg.Data), pubadapter.Opts{
 			Attributes: msg.Attributes,
 		}); err != nil {
 			msg.Nack()
@@ -83,10 +85,24 @@ func (h Handle[T]) ToGPubSubHandler(pub pubadapter.GenericPublisher) gpubsub.Han
 		Handler: func(ctx context.Context, msg *pubsub.Message) {
 			defer msg.Nack()
 
-			if err := h.Handler(AsyncCtx[T]{
+			// retry_count is set by retryable when the message is published again
+			retry, _ := strconv.Atoi(msg.Attributes["retry_count"])
+			maxRetry, _ := strconv.Atoi(msg.Attributes["max_retries"])
+			err := h.Handler(AsyncCtx[T]{
 				ctx:         ctx,
 				bytePayload: msg.Data,
-			}); err != nil {
+				retry:       retry,
+				maxRetry:    maxRetry,
+			})
+
+			// a paused delivery goes back to Pub/Sub, the subscription retry policy
+			// delays the redelivery and it does not count as a retry
+			if errors.Is(err, domain.DeliveryPaused) {
+				msg.Nack()
+				return
+			}
+
+			if err != nil {
 				msg.Attributes["msg"] = err.Error()
 				retryable(ctx, msg)
 				return
@@ -0,0 +1,27 @@
+package asyncadapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IsaacDSC/gqueue/pkg/pgqueue"
+)
+
+func (h Handle[T]) ToPgQueueHandler() pgqueue.Handle {
+	return pgqueue.Handle{
+		TopicName: h.EventName,
+		Handler: func(ctx context.Context, job pgqueue.Job) error {
+			if err := h.Handler(AsyncCtx[T]{
+				ctx:         ctx,
+				bytePayload: job.Payload,
+				// the attempts of a job count the one being handled
+				retry:    max(job.Attempts-1, 0),
+				maxRetry: job.MaxRetries,
+			}); err != nil {
+				return fmt.Errorf("handle job: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
@@ -8,3 +8,13 @@ func NewAsyncCtx[T any](ctx context.Context, payload []byte) AsyncCtx[T] {
 		bytePayload: payload,
 	}
 }
+
+// NewRetryAsyncCtx is the context of an attempt after retry failed ones, out of maxRetry retries
+func NewRetryAsyncCtx[T any](ctx context.Context, payload []byte, retry, maxRetry int) AsyncCtx[T] {
+	return AsyncCtx[T]{
+		ctx:         ctx,
+		bytePayload: payload,
+		retry:       retry,
+		maxRetry:    maxRetry,
+	}
+}
@@ -39,3 +39,38 @@ func HasUser(ctx context.Context) bool {
 	_, ok := UserFromContext(ctx)
 	return ok
 }
+
+// Identity is a publisher authenticated with a token
+type Identity struct {
+	Subject   string
+	Publisher string
+	// Events the publisher can publish, "*" allows every event
+	Events []string
+	// Project is the project the publisher belongs to, empty for the default project
+	Project string
+	Claims  map[string]any
+}
+
+// CanPublish reports whether the identity can publish the event
+func (i Identity) CanPublish(eventName string) bool {
+	for _, allowed := range i.Events {
+		if allowed == "*" || allowed == eventName {
+			return true
+		}
+	}
+
+	return false
+}
+
+type identityKey struct{}
+
+// WithIdentity adds the identity of a token to the context
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext retrieves the identity of the token the request was authenticated with
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+const (
+	defaultJWKSRefresh = 5 * time.Minute
+	// minJWKSReload limits how often an unknown key id reloads the key set, tokens with made up
+	// key ids would otherwise make every request fetch it
+	minJWKSReload = 30 * time.Second
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS reads the RSA and EC public keys of a JSON Web Key Set by key id. Keys of other types
+// or only used for encryption are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var key crypto.PublicKey
+		var err error
+		switch jwk.Kty {
+		case "RSA":
+			key, err = jwk.rsaKey()
+		case "EC":
+			key, err = jwk.ecKey()
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %q: %w", jwk.Kid, err)
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) rsaKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+func (k jsonWebKey) ecKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x: %w", err)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y: %w", err)
+	}
+
+	key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("point is not on the curve")
+	}
+
+	return key, nil
+}
+
+// JWKS is a key set loaded from a file or an URL. It is reloaded every refresh interval and when a
+// token is signed with a key it does not know, so rotated keys are picked up. A single load runs at
+// a time: stale keys are served while it refreshes them, only unknown keys wait for it.
+type JWKS struct {
+	source  string
+	refresh time.Duration
+	client  *http.Client
+
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+	loading  *jwksLoad
+}
+
+// jwksLoad is a load of the key set in progress, done is closed once err is set
+type jwksLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// NewJWKS creates a key set read from source, an http(s) URL or a file path
+func NewJWKS(source string, refresh time.Duration) *JWKS {
+	if refresh <= 0 {
+		refresh = defaultJWKSRefresh
+	}
+
+	return &JWKS{source: source, refresh: refresh, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Key returns the public key of the key id
+func (s *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+
+	key, ok := s.keys[kid]
+	age := time.Since(s.loadedAt)
+	switch {
+	case ok && age < s.refresh:
+		s.mu.Unlock()
+		return key, nil
+	case ok:
+		// the key set is kept while it is refreshed, or while its source is unavailable
+		s.startLoad(ctx)
+		s.mu.Unlock()
+		return key, nil
+	case s.keys != nil && age < minJWKSReload:
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+
+	load := s.startLoad(ctx)
+	s.mu.Unlock()
+
+	select {
+	case <-load.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if load.err != nil {
+		return nil, load.err
+	}
+
+	s.mu.Lock()
+	key, ok = s.keys[kid]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+
+	return key, nil
+}
+
+// startLoad loads the key set in the background unless a load is already running, s.mu must be
+// held. The load outlives the request starting it.
+func (s *JWKS) startLoad(ctx context.Context) *jwksLoad {
+	if s.loading != nil {
+		return s.loading
+	}
+
+	load := &jwksLoad{done: make(chan struct{})}
+	s.loading = load
+
+	go func() {
+		keys, err := s.load(context.WithoutCancel(ctx))
+
+		s.mu.Lock()
+		if err == nil {
+			s.keys = keys
+			s.loadedAt = time.Now()
+		}
+		s.loading = nil
+		s.mu.Unlock()
+
+		load.err = err
+		close(load.done)
+	}()
+
+	return load
+}
+
+func (s *JWKS) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		data, err := os.ReadFile(strings.TrimPrefix(s.source, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwks: %w", err)
+		}
+
+		return ParseJWKS(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jwks request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks: %w", err)
+	}
+
+	return ParseJWKS(data)
+}
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// KeySet finds the public key a token was signed with
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// ClaimsMapping names the claims the identity of a publisher is read from
+type ClaimsMapping struct {
+	// Publisher is the claim naming the publisher, "sub" when empty
+	Publisher string
+	// Events is the claim listing the events the publisher can publish, as an array or a space
+	// separated string. "*" allows every event.
+	Events string
+	// Project is the claim naming the project of the publisher, the default project when missing
+	Project string
+}
+
+// JWTConfig configures the checks of a JWTVerifier, empty values are not checked
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew on the expiry and not before claims
+	Leeway  time.Duration
+	Mapping ClaimsMapping
+}
+
+// JWTVerifier verifies the signature and claims of JWTs and maps them to an Identity.
+// RS256, RS384, RS512, ES256, ES384 and ES512 signatures are supported.
+type JWTVerifier struct {
+	keys   KeySet
+	config JWTConfig
+	now    func() time.Time
+}
+
+func NewJWTVerifier(keys KeySet, config JWTConfig) *JWTVerifier {
+	if config.Mapping.Publisher == "" {
+		config.Mapping.Publisher = "sub"
+	}
+
+	return &JWTVerifier{keys: keys, config: config, now: time.Now}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify checks the token and returns the identity of its publisher
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Identity{}, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+
+	key, err := v.keys.Key(ctx, header.Kid)
+	if errors.Is(err, ErrUnknownKey) {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Identity{}, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+
+	if err := v.checkClaims(claims); err != nil {
+		return Identity{}, err
+	}
+
+	return v.identity(claims)
+}
+
+func (v *JWTVerifier) checkClaims(claims map[string]any) error {
+	now := v.now()
+
+	// the tokens are publisher credentials, one without an expiry would be valid forever
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+
+	if !now.Before(time.Unix(int64(exp), 0).Add(v.config.Leeway)) {
+		return ErrTokenExpired
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.config.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+
+	if v.config.Issuer != "" && claims["iss"] != v.config.Issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+
+	if v.config.Audience != "" && !slices.Contains(stringList(claims["aud"]), v.config.Audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+func (v *JWTVerifier) identity(claims map[string]any) (Identity, error) {
+	mapping := v.config.Mapping
+
+	publisher, _ := claims[mapping.Publisher].(string)
+	if publisher == "" {
+		return Identity{}, fmt.Errorf("%w: missing %s claim", ErrInvalidToken, mapping.Publisher)
+	}
+
+	identity := Identity{Publisher: publisher, Claims: claims}
+	identity.Subject, _ = claims["sub"].(string)
+	if mapping.Events != "" {
+		identity.Events = stringList(claims[mapping.Events])
+	}
+
+	if mapping.Project != "" {
+		identity.Project, _ = claims[mapping.Project].(string)
+	}
+
+	return identity, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// stringList reads a claim holding a string, a space separated list or an array of strings
+func stringList(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		// "none" and HMAC algorithms are rejected, the key set only holds public keys
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	hasher := hash.New()
+	hasher.Write([]byte(signed))
+	digest := hasher.Sum(nil)
+
+	switch {
+	case strings.HasPrefix(alg, "RS"):
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key does not match the algorithm")
+		}
+
+		return rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature)
+	default:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key does not match the algorithm")
+		}
+
+		// ES signatures are r and s concatenated, each the size of the curve
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid signature size")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("signature mismatch")
+		}
+
+		return nil
+	}
+}
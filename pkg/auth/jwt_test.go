@@ -0,0 +1,287 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testIssuer signs tokens with its own keys and publishes them as a JWKS, like the mesh does
+type testIssuer struct {
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) testIssuer {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return testIssuer{rsaKey: rsaKey, ecKey: ecKey}
+}
+
+func (i testIssuer) jwks(t *testing.T) []byte {
+	t.Helper()
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	data, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(i.rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(i.rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(i.ecKey.X.FillBytes(make([]byte, 32))), "y": b64(i.ecKey.Y.FillBytes(make([]byte, 32)))},
+		{"kty": "oct", "kid": "hmac-1", "k": "c2VjcmV0"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func (i testIssuer) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, i.rsaKey, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, i.ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signed + "." + b64(signature)
+}
+
+func publisherClaims() map[string]any {
+	return map[string]any{
+		"iss":            "https://mesh.internal",
+		"aud":            []string{"gqueue"},
+		"sub":            "spiffe://mesh/billing",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"gqueue_events":  []string{"payment.processed", "payment.refunded"},
+		"gqueue_project": "acme",
+	}
+}
+
+func testConfig() JWTConfig {
+	return JWTConfig{
+		Issuer:   "https://mesh.internal",
+		Audience: "gqueue",
+		Mapping:  ClaimsMapping{Events: "gqueue_events", Project: "gqueue_project"},
+	}
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	issuer := newTestIssuer(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(issuer.jwks(t))
+	}))
+	defer server.Close()
+
+	verifier := NewJWTVerifier(NewJWKS(server.URL, time.Minute), testConfig())
+
+	with := func(change func(claims map[string]any)) map[string]any {
+		claims := publisherClaims()
+		change(claims)
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "RS256", token: issuer.sign(t, "RS256", "rsa-1", publisherClaims())},
+		{name: "ES256", token: issuer.sign(t, "ES256", "ec-1", publisherClaims())},
+		{name: "expired", token: issuer.sign(t, "RS256", "rsa-1", with(func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() })), wantErr: ErrTokenExpired},
+		{name: "missing exp", token: issuer.sign(t, "RS256", "rsa-1", with(func(c map[string]any) { delete(c, "exp") })), wantErr: ErrInvalidToken},
+		{name: "exp not a number", token: issuer.sign(t, "RS256", "rsa-1", with(func(c map[string]any) { c["exp"] = "tomorrow" })), wantErr: ErrInvalidToken},
+		{name: "not valid yet", token: issuer.sign(t, "RS256", "rsa-1", with(func(c map[string]any) { c["nbf"] = time.Now().Add(time.Hour).Unix() })), wantErr: ErrInvalidToken},
+		{name: "other issuer", token: issuer.sign(t, "RS256", "rsa-1", with(func(c map[string]any) { c["iss"] = "https://other" })), wantErr: ErrInvalidToken},
+		{name: "other audience", token: issuer.sign(t, "RS256", "rsa-1", with(func(c map[string]any) { c["aud"] = "other" })), wantErr: ErrInvalidToken},
+		{name: "missing publisher", token: issuer.sign(t, "RS256", "rsa-1", with(func(c map[string]any) { delete(c, "sub") })), wantErr: ErrInvalidToken},
+		{name: "unknown key", token: issuer.sign(t, "RS256", "rsa-2", publisherClaims()), wantErr: ErrInvalidToken},
+		{name: "key of another algorithm", token: issuer.sign(t, "ES256", "rsa-1", publisherClaims()), wantErr: ErrInvalidToken},
+		{name: "unsigned", token: issuer.sign(t, "none", "rsa-1", publisherClaims()), wantErr: ErrInvalidToken},
+		{name: "tampered", token: issuer.sign(t, "RS256", "rsa-1", publisherClaims()) + "A", wantErr: ErrInvalidToken},
+		{name: "malformed", token: "not-a-jwt", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := verifier.Verify(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			if identity.Publisher != "spiffe://mesh/billing" {
+				t.Errorf("Publisher = %q", identity.Publisher)
+			}
+
+			if identity.Project != "acme" {
+				t.Errorf("Project = %q", identity.Project)
+			}
+
+			if !slices.Equal(identity.Events, []string{"payment.processed", "payment.refunded"}) {
+				t.Errorf("Events = %v", identity.Events)
+			}
+		})
+	}
+}
+
+func TestJWTVerifier_ClaimsMapping(t *testing.T) {
+	issuer := newTestIssuer(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, issuer.jwks(t), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewJWTVerifier(NewJWKS(path, time.Minute), JWTConfig{
+		Mapping: ClaimsMapping{Publisher: "client_id", Events: "scope"},
+	})
+
+	token := issuer.sign(t, "ES256", "ec-1", map[string]any{
+		"sub":       "user-1",
+		"client_id": "billing",
+		"scope":     "order.created payment.processed",
+		"exp":       time.Now().Add(time.Hour).Unix(),
+	})
+
+	identity, err := verifier.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if identity.Publisher != "billing" || identity.Subject != "user-1" {
+		t.Errorf("identity = %+v", identity)
+	}
+
+	if !identity.CanPublish("order.created") || identity.CanPublish("order.cancelled") {
+		t.Errorf("Events = %v", identity.Events)
+	}
+}
+
+func TestJWKS_KeepsKeysWhenSourceFails(t *testing.T) {
+	issuer := newTestIssuer(t)
+	var available atomic.Bool
+	available.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(issuer.jwks(t))
+	}))
+	defer server.Close()
+
+	jwks := NewJWKS(server.URL, time.Nanosecond)
+	if _, err := jwks.Key(context.Background(), "rsa-1"); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+
+	available.Store(false)
+	if _, err := jwks.Key(context.Background(), "rsa-1"); err != nil {
+		t.Errorf("Key() error = %v, the loaded keys should be kept", err)
+	}
+}
+
+func TestIdentity_CanPublish(t *testing.T) {
+	if !(Identity{Events: []string{"*"}}).CanPublish("order.created") {
+		t.Error("* should allow every event")
+	}
+
+	if (Identity{}).CanPublish("order.created") {
+		t.Error("an identity without events should not publish")
+	}
+}
+
+func TestJWKS_RefreshesWithoutBlocking(t *testing.T) {
+	issuer := newTestIssuer(t)
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first fetch answers right away, the refreshes hang until released
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		w.Write(issuer.jwks(t))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	jwks := NewJWKS(server.URL, time.Nanosecond)
+	if _, err := jwks.Key(context.Background(), "rsa-1"); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	started := time.Now()
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := jwks.Key(context.Background(), "ec-1"); err != nil {
+				t.Errorf("Key() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Key() waited %s for the refresh, the stale keys should be served", elapsed)
+	}
+
+	// the refresh reaches the source in the background
+	deadline := time.Now().Add(time.Second)
+	for fetches.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want a single refresh in flight", got)
+	}
+}
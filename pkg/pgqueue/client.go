@@ -0,0 +1,176 @@
+package pgqueue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+const (
+	defaultQueue      = "default"
+	defaultMaxRetries = 3
+)
+
+// EnqueueOption customizes a job before it is inserted.
+type EnqueueOption func(*enqueueConfig)
+
+type enqueueConfig struct {
+	queue      string
+	maxRetries int
+	processIn  time.Duration
+	processAt  *time.Time
+}
+
+// WithQueue sets the logical queue the job belongs to.
+func WithQueue(queue string) EnqueueOption {
+	return func(c *enqueueConfig) {
+		c.queue = queue
+	}
+}
+
+// WithMaxRetries sets how many times a failed job is retried before being archived.
+func WithMaxRetries(n int) EnqueueOption {
+	return func(c *enqueueConfig) {
+		if n < 0 {
+			n = 0
+		}
+		c.maxRetries = n
+	}
+}
+
+// WithProcessIn delays the first execution of the job by d.
+func WithProcessIn(d time.Duration) EnqueueOption {
+	return func(c *enqueueConfig) {
+		c.processIn = d
+		c.processAt = nil
+	}
+}
+
+// WithProcessAt schedules the first execution of the job at t.
+func WithProcessAt(t time.Time) EnqueueOption {
+	return func(c *enqueueConfig) {
+		c.processAt = &t
+		c.processIn = 0
+	}
+}
+
+// Client enqueues and inspects jobs stored in Postgres.
+type Client struct {
+	db *sql.DB
+}
+
+func NewClient(db *sql.DB) *Client {
+	return &Client{db: db}
+}
+
+func (c *Client) Enqueue(ctx context.Context, topic string, payload []byte, opts ...EnqueueOption) (Job, error) {
+	conf := enqueueConfig{queue: defaultQueue, maxRetries: defaultMaxRetries}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	// run_at is computed by the database so every worker compares against the same clock
+	query := fmt.Sprintf(`
+		INSERT INTO jobs (id, queue, topic, payload, state, max_retries, run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW() + ($8 * INTERVAL '1 millisecond')))
+		RETURNING %s`, jobFields)
+
+	row := c.db.QueryRowContext(ctx, query,
+		uuid.New(),
+		conf.queue,
+		topic,
+		payload,
+		StatePending,
+		conf.maxRetries,
+		conf.processAt,
+		conf.processIn.Milliseconds(),
+	)
+
+	job, err := scanJob(row)
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (c *Client) GetJob(ctx context.Context, id uuid.UUID) (Job, error) {
+	query := fmt.Sprintf(`SELECT %s FROM jobs WHERE id = $1`, jobFields)
+
+	job, err := scanJob(c.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Job{}, ErrJobNotFound
+	}
+
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ListJobs returns jobs in the given state ordered by the time they became runnable.
+func (c *Client) ListJobs(ctx context.Context, state State, limit, offset int) ([]Job, error) {
+	query := fmt.Sprintf(`SELECT %s FROM jobs WHERE state = $1 ORDER BY run_at LIMIT $2 OFFSET $3`, jobFields)
+
+	rows, err := c.db.QueryContext(ctx, query, state, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	defer rows.Close()
+
+	jobs := make([]Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Requeue moves an archived job back to pending with a fresh attempt budget.
+func (c *Client) Requeue(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE jobs
+		SET state = $2, attempts = 0, run_at = NOW(), locked_until = NULL, updated_at = NOW()
+		WHERE id = $1 AND state = $3`
+
+	result, err := c.db.ExecContext(ctx, query, id, StatePending, StateArchived)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a job that is not currently being processed.
+func (c *Client) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := c.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = $1 AND state != $2`, id, StateRunning)
+	if err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package pgqueue
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// State is the lifecycle state of a job stored in the jobs table.
+type State string
+
+const (
+	StatePending  State = "pending"
+	StateRunning  State = "running"
+	StateArchived State = "archived"
+)
+
+func (s State) String() string {
+	return string(s)
+}
+
+// Job is a unit of work claimed by a worker from the jobs table.
+type Job struct {
+	ID          uuid.UUID  `json:"id"`
+	Queue       string     `json:"queue"`
+	Topic       string     `json:"topic"`
+	Payload     []byte     `json:"payload"`
+	State       State      `json:"state"`
+	Attempts    int        `json:"attempts"`
+	MaxRetries  int        `json:"max_retries"`
+	RunAt       time.Time  `json:"run_at"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Exhausted reports whether the job has used all of its attempts.
+// The first execution is not a retry, so a job runs at most MaxRetries+1 times.
+func (j Job) Exhausted() bool {
+	return j.Attempts > j.MaxRetries
+}
+
+const jobFields = `
+	id,
+	queue,
+	topic,
+	payload,
+	state,
+	attempts,
+	max_retries,
+	run_at,
+	locked_until,
+	last_error,
+	created_at,
+	updated_at
+`
+
+func scanJob(row interface{ Scan(dest ...any) error }) (Job, error) {
+	var job Job
+	var lastError *string
+	if err := row.Scan(
+		&job.ID,
+		&job.Queue,
+		&job.Topic,
+		&job.Payload,
+		&job.State,
+		&job.Attempts,
+		&job.MaxRetries,
+		&job.RunAt,
+		&job.LockedUntil,
+		&lastError,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	); err != nil {
+		return Job{}, err
+	}
+
+	if lastError != nil {
+		job.LastError = *lastError
+	}
+
+	return job, nil
+}
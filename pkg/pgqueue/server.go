@@ -0,0 +1,318 @@
+package pgqueue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/logs"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Handle binds a topic to the function that processes its jobs.
+type Handle struct {
+	TopicName string
+	Handler   func(ctx context.Context, job Job) error
+}
+
+type Config struct {
+	// Concurrency is the number of jobs processed at the same time.
+	Concurrency int
+	// PollInterval is how long an idle worker waits before trying to claim again.
+	PollInterval time.Duration
+	// VisibilityTimeout is how long a claimed job stays invisible to other workers.
+	// A job whose worker crashed becomes claimable again once it elapses.
+	VisibilityTimeout time.Duration
+	// RetryDelay returns how long to wait before the given attempt is retried.
+	RetryDelay func(attempt int) time.Duration
+	// IsFailure reports whether a handler error counts as a failed attempt. A job whose error is
+	// not a failure runs again after DeferDelay without consuming an attempt. Every error is a failure by default.
+	IsFailure func(err error) bool
+	// DeferDelay is how long a job that did not fail waits before running again.
+	DeferDelay time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Concurrency <= 0 {
+		c.Concurrency = 10
+	}
+
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+
+	if c.VisibilityTimeout <= 0 {
+		c.VisibilityTimeout = 5 * time.Minute
+	}
+
+	if c.RetryDelay == nil {
+		c.RetryDelay = DefaultRetryDelay
+	}
+
+	if c.IsFailure == nil {
+		c.IsFailure = func(error) bool { return true }
+	}
+
+	if c.DeferDelay <= 0 {
+		c.DeferDelay = 30 * time.Second
+	}
+
+	return c
+}
+
+// DefaultRetryDelay is an exponential backoff starting at 5s and capped at 10m, with up to 20% jitter.
+func DefaultRetryDelay(attempt int) time.Duration {
+	const (
+		base     = 5 * time.Second
+		maxDelay = 10 * time.Minute
+	)
+
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempt-1)))
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+
+	jitter := time.Duration(rand.Int64N(int64(delay) / 5))
+	return delay - jitter
+}
+
+// Server claims jobs with FOR UPDATE SKIP LOCKED and dispatches them to the registered handlers.
+type Server struct {
+	db   *sql.DB
+	conf Config
+
+	// mu guards handlers, they can be registered while the server runs
+	mu       sync.RWMutex
+	handlers map[string]Handle
+}
+
+func NewServer(db *sql.DB, conf Config) *Server {
+	return &Server{
+		db:       db,
+		conf:     conf.withDefaults(),
+		handlers: make(map[string]Handle),
+	}
+}
+
+// Handle registers the handler of a topic, the running workers claim its jobs from their next poll
+func (s *Server) Handle(h Handle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[h.TopicName] = h
+}
+
+// Run blocks processing jobs until ctx is cancelled, then waits for in-flight jobs to finish.
+func (s *Server) Run(ctx context.Context) error {
+	if len(s.topics()) == 0 {
+		return errors.New("no handlers registered")
+	}
+
+	var wg sync.WaitGroup
+	for range s.conf.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (s *Server) topics() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	topics := make([]string, 0, len(s.handlers))
+	for topic := range s.handlers {
+		topics = append(topics, topic)
+	}
+
+	return topics
+}
+
+func (s *Server) work(ctx context.Context) {
+	l := ctxlogger.GetLogger(ctx)
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		job, err := s.claim(ctx, s.topics())
+		if errors.Is(err, sql.ErrNoRows) {
+			s.idle(ctx)
+			continue
+		}
+
+		if err != nil {
+			if ctx.Err() == nil {
+				l.Error("Error claiming job", "error", err)
+			}
+			s.idle(ctx)
+			continue
+		}
+
+		s.process(ctx, job)
+	}
+}
+
+func (s *Server) idle(ctx context.Context) {
+	select {
+	case <-time.After(s.conf.PollInterval):
+	case <-ctx.Done():
+	}
+}
+
+// claim picks the next runnable job: pending jobs whose run_at has passed, or
+// running jobs whose lock expired because the worker holding them died.
+func (s *Server) claim(ctx context.Context, topics []string) (Job, error) {
+	query := fmt.Sprintf(`
+		UPDATE jobs
+		SET state = $1,
+			attempts = attempts + 1,
+			locked_until = NOW() + ($2 * INTERVAL '1 millisecond'),
+			updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE topic = ANY($3)
+			AND (
+				(state = $4 AND run_at <= NOW())
+				OR (state = $1 AND locked_until < NOW())
+			)
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s`, jobFields)
+
+	return scanJob(s.db.QueryRowContext(ctx, query,
+		StateRunning,
+		s.conf.VisibilityTimeout.Milliseconds(),
+		pq.Array(topics),
+		StatePending,
+	))
+}
+
+func (s *Server) process(ctx context.Context, job Job) {
+	logger := logs.With(
+		"job_id", job.ID.String(),
+		"topic", job.Topic,
+		"attempt", job.Attempts,
+	)
+
+	// the handler must finish before the lock expires, otherwise another worker could pick the job up
+	jobCtx, cancel := context.WithTimeout(ctxlogger.WithLogger(ctx, logger), s.conf.VisibilityTimeout)
+	defer cancel()
+
+	s.mu.RLock()
+	handle := s.handlers[job.Topic]
+	s.mu.RUnlock()
+
+	err := handle.Handler(jobCtx, job)
+
+	// state transitions use the parent context so a shutdown does not leave the job locked.
+	// They are also guarded by the attempt number: if the lock expired and another worker
+	// claimed the job in the meantime, this worker no longer owns it.
+	finishCtx := context.WithoutCancel(ctx)
+	if err == nil {
+		if err := s.complete(finishCtx, job); err != nil {
+			logger.Error("Error completing job", "error", err)
+		}
+		return
+	}
+
+	if !s.conf.IsFailure(err) {
+		logger.Info("Job deferred", "reason", err)
+		if err := s.deferJob(finishCtx, job, err); err != nil {
+			logger.Error("Error deferring job", "error", err)
+		}
+		return
+	}
+
+	attrs := attribute.String("topic", job.Topic)
+	if job.Exhausted() {
+		telemetry.TaskConsumerArchived.Increment(finishCtx, attrs)
+		logger.Warn("Retry exhausted, archiving job", "error", err)
+		if err := s.archive(finishCtx, job, err); err != nil {
+			logger.Error("Error archiving job", "error", err)
+		}
+		return
+	}
+
+	telemetry.TaskConsumerRetries.Increment(finishCtx, attrs)
+	logger.Warn("Job failed, scheduling retry", "error", err)
+	if err := s.retry(finishCtx, job, err); err != nil {
+		logger.Error("Error scheduling job retry", "error", err)
+	}
+}
+
+func (s *Server) complete(ctx context.Context, job Job) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = $1 AND attempts = $2`, job.ID, job.Attempts); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Server) retry(ctx context.Context, job Job, cause error) error {
+	delay := s.conf.RetryDelay(job.Attempts)
+	query := `
+		UPDATE jobs
+		SET state = $2,
+			run_at = NOW() + ($3 * INTERVAL '1 millisecond'),
+			locked_until = NULL,
+			last_error = $4,
+			updated_at = NOW()
+		WHERE id = $1 AND attempts = $5`
+
+	if _, err := s.db.ExecContext(ctx, query, job.ID, StatePending, delay.Milliseconds(), cause.Error(), job.Attempts); err != nil {
+		return fmt.Errorf("failed to retry job: %w", err)
+	}
+
+	return nil
+}
+
+// deferJob reschedules the job and gives the claimed attempt back
+func (s *Server) deferJob(ctx context.Context, job Job, cause error) error {
+	query := `
+		UPDATE jobs
+		SET state = $2,
+			attempts = attempts - 1,
+			run_at = NOW() + ($3 * INTERVAL '1 millisecond'),
+			locked_until = NULL,
+			last_error = $4,
+			updated_at = NOW()
+		WHERE id = $1 AND attempts = $5`
+
+	if _, err := s.db.ExecContext(ctx, query, job.ID, StatePending, s.conf.DeferDelay.Milliseconds(), cause.Error(), job.Attempts); err != nil {
+		return fmt.Errorf("failed to defer job: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Server) archive(ctx context.Context, job Job, cause error) error {
+	query := `
+		UPDATE jobs
+		SET state = $2, locked_until = NULL, last_error = $3, updated_at = NOW()
+		WHERE id = $1 AND attempts = $4`
+
+	if _, err := s.db.ExecContext(ctx, query, job.ID, StateArchived, cause.Error(), job.Attempts); err != nil {
+		return fmt.Errorf("failed to archive job: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,284 @@
+package pgqueue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/internal/migrations"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{name: "first_attempt_waits_around_base", attempt: 1, min: 4 * time.Second, max: 5 * time.Second},
+		{name: "zero_attempt_is_treated_as_first", attempt: 0, min: 4 * time.Second, max: 5 * time.Second},
+		{name: "grows_exponentially", attempt: 3, min: 16 * time.Second, max: 20 * time.Second},
+		{name: "is_capped", attempt: 30, min: 8 * time.Minute, max: 10 * time.Minute},
+		{name: "overflow_is_capped", attempt: 5000, min: 8 * time.Minute, max: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 50 {
+				delay := DefaultRetryDelay(tt.attempt)
+				assert.GreaterOrEqual(t, delay, tt.min)
+				assert.LessOrEqual(t, delay, tt.max)
+			}
+		})
+	}
+}
+
+func TestJob_Exhausted(t *testing.T) {
+	assert.True(t, Job{Attempts: 1, MaxRetries: 0}.Exhausted())
+	assert.False(t, Job{Attempts: 3, MaxRetries: 3}.Exhausted())
+	assert.True(t, Job{Attempts: 4, MaxRetries: 3}.Exhausted())
+}
+
+func TestConfig_WithDefaults(t *testing.T) {
+	conf := Config{}.withDefaults()
+
+	assert.Equal(t, 10, conf.Concurrency)
+	assert.Equal(t, time.Second, conf.PollInterval)
+	assert.Equal(t, 5*time.Minute, conf.VisibilityTimeout)
+	assert.NotNil(t, conf.RetryDelay)
+	assert.True(t, conf.IsFailure(errors.New("boom")))
+	assert.Equal(t, 30*time.Second, conf.DeferDelay)
+
+	custom := Config{Concurrency: 2, PollInterval: time.Millisecond, VisibilityTimeout: time.Minute}.withDefaults()
+	assert.Equal(t, 2, custom.Concurrency)
+	assert.Equal(t, time.Millisecond, custom.PollInterval)
+	assert.Equal(t, time.Minute, custom.VisibilityTimeout)
+}
+
+// testDB connects to the database in DB_CONNECTION_STRING with the jobs table migrated,
+// skipping the test when it is not set
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("DB_CONNECTION_STRING")
+	if dsn == "" {
+		t.Skip("DB_CONNECTION_STRING is not set")
+	}
+
+	ctx := context.Background()
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	require.NoError(t, db.PingContext(ctx))
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrations.New(db)
+	require.NoError(t, err)
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	return db
+}
+
+// testTopic is a topic of its own, so the test only claims the jobs it enqueued
+func testTopic(t *testing.T, db *sql.DB) string {
+	t.Helper()
+
+	topic := "pgqueue.test." + uuid.NewString()
+	t.Cleanup(func() {
+		db.ExecContext(context.Background(), `DELETE FROM jobs WHERE topic = $1`, topic)
+	})
+
+	return topic
+}
+
+func TestServer_ClaimAndAck(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	topic := testTopic(t, db)
+
+	client := NewClient(db)
+	enqueued, err := client.Enqueue(ctx, topic, []byte(`{"id":1}`))
+	require.NoError(t, err)
+
+	server := NewServer(db, Config{})
+	server.Handle(Handle{TopicName: topic, Handler: func(ctx context.Context, job Job) error { return nil }})
+
+	job, err := server.claim(ctx, []string{topic})
+	require.NoError(t, err)
+	assert.Equal(t, enqueued.ID, job.ID)
+	assert.Equal(t, StateRunning, job.State)
+	assert.Equal(t, 1, job.Attempts)
+	assert.NotNil(t, job.LockedUntil)
+
+	_, err = server.claim(ctx, []string{topic})
+	assert.ErrorIs(t, err, sql.ErrNoRows, "a claimed job is invisible to other workers")
+
+	server.process(ctx, job)
+
+	_, err = client.GetJob(ctx, job.ID)
+	assert.ErrorIs(t, err, ErrJobNotFound, "an acked job is deleted")
+}
+
+func TestServer_RetryThenArchive(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	topic := testTopic(t, db)
+
+	client := NewClient(db)
+	enqueued, err := client.Enqueue(ctx, topic, []byte(`{}`), WithMaxRetries(1))
+	require.NoError(t, err)
+
+	server := NewServer(db, Config{RetryDelay: func(int) time.Duration { return 0 }})
+	server.Handle(Handle{TopicName: topic, Handler: func(ctx context.Context, job Job) error { return errors.New("boom") }})
+
+	job, err := server.claim(ctx, []string{topic})
+	require.NoError(t, err)
+	server.process(ctx, job)
+
+	retried, err := client.GetJob(ctx, enqueued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatePending, retried.State)
+	assert.Equal(t, 1, retried.Attempts)
+	assert.Equal(t, "boom", retried.LastError)
+	assert.Nil(t, retried.LockedUntil)
+
+	job, err = server.claim(ctx, []string{topic})
+	require.NoError(t, err)
+	assert.Equal(t, 2, job.Attempts)
+	server.process(ctx, job)
+
+	archived, err := client.GetJob(ctx, enqueued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateArchived, archived.State)
+	assert.Equal(t, 2, archived.Attempts)
+
+	_, err = server.claim(ctx, []string{topic})
+	assert.ErrorIs(t, err, sql.ErrNoRows, "an archived job is not claimed")
+}
+
+func TestServer_DeferKeepsAttempts(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	topic := testTopic(t, db)
+
+	client := NewClient(db)
+	enqueued, err := client.Enqueue(ctx, topic, []byte(`{}`), WithMaxRetries(0))
+	require.NoError(t, err)
+
+	server := NewServer(db, Config{IsFailure: func(error) bool { return false }})
+	server.Handle(Handle{TopicName: topic, Handler: func(ctx context.Context, job Job) error { return errors.New("paused") }})
+
+	job, err := server.claim(ctx, []string{topic})
+	require.NoError(t, err)
+	server.process(ctx, job)
+
+	deferred, err := client.GetJob(ctx, enqueued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatePending, deferred.State, "a job that did not fail is not archived")
+	assert.Equal(t, 0, deferred.Attempts, "a job that did not fail gives its attempt back")
+	assert.True(t, deferred.RunAt.After(deferred.UpdatedAt), "a deferred job waits before running again")
+}
+
+func TestServer_ConcurrentServersClaimOnce(t *testing.T) {
+	const total = 50
+
+	ctx := context.Background()
+	db := testDB(t)
+	topic := testTopic(t, db)
+
+	client := NewClient(db)
+	for range total {
+		_, err := client.Enqueue(ctx, topic, []byte(`{}`))
+		require.NoError(t, err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	handled := make(map[uuid.UUID]int)
+	handler := func(ctx context.Context, job Job) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		handled[job.ID]++
+		if len(handled) == total {
+			cancel()
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for range 2 {
+		server := NewServer(db, Config{Concurrency: 5, PollInterval: 10 * time.Millisecond})
+		server.Handle(Handle{TopicName: topic, Handler: handler})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, server.Run(runCtx))
+		}()
+	}
+
+	select {
+	case <-runCtx.Done():
+	case <-time.After(10 * time.Second):
+		cancel()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Len(t, handled, total)
+	for id, count := range handled {
+		assert.Equal(t, 1, count, "job %s was claimed more than once", id)
+	}
+}
+
+func TestServer_ReclaimAfterVisibilityTimeout(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	topic := testTopic(t, db)
+
+	client := NewClient(db)
+	enqueued, err := client.Enqueue(ctx, topic, []byte(`{}`))
+	require.NoError(t, err)
+
+	handle := Handle{TopicName: topic, Handler: func(ctx context.Context, job Job) error { return nil }}
+	dead := NewServer(db, Config{VisibilityTimeout: 100 * time.Millisecond})
+	dead.Handle(handle)
+	alive := NewServer(db, Config{VisibilityTimeout: time.Minute})
+	alive.Handle(handle)
+
+	// the worker of dead claims the job and never finishes it
+	stale, err := dead.claim(ctx, []string{topic})
+	require.NoError(t, err)
+
+	_, err = alive.claim(ctx, []string{topic})
+	assert.ErrorIs(t, err, sql.ErrNoRows, "the job is locked until its visibility timeout")
+
+	time.Sleep(200 * time.Millisecond)
+
+	reclaimed, err := alive.claim(ctx, []string{topic})
+	require.NoError(t, err)
+	assert.Equal(t, enqueued.ID, reclaimed.ID)
+	assert.Equal(t, 2, reclaimed.Attempts)
+
+	// the late ack of the dead worker no longer owns the job
+	dead.process(ctx, stale)
+	job, err := client.GetJob(ctx, enqueued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateRunning, job.State)
+
+	alive.process(ctx, reclaimed)
+	_, err = client.GetJob(ctx, enqueued.ID)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
@@ -6,16 +6,18 @@ import (
 )
 
 type PublisherStrategy struct {
-	TasksPublisher  GenericPublisher
-	PubsubPublisher GenericPublisher
+	TasksPublisher   GenericPublisher
+	PubsubPublisher  GenericPublisher
+	DurablePublisher GenericPublisher
 }
 
 var _ GenericPublisher = (*PublisherStrategy)(nil)
 
 func NewStrategy(classificationResult *ClassificationResult) *PublisherStrategy {
 	return &PublisherStrategy{
-		TasksPublisher:  classificationResult.InternalPublisher,
-		PubsubPublisher: classificationResult.ExternalPublisher,
+		TasksPublisher:   classificationResult.InternalPublisher,
+		PubsubPublisher:  classificationResult.ExternalPublisher,
+		DurablePublisher: classificationResult.DurablePublisher,
 	}
 }
 
@@ -25,6 +27,11 @@ func (s *PublisherStrategy) Publish(ctx context.Context, eventName string, paylo
 		return s.TasksPublisher.Publish(ctx, eventName, payload, opts)
 	case LowLatency:
 		return s.PubsubPublisher.Publish(ctx, eventName, payload, opts)
+	case DurableSQL:
+		if s.DurablePublisher == nil {
+			return fmt.Errorf("publisher for %s is not enabled", opts.WQType)
+		}
+		return s.DurablePublisher.Publish(ctx, eventName, payload, opts)
 	default:
 		return fmt.Errorf("invalid publish type: %s", opts.WQType)
 	}
@@ -0,0 +1,75 @@
+package pubadapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublisherStrategy_Publish(t *testing.T) {
+	tests := []struct {
+		name        string
+		wqType      WQType
+		withDurable bool
+		expect      string
+		wantErr     bool
+	}{
+		{name: "low_throughput_goes_to_tasks", wqType: LowThroughput, withDurable: true, expect: "tasks"},
+		{name: "high_throughput_goes_to_tasks", wqType: HighThroughput, withDurable: true, expect: "tasks"},
+		{name: "low_latency_goes_to_pubsub", wqType: LowLatency, withDurable: true, expect: "pubsub"},
+		{name: "durable_sql_goes_to_durable", wqType: DurableSQL, withDurable: true, expect: "durable"},
+		{name: "durable_sql_without_publisher_fails", wqType: DurableSQL, wantErr: true},
+		{name: "unknown_type_fails", wqType: "unknown", withDurable: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			publishers := map[string]*MockGenericPublisher{
+				"tasks":   NewMockGenericPublisher(ctrl),
+				"pubsub":  NewMockGenericPublisher(ctrl),
+				"durable": NewMockGenericPublisher(ctrl),
+			}
+
+			opts := Opts{WQType: tt.wqType}
+			if tt.expect != "" {
+				publishers[tt.expect].EXPECT().Publish(gomock.Any(), "topic", "payload", opts).Return(nil)
+			}
+
+			result := &ClassificationResult{
+				InternalPublisher: publishers["tasks"],
+				ExternalPublisher: publishers["pubsub"],
+			}
+			if tt.withDurable {
+				result.DurablePublisher = publishers["durable"]
+			}
+
+			err := NewStrategy(result).Publish(context.Background(), "topic", "payload", opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestToEnqueueOptions(t *testing.T) {
+	opts := toEnqueueOptions([]asynq.Option{
+		asynq.MaxRetry(5),
+		asynq.Queue("critical"),
+		asynq.ProcessIn(time.Minute),
+		asynq.Retention(time.Hour),
+		asynq.Unique(time.Minute),
+	})
+
+	// retention and uniqueness have no equivalent in the SQL queue
+	assert.Len(t, opts, 3)
+}
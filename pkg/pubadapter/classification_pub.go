@@ -3,6 +3,7 @@ package pubadapter
 type ClassificationResult struct {
 	InternalPublisher GenericPublisher
 	ExternalPublisher GenericPublisher
+	DurablePublisher  GenericPublisher
 }
 
 func ClassificationPublisher(gcppubsub, redisAsync GenericPublisher) ClassificationResult {
@@ -19,9 +19,13 @@ var EmptyOpts = Opts{
 
 type WQType string
 
+func (wt WQType) String() string {
+	return string(wt)
+}
+
 func (wt WQType) Validate() error {
 	switch wt {
-	case LowThroughput, HighThroughput, LowLatency:
+	case LowThroughput, HighThroughput, LowLatency, DurableSQL:
 		return nil
 	default:
 		return fmt.Errorf("invalid WQType: %s", wt)
@@ -32,4 +36,5 @@ const (
 	LowThroughput  WQType = "low_throughput"
 	HighThroughput WQType = "high_throughput"
 	LowLatency     WQType = "low_latency"
+	DurableSQL     WQType = "durable_sql"
 )
@@ -0,0 +1,67 @@
+package pubadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IsaacDSC/gqueue/pkg/ctxlogger"
+	"github.com/IsaacDSC/gqueue/pkg/pgqueue"
+	"github.com/IsaacDSC/gqueue/pkg/telemetry"
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type DurableSQLPublisher struct {
+	client *pgqueue.Client
+}
+
+var _ GenericPublisher = (*DurableSQLPublisher)(nil)
+
+func NewDurableSQLPublisher(client *pgqueue.Client) *DurableSQLPublisher {
+	return &DurableSQLPublisher{client: client}
+}
+
+func (d *DurableSQLPublisher) Publish(ctx context.Context, eventName string, payload any, opts Opts) error {
+	l := ctxlogger.GetLogger(ctx)
+
+	p, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not marshal payload: %v", err)
+	}
+
+	job, err := d.client.Enqueue(ctx, eventName, p, toEnqueueOptions(opts.AsynqOpts)...)
+	if err != nil {
+		telemetry.TaskPublisherRequests.Increment(
+			ctx,
+			attribute.String("event_name", eventName),
+			attribute.String("wq_type", DurableSQL.String()),
+			attribute.String("error", err.Error()),
+		)
+		return fmt.Errorf("could not enqueue job: %v", err)
+	}
+
+	l.Debug("enqueued job", "id", job.ID, "queue", job.Queue)
+	return nil
+}
+
+// toEnqueueOptions translates the asynq options built from an event configuration
+// so both task backends honour the same max retries, queue and schedule settings.
+func toEnqueueOptions(opts []asynq.Option) []pgqueue.EnqueueOption {
+	output := make([]pgqueue.EnqueueOption, 0, len(opts))
+	for _, opt := range opts {
+		switch opt.Type() {
+		case asynq.MaxRetryOpt:
+			output = append(output, pgqueue.WithMaxRetries(opt.Value().(int)))
+		case asynq.QueueOpt:
+			output = append(output, pgqueue.WithQueue(opt.Value().(string)))
+		case asynq.ProcessInOpt:
+			output = append(output, pgqueue.WithProcessIn(opt.Value().(time.Duration)))
+		case asynq.ProcessAtOpt:
+			output = append(output, pgqueue.WithProcessAt(opt.Value().(time.Time)))
+		}
+	}
+
+	return output
+}
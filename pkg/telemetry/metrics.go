@@ -14,8 +14,9 @@ type Metric struct {
 
 var (
 	// Mem Store
-	MemStoreEventNotFound = Metric{Name: "mem_store_event_not_found_total", Description: "Total of events not found in the memory store"}
-	MemActivityDuration   = Metric{Name: "mem_store_activity_duration", Description: "Activity of the memory store"}
+	MemStoreEventNotFound   = Metric{Name: "mem_store_event_not_found_total", Description: "Total of events not found in the memory store"}
+	MemActivityDuration     = Metric{Name: "mem_store_activity_duration", Description: "Activity of the memory store"}
+	MemStoreRegistryVersion = Metric{Name: "mem_store_registry_version", Description: "Registry version applied to the memory store"} // Filter by instance_id
 
 	// HTTP Server
 	HTTPServerRequests        = Metric{Name: "http_server_requests_total", Description: "Total of requests to the HTTP server"} // Filter by http.response_code
@@ -24,11 +25,12 @@ var (
 	HTTPClientRequests        = Metric{Name: "http_client_requests_total", Description: "Total of requests to the HTTP client"}
 	HTTPClientRequestDuration = Metric{Name: "http_client_request_duration_seconds", Description: "Duration of requests to the HTTP client"}
 	// PubSub
-	PubSubPublisherRequests  = Metric{Name: "pubsub_publisher_requests_total", Description: "Total of requests to the pubsub publisher"}
-	PubSubConsumerRetries    = Metric{Name: "pubsub_consumer_retries_total", Description: "Total of retries for a consumer"}
-	PubSubConsumerDlq        = Metric{Name: "pubsub_consumer_dlq_total", Description: "Total of archived messages for a consumer"}
-	PubSubConsumerDuration   = Metric{Name: "pubsub_consumer_duration_seconds", Description: "Duration of a consumer"}
-	PubSubConsumerLagSeconds = Metric{Name: "pubsub_consumer_lag_seconds", Description: "Time in seconds between message publish and consumer processing start"}
+	PubSubPublisherRequests   = Metric{Name: "pubsub_publisher_requests_total", Description: "Total of requests to the pubsub publisher"}
+	PubSubConsumerRetries     = Metric{Name: "pubsub_consumer_retries_total", Description: "Total of retries for a consumer"}
+	PubSubConsumerDlq         = Metric{Name: "pubsub_consumer_dlq_total", Description: "Total of archived messages for a consumer"}
+	PubSubConsumerDuration    = Metric{Name: "pubsub_consumer_duration_seconds", Description: "Duration of a consumer"}
+	PubSubConsumerLagSeconds  = Metric{Name: "pubsub_consumer_lag_seconds", Description: "Time in seconds between message publish and consumer processing start"}
+	PubSubSubscriptionBacklog = Metric{Name: "pubsub_subscription_backlog", Description: "Messages not yet acknowledged in a subscription"} // Filter by project, subscription and event
 	//  Task
 	TaskPublisherRequests       = Metric{Name: "task_publisher_requests_total", Description: "Total of requests to the task publisher"}
 	TaskConsumerRetries         = Metric{Name: "task_consumer_retries_total", Description: "Total of retries for a consumer"}
@@ -38,6 +40,11 @@ var (
 	TaskConsumerTotalProcessing = Metric{Name: "task_consumer_total_processing", Description: "Total of tasks being consumed"}           // Filter by task.event_name
 	TaskConsumerTotalFailure    = Metric{Name: "task_consumer_total_failure", Description: "Total of tasks being consumed with failure"} // Filter by task.event_name
 	TaskConsumerTotalSuccess    = Metric{Name: "task_consumer_total_success", Description: "Total of tasks being consumed with success"} // Filter by task.event_name
+	TaskQueueBacklog            = Metric{Name: "task_queue_backlog", Description: "Tasks waiting in a queue"}                            // Filter by queue, state, task.type and event
+	// Timeline
+	TimelineDropped = Metric{Name: "timeline_dropped_total", Description: "Total of timeline entries dropped because the recorder buffer was full"}
+	// Quarantine
+	ConsumerQuarantined = Metric{Name: "consumer_quarantined_total", Description: "Total of consumers quarantined after exhausting their failure budget"} // Filter by event and consumer.service_name
 )
 
 func (m Metric) Count(ctx context.Context, value int64, attrs ...attribute.KeyValue) {
@@ -61,6 +68,13 @@ func (m Metric) Decrement(ctx context.Context, attrs ...attribute.KeyValue) {
 	}
 }
 
+func (m Metric) Set(ctx context.Context, value int64, attrs ...attribute.KeyValue) {
+	meter := MeterFromContext(ctx)
+	if gauge, err := meter.Int64Gauge(m.Name, metric.WithDescription(m.Description)); err == nil {
+		gauge.Record(ctx, value, metric.WithAttributes(attrs...))
+	}
+}
+
 func (m Metric) Record(ctx context.Context, value float64, attrs ...attribute.KeyValue) {
 	meter := MeterFromContext(ctx)
 	if histogram, err := meter.Float64Histogram(m.Name, metric.WithDescription(m.Description)); err == nil {
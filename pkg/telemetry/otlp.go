@@ -0,0 +1,169 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	ExporterPrometheus = "prometheus"
+	ExporterOTLP       = "otlp"
+
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http/protobuf"
+
+	TemporalityCumulative = "cumulative"
+	TemporalityDelta      = "delta"
+)
+
+// OTLPConfig configures the push of metrics and spans to an OpenTelemetry collector
+type OTLPConfig struct {
+	// Endpoint is the base URL of the collector, e.g. http://collector:4318 for http/protobuf or
+	// http://collector:4317 for grpc. The https scheme enables TLS.
+	Endpoint string
+	// Protocol is http/protobuf, the default, or grpc
+	Protocol string
+	// Interval between two exports of the metrics, 60s when zero
+	Interval time.Duration
+	// Timeout of an export of the metrics, 30s when zero
+	Timeout time.Duration
+	// Temporality of the counters and histograms, cumulative by default or delta. Up down
+	// counters and gauges are always cumulative.
+	Temporality string
+}
+
+// ResourceConfig describes the process in the attributes of its metrics and spans. The standard
+// OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME variables override them.
+type ResourceConfig struct {
+	ServiceName string
+	// Scope is the services run by the process, e.g. all or task
+	Scope string
+	// InstanceID is the host name with a random suffix when empty
+	InstanceID string
+	// Version is the one of the build when empty
+	Version string
+}
+
+func newResource(ctx context.Context, cfg ResourceConfig) (*resource.Resource, error) {
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		host, _ := os.Hostname()
+		instanceID = fmt.Sprintf("%s-%s", host, uuid.NewString()[:8])
+	}
+
+	version := cfg.Version
+	if version == "" {
+		version = buildVersion()
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("service.instance.id", instanceID),
+			attribute.String("service.version", version),
+			attribute.String("gqueue.scope", cfg.Scope),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create resource: %w", err)
+	}
+
+	return res, nil
+}
+
+// buildVersion is the version of the module, or the commit it was built from
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+			return setting.Value[:12]
+		}
+	}
+
+	return "devel"
+}
+
+// newOTLPReader pushes the metrics to the collector periodically
+func newOTLPReader(ctx context.Context, cfg OTLPConfig) (sdkmetric.Reader, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("the %s metrics exporter needs an endpoint", ExporterOTLP)
+	}
+
+	temporality, err := temporalitySelector(cfg.Temporality)
+	if err != nil {
+		return nil, err
+	}
+
+	var exporter sdkmetric.Exporter
+	switch cfg.Protocol {
+	case ProtocolGRPC:
+		exporter, err = otlpmetricgrpc.New(ctx,
+			otlpmetricgrpc.WithEndpointURL(cfg.Endpoint),
+			otlpmetricgrpc.WithTemporalitySelector(temporality),
+		)
+	case "", ProtocolHTTP:
+		exporter, err = otlpmetrichttp.New(ctx,
+			otlpmetrichttp.WithEndpointURL(signalURL(cfg.Endpoint, "metrics")),
+			otlpmetrichttp.WithTemporalitySelector(temporality),
+		)
+	default:
+		return nil, fmt.Errorf("unsupported otlp protocol %q, use %s or %s", cfg.Protocol, ProtocolHTTP, ProtocolGRPC)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create otlp metrics exporter: %w", err)
+	}
+
+	opts := make([]sdkmetric.PeriodicReaderOption, 0, 2)
+	if cfg.Interval > 0 {
+		opts = append(opts, sdkmetric.WithInterval(cfg.Interval))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, sdkmetric.WithTimeout(cfg.Timeout))
+	}
+
+	return sdkmetric.NewPeriodicReader(exporter, opts...), nil
+}
+
+func temporalitySelector(temporality string) (sdkmetric.TemporalitySelector, error) {
+	switch temporality {
+	case "", TemporalityCumulative:
+		return sdkmetric.DefaultTemporalitySelector, nil
+	case TemporalityDelta:
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch kind {
+			case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindHistogram, sdkmetric.InstrumentKindObservableCounter:
+				return metricdata.DeltaTemporality
+			default:
+				return metricdata.CumulativeTemporality
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported temporality %q, use %s or %s", temporality, TemporalityCumulative, TemporalityDelta)
+	}
+}
+
+// signalURL is the OTLP/HTTP path of the signal under the base URL of the collector
+func signalURL(endpoint, signal string) string {
+	return strings.TrimSuffix(endpoint, "/") + "/v1/" + signal
+}
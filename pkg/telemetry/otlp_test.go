@@ -0,0 +1,154 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector stands in for an OpenTelemetry collector, it keeps the requests it receives
+type collector struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+	requests chan *colmetricpb.ExportMetricsServiceRequest
+}
+
+func newCollector() *collector {
+	return &collector{requests: make(chan *colmetricpb.ExportMetricsServiceRequest, 10)}
+}
+
+func (c *collector) Export(_ context.Context, req *colmetricpb.ExportMetricsServiceRequest) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	c.requests <- req
+	return &colmetricpb.ExportMetricsServiceResponse{}, nil
+}
+
+// serveHTTP accepts OTLP/HTTP requests on /v1/metrics
+func (c *collector) serveHTTP(t *testing.T) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req colmetricpb.ExportMetricsServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+		c.requests <- &req
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		data, _ := proto.Marshal(&colmetricpb.ExportMetricsServiceResponse{})
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+// serveGRPC accepts OTLP/gRPC requests
+func (c *collector) serveGRPC(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	colmetricpb.RegisterMetricsServiceServer(server, c)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return "http://" + listener.Addr().String()
+}
+
+func (c *collector) receive(t *testing.T) *colmetricpb.ExportMetricsServiceRequest {
+	t.Helper()
+
+	select {
+	case req := <-c.requests:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("the collector received no metrics")
+		return nil
+	}
+}
+
+func TestNewMeterProvider_OTLP(t *testing.T) {
+	tests := []struct {
+		name        string
+		protocol    string
+		temporality string
+		want        metricpb.AggregationTemporality
+	}{
+		{name: "http cumulative", protocol: ProtocolHTTP, want: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE},
+		{name: "grpc delta", protocol: ProtocolGRPC, temporality: TemporalityDelta, want: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newCollector()
+
+			endpoint := c.serveHTTP(t)
+			if tt.protocol == ProtocolGRPC {
+				endpoint = c.serveGRPC(t)
+			}
+
+			res, err := newResource(ctx, ResourceConfig{ServiceName: "gqueue", Scope: "task", InstanceID: "worker-1", Version: "v1.2.3"})
+			require.NoError(t, err)
+
+			provider, _, err := newMeterProvider(ctx, Config{
+				Enabled:   true,
+				Exporters: []string{ExporterOTLP},
+				OTLP:      OTLPConfig{Endpoint: endpoint, Protocol: tt.protocol, Interval: time.Hour, Temporality: tt.temporality},
+			}, res)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = provider.Shutdown(ctx) })
+
+			ctx = WithMeter(ctx, provider.Meter("test"))
+			TaskConsumerTotalSuccess.Count(ctx, 3)
+			require.NoError(t, provider.ForceFlush(ctx))
+
+			req := c.receive(t)
+			require.Len(t, req.ResourceMetrics, 1)
+
+			attrs := make(map[string]string)
+			for _, attr := range req.ResourceMetrics[0].Resource.Attributes {
+				attrs[attr.Key] = attr.Value.GetStringValue()
+			}
+			assert.Equal(t, "gqueue", attrs["service.name"])
+			assert.Equal(t, "task", attrs["gqueue.scope"])
+			assert.Equal(t, "worker-1", attrs["service.instance.id"])
+			assert.Equal(t, "v1.2.3", attrs["service.version"])
+
+			metric := req.ResourceMetrics[0].ScopeMetrics[0].Metrics[0]
+			assert.Equal(t, TaskConsumerTotalSuccess.Name, metric.Name)
+			assert.Equal(t, tt.want, metric.GetSum().AggregationTemporality)
+			assert.Equal(t, int64(3), metric.GetSum().DataPoints[0].GetAsInt())
+		})
+	}
+}
+
+func TestNewMeterProvider_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "unknown exporter", cfg: Config{Enabled: true, Exporters: []string{"statsd"}}},
+		{name: "otlp without endpoint", cfg: Config{Enabled: true, Exporters: []string{ExporterOTLP}}},
+		{name: "unknown protocol", cfg: Config{Enabled: true, Exporters: []string{ExporterOTLP}, OTLP: OTLPConfig{Endpoint: "http://localhost:4318", Protocol: "thrift"}}},
+		{name: "unknown temporality", cfg: Config{Enabled: true, Exporters: []string{ExporterOTLP}, OTLP: OTLPConfig{Endpoint: "http://localhost:4318", Temporality: "lowmemory"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := newMeterProvider(context.Background(), tt.cfg, nil)
+			assert.Error(t, err)
+		})
+	}
+}
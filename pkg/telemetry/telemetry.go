@@ -2,13 +2,19 @@ package telemetry
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
 
@@ -19,6 +25,12 @@ import (
 // Config encapsulates metric provider initialization options.
 type Config struct {
 	Enabled bool
+	// Exporters of the metrics: prometheus serves them on the metrics handler and otlp pushes
+	// them to the OTLP endpoint. Prometheus only when empty.
+	Exporters []string
+	OTLP      OTLPConfig
+	Resource  ResourceConfig
+	Tracing   TracingConfig
 }
 
 // state holds the current meter provider and metrics handler.
@@ -28,8 +40,10 @@ type Config struct {
 // instead of using package-level globals; that would avoid any sync primitive.
 type state struct {
 	provider *sdkmetric.MeterProvider
-	handler  http.Handler
-	initErr  error // non-nil if New failed during init
+	// tracer is nil when spans are not exported
+	tracer  *sdktrace.TracerProvider
+	handler http.Handler
+	initErr error // non-nil if New failed during init
 }
 
 var (
@@ -46,30 +60,42 @@ func init() {
 	})
 }
 
-// New initializes the global MeterProvider and the HTTP metrics handler.
+// New initializes the global MeterProvider, TracerProvider and the HTTP metrics handler.
 // It should be called once at application startup. Initialization runs at most once;
 // subsequent calls return the same handler and any initial error.
 func New(cfg Config) (http.Handler, error) {
 	once.Do(func() {
 		var s state
-		if !cfg.Enabled {
-			s.provider = sdkmetric.NewMeterProvider()
-			s.handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-				w.WriteHeader(http.StatusOK)
-				_, _ = w.Write([]byte("# metrics disabled\n"))
-			})
-		} else {
-			exp, err := otelprom.New(otelprom.WithRegisterer(promclient.DefaultRegisterer))
-			if err != nil {
-				s.initErr = err
-				s.handler = stateVal.Load().(*state).handler // keep placeholder
-				stateVal.Store(&s)
-				return
-			}
-			s.provider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(exp))
-			s.handler = promhttp.Handler()
+		ctx := context.Background()
+
+		res, err := newResource(ctx, cfg.Resource)
+		if err != nil {
+			s.initErr = err
+			s.handler = stateVal.Load().(*state).handler // keep placeholder
+			stateVal.Store(&s)
+			return
+		}
+
+		s.provider, s.handler, err = newMeterProvider(ctx, cfg, res)
+		if err != nil {
+			s.initErr = err
+			s.handler = stateVal.Load().(*state).handler // keep placeholder
+			stateVal.Store(&s)
+			return
 		}
 		otel.SetMeterProvider(s.provider)
+
+		// the trace context is propagated even when spans are not exported
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+		tracer, err := newTracerProvider(ctx, cfg.OTLP, cfg.Tracing, res)
+		if err != nil {
+			s.initErr = err
+		}
+		if tracer != nil {
+			s.tracer = tracer
+			otel.SetTracerProvider(tracer)
+		}
+
 		stateVal.Store(&s)
 	})
 
@@ -80,13 +106,64 @@ func New(cfg Config) (http.Handler, error) {
 	return cur.handler, nil
 }
 
-// Shutdown stops the global MeterProvider and releases resources.
+// newMeterProvider reads the metrics with every exporter of the config, the handler serves them
+// when prometheus is one of them
+func newMeterProvider(ctx context.Context, cfg Config, res *resource.Resource) (*sdkmetric.MeterProvider, http.Handler, error) {
+	if !cfg.Enabled {
+		return sdkmetric.NewMeterProvider(), message("# metrics disabled\n"), nil
+	}
+
+	exporters := cfg.Exporters
+	if len(exporters) == 0 {
+		exporters = []string{ExporterPrometheus}
+	}
+
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	handler := message("# metrics exported with OTLP\n")
+	for _, name := range exporters {
+		switch strings.TrimSpace(name) {
+		case ExporterPrometheus:
+			exp, err := otelprom.New(otelprom.WithRegisterer(promclient.DefaultRegisterer))
+			if err != nil {
+				return nil, nil, err
+			}
+			opts = append(opts, sdkmetric.WithReader(exp))
+			handler = promhttp.Handler()
+		case ExporterOTLP:
+			reader, err := newOTLPReader(ctx, cfg.OTLP)
+			if err != nil {
+				return nil, nil, err
+			}
+			opts = append(opts, sdkmetric.WithReader(reader))
+		default:
+			return nil, nil, fmt.Errorf("unsupported metrics exporter %q, use %s or %s", name, ExporterPrometheus, ExporterOTLP)
+		}
+	}
+
+	return sdkmetric.NewMeterProvider(opts...), handler, nil
+}
+
+func message(text string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(text))
+	})
+}
+
+// Shutdown stops the global MeterProvider and TracerProvider, flushing pending spans, and
+// releases resources.
 func Shutdown(ctx context.Context) error {
 	cur := stateVal.Load().(*state)
+
+	var err error
+	if cur.tracer != nil {
+		err = cur.tracer.Shutdown(ctx)
+	}
+
 	if cur.provider == nil {
-		return nil
+		return err
 	}
-	return cur.provider.Shutdown(ctx)
+	return errors.Join(err, cur.provider.Shutdown(ctx))
 }
 
 // Meter returns a Meter from the global provider.
@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/IsaacDSC/gqueue"
+
+// TracingConfig configures the spans, they are exported to the OTLP endpoint. Without an endpoint
+// spans are not recorded, the trace context of the publishers is still forwarded to the consumers.
+type TracingConfig struct {
+	// SampleRatio is the share of the traces started by gqueue that are sampled, traces started
+	// by a publisher follow its decision
+	SampleRatio float64
+}
+
+// newTracerProvider returns nil when spans are not exported
+func newTracerProvider(ctx context.Context, otlp OTLPConfig, cfg TracingConfig, res *resource.Resource) (*sdktrace.TracerProvider, error) {
+	if otlp.Endpoint == "" {
+		return nil, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch otlp.Protocol {
+	case ProtocolGRPC:
+		exporter, err = otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(otlp.Endpoint))
+	case "", ProtocolHTTP:
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(signalURL(otlp.Endpoint, "traces")))
+	default:
+		return nil, fmt.Errorf("unsupported otlp protocol %q, use %s or %s", otlp.Protocol, ProtocolHTTP, ProtocolGRPC)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create trace exporter: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	), nil
+}
+
+// StartSpan starts a span of gqueue, end it with EndSpan
+func StartSpan(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the error of the operation, if any, and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// InjectMap writes the trace context of ctx in a map carried with a message
+func InjectMap(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+
+	return carrier
+}
+
+// ExtractMap continues in ctx the trace context carried with a message
+func ExtractMap(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// InjectHeaders writes the trace context of ctx as traceparent and tracestate headers
+func InjectHeaders(ctx context.Context, headers http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(headers))
+}
+
+// ExtractHeaders continues in ctx the trace context of the headers of a request
+func ExtractHeaders(ctx context.Context, headers http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(headers))
+}